@@ -0,0 +1,45 @@
+package collectorhealth
+
+import (
+	"sync"
+	"time"
+)
+
+// RemoteReport is the last collector health pushed by a cluster agent.
+type RemoteReport struct {
+	ReceivedAt time.Time
+	Collectors []Status
+}
+
+// RemoteStore keeps the latest pushed collector health per agent host, in memory only.
+type RemoteStore struct {
+	mu     sync.RWMutex
+	byHost map[uint]RemoteReport
+}
+
+// NewRemoteStore creates an empty store.
+func NewRemoteStore() *RemoteStore {
+	return &RemoteStore{byHost: make(map[uint]RemoteReport)}
+}
+
+// Set replaces the report of hostID.
+func (r *RemoteStore) Set(hostID uint, collectors []Status) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byHost[hostID] = RemoteReport{ReceivedAt: time.Now().UTC(), Collectors: collectors}
+}
+
+// Get returns the last report of hostID.
+func (r *RemoteStore) Get(hostID uint) (RemoteReport, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rep, ok := r.byHost[hostID]
+	return rep, ok
+}
+
+// Delete forgets hostID, e.g. after the host was removed.
+func (r *RemoteStore) Delete(hostID uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byHost, hostID)
+}
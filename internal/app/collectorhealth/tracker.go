@@ -0,0 +1,184 @@
+// Package collectorhealth tracks the health of every module collector in memory.
+// The scheduler records each run; the status endpoint, /health, agent pushes and
+// Prometheus read the same statuses so stale data is never silent.
+package collectorhealth
+
+import (
+	"sync"
+	"time"
+)
+
+// Collector states reported in Status.State.
+const (
+	StatePending = "pending" // registered, no run finished yet
+	StateOK      = "ok"      // last run succeeded recently
+	StateFailing = "failing" // last run failed
+	StateStale   = "stale"   // no success within StaleAfterIntervals intervals
+)
+
+// StaleAfterIntervals is how many intervals may pass without a success before a collector is stale.
+const StaleAfterIntervals = 3
+
+// Status is the health of one module collector.
+type Status struct {
+	Module              string     `json:"module"`
+	State               string     `json:"state"`
+	IntervalSeconds     float64    `json:"interval_seconds"`
+	TimeoutSeconds      float64    `json:"timeout_seconds"`
+	LastRunAt           *time.Time `json:"last_run_at"`
+	LastSuccessAt       *time.Time `json:"last_success_at"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastDurationSeconds float64    `json:"last_duration_seconds"`
+	Runs                uint64     `json:"runs"`
+	Errors              uint64     `json:"errors"`
+	Timeouts            uint64     `json:"timeouts"`
+	Skipped             uint64     `json:"skipped"`
+	Overruns            uint64     `json:"overruns"`
+}
+
+// Healthy reports whether every collector is ok (pending collectors do not count as failures).
+func Healthy(statuses []Status) bool {
+	for _, st := range statuses {
+		if st.State == StateFailing || st.State == StateStale {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary is the collector health without error details, for unauthenticated callers.
+type Summary struct {
+	// State is "ok", or "degraded" when a collector is failing or stale
+	State   string   `json:"state"`
+	Failing []string `json:"failing,omitempty"`
+}
+
+// Summarize reduces statuses to the overall state and the modules that are failing or stale.
+func Summarize(statuses []Status) Summary {
+	summary := Summary{State: "ok"}
+	for _, st := range statuses {
+		if st.State == StateFailing || st.State == StateStale {
+			summary.Failing = append(summary.Failing, st.Module)
+		}
+	}
+	if len(summary.Failing) > 0 {
+		summary.State = "degraded"
+	}
+	return summary
+}
+
+type moduleHealth struct {
+	status   Status
+	interval time.Duration
+}
+
+// Tracker holds the health of the local collectors.
+type Tracker struct {
+	mu      sync.RWMutex
+	order   []string
+	modules map[string]*moduleHealth
+	now     func() time.Time
+}
+
+// NewTracker creates an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		modules: make(map[string]*moduleHealth),
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Register adds a module with its schedule; registering twice updates the schedule.
+func (t *Tracker) Register(module string, interval, timeout time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	m, ok := t.modules[module]
+	if !ok {
+		m = &moduleHealth{status: Status{Module: module}}
+		t.modules[module] = m
+		t.order = append(t.order, module)
+	}
+	m.interval = interval
+	m.status.IntervalSeconds = interval.Seconds()
+	m.status.TimeoutSeconds = timeout.Seconds()
+}
+
+// RecordRun records a finished run. timedOut marks errors caused by the collector timeout.
+func (t *Tracker) RecordRun(module string, duration time.Duration, err error, timedOut bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	m := t.module(module)
+	now := t.now()
+
+	st := &m.status
+	st.LastRunAt = &now
+	st.LastDurationSeconds = duration.Seconds()
+	st.Runs++
+	if err != nil {
+		st.Errors++
+		st.ConsecutiveFailures++
+		st.LastError = err.Error()
+		st.LastErrorAt = &now
+		if timedOut {
+			st.Timeouts++
+		}
+		return
+	}
+	st.ConsecutiveFailures = 0
+	st.LastSuccessAt = &now
+}
+
+// RecordSkip counts a tick dropped because the previous run was still in progress.
+func (t *Tracker) RecordSkip(module string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.module(module).status.Skipped++
+}
+
+// RecordOverrun counts a run that took longer than its interval.
+func (t *Tracker) RecordOverrun(module string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.module(module).status.Overruns++
+}
+
+// Statuses returns a copy of every module's status in registration order.
+func (t *Tracker) Statuses() []Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	now := t.now()
+	out := make([]Status, 0, len(t.order))
+	for _, name := range t.order {
+		m := t.modules[name]
+		st := m.status
+		st.State = state(st, m.interval, now)
+		out = append(out, st)
+	}
+	return out
+}
+
+// module returns the module's entry, registering unknown modules without a schedule. Callers hold mu.
+func (t *Tracker) module(name string) *moduleHealth {
+	m, ok := t.modules[name]
+	if !ok {
+		m = &moduleHealth{status: Status{Module: name}}
+		t.modules[name] = m
+		t.order = append(t.order, name)
+	}
+	return m
+}
+
+func state(st Status, interval time.Duration, now time.Time) string {
+	switch {
+	case st.LastRunAt == nil:
+		return StatePending
+	case st.ConsecutiveFailures > 0:
+		return StateFailing
+	case interval > 0 && st.LastSuccessAt != nil && now.Sub(*st.LastSuccessAt) > StaleAfterIntervals*interval:
+		return StateStale
+	default:
+		return StateOK
+	}
+}
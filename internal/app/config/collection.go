@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultCollectInterval is how often a module collects when COLLECT_INTERVAL is unset.
+	DefaultCollectInterval = 5 * time.Second
+	// DefaultCollectTimeout bounds one collector run when COLLECT_TIMEOUT is unset.
+	DefaultCollectTimeout = 30 * time.Second
+
+	minCollectInterval = time.Second
+
+	collectIntervalEnv = "COLLECT_INTERVAL"
+	collectTimeoutEnv  = "COLLECT_TIMEOUT"
+)
+
+// CollectorSchedule is how often one module collects and how long a single run may take.
+type CollectorSchedule struct {
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// CollectionConfig holds the default schedule and per-module overrides keyed by lower-case module name.
+type CollectionConfig struct {
+	Default CollectorSchedule
+	Modules map[string]CollectorSchedule
+}
+
+// For returns the schedule of module, falling back to the default for unset fields.
+func (c CollectionConfig) For(module string) CollectorSchedule {
+	sched := c.Default
+	if o, ok := c.Modules[strings.ToLower(module)]; ok {
+		if o.Interval > 0 {
+			sched.Interval = o.Interval
+		}
+		if o.Timeout > 0 {
+			sched.Timeout = o.Timeout
+		}
+	}
+	return sched
+}
+
+// loadCollectionConfig reads COLLECT_INTERVAL / COLLECT_TIMEOUT and their per-module
+// COLLECT_INTERVAL_<MODULE> / COLLECT_TIMEOUT_<MODULE> overrides (e.g. COLLECT_INTERVAL_DOCKER=15s).
+func loadCollectionConfig() (CollectionConfig, error) {
+	cfg := CollectionConfig{
+		Default: CollectorSchedule{Interval: DefaultCollectInterval, Timeout: DefaultCollectTimeout},
+		Modules: make(map[string]CollectorSchedule),
+	}
+
+	var err error
+	if v := os.Getenv(collectIntervalEnv); v != "" {
+		if cfg.Default.Interval, err = parseCollectDuration(collectIntervalEnv, v, minCollectInterval); err != nil {
+			return cfg, err
+		}
+	}
+	if v := os.Getenv(collectTimeoutEnv); v != "" {
+		if cfg.Default.Timeout, err = parseCollectDuration(collectTimeoutEnv, v, 0); err != nil {
+			return cfg, err
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if value == "" {
+			continue
+		}
+		var module string
+		var isInterval bool
+		switch {
+		case strings.HasPrefix(key, collectIntervalEnv+"_"):
+			module, isInterval = strings.TrimPrefix(key, collectIntervalEnv+"_"), true
+		case strings.HasPrefix(key, collectTimeoutEnv+"_"):
+			module = strings.TrimPrefix(key, collectTimeoutEnv+"_")
+		default:
+			continue
+		}
+		if module == "" {
+			continue
+		}
+
+		module = strings.ToLower(module)
+		sched := cfg.Modules[module]
+		if isInterval {
+			if sched.Interval, err = parseCollectDuration(key, value, minCollectInterval); err != nil {
+				return cfg, err
+			}
+		} else {
+			if sched.Timeout, err = parseCollectDuration(key, value, 0); err != nil {
+				return cfg, err
+			}
+		}
+		cfg.Modules[module] = sched
+	}
+	return cfg, nil
+}
+
+// parseCollectDuration accepts a Go duration ("15s", "1m") or a plain number of seconds.
+func parseCollectDuration(key, value string, min time.Duration) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		secs, aerr := strconv.Atoi(value)
+		if aerr != nil {
+			return 0, fmt.Errorf("%s: invalid duration %q", key, value)
+		}
+		d = time.Duration(secs) * time.Second
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s: must be positive, got %q", key, value)
+	}
+	if d < min {
+		return 0, fmt.Errorf("%s: must be at least %s, got %q", key, min, value)
+	}
+	return d, nil
+}
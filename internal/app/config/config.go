@@ -48,6 +48,12 @@ type Config struct {
 	// Data retention
 	RetentionDays int // METRICS_RETENTION_DAYS: how long to keep historical metrics, default 30
 
+	// Collection schedule (COLLECT_INTERVAL[_<MODULE>], COLLECT_TIMEOUT[_<MODULE>])
+	Collection CollectionConfig
+
+	// Modules
+	ModulesDisabled []string // MODULES_DISABLED: comma-separated modules not to build, e.g. "docker,sensors"
+
 	// Observability
 	PrometheusEnabled bool   // PROMETHEUS_ENABLED: expose /metrics endpoint, default false
 	PrometheusAuth    bool   // PROMETHEUS_AUTH: require Bearer token for /metrics, default false
@@ -104,6 +110,20 @@ func Load() (*Config, error) {
 	}
 	config.RetentionDays = retentionDays
 
+	// Collection schedule
+	collection, err := loadCollectionConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load collection configuration: %w", err)
+	}
+	config.Collection = collection
+
+	// Modules (names are validated against the registry at startup)
+	for _, name := range strings.Split(os.Getenv("MODULES_DISABLED"), ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			config.ModulesDisabled = append(config.ModulesDisabled, name)
+		}
+	}
+
 	// Observability
 	prometheusEnv := strings.ToLower(getEnv("PROMETHEUS_ENABLED", "false"))
 	config.PrometheusEnabled = prometheusEnv == "true" || prometheusEnv == "1"
@@ -0,0 +1,243 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	cpuentities "system-stats/internal/modules/cpu/infrastructure/entities"
+	diskentities "system-stats/internal/modules/disk/infrastructure/entities"
+	dockerdomain "system-stats/internal/modules/docker/domain/repositories"
+	dockerentities "system-stats/internal/modules/docker/infrastructure/entities"
+	hostentities "system-stats/internal/modules/hosts/infrastructure/entities"
+	memoryentities "system-stats/internal/modules/memory/infrastructure/entities"
+	networkentities "system-stats/internal/modules/network/infrastructure/entities"
+)
+
+// hostKeyedTable is a metric table whose primary key moved from (timestamp) to (host_id, timestamp).
+type hostKeyedTable struct {
+	name  string
+	model any
+	// pk lists the new primary key columns in order
+	pk []string
+}
+
+// hostKeyedTables are rebuilt parents first; docker_container_entities must follow docker_metrics
+// because its host_id is backfilled from the parent row.
+var hostKeyedTables = []hostKeyedTable{
+	{name: "cpu_metrics", model: &cpuentities.HistoricalCPUMetric{}, pk: []string{"host_id", "timestamp"}},
+	{name: "memory_metrics", model: &memoryentities.HistoricalMemoryMetric{}, pk: []string{"host_id", "timestamp"}},
+	{name: "disk_metrics", model: &diskentities.HistoricalDiskMetric{}, pk: []string{"host_id", "timestamp"}},
+	{name: "network_metrics", model: &networkentities.HistoricalNetworkMetric{}, pk: []string{"host_id", "timestamp"}},
+	{name: "docker_metrics", model: &dockerdomain.HistoricalDockerMetric{}, pk: []string{"host_id", "timestamp"}},
+	{name: "docker_container_entities", model: &dockerentities.DockerContainerEntity{}, pk: []string{"host_id", "id", "metric_timestamp"}},
+}
+
+const dockerContainersTable = "docker_container_entities"
+
+// legacyContainerFK is the constraint GORM created when containers referenced docker_metrics(timestamp) only.
+const legacyContainerFK = "fk_docker_metrics_containers"
+
+// hostScopedKeysUp converts metric tables created with a timestamp-only primary key to
+// host-scoped composite keys. Rows with a NULL host_id are assigned to the local collector host,
+// which is the only host that could have written them. Container rows take host_id from the
+// docker_metrics row sharing their metric_timestamp; on both dialects, container rows without
+// such a parent are deleted, as they cannot satisfy the new foreign key. Tables that do not exist
+// yet or are already keyed by host are left alone, so fresh databases pass through untouched.
+var hostScopedKeysUp = Steps{
+	"sqlite": func(db *gorm.DB) error {
+		pending, err := legacyHostKeyedTables(db)
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+		return migrateHostKeysSQLite(db, pending)
+	},
+	"postgres": func(db *gorm.DB) error {
+		pending, err := legacyHostKeyedTables(db)
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+		return migrateHostKeysPostgres(db, pending)
+	},
+}
+
+// legacyHostKeyedTables returns the existing tables whose host_id is missing or not part of the primary key.
+func legacyHostKeyedTables(db *gorm.DB) ([]hostKeyedTable, error) {
+	var pending []hostKeyedTable
+	for _, t := range hostKeyedTables {
+		if !db.Migrator().HasTable(t.name) {
+			continue
+		}
+		cols, err := db.Migrator().ColumnTypes(t.name)
+		if err != nil {
+			return nil, fmt.Errorf("inspect %s: %w", t.name, err)
+		}
+		keyed := false
+		for _, c := range cols {
+			if c.Name() != "host_id" {
+				continue
+			}
+			pk, ok := c.PrimaryKey()
+			keyed = ok && pk
+		}
+		if !keyed {
+			pending = append(pending, t)
+		}
+	}
+	return pending, nil
+}
+
+// migrateHostKeysPostgres alters tables in place inside one transaction (Postgres DDL is transactional).
+func migrateHostKeysPostgres(db *gorm.DB, pending []hostKeyedTable) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// The old FK points at docker_metrics(timestamp) and blocks dropping that primary key
+		if tx.Migrator().HasTable(dockerContainersTable) {
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s`, dockerContainersTable, legacyContainerFK)).Error; err != nil {
+				return fmt.Errorf("drop %s: %w", legacyContainerFK, err)
+			}
+		}
+
+		for _, t := range pending {
+			if t.name == dockerContainersTable {
+				if !tx.Migrator().HasColumn(t.name, "host_id") {
+					if err := tx.Exec(`ALTER TABLE docker_container_entities ADD COLUMN host_id bigint`).Error; err != nil {
+						return fmt.Errorf("add host_id to %s: %w", t.name, err)
+					}
+				}
+				if err := tx.Exec(`UPDATE docker_container_entities c SET host_id = (
+					SELECT MIN(d.host_id) FROM docker_metrics d WHERE d.timestamp = c.metric_timestamp
+				) WHERE c.host_id IS NULL`).Error; err != nil {
+					return fmt.Errorf("backfill %s.host_id: %w", t.name, err)
+				}
+				// Rows without a parent snapshot cannot satisfy the new FK
+				if err := tx.Exec(`DELETE FROM docker_container_entities c WHERE c.host_id IS NULL OR NOT EXISTS (
+					SELECT 1 FROM docker_metrics d WHERE d.host_id = c.host_id AND d.timestamp = c.metric_timestamp
+				)`).Error; err != nil {
+					return fmt.Errorf("remove orphaned %s rows: %w", t.name, err)
+				}
+			}
+			if err := tx.Exec(fmt.Sprintf(`UPDATE %s SET host_id = ? WHERE host_id IS NULL`, t.name), hostentities.LocalCollectorHostID).Error; err != nil {
+				return fmt.Errorf("backfill %s.host_id: %w", t.name, err)
+			}
+
+			var pkName string
+			if err := tx.Raw(`SELECT constraint_name FROM information_schema.table_constraints
+				WHERE table_schema = current_schema() AND table_name = ? AND constraint_type = 'PRIMARY KEY'`, t.name).
+				Scan(&pkName).Error; err != nil {
+				return fmt.Errorf("find primary key of %s: %w", t.name, err)
+			}
+			if pkName != "" {
+				if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %q`, t.name, pkName)).Error; err != nil {
+					return fmt.Errorf("drop primary key of %s: %w", t.name, err)
+				}
+			}
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN host_id SET NOT NULL`, t.name)).Error; err != nil {
+				return fmt.Errorf("set %s.host_id not null: %w", t.name, err)
+			}
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD PRIMARY KEY (%s)`, t.name, strings.Join(t.pk, ", "))).Error; err != nil {
+				return fmt.Errorf("add primary key to %s: %w", t.name, err)
+			}
+			// The single-column host_id index is covered by the new primary key
+			if err := tx.Exec(fmt.Sprintf(`DROP INDEX IF EXISTS idx_%s_host_id`, t.name)).Error; err != nil {
+				return fmt.Errorf("drop host_id index of %s: %w", t.name, err)
+			}
+		}
+		// AutoMigrate recreates the container FK on (host_id, metric_timestamp)
+		return nil
+	})
+}
+
+// migrateHostKeysSQLite rebuilds tables because SQLite cannot alter a primary key:
+// rename the old table, create the new one from the model, copy rows, drop the old one.
+func migrateHostKeysSQLite(db *gorm.DB, pending []hostKeyedTable) error {
+	// Renaming a parent table would otherwise rewrite or enforce child FKs mid-rebuild.
+	// foreign_keys cannot change inside a transaction, so both pragmas are set on the connection first.
+	if err := db.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+		return err
+	}
+	defer db.Exec("PRAGMA foreign_keys = ON")
+	if err := db.Exec("PRAGMA legacy_alter_table = ON").Error; err != nil {
+		return err
+	}
+	defer db.Exec("PRAGMA legacy_alter_table = OFF")
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		legacyCols := make(map[string][]string, len(pending))
+		for _, t := range pending {
+			cols, err := tx.Migrator().ColumnTypes(t.name)
+			if err != nil {
+				return fmt.Errorf("inspect %s: %w", t.name, err)
+			}
+			for _, c := range cols {
+				legacyCols[t.name] = append(legacyCols[t.name], c.Name())
+			}
+
+			// Index names are global in SQLite and would collide with the rebuilt table's indexes
+			var indexes []string
+			if err := tx.Raw(`SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL`, t.name).
+				Scan(&indexes).Error; err != nil {
+				return fmt.Errorf("list indexes of %s: %w", t.name, err)
+			}
+			for _, idx := range indexes {
+				if err := tx.Exec(fmt.Sprintf("DROP INDEX `%s`", idx)).Error; err != nil {
+					return fmt.Errorf("drop index %s: %w", idx, err)
+				}
+			}
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE `%s` RENAME TO `%s__legacy`", t.name, t.name)).Error; err != nil {
+				return fmt.Errorf("rename %s: %w", t.name, err)
+			}
+		}
+
+		for _, t := range pending {
+			if err := tx.Migrator().CreateTable(t.model); err != nil {
+				return fmt.Errorf("create %s: %w", t.name, err)
+			}
+
+			newCols, err := tx.Migrator().ColumnTypes(t.name)
+			if err != nil {
+				return fmt.Errorf("inspect %s: %w", t.name, err)
+			}
+			old := make(map[string]struct{}, len(legacyCols[t.name]))
+			for _, c := range legacyCols[t.name] {
+				old[c] = struct{}{}
+			}
+			var copyCols []string
+			for _, c := range newCols {
+				if _, ok := old[c.Name()]; ok && c.Name() != "host_id" {
+					copyCols = append(copyCols, "`"+c.Name()+"`")
+				}
+			}
+
+			selectCols := make([]string, len(copyCols))
+			for i, c := range copyCols {
+				selectCols[i] = "l." + c
+			}
+			stmt := fmt.Sprintf("INSERT INTO `%s` (`host_id`, %s) SELECT COALESCE(l.host_id, ?), %s FROM `%s__legacy` l",
+				t.name, strings.Join(copyCols, ", "), strings.Join(selectCols, ", "), t.name)
+			args := []any{hostentities.LocalCollectorHostID}
+			if t.name == dockerContainersTable {
+				// Like the Postgres path: host_id from the parent, rows without a parent are dropped
+				hostExpr := "(SELECT MIN(d.host_id) FROM docker_metrics d WHERE d.timestamp = l.metric_timestamp)"
+				if _, hadHost := old["host_id"]; hadHost {
+					hostExpr = "COALESCE(l.host_id, " + hostExpr + ")"
+				}
+				stmt = fmt.Sprintf("INSERT INTO `%s` (`host_id`, %s) SELECT %s, %s FROM `%s__legacy` l"+
+					" WHERE EXISTS (SELECT 1 FROM docker_metrics p WHERE p.host_id = %s AND p.timestamp = l.metric_timestamp)",
+					t.name, strings.Join(copyCols, ", "), hostExpr, strings.Join(selectCols, ", "), t.name, hostExpr)
+				args = nil
+			}
+			if err := tx.Exec(stmt, args...).Error; err != nil {
+				return fmt.Errorf("copy %s: %w", t.name, err)
+			}
+		}
+
+		// Drop children before parents
+		for i := len(pending) - 1; i >= 0; i-- {
+			if err := tx.Exec(fmt.Sprintf("DROP TABLE `%s__legacy`", pending[i].name)).Error; err != nil {
+				return fmt.Errorf("drop legacy %s: %w", pending[i].name, err)
+			}
+		}
+		return nil
+	})
+}
@@ -1,5 +1,5 @@
  // Package database provides database migration functionality.
- // This file contains migration logic for all database entities.
+ // This file contains the ordered, versioned migrations for all database entities.
 package database
 
 import (
@@ -11,6 +11,7 @@ import (
 	diskentities "system-stats/internal/modules/disk/infrastructure/entities"
 	dockerdomain "system-stats/internal/modules/docker/domain/repositories"
 	dockerentities "system-stats/internal/modules/docker/infrastructure/entities"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
 	hostentities "system-stats/internal/modules/hosts/infrastructure/entities"
 	inventities "system-stats/internal/modules/invitations/infrastructure/entities"
 	memoryentities "system-stats/internal/modules/memory/infrastructure/entities"
@@ -19,46 +20,124 @@ import (
 	userentities "system-stats/internal/modules/users/infrastructure/entities"
 )
 
- // Migrate performs automatic schema migration for all database entities.
- // This function creates all necessary tables and ensures proper foreign key relationships.
-func Migrate(db *gorm.DB) error {
-	// Auto-migrate all historical metric entities to create database tables
-	err := db.AutoMigrate(
-		&cpuentities.HistoricalCPUMetric{},
-		&memoryentities.HistoricalMemoryMetric{},
-		&diskentities.HistoricalDiskMetric{},
-		&networkentities.HistoricalNetworkMetric{},
-		&dockerdomain.HistoricalDockerMetric{},
-		&dockerentities.DockerContainerEntity{},
-		&hostentities.Host{},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to migrate historical metrics: %w", err)
-	}
-
-	// Migrate user entities separately to ensure proper foreign key relationships
-	err = db.AutoMigrate(&userentities.User{})
-	if err != nil {
-		return fmt.Errorf("failed to migrate users: %w", err)
-	}
-
-	err = db.AutoMigrate(&userentities.RefreshToken{})
-	if err != nil {
-		return fmt.Errorf("failed to migrate refresh tokens: %w", err)
+// Migrations returns every schema migration in version order. Append new entries with the
+// next version number; never edit or renumber a migration that has shipped.
+func Migrations() []Migration {
+	return []Migration{
+		{
+			// Databases created before versioning used timestamp-only keys; rebuild them before
+			// the baseline AutoMigrate inspects the tables. Intentionally irreversible: the legacy
+			// keys cannot hold rows of more than one host, so there is no down step.
+			Version:        1,
+			Name:           "host_scoped_metric_keys",
+			Up:             hostScopedKeysUp,
+			OwnTransaction: true,
+		},
+		{
+			// Schema as produced by AutoMigrate when versioning was introduced. Idempotent, so
+			// existing databases are adopted at this version without changes.
+			Version: 2,
+			Name:    "baseline_schema",
+			Up: Steps{AnyDialect: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(
+					&cpuentities.HistoricalCPUMetric{},
+					&memoryentities.HistoricalMemoryMetric{},
+					&diskentities.HistoricalDiskMetric{},
+					&diskentities.HistoricalDiskMountMetric{},
+					&diskentities.HistoricalDiskIOMetric{},
+					&networkentities.HistoricalNetworkMetric{},
+					&networkentities.HistoricalNetworkInterfaceMetric{},
+					&dockerdomain.HistoricalDockerMetric{},
+					&dockerentities.DockerContainerEntity{},
+					&hostentities.Host{},
+				); err != nil {
+					return fmt.Errorf("historical metrics: %w", err)
+				}
+				if err := tx.AutoMigrate(&userentities.User{}, &userentities.RefreshToken{}); err != nil {
+					return fmt.Errorf("users: %w", err)
+				}
+				if err := tx.AutoMigrate(&nodeentities.NodeJoinToken{}, &nodeentities.NodeCredential{}); err != nil {
+					return fmt.Errorf("node entities: %w", err)
+				}
+				return nil
+			}},
+			Down: Steps{AnyDialect: func(tx *gorm.DB) error {
+				// DropTable orders the models so children go before the tables they reference
+				return tx.Migrator().DropTable(
+					&cpuentities.HistoricalCPUMetric{},
+					&memoryentities.HistoricalMemoryMetric{},
+					&diskentities.HistoricalDiskMetric{},
+					&diskentities.HistoricalDiskMountMetric{},
+					&diskentities.HistoricalDiskIOMetric{},
+					&networkentities.HistoricalNetworkMetric{},
+					&networkentities.HistoricalNetworkInterfaceMetric{},
+					&dockerdomain.HistoricalDockerMetric{},
+					&dockerentities.DockerContainerEntity{},
+					&hostentities.Host{},
+					&userentities.User{},
+					&userentities.RefreshToken{},
+					&nodeentities.NodeJoinToken{},
+					&nodeentities.NodeCredential{},
+				)
+			}},
+		},
+		{
+			// Older invitation rows have NULL email; backfill before the NOT NULL column is enforced
+			Version: 3,
+			Name:    "user_invitations_email_not_null",
+			Up: Steps{AnyDialect: func(tx *gorm.DB) error {
+				if tx.Migrator().HasTable(&inventities.UserInvitation{}) {
+					if err := tx.Exec("UPDATE user_invitations SET email = '' WHERE email IS NULL").Error; err != nil {
+						return err
+					}
+				}
+				return tx.AutoMigrate(&inventities.UserInvitation{})
+			}},
+			// Backfilled emails stay empty strings; only the constraint is dropped
+			Down: Steps{AnyDialect: func(tx *gorm.DB) error {
+				if err := tx.Migrator().AlterColumn(&nullableEmailInvitation{}, "Email"); err != nil {
+					return err
+				}
+				// The SQLite table rebuild behind AlterColumn does not carry the indexes over
+				for _, idx := range []string{"idx_user_invitations_token", "idx_user_invitations_deleted_at"} {
+					if !tx.Migrator().HasIndex(&nullableEmailInvitation{}, idx) {
+						if err := tx.Migrator().CreateIndex(&nullableEmailInvitation{}, idx); err != nil {
+							return fmt.Errorf("recreate %s: %w", idx, err)
+						}
+					}
+				}
+				return nil
+			}},
+		},
+		{
+			// Versions 4-6 belong to the exec, custom and processes modules
+			Version: 7,
+			Name:    "host_events_table",
+			Up: Steps{AnyDialect: func(tx *gorm.DB) error {
+				return tx.Migrator().CreateTable(&evententities.HostEvent{})
+			}},
+			Down: Steps{AnyDialect: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&evententities.HostEvent{})
+			}},
+		},
 	}
+}
 
-	// Fix existing invitations with NULL email before adding NOT NULL constraint
-	_ = db.Exec("UPDATE user_invitations SET email = '' WHERE email IS NULL")
+// nullableEmailInvitation is user_invitations as it was before version 3 made email NOT NULL.
+type nullableEmailInvitation struct {
+	inventities.UserInvitation
+	Email *string `gorm:"size:255"`
+}
 
-	err = db.AutoMigrate(&inventities.UserInvitation{})
-	if err != nil {
-		return fmt.Errorf("failed to migrate user invitations: %w", err)
-	}
+// TableName returns the table name for GORM operations.
+func (nullableEmailInvitation) TableName() string { return "user_invitations" }
 
-	err = db.AutoMigrate(&nodeentities.NodeJoinToken{}, &nodeentities.NodeCredential{})
-	if err != nil {
-		return fmt.Errorf("failed to migrate node entities: %w", err)
+ // Migrate applies all pending migrations at startup: the core list plus any module migrations
+ // (see registry.Registry.Migrations). It refuses to run when the database was migrated by a
+ // newer binary (ErrSchemaNewer).
+func Migrate(db *gorm.DB, migrations []Migration) error {
+	if _, err := NewMigrator(db, migrations).Up(0, false); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
 	}
-
 	return nil
 }
@@ -0,0 +1,265 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AnyDialect keys a Steps entry that applies when no dialect-specific step is registered.
+const AnyDialect = "*"
+
+// ErrSchemaNewer is returned when the database was migrated by a newer binary.
+var ErrSchemaNewer = errors.New("database schema is newer than this binary supports")
+
+// ErrIrreversible is returned when rolling back a migration that has no down step for the dialect.
+var ErrIrreversible = errors.New("migration has no down step")
+
+// Step performs one schema change against tx.
+type Step func(tx *gorm.DB) error
+
+// Steps maps a GORM dialect name ("sqlite", "postgres") or AnyDialect to a Step.
+type Steps map[string]Step
+
+// For returns the step registered for dialect, falling back to AnyDialect.
+func (s Steps) For(dialect string) (Step, bool) {
+	if step, ok := s[dialect]; ok && step != nil {
+		return step, true
+	}
+	step, ok := s[AnyDialect]
+	return step, ok && step != nil
+}
+
+// Migration is one ordered schema change. Versions must be unique and increasing.
+type Migration struct {
+	Version int
+	Name    string
+	Up      Steps
+	// Down is optional; a missing step makes the migration irreversible for that dialect
+	Down Steps
+	// OwnTransaction marks steps that manage their own transaction (e.g. SQLite table
+	// rebuilds that must toggle PRAGMAs outside one); the version row is recorded afterwards.
+	OwnTransaction bool
+}
+
+// SchemaMigration records an applied migration in the schema_migrations table.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (SchemaMigration) TableName() string { return "schema_migrations" }
+
+// MigrationStatus describes one known migration and whether it has been applied.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator applies an ordered list of migrations and tracks them in schema_migrations.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the given migrations, sorted by version.
+func NewMigrator(db *gorm.DB, migrations []Migration) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// LatestVersion returns the highest version this binary knows about.
+func (m *Migrator) LatestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// CurrentVersion returns the highest applied version, or 0 for a database without schema_migrations.
+func (m *Migrator) CurrentVersion() (int, error) {
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return 0, nil
+	}
+	var version *int
+	if err := m.db.Model(&SchemaMigration{}).Select("MAX(version)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	if version == nil {
+		return 0, nil
+	}
+	return *version, nil
+}
+
+// CheckNotNewer refuses databases migrated past the latest version known to this binary,
+// so an older release cannot write into a schema it does not understand.
+func (m *Migrator) CheckNotNewer() error {
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if latest := m.LatestVersion(); current > latest {
+		return fmt.Errorf("%w: database is at version %d, binary supports up to %d", ErrSchemaNewer, current, latest)
+	}
+	return nil
+}
+
+// Status lists every known migration with its applied state.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if rec, ok := applied[mig.Version]; ok {
+			at := rec.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// Pending returns migrations not yet applied, in version order.
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies pending migrations up to and including target (0 means all). With dryRun the
+// pending list is returned without touching the database. The returned slice holds the
+// migrations that were (or would be) applied.
+func (m *Migrator) Up(target int, dryRun bool) ([]Migration, error) {
+	if err := m.CheckNotNewer(); err != nil {
+		return nil, err
+	}
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := m.db.Dialector.Name()
+	var run []Migration
+	for _, mig := range pending {
+		if target > 0 && mig.Version > target {
+			break
+		}
+		if _, ok := mig.Up.For(dialect); !ok {
+			return run, fmt.Errorf("migration %d %s has no up step for %s", mig.Version, mig.Name, dialect)
+		}
+		run = append(run, mig)
+	}
+	if dryRun {
+		return run, nil
+	}
+
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+	for i, mig := range run {
+		step, _ := mig.Up.For(dialect)
+		if err := m.apply(mig, step, func(tx *gorm.DB) error {
+			return tx.Create(&SchemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now().UTC()}).Error
+		}); err != nil {
+			return run[:i], fmt.Errorf("apply migration %d %s: %w", mig.Version, mig.Name, err)
+		}
+	}
+	return run, nil
+}
+
+// Down rolls back the last n applied migrations (newest first). With dryRun the migrations
+// that would be rolled back are returned without touching the database.
+func (m *Migrator) Down(n int, dryRun bool) ([]Migration, error) {
+	if err := m.CheckNotNewer(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := m.db.Dialector.Name()
+	var run []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(run) < n; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if _, ok := mig.Down.For(dialect); !ok {
+			return run, fmt.Errorf("%w: %d %s on %s", ErrIrreversible, mig.Version, mig.Name, dialect)
+		}
+		run = append(run, mig)
+	}
+	if dryRun {
+		return run, nil
+	}
+
+	for i, mig := range run {
+		step, _ := mig.Down.For(dialect)
+		if err := m.apply(mig, step, func(tx *gorm.DB) error {
+			return tx.Delete(&SchemaMigration{}, "version = ?", mig.Version).Error
+		}); err != nil {
+			return run[:i], fmt.Errorf("roll back migration %d %s: %w", mig.Version, mig.Name, err)
+		}
+	}
+	return run, nil
+}
+
+// apply runs step and record atomically, or sequentially for OwnTransaction migrations.
+func (m *Migrator) apply(mig Migration, step Step, record func(tx *gorm.DB) error) error {
+	if mig.OwnTransaction {
+		if err := step(m.db); err != nil {
+			return err
+		}
+		return record(m.db)
+	}
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := step(tx); err != nil {
+			return err
+		}
+		return record(tx)
+	})
+}
+
+func (m *Migrator) ensureTable() error {
+	if err := m.db.Migrator().AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) applied() (map[int]SchemaMigration, error) {
+	out := make(map[int]SchemaMigration)
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return out, nil
+	}
+	var rows []SchemaMigration
+	if err := m.db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	for _, r := range rows {
+		out[r.Version] = r
+	}
+	return out, nil
+}
@@ -8,18 +8,16 @@ import (
 
 	"gorm.io/gorm"
 
+	"system-stats/internal/app/collectorhealth"
 	"system-stats/internal/app/config"
 	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/app/snapshot"
 	"system-stats/internal/app/stream"
 
-	cpuservice "system-stats/internal/modules/cpu/application"
-	cpurepos "system-stats/internal/modules/cpu/infrastructure/repositories"
-	diskservice "system-stats/internal/modules/disk/application"
-	diskrepos "system-stats/internal/modules/disk/infrastructure/repositories"
-	dockerservice "system-stats/internal/modules/docker/application"
-	dockerdomain "system-stats/internal/modules/docker/domain/repositories"
-	dockercollectors "system-stats/internal/modules/docker/infrastructure/collectors"
-	dockerrepos "system-stats/internal/modules/docker/infrastructure/repositories"
+	collectorsservice "system-stats/internal/modules/collectors/application"
+	eventsservice "system-stats/internal/modules/events/application"
+	eventrepos "system-stats/internal/modules/events/infrastructure/repositories"
 	healthservice "system-stats/internal/modules/health/application"
 	historyapp "system-stats/internal/modules/history_metrics/application"
 	historycore "system-stats/internal/modules/history_metrics/core"
@@ -29,11 +27,6 @@ import (
 	nodeservice "system-stats/internal/modules/nodes/application"
 	noderepos "system-stats/internal/modules/nodes/infrastructure/repositories"
 	hostrepos "system-stats/internal/modules/hosts/infrastructure/repositories"
-	memoryservice "system-stats/internal/modules/memory/application"
-	memoryrepos "system-stats/internal/modules/memory/infrastructure/repositories"
-	networkservice "system-stats/internal/modules/network/application"
-	networkrepos "system-stats/internal/modules/network/infrastructure/repositories"
-	sensorsservice "system-stats/internal/modules/sensors/application"
 	systemsrv "system-stats/internal/modules/system/application"
 	userapp "system-stats/internal/modules/users/application"
 	userrepos "system-stats/internal/modules/users/infrastructure/repositories"
@@ -49,26 +42,21 @@ type Container struct {
 	logger *log.Logger
 	db     *gorm.DB
 
-	// repositories for each metric type
-	cpuRepository     cpurepos.CPURepository
-	memoryRepository  memoryrepos.MemoryRepository
-	diskRepository    diskrepos.DiskRepository
-	networkRepository networkrepos.NetworkRepository
-	dockerRepository  dockerdomain.DockerRepository
-	hostRepository    hostrepos.HostRepository
+	// host repository; metric modules own their repositories (see modules)
+	hostRepository hostrepos.HostRepository
 
 	// user repositories
 	userRepository         userrepos.UserRepository
 	refreshTokenRepository userrepos.RefreshTokenRepository
 
-	// individual services for each metric type
-	cpuService     cpuservice.Service
-	memoryService  memoryservice.Service
-	diskService    diskservice.Service
-	networkService networkservice.Service
-	dockerService  dockerservice.Service
-	hostService    hostservice.Service
-	healthService  healthservice.Service
+	// core services
+	hostService   hostservice.Service
+	healthService healthservice.Service
+	eventsService eventsservice.Service
+
+	// registry of metric modules and the enabled ones built from it
+	registry *registry.Registry
+	modules  []registry.Built
 
 	// user services
 	userService  userapp.UserService
@@ -89,20 +77,30 @@ type Container struct {
 	// historicalMetricsService manages historical metrics collection and storage
 	historicalMetricsService historycore.HistoricalMetricsService
 
-	// sensors service
-	sensorsService sensorsservice.Service
-
 	// broker for SSE real-time metrics streaming
 	broker *stream.Broker
+
+	// snapshots holds the latest collection cycle shared by SSE, push, Prometheus and /metrics/current
+	snapshots *snapshot.Store
+
+	// collectorHealth tracks local collector runs; remoteCollectorHealth keeps what agents pushed
+	collectorHealth       *collectorhealth.Tracker
+	remoteCollectorHealth *collectorhealth.RemoteStore
+	collectorsService     collectorsservice.Service
 }
 
  // NewContainer creates a new dependency injection container with all application dependencies.
  // This constructor initializes the database, creates all repositories, services, collectors,
  // cache instances, and command/query handlers in the correct dependency order.
-func NewContainer(logger *log.Logger, dbConfig config.DatabaseConfig, jwtSecret, refreshSecret string, startTime time.Time) (*Container, error) {
+func NewContainer(logger *log.Logger, dbConfig config.DatabaseConfig, collection config.CollectionConfig, modules *registry.Registry, jwtSecret, refreshSecret string, startTime time.Time) (*Container, error) {
 	container := &Container{
-		logger: logger,
-		broker: stream.NewBroker(),
+		logger:    logger,
+		registry:  modules,
+		broker:    stream.NewBroker(),
+		snapshots: snapshot.NewStore(),
+
+		collectorHealth:       collectorhealth.NewTracker(),
+		remoteCollectorHealth: collectorhealth.NewRemoteStore(),
 	}
 
 	// Initialize GORM database connection
@@ -111,20 +109,15 @@ func NewContainer(logger *log.Logger, dbConfig config.DatabaseConfig, jwtSecret,
 		return nil, err
 	}
 
-	// Perform database migrations
-	if err := database.Migrate(db); err != nil {
+	// Perform database migrations (core and every registered module)
+	if err := database.Migrate(db, modules.Migrations()); err != nil {
 		return nil, err
 	}
 
 	container.db = db
 
-	// Create repositories for each module
-	container.cpuRepository = cpurepos.NewCPURepository(db)
-	container.memoryRepository = memoryrepos.NewMemoryRepository(db)
-	container.diskRepository = diskrepos.NewDiskRepository(db)
-	container.networkRepository = networkrepos.NewNetworkRepository(db)
-	container.dockerRepository = dockerrepos.NewDockerRepository(db)
-	container.hostRepository = hostrepos.NewHostRepository(db)
+	// Create core repositories
+	container.hostRepository = hostrepos.NewHostRepository(db, modules.HostTables())
 	container.nodeJoinTokenRepo = noderepos.NewNodeJoinTokenRepository(db)
 	container.nodeCredRepo = noderepos.NewNodeCredentialRepository(db)
 
@@ -132,15 +125,30 @@ func NewContainer(logger *log.Logger, dbConfig config.DatabaseConfig, jwtSecret,
 	container.userRepository = userrepos.NewUserRepository(db)
 	container.refreshTokenRepository = userrepos.NewRefreshTokenRepository(db)
 
-	// Create individual services for each metric type
-	container.cpuService = cpuservice.NewService(container.logger, container.cpuRepository)
-	container.memoryService = memoryservice.NewService(container.logger, container.memoryRepository)
-	container.diskService = diskservice.NewService(container.logger, container.diskRepository)
-	container.networkService = networkservice.NewService(container.logger, container.networkRepository)
-	container.dockerService = dockerservice.NewService(container.logger, dockercollectors.NewDockerMetricsCollector(container.logger), container.dockerRepository)
+	// Create core services
 	container.hostService = hostservice.NewService(container.logger, container.hostRepository, container.nodeCredRepo)
-	container.healthService = healthservice.NewService(container.logger, container.hostRepository, container.nodeCredRepo, startTime)
-	container.sensorsService = sensorsservice.NewService(container.logger)
+	container.eventsService = eventsservice.NewService(container.logger, eventrepos.NewHostEventRepository(db))
+	container.healthService = healthservice.NewService(container.logger, container.hostRepository, container.nodeCredRepo, container.collectorHealth, container.eventsService, startTime)
+
+	// Build the enabled metric modules
+	container.modules, err = modules.Build(registry.Deps{Logger: logger, DB: db, Hosts: container.hostService, Events: container.eventsService})
+	if err != nil {
+		return nil, err
+	}
+	var collectors []historycore.ModuleCollector
+	pushReceivers := make(map[string]nodeservice.PushReceiver)
+	var pushClocks []nodeservice.PushClockReceiver
+	for _, m := range container.modules {
+		if m.Collector != nil {
+			collectors = append(collectors, *m.Collector)
+		}
+		if m.ReceivePush != nil {
+			pushReceivers[m.Name] = m.ReceivePush
+		}
+		if m.ReceivePushClock != nil {
+			pushClocks = append(pushClocks, m.ReceivePushClock)
+		}
+	}
 
 	// Create user services (using JWT secrets from configuration)
 	container.tokenService = userapp.NewTokenService(
@@ -152,32 +160,22 @@ func NewContainer(logger *log.Logger, dbConfig config.DatabaseConfig, jwtSecret,
 	)
 	container.invRepository = invrepos.NewInvitationRepository(db)
 	container.invService = invservice.NewService(logger, container.invRepository)
-	container.nodeService = nodeservice.NewService(logger, container.nodeJoinTokenRepo, container.nodeCredRepo, container.hostRepository)
+	container.nodeService = nodeservice.NewService(logger, container.nodeJoinTokenRepo, container.nodeCredRepo, container.hostRepository, container.remoteCollectorHealth, pushReceivers, pushClocks)
 	container.userService = userapp.NewUserService(container.userRepository, container.tokenService, container.invService)
 
-	// Create system service that aggregates all metrics
-	container.systemService = systemsrv.NewService(
-		container.logger,
-		container.cpuService,
-		container.memoryService,
-		container.diskService,
-		container.networkService,
-		container.dockerService,
-	)
+	// Create system service that aggregates the module collectors
+	container.systemService = systemsrv.NewService(container.logger, container.snapshots, collectors)
 
-	// Create historical metrics service
-	metricsCollector := historyapp.NewMetricsCollector(
-		container.cpuService,
-		container.memoryService,
-		container.diskService,
-		container.networkService,
-		container.dockerService,
-	)
+	// Create historical metrics service; every module collector runs on its own schedule
 	container.historicalMetricsService = historyapp.NewHistoricalMetricsService(
 		container.logger,
-		metricsCollector,
+		container.systemService.Collectors(),
+		collection,
+		container.snapshots,
+		container.collectorHealth,
 		container.hostService,
 	)
+	container.collectorsService = collectorsservice.NewService(container.collectorHealth, container.remoteCollectorHealth)
 
 	return container, nil
 }
@@ -189,34 +187,19 @@ func (c *Container) GetLogger() *log.Logger {
 	return c.logger
 }
 
- // GetCPUService returns the CPU metrics service instance.
-func (c *Container) GetCPUService() cpuservice.Service {
-	return c.cpuService
-}
-
- // GetMemoryService returns the memory metrics service instance.
-func (c *Container) GetMemoryService() memoryservice.Service {
-	return c.memoryService
-}
-
- // GetDiskService returns the disk metrics service instance.
-func (c *Container) GetDiskService() diskservice.Service {
-	return c.diskService
-}
-
- // GetNetworkService returns the network metrics service instance.
-func (c *Container) GetNetworkService() networkservice.Service {
-	return c.networkService
+ // GetHostService returns the host service instance.
+func (c *Container) GetHostService() hostservice.Service {
+	return c.hostService
 }
 
- // GetDockerService returns the docker metrics service instance.
-func (c *Container) GetDockerService() dockerservice.Service {
-	return c.dockerService
+// GetModules returns the enabled metric modules in registration order.
+func (c *Container) GetModules() []registry.Built {
+	return c.modules
 }
 
- // GetHostService returns the host service instance.
-func (c *Container) GetHostService() hostservice.Service {
-	return c.hostService
+// GetRegistry returns the metric module registry.
+func (c *Container) GetRegistry() *registry.Registry {
+	return c.registry
 }
 
  // GetHealthService returns the health service instance.
@@ -224,16 +207,16 @@ func (c *Container) GetHealthService() healthservice.Service {
 	return c.healthService
 }
 
+// GetEventsService returns the host events service instance.
+func (c *Container) GetEventsService() eventsservice.Service {
+	return c.eventsService
+}
+
  // GetSystemService returns the system metrics service instance.
 func (c *Container) GetSystemService() systemsrv.Service {
 	return c.systemService
 }
 
-// GetSensorsService returns the sensors service instance.
-func (c *Container) GetSensorsService() sensorsservice.Service {
-	return c.sensorsService
-}
-
  // GetUserService returns the user service instance.
 func (c *Container) GetUserService() userapp.UserService {
 	return c.userService
@@ -268,3 +251,18 @@ func (c *Container) GetDB() *gorm.DB {
 func (c *Container) GetBroker() *stream.Broker {
 	return c.broker
 }
+
+// GetCollectorHealth returns the tracker of local collector runs.
+func (c *Container) GetCollectorHealth() *collectorhealth.Tracker {
+	return c.collectorHealth
+}
+
+// GetCollectorsService returns the collector health service instance.
+func (c *Container) GetCollectorsService() collectorsservice.Service {
+	return c.collectorsService
+}
+
+// GetSnapshotStore returns the store holding the latest collection snapshot.
+func (c *Container) GetSnapshotStore() *snapshot.Store {
+	return c.snapshots
+}
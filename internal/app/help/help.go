@@ -24,6 +24,8 @@ Configuration:
 Usage:
   system-stats              # Start server with default configuration
   system-stats -help        # Show this help message
+  system-stats migrate status|up|down [-dry-run]
+                            # Inspect or apply schema migrations (see: system-stats migrate help)
 
 Environment Variables:
 
@@ -38,9 +40,62 @@ Environment Variables:
                             Example: DEBUG=true
 
     PROMETHEUS_ENABLED      Expose Prometheus /metrics endpoint: "true", "1", "false", or "0" (default: "false")
-                            Exports HTTP request metrics, Go runtime metrics, and system metrics (CPU, RAM, disk, network).
+                            Exports HTTP request metrics, Go runtime metrics, system metrics (CPU, RAM, disk, network),
+                            and collector health (collector_duration_seconds, collector_errors_total).
                             Example: PROMETHEUS_ENABLED=true
 
+  Collection Schedule:
+    COLLECT_INTERVAL        How often each module collects: Go duration or seconds, at least 1s (default: "5s")
+                            Example: COLLECT_INTERVAL=10s
+
+    COLLECT_TIMEOUT         Maximum duration of one collector run (default: "30s")
+                            Example: COLLECT_TIMEOUT=20s
+
+    COLLECT_INTERVAL_<MODULE>, COLLECT_TIMEOUT_<MODULE>
+                            Per-module overrides for cpu, memory, disk, network, docker
+                            Example: COLLECT_INTERVAL_DISK=60s COLLECT_INTERVAL_DOCKER=15s
+
+  Metric Modules:
+    MODULES_DISABLED        Comma-separated modules not to collect or serve: cpu, memory, disk, network, pressure, sockets, ports, storage, smart, docker, sensors, timesync, logins, kernellog, exec, custom, processes, watchlist
+                            Example: MODULES_DISABLED=docker (hosts without Docker)
+
+    EXEC_CHECKS_FILE        JSON file of custom check commands run by the exec module (format: json, prometheus or nagios)
+                            Example: {"checks":[{"name":"queue_depth","command":["/usr/local/bin/queue-depth"],"format":"json","interval":"1m","timeout":"5s"}]}
+
+    PROCESS_WATCH_FILE      JSON file of processes the watchlist module checks (match by process name, cmdline regex or pidfile)
+                            Example: {"watches":[{"name":"nginx","process":"nginx"},{"name":"api","pidfile":"/run/api.pid"}]}
+
+    DISK_MOUNT_TIMEOUT      How long a mount may take to answer statfs before it is reported stale (default: 2s)
+                            Example: DISK_MOUNT_TIMEOUT=5s
+
+    PROCESSES_TOP_N         Processes per top CPU / memory list (1-100, default: 10)
+                            Example: PROCESSES_TOP_N=20
+
+    PROCESSES_RETENTION     How long process history is kept (default: 24h, capped by METRICS_RETENTION_DAYS)
+                            Example: PROCESSES_RETENTION=6h
+
+    SMART_DEVICES           Comma-separated disks for smartctl, optionally with a -d type (default: smartctl --scan)
+                            Example: SMART_DEVICES=/dev/sda,/dev/sdb:sat
+
+    SMART_JSON_DIR          Read smartctl --json -a output files (*.json) instead of running smartctl
+                            Example: SMART_JSON_DIR=/host/var/lib/smart
+
+    SMART_INTERVAL          Minimum time between smartctl runs (default: 30m)
+                            Example: SMART_INTERVAL=1h
+
+    TIMESYNC_SKEW_THRESHOLD Clock skew of an agent push beyond which main flags the host (default: 2s)
+                            Example: TIMESYNC_SKEW_THRESHOLD=500ms
+
+    LOGINS_AUTH_LOGS        Comma-separated auth logs (host paths) to count failed SSH / sudo logins in, or none
+                            (default: /var/log/auth.log,/var/log/secure)
+                            Example: LOGINS_AUTH_LOGS=/var/log/auth.log
+
+    KERNELLOG_FILE          Kernel log file (host path) to follow instead of /dev/kmsg
+                            Example: KERNELLOG_FILE=/var/log/kern.log
+
+    KERNELLOG_QUIET         Time without messages of a category before its problem is resolved (default: 1h)
+                            Example: KERNELLOG_QUIET=30m
+
   Database Configuration:
     DB_TYPE                 Database type: "sqlite" (default: "sqlite")
                             Example: DB_TYPE=sqlite
@@ -58,8 +113,8 @@ Environment Variables:
 
   Docker / host metrics (optional, when bind-mounting the host at /host):
     HOST_PROC               Path to host /proc (default /proc). Example: /host/proc
-    HOST_SYS                Path to host /sys (default /sys). Example: /host/sys
-    HOST_ETC                Path to host /etc for hostname file. Example: /host/etc
+    HOST_SYS                Path to host /sys, also for cgroup v2 (default /sys). Example: /host/sys
+    HOST_ETC                Path to host /etc for hostname and passwd files. Example: /host/etc
     HOST_ROOT               Host root bind-mount for disk totals. Example: /host
     NODE_STATS_HOSTNAME     Override UI/API hostname (container ID otherwise)
     NODE_STATS_IPV4         Override host IPv4 on the machine card (Docker bridge IP otherwise)
@@ -96,11 +151,30 @@ API Endpoints:
   Metrics (Protected):
     GET /api/v1/metrics/current    - Current system metrics for dashboard
     GET /api/v1/cpu                - CPU statistics (JSON)
-    GET /api/v1/memory             - Memory statistics (JSON)
+    GET /api/v1/memory             - Memory statistics incl. slab, hugepages, vmstat rates, OOM kills (JSON)
+    GET /api/v1/pressure           - Pressure stall information and cgroup v2 statistics (JSON, ?cgroup=)
+    GET /api/v1/sockets            - TCP connection states, socket memory, TCP error rates, conntrack usage (JSON)
+    GET /api/v1/ports              - Listening ports with owning process and publishing containers (JSON)
+    GET /api/v1/storage            - Software RAID, ZFS pool and btrfs health (JSON)
+    GET /api/v1/smart              - SMART disk health with block device I/O (JSON)
     GET /api/v1/disk               - Disk statistics (JSON)
-    GET /api/v1/network            - Network statistics (JSON)
+    GET /api/v1/network            - Network statistics with link speed, state and utilisation (JSON)
     GET /api/v1/docker             - Docker containers statistics (JSON)
-    GET /api/v1/sensors            - Temperature sensors readings (JSON)
+    GET /api/v1/sensors            - Temperatures, fans, voltages, power and RAPL readings with history (JSON, ?sensor=&type=)
+    GET /api/v1/timesync           - Clock sync state (kernel, chrony, timesyncd) and agent clock skew (JSON)
+    GET /api/v1/logins             - Login sessions, wtmp events and failed SSH / sudo logins (JSON, ?user=&service=)
+    GET /api/v1/kernellog          - Kernel log OOM, I/O, filesystem, hardware, segfault and kernel fault counts (JSON, ?category=)
+    GET /api/v1/exec               - Custom check results (JSON, ?check=)
+    GET /api/v1/exec/gauges        - Custom check gauge history (JSON, ?check=&name=)
+    GET /api/v1/custom-metrics     - Pushed custom metric samples (JSON, ?name=&label=key=value)
+    GET /api/v1/custom-metrics/series - Custom metric series and newest values (JSON)
+    POST /api/v1/custom-metrics/ingest - Write custom metric samples (Bearer custom metrics token)
+    GET|POST /api/v1/custom-metrics/tokens, DELETE /api/v1/custom-metrics/tokens/:id - Ingest tokens (admin only)
+    GET /api/v1/processes          - Top CPU and memory processes (JSON)
+    GET /api/v1/processes/history  - Process history (JSON, ?pid=&name=)
+    GET /api/v1/watchlist          - Watched process states (JSON, ?watch=)
+    GET /api/v1/events             - Host events and open problems (JSON, ?hours=24&source=&subject=)
+    GET /api/v1/collectors/status  - Per-module collector health (JSON)
     GET /api/v1/hosts              - All registered hosts (JSON)
     GET /api/v1/hosts/current      - Current host information (JSON)
     POST /api/v1/hosts/register    - Register/update current host
@@ -0,0 +1,30 @@
+// Package hostfs resolves paths of the host's /proc, /sys, /etc and root file system. In
+// containerised deployments the host directories are bind-mounted and named by HOST_PROC,
+// HOST_SYS, HOST_ETC and HOST_ROOT (the same variables gopsutil reads).
+package hostfs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func resolve(env, fallback string, parts []string) string {
+	base := strings.TrimSpace(os.Getenv(env))
+	if base == "" {
+		base = fallback
+	}
+	return filepath.Join(append([]string{base}, parts...)...)
+}
+
+// Proc joins parts to HOST_PROC (default /proc).
+func Proc(parts ...string) string { return resolve("HOST_PROC", "/proc", parts) }
+
+// Sys joins parts to HOST_SYS (default /sys).
+func Sys(parts ...string) string { return resolve("HOST_SYS", "/sys", parts) }
+
+// Etc joins parts to HOST_ETC (default /etc).
+func Etc(parts ...string) string { return resolve("HOST_ETC", "/etc", parts) }
+
+// Root joins parts to HOST_ROOT (default /), for host files outside /proc, /sys and /etc.
+func Root(parts ...string) string { return resolve("HOST_ROOT", "/", parts) }
@@ -7,12 +7,12 @@ func EmptyCPUPayload() map[string]any {
 
 // EmptyMemoryPayload returns an empty memory metrics response.
 func EmptyMemoryPayload() map[string]any {
-	return map[string]any{"latest": nil, "history": []any{}}
+	return map[string]any{"latest": nil, "history": []any{}, "extended_history": []any{}}
 }
 
 // EmptyDiskPayload returns an empty disk metrics response.
 func EmptyDiskPayload() map[string]any {
-	return map[string]any{"latest": nil, "history": []any{}}
+	return map[string]any{"latest": nil, "history": []any{}, "mount_history": []any{}, "io_history": []any{}, "nfs_history": []any{}}
 }
 
 // EmptyNetworkPayload returns an empty network metrics response.
@@ -20,14 +20,24 @@ func EmptyNetworkPayload() map[string]any {
 	return map[string]any{"latest": nil, "history": []any{}}
 }
 
+// EmptyNetworkInterfacesPayload returns an empty per-interface history response.
+func EmptyNetworkInterfacesPayload() map[string]any {
+	return map[string]any{"history": []any{}}
+}
+
 // EmptyDockerPayload returns an empty Docker metrics response.
 func EmptyDockerPayload() map[string]any {
 	return map[string]any{"latest": nil, "history": []any{}, "docker_available": false}
 }
 
+// EmptyDockerContainerHistoryPayload returns an empty container history response.
+func EmptyDockerContainerHistoryPayload() map[string]any {
+	return map[string]any{"series": []any{}}
+}
+
 // EmptySensorsPayload returns sensors for a host we cannot read locally (JSON null — frontend distinguishes from empty Linux readings).
 func EmptySensorsPayload() map[string]any {
-	return map[string]any{"sensors": nil}
+	return map[string]any{"sensors": nil, "latest": []any{}, "history": []any{}}
 }
 
 // EmptyCurrentMetricsPayload matches /metrics/current shape when no live snapshot exists for the host.
@@ -39,5 +49,86 @@ func EmptyCurrentMetricsPayload() map[string]any {
 		"disk":      nil,
 		"network":   nil,
 		"docker":    nil,
+		"pressure":  nil,
 	}
 }
+
+// EmptyCollectorsStatusPayload returns an empty collector health response for an unknown host.
+func EmptyCollectorsStatusPayload() map[string]any {
+	return map[string]any{"host_id": nil, "healthy": nil, "reported_at": nil, "collectors": []any{}}
+}
+
+// EmptyExecPayload returns an empty exec checks response.
+func EmptyExecPayload() map[string]any {
+	return map[string]any{"latest": []any{}, "history": []any{}}
+}
+
+// EmptyExecGaugesPayload returns an empty exec gauge history response.
+func EmptyExecGaugesPayload() map[string]any {
+	return map[string]any{"history": []any{}}
+}
+
+// EmptyCustomMetricsPayload returns an empty custom metrics series response.
+func EmptyCustomMetricsPayload() map[string]any {
+	return map[string]any{"series": []any{}}
+}
+
+// EmptyProcessesPayload returns an empty top processes response.
+func EmptyProcessesPayload() map[string]any {
+	return map[string]any{"latest": nil}
+}
+
+// EmptyProcessHistoryPayload returns an empty process history response.
+func EmptyProcessHistoryPayload() map[string]any {
+	return map[string]any{"history": []any{}}
+}
+
+// EmptyEventsPayload returns an empty host events response.
+func EmptyEventsPayload() map[string]any {
+	return map[string]any{"events": []any{}, "problems": []any{}}
+}
+
+// EmptyWatchlistPayload returns an empty process watchlist response.
+func EmptyWatchlistPayload() map[string]any {
+	return map[string]any{"latest": []any{}, "history": []any{}}
+}
+
+// EmptyPressurePayload returns an empty PSI and cgroup response.
+func EmptyPressurePayload() map[string]any {
+	return map[string]any{"latest": nil, "history": []any{}, "cgroup_history": []any{}}
+}
+
+// EmptySocketsPayload returns an empty socket and TCP statistics response.
+func EmptySocketsPayload() map[string]any {
+	return map[string]any{"latest": nil, "history": []any{}}
+}
+
+// EmptyPortsPayload returns an empty listening ports response.
+func EmptyPortsPayload() map[string]any {
+	return map[string]any{"ports": []any{}, "unmatched_container_ports": []any{}}
+}
+
+// EmptyStoragePayload returns an empty storage health response.
+func EmptyStoragePayload() map[string]any {
+	return map[string]any{"latest": []any{}, "history": []any{}}
+}
+
+// EmptySmartPayload returns an empty SMART disk health response.
+func EmptySmartPayload() map[string]any {
+	return map[string]any{"latest": []any{}, "history": []any{}}
+}
+
+// EmptyTimeSyncPayload returns an empty time sync and clock skew response.
+func EmptyTimeSyncPayload() map[string]any {
+	return map[string]any{"latest": nil, "history": []any{}, "skew": nil, "skew_history": []any{}, "skew_threshold_seconds": nil}
+}
+
+// EmptyLoginsPayload returns an empty login sessions response.
+func EmptyLoginsPayload() map[string]any {
+	return map[string]any{"sessions": []any{}, "events": []any{}, "failures": []any{}}
+}
+
+// EmptyKernelLogPayload returns an empty kernel log response.
+func EmptyKernelLogPayload() map[string]any {
+	return map[string]any{"samples": []any{}, "counts": []any{}}
+}
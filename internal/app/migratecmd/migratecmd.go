@@ -0,0 +1,136 @@
+// Package migratecmd implements the "migrate" subcommand for inspecting and applying schema migrations.
+package migratecmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"system-stats/internal/app/config"
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/modules"
+)
+
+const usage = `Usage:
+  system-stats migrate status            # List migrations and whether each is applied
+  system-stats migrate up [-to N]        # Apply pending migrations (up to version N)
+  system-stats migrate down [-steps N]   # Roll back the last N migrations (default 1)
+
+Flags:
+  -dry-run   Print what would run without changing the database
+
+The database is selected with DB_TYPE and DB_DSN, as for the server.
+`
+
+// Run executes the migrate subcommand with args (everything after "migrate") and returns the exit code.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+
+	action := args[0]
+	fs := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dryRun := fs.Bool("dry-run", false, "Print what would run without changing the database")
+	to := fs.Int("to", 0, "Apply migrations up to and including this version (0 = all)")
+	steps := fs.Int("steps", 1, "Number of migrations to roll back")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	db, err := database.Initialize(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	// Module migrations apply whether or not a module is disabled, so the schema never depends on config
+	reg, err := registry.New(modules.All(), nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid module registry: %v\n", err)
+		return 1
+	}
+	m := database.NewMigrator(db, reg.Migrations())
+
+	switch action {
+	case "status":
+		return printStatus(m, stdout, stderr)
+	case "up":
+		ran, err := m.Up(*to, *dryRun)
+		if err == nil || len(ran) > 0 {
+			report(stdout, "apply", "Applied", ran, *dryRun)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Migration failed: %v\n", err)
+			return 1
+		}
+	case "down":
+		if *steps < 1 {
+			fmt.Fprintln(stderr, "-steps must be at least 1")
+			return 2
+		}
+		ran, err := m.Down(*steps, *dryRun)
+		if err == nil || len(ran) > 0 {
+			report(stdout, "roll back", "Rolled back", ran, *dryRun)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Rollback failed: %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(stderr, "Unknown migrate command %q\n\n%s", action, usage)
+		return 2
+	}
+	return 0
+}
+
+func printStatus(m *database.Migrator, stdout, stderr io.Writer) int {
+	current, err := m.CurrentVersion()
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to read schema version: %v\n", err)
+		return 1
+	}
+	statuses, err := m.Status()
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to read migrations: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Schema version: %d (binary supports %d)\n\n", current, m.LatestVersion())
+	for _, st := range statuses {
+		state := "pending"
+		if st.Applied {
+			state = "applied " + st.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(stdout, "  %4d  %-40s %s\n", st.Version, st.Name, state)
+	}
+	if current > m.LatestVersion() {
+		fmt.Fprintln(stdout, "\nDatabase is newer than this binary; upgrade before starting the server.")
+	}
+	return 0
+}
+
+func report(stdout io.Writer, verb, done string, ran []database.Migration, dryRun bool) {
+	if len(ran) == 0 {
+		fmt.Fprintf(stdout, "Nothing to %s.\n", verb)
+		return
+	}
+	prefix := done
+	if dryRun {
+		prefix = "Would " + verb
+	}
+	for _, mig := range ran {
+		fmt.Fprintf(stdout, "%s %d %s\n", prefix, mig.Version, mig.Name)
+	}
+}
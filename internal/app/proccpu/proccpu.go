@@ -0,0 +1,35 @@
+// Package proccpu derives a process's CPU usage from its cumulative CPU time, for the modules
+// that keep the previous reading of each process between collections.
+package proccpu
+
+import "time"
+
+// Sample is a process's cumulative CPU time when it was read.
+type Sample struct {
+	// Created is the process start time in Unix milliseconds; a different value under the same
+	// PID means the PID was reused
+	Created int64
+	// Seconds is the user plus system CPU time
+	Seconds float64
+	At      time.Time
+}
+
+// Percent is the CPU used between prev and cur (100 = one core), or since the process started
+// when prev is not an earlier sample of the same process (first reading, new process, reused PID).
+func Percent(prev, cur Sample) float64 {
+	if prev.At.IsZero() || prev.Created != cur.Created {
+		if cur.Created <= 0 {
+			return 0
+		}
+		elapsed := cur.At.Sub(time.UnixMilli(cur.Created)).Seconds()
+		if elapsed <= 0 {
+			return 0
+		}
+		return cur.Seconds / elapsed * 100
+	}
+	elapsed := cur.At.Sub(prev.At).Seconds()
+	if elapsed <= 0 || cur.Seconds < prev.Seconds {
+		return 0
+	}
+	return (cur.Seconds - prev.Seconds) / elapsed * 100
+}
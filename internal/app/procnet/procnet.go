@@ -0,0 +1,159 @@
+// Package procnet parses the network files under HOST_PROC/net: the tcp / udp socket tables,
+// sockstat and the snmp / netstat counter tables. The files describe the network namespace of
+// the reading process, so host-wide values need host networking in containerised deployments.
+package procnet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+
+	"system-stats/internal/app/hostfs"
+)
+
+// TCP states as numbered in include/net/tcp_states.h.
+const (
+	StateEstablished = 0x01
+	StateSynSent     = 0x02
+	StateSynRecv     = 0x03
+	StateFinWait1    = 0x04
+	StateFinWait2    = 0x05
+	StateTimeWait    = 0x06
+	StateClose       = 0x07
+	StateCloseWait   = 0x08
+	StateLastAck     = 0x09
+	StateListen      = 0x0A
+	StateClosing     = 0x0B
+	StateNewSynRecv  = 0x0C
+)
+
+// Socket is one row of a tcp, tcp6, udp or udp6 table.
+type Socket struct {
+	Local  netip.AddrPort
+	Remote netip.AddrPort
+	// State is a TCP state; UDP sockets report StateClose, or StateEstablished when connected
+	State uint8
+	UID   uint32
+	Inode uint64
+}
+
+// ReadSockets reads HOST_PROC/net/<table> (e.g. "tcp", "tcp6"). A missing table (IPv6 disabled)
+// yields no sockets and no error.
+func ReadSockets(table string) ([]Socket, error) {
+	raw, err := os.ReadFile(hostfs.Proc("net", table))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseSockets(raw)
+}
+
+// ParseSockets parses a /proc/net/tcp-style table; the header line is skipped.
+func ParseSockets(raw []byte) ([]Socket, error) {
+	var sockets []Socket
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for first := true; scanner.Scan(); first = false {
+		fields := strings.Fields(scanner.Text())
+		if first || len(fields) < 10 {
+			continue
+		}
+		local, err := parseAddrPort(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("local address %q: %w", fields[1], err)
+		}
+		remote, err := parseAddrPort(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("remote address %q: %w", fields[2], err)
+		}
+		state, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("state %q: %w", fields[3], err)
+		}
+		uid, _ := strconv.ParseUint(fields[7], 10, 32)
+		inode, _ := strconv.ParseUint(fields[9], 10, 64)
+		sockets = append(sockets, Socket{Local: local, Remote: remote, State: uint8(state), UID: uint32(uid), Inode: inode})
+	}
+	return sockets, scanner.Err()
+}
+
+// parseAddrPort decodes "0100007F:0016" (IPv4) or the 32-digit IPv6 form. The kernel prints the
+// address as 32-bit words in host byte order.
+func parseAddrPort(s string) (netip.AddrPort, error) {
+	addrHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return netip.AddrPort{}, errors.New("missing port")
+	}
+	b, err := hex.DecodeString(addrHex)
+	if err != nil || (len(b) != 4 && len(b) != 16) {
+		return netip.AddrPort{}, errors.New("bad address")
+	}
+	for i := 0; i < len(b); i += 4 {
+		binary.BigEndian.PutUint32(b[i:], binary.LittleEndian.Uint32(b[i:]))
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return netip.AddrPortFrom(addr.Unmap(), uint16(port)), nil
+}
+
+// ParseSockstat parses sockstat lines such as "TCP: inuse 5 orphan 0 tw 2 alloc 7 mem 1" into
+// values keyed by protocol and field.
+func ParseSockstat(raw []byte) map[string]map[string]uint64 {
+	out := make(map[string]map[string]uint64)
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		proto, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		values := make(map[string]uint64, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			if v, err := strconv.ParseUint(fields[i+1], 10, 64); err == nil {
+				values[fields[i]] = v
+			}
+		}
+		out[proto] = values
+	}
+	return out
+}
+
+// ParseCounterTables parses snmp / netstat files, where a header line naming the counters is
+// followed by a line with their values ("Tcp: ActiveOpens ..." then "Tcp: 10 ..."). Values are
+// keyed by table and counter; some are signed (Tcp MaxConn is -1).
+func ParseCounterTables(raw []byte) map[string]map[string]int64 {
+	out := make(map[string]map[string]int64)
+	var header []string
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if header == nil || header[0] != fields[0] {
+			header = fields
+			continue
+		}
+		values := make(map[string]int64, len(fields)-1)
+		for i := 1; i < len(fields) && i < len(header); i++ {
+			if v, err := strconv.ParseInt(fields[i], 10, 64); err == nil {
+				values[header[i]] = v
+			}
+		}
+		out[strings.TrimSuffix(fields[0], ":")] = values
+		header = nil
+	}
+	return out
+}
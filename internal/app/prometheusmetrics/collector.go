@@ -2,15 +2,9 @@
 package prometheusmetrics
 
 import (
-	"context"
-	"time"
-
 	"github.com/prometheus/client_golang/prometheus"
 
-	cpuservice "system-stats/internal/modules/cpu/application"
-	diskservice "system-stats/internal/modules/disk/application"
-	memoryservice "system-stats/internal/modules/memory/application"
-	networkservice "system-stats/internal/modules/network/application"
+	"system-stats/internal/app/snapshot"
 )
 
 var (
@@ -23,24 +17,46 @@ var (
 	descMemUsed  = prometheus.NewDesc("system_memory_used_bytes", "Memory currently in use, in bytes.", nil, nil)
 	descMemTotal = prometheus.NewDesc("system_memory_total_bytes", "Total physical memory, in bytes.", nil, nil)
 
+	descMemSlab        = prometheus.NewDesc("system_memory_slab_bytes", "Kernel slab memory, in bytes.", []string{"kind"}, nil)
+	descMemDirty       = prometheus.NewDesc("system_memory_dirty_bytes", "Memory waiting to be written back to disk, in bytes.", nil, nil)
+	descMemWriteback   = prometheus.NewDesc("system_memory_writeback_bytes", "Memory being written back to disk, in bytes.", nil, nil)
+	descMemCommitted   = prometheus.NewDesc("system_memory_committed_as_bytes", "Memory allocated by processes (Committed_AS), in bytes.", nil, nil)
+	descMemCommitLimit = prometheus.NewDesc("system_memory_commit_limit_bytes", "Memory the overcommit policy allows to allocate, in bytes.", nil, nil)
+	descMemHugePages   = prometheus.NewDesc("system_memory_hugepages", "Huge pages by state.", []string{"state"}, nil)
+	descVmstatSwapIn   = prometheus.NewDesc("system_vmstat_swap_in_pages_total", "Pages swapped in since boot.", nil, nil)
+	descVmstatSwapOut  = prometheus.NewDesc("system_vmstat_swap_out_pages_total", "Pages swapped out since boot.", nil, nil)
+	descVmstatFaults   = prometheus.NewDesc("system_vmstat_page_faults_total", "Page faults since boot.", []string{"kind"}, nil)
+	descVmstatOOMKills = prometheus.NewDesc("system_vmstat_oom_kills_total", "OOM killer invocations since boot.", nil, nil)
+
 	descDiskUsage = prometheus.NewDesc("system_disk_usage_percent", "Current disk utilization percentage.", nil, nil)
 	descDiskUsed  = prometheus.NewDesc("system_disk_used_bytes", "Disk space currently in use, in bytes.", nil, nil)
 	descDiskTotal = prometheus.NewDesc("system_disk_total_bytes", "Total disk space, in bytes.", nil, nil)
 
+	descDiskMountStale   = prometheus.NewDesc("system_disk_mount_stale", "1 when the mount did not answer statfs in time (e.g. a hung NFS or CIFS server), else 0.", []string{"mountpoint", "fstype"}, nil)
+	descNFSOps           = prometheus.NewDesc("system_nfs_ops_total", "NFS RPC requests sent for a mount.", []string{"mountpoint"}, nil)
+	descNFSRetrans       = prometheus.NewDesc("system_nfs_retransmissions_total", "NFS RPC retransmissions for a mount.", []string{"mountpoint"}, nil)
+	descNFSMajorTimeouts = prometheus.NewDesc("system_nfs_major_timeouts_total", "NFS RPC requests that hit a major timeout.", []string{"mountpoint"}, nil)
+	descNFSBytes         = prometheus.NewDesc("system_nfs_bytes_total", "Bytes read from and written to the NFS server.", []string{"mountpoint", "direction"}, nil)
+
 	descNetBytesSent = prometheus.NewDesc("system_network_bytes_sent_total", "Total bytes sent per network interface.", []string{"interface"}, nil)
 	descNetBytesRecv = prometheus.NewDesc("system_network_bytes_recv_total", "Total bytes received per network interface.", []string{"interface"}, nil)
+
+	descNetLinkSpeed      = prometheus.NewDesc("system_network_link_speed_bytes", "Negotiated link speed in bytes per second.", []string{"interface"}, nil)
+	descNetMTU            = prometheus.NewDesc("system_network_mtu_bytes", "Interface MTU in bytes.", []string{"interface"}, nil)
+	descNetUp             = prometheus.NewDesc("system_network_up", "Whether the interface operational state is up.", []string{"interface", "operstate"}, nil)
+	descNetCarrier        = prometheus.NewDesc("system_network_carrier", "Whether the physical link is detected.", []string{"interface"}, nil)
+	descNetCarrierChanges = prometheus.NewDesc("system_network_carrier_changes_total", "Link up/down transitions since boot.", []string{"interface"}, nil)
+	descNetUtil           = prometheus.NewDesc("system_network_utilization_percent", "Throughput as a percentage of the link speed.", []string{"interface", "direction"}, nil)
 )
 
-// SystemCollector implements prometheus.Collector and exposes live system metrics.
+// SystemCollector implements prometheus.Collector and exposes the latest collected snapshot.
+// Scrapes never run collectors themselves.
 type SystemCollector struct {
-	cpu     cpuservice.Service
-	memory  memoryservice.Service
-	disk    diskservice.Service
-	network networkservice.Service
+	store *snapshot.Store
 }
 
-func newSystemCollector(cpu cpuservice.Service, mem memoryservice.Service, disk diskservice.Service, net networkservice.Service) *SystemCollector {
-	return &SystemCollector{cpu: cpu, memory: mem, disk: disk, network: net}
+func newSystemCollector(store *snapshot.Store) *SystemCollector {
+	return &SystemCollector{store: store}
 }
 
 // Describe sends all descriptor pointers to the channel.
@@ -52,41 +68,128 @@ func (c *SystemCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- descMemUsage
 	ch <- descMemUsed
 	ch <- descMemTotal
+	ch <- descMemSlab
+	ch <- descMemDirty
+	ch <- descMemWriteback
+	ch <- descMemCommitted
+	ch <- descMemCommitLimit
+	ch <- descMemHugePages
+	ch <- descVmstatSwapIn
+	ch <- descVmstatSwapOut
+	ch <- descVmstatFaults
+	ch <- descVmstatOOMKills
 	ch <- descDiskUsage
 	ch <- descDiskUsed
 	ch <- descDiskTotal
+	ch <- descDiskMountStale
+	ch <- descNFSOps
+	ch <- descNFSRetrans
+	ch <- descNFSMajorTimeouts
+	ch <- descNFSBytes
 	ch <- descNetBytesSent
 	ch <- descNetBytesRecv
+	ch <- descNetLinkSpeed
+	ch <- descNetMTU
+	ch <- descNetUp
+	ch <- descNetCarrier
+	ch <- descNetCarrierChanges
+	ch <- descNetUtil
 }
 
-// Collect fetches fresh metrics from each service and sends them to the channel.
+// Collect sends the metrics of the latest snapshot; nothing is sent before the first cycle.
 func (c *SystemCollector) Collect(ch chan<- prometheus.Metric) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	snap := c.store.Latest()
+	if snap == nil {
+		return
+	}
 
-	if m, err := c.cpu.Collect(ctx); err == nil {
+	if m := snap.CPU; m != nil {
 		ch <- prometheus.MustNewConstMetric(descCPUUsage, prometheus.GaugeValue, m.UsagePercent)
 		ch <- prometheus.MustNewConstMetric(descCPULoadAvg1, prometheus.GaugeValue, m.LoadAvg1)
 		ch <- prometheus.MustNewConstMetric(descCPULoadAvg5, prometheus.GaugeValue, m.LoadAvg5)
 		ch <- prometheus.MustNewConstMetric(descCPULoadAvg15, prometheus.GaugeValue, m.LoadAvg15)
 	}
 
-	if m, err := c.memory.Collect(ctx); err == nil {
+	if m := snap.Memory; m != nil {
 		ch <- prometheus.MustNewConstMetric(descMemUsage, prometheus.GaugeValue, m.UsagePercent)
 		ch <- prometheus.MustNewConstMetric(descMemUsed, prometheus.GaugeValue, float64(m.Used))
 		ch <- prometheus.MustNewConstMetric(descMemTotal, prometheus.GaugeValue, float64(m.Total))
+		if e := m.Extended; e != nil {
+			ch <- prometheus.MustNewConstMetric(descMemSlab, prometheus.GaugeValue, float64(e.SlabReclaimable), "reclaimable")
+			ch <- prometheus.MustNewConstMetric(descMemSlab, prometheus.GaugeValue, float64(e.SlabUnreclaimable), "unreclaimable")
+			ch <- prometheus.MustNewConstMetric(descMemDirty, prometheus.GaugeValue, float64(e.Dirty))
+			ch <- prometheus.MustNewConstMetric(descMemWriteback, prometheus.GaugeValue, float64(e.Writeback))
+			ch <- prometheus.MustNewConstMetric(descMemCommitted, prometheus.GaugeValue, float64(e.CommittedAS))
+			ch <- prometheus.MustNewConstMetric(descMemCommitLimit, prometheus.GaugeValue, float64(e.CommitLimit))
+			ch <- prometheus.MustNewConstMetric(descMemHugePages, prometheus.GaugeValue, float64(e.HugePagesTotal), "total")
+			ch <- prometheus.MustNewConstMetric(descMemHugePages, prometheus.GaugeValue, float64(e.HugePagesFree), "free")
+			ch <- prometheus.MustNewConstMetric(descMemHugePages, prometheus.GaugeValue, float64(e.HugePagesReserved), "reserved")
+			ch <- prometheus.MustNewConstMetric(descMemHugePages, prometheus.GaugeValue, float64(e.HugePagesSurplus), "surplus")
+			ch <- prometheus.MustNewConstMetric(descVmstatSwapIn, prometheus.CounterValue, float64(e.Counters.SwapIn))
+			ch <- prometheus.MustNewConstMetric(descVmstatSwapOut, prometheus.CounterValue, float64(e.Counters.SwapOut))
+			ch <- prometheus.MustNewConstMetric(descVmstatFaults, prometheus.CounterValue, float64(e.Counters.PageFaults), "all")
+			ch <- prometheus.MustNewConstMetric(descVmstatFaults, prometheus.CounterValue, float64(e.Counters.MajorFaults), "major")
+			ch <- prometheus.MustNewConstMetric(descVmstatOOMKills, prometheus.CounterValue, float64(e.Counters.OOMKill))
+		}
 	}
 
-	if m, err := c.disk.Collect(ctx); err == nil {
+	if m := snap.Disk; m != nil {
 		ch <- prometheus.MustNewConstMetric(descDiskUsage, prometheus.GaugeValue, m.UsagePercent)
 		ch <- prometheus.MustNewConstMetric(descDiskUsed, prometheus.GaugeValue, float64(m.Used))
 		ch <- prometheus.MustNewConstMetric(descDiskTotal, prometheus.GaugeValue, float64(m.Total))
+		// Stacked and bind mounts repeat a mountpoint; a duplicate series would make the whole scrape fail
+		seen := make(map[string]bool, len(m.Mounts))
+		for _, mount := range m.Mounts {
+			if seen[mount.Path] {
+				continue
+			}
+			seen[mount.Path] = true
+			ch <- prometheus.MustNewConstMetric(descDiskMountStale, prometheus.GaugeValue, boolValue(mount.Stale), mount.Path, mount.Fstype)
+		}
+		seenNFS := make(map[string]bool, len(m.NFSMounts))
+		for _, nfs := range m.NFSMounts {
+			if seenNFS[nfs.Mountpoint] {
+				continue
+			}
+			seenNFS[nfs.Mountpoint] = true
+			ch <- prometheus.MustNewConstMetric(descNFSOps, prometheus.CounterValue, float64(nfs.Ops), nfs.Mountpoint)
+			ch <- prometheus.MustNewConstMetric(descNFSRetrans, prometheus.CounterValue, float64(nfs.Retransmissions), nfs.Mountpoint)
+			ch <- prometheus.MustNewConstMetric(descNFSMajorTimeouts, prometheus.CounterValue, float64(nfs.MajorTimeouts), nfs.Mountpoint)
+			ch <- prometheus.MustNewConstMetric(descNFSBytes, prometheus.CounterValue, float64(nfs.ReadBytes), nfs.Mountpoint, "read")
+			ch <- prometheus.MustNewConstMetric(descNFSBytes, prometheus.CounterValue, float64(nfs.WriteBytes), nfs.Mountpoint, "write")
+		}
 	}
 
-	if m, err := c.network.Collect(ctx); err == nil {
+	if m := snap.Network; m != nil {
 		for _, iface := range m.Interfaces {
 			ch <- prometheus.MustNewConstMetric(descNetBytesSent, prometheus.CounterValue, float64(iface.BytesSent), iface.Name)
 			ch <- prometheus.MustNewConstMetric(descNetBytesRecv, prometheus.CounterValue, float64(iface.BytesRecv), iface.Name)
+			if iface.LinkSpeedMbps != nil {
+				ch <- prometheus.MustNewConstMetric(descNetLinkSpeed, prometheus.GaugeValue, float64(*iface.LinkSpeedMbps)*1e6/8, iface.Name)
+			}
+			if iface.MTU > 0 {
+				ch <- prometheus.MustNewConstMetric(descNetMTU, prometheus.GaugeValue, float64(iface.MTU), iface.Name)
+			}
+			if iface.OperState != "" {
+				ch <- prometheus.MustNewConstMetric(descNetUp, prometheus.GaugeValue, boolValue(iface.OperState == "up"), iface.Name, iface.OperState)
+			}
+			if iface.Carrier != nil {
+				ch <- prometheus.MustNewConstMetric(descNetCarrier, prometheus.GaugeValue, boolValue(*iface.Carrier), iface.Name)
+			}
+			if iface.CarrierChanges != nil {
+				ch <- prometheus.MustNewConstMetric(descNetCarrierChanges, prometheus.CounterValue, float64(*iface.CarrierChanges), iface.Name)
+			}
+			if iface.RxUtilPercent != nil && iface.TxUtilPercent != nil {
+				ch <- prometheus.MustNewConstMetric(descNetUtil, prometheus.GaugeValue, *iface.RxUtilPercent, iface.Name, "rx")
+				ch <- prometheus.MustNewConstMetric(descNetUtil, prometheus.GaugeValue, *iface.TxUtilPercent, iface.Name, "tx")
+			}
 		}
 	}
 }
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package prometheusmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/app/collectorhealth"
+)
+
+var (
+	descCollectorDuration    = prometheus.NewDesc("collector_duration_seconds", "Duration of the last run of each metric collector.", []string{"module"}, nil)
+	descCollectorErrors      = prometheus.NewDesc("collector_errors_total", "Failed runs of each metric collector, including timeouts.", []string{"module"}, nil)
+	descCollectorRuns        = prometheus.NewDesc("collector_runs_total", "Finished runs of each metric collector.", []string{"module"}, nil)
+	descCollectorSkipped     = prometheus.NewDesc("collector_skipped_total", "Ticks skipped because the previous run was still in progress.", []string{"module"}, nil)
+	descCollectorFailures    = prometheus.NewDesc("collector_consecutive_failures", "Failed runs since the last success of each metric collector.", []string{"module"}, nil)
+	descCollectorLastSuccess = prometheus.NewDesc("collector_last_success_timestamp_seconds", "Unix time of the last successful run of each metric collector.", []string{"module"}, nil)
+)
+
+// CollectorHealthCollector exports the in-memory collector health tracker.
+type CollectorHealthCollector struct {
+	tracker *collectorhealth.Tracker
+}
+
+func newCollectorHealthCollector(tracker *collectorhealth.Tracker) *CollectorHealthCollector {
+	return &CollectorHealthCollector{tracker: tracker}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *CollectorHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descCollectorDuration
+	ch <- descCollectorErrors
+	ch <- descCollectorRuns
+	ch <- descCollectorSkipped
+	ch <- descCollectorFailures
+	ch <- descCollectorLastSuccess
+}
+
+// Collect sends the current status of every collector.
+func (c *CollectorHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, st := range c.tracker.Statuses() {
+		ch <- prometheus.MustNewConstMetric(descCollectorDuration, prometheus.GaugeValue, st.LastDurationSeconds, st.Module)
+		ch <- prometheus.MustNewConstMetric(descCollectorErrors, prometheus.CounterValue, float64(st.Errors), st.Module)
+		ch <- prometheus.MustNewConstMetric(descCollectorRuns, prometheus.CounterValue, float64(st.Runs), st.Module)
+		ch <- prometheus.MustNewConstMetric(descCollectorSkipped, prometheus.CounterValue, float64(st.Skipped), st.Module)
+		ch <- prometheus.MustNewConstMetric(descCollectorFailures, prometheus.GaugeValue, float64(st.ConsecutiveFailures), st.Module)
+		if st.LastSuccessAt != nil {
+			ch <- prometheus.MustNewConstMetric(descCollectorLastSuccess, prometheus.GaugeValue, float64(st.LastSuccessAt.UnixNano())/1e9, st.Module)
+		}
+	}
+}
@@ -11,10 +11,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
-	cpuservice "system-stats/internal/modules/cpu/application"
-	diskservice "system-stats/internal/modules/disk/application"
-	memoryservice "system-stats/internal/modules/memory/application"
-	networkservice "system-stats/internal/modules/network/application"
+	"system-stats/internal/app/collectorhealth"
+	"system-stats/internal/app/snapshot"
 )
 
 // Metrics holds a dedicated Prometheus registry and all application metric instruments.
@@ -25,19 +23,17 @@ type Metrics struct {
 }
 
 // New creates a Prometheus registry populated with Go runtime metrics, process metrics,
-// system metrics (CPU/RAM/disk/network), and HTTP request metrics.
-func New(
-	cpuSvc cpuservice.Service,
-	memSvc memoryservice.Service,
-	diskSvc diskservice.Service,
-	netSvc networkservice.Service,
-) *Metrics {
+// system metrics (CPU/RAM/disk/network) from the latest snapshot, collector health, metrics of
+// modules (extra), and HTTP request metrics.
+func New(store *snapshot.Store, tracker *collectorhealth.Tracker, extra ...prometheus.Collector) *Metrics {
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
-		newSystemCollector(cpuSvc, memSvc, diskSvc, netSvc),
+		newSystemCollector(store),
+		newCollectorHealthCollector(tracker),
 	)
+	reg.MustRegister(extra...)
 
 	httpReqs := prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "http_requests_total",
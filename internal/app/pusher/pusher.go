@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/collectorhealth"
+	"system-stats/internal/app/snapshot"
 )
 
 var loggedPushDisabled sync.Once
@@ -22,11 +24,19 @@ type PushPayload struct {
 	MemoryUsagePercent float64 `json:"memory_usage_percent"`
 	HostName           string  `json:"host_name,omitempty"`
 	HostIPv4           string  `json:"host_ipv4,omitempty"`
+
+	Collectors []collectorhealth.Status `json:"collectors,omitempty"`
+	// SentAt is this agent's clock when the push is sent; main compares it to its own to measure clock skew
+	SentAt time.Time `json:"sent_at"`
+	// Modules carries module data for main to store, keyed by module name (registry.Instance.PushData)
+	Modules map[string]any `json:"modules,omitempty"`
 }
 
 // Push sends metrics to the main node. Non-blocking; runs in goroutine.
 // hostName and hostIPv4 should be the agent's effective CollectHostInfo values (wizard NODE_STATS_* included).
-func Push(ctx context.Context, logger *log.Logger, mainURL, token string, metrics map[string]interface{}, hostName, hostIPv4 string) {
+// collectors is this agent's collector health so main can show failing collectors of remote hosts.
+// modules is the data of modules that push (e.g. top processes), keyed by module name.
+func Push(ctx context.Context, logger *log.Logger, mainURL, token string, snap *snapshot.Snapshot, collectors []collectorhealth.Status, modules map[string]any, hostName, hostIPv4 string) {
 	if mainURL == "" || token == "" {
 		loggedPushDisabled.Do(func() {
 			logger.Warn("Cluster push is disabled — set MAIN_NODE_URL and NODE_ACCESS_TOKEN so the main node receives heartbeats (last_seen). Connect from the agent UI or add these to .env / Docker env.")
@@ -34,7 +44,10 @@ func Push(ctx context.Context, logger *log.Logger, mainURL, token string, metric
 		return
 	}
 
-	payload := buildPayload(metrics, hostName, hostIPv4)
+	payload := buildPayload(snap, hostName, hostIPv4)
+	payload.Collectors = collectors
+	payload.Modules = modules
+	payload.SentAt = time.Now().UTC()
 	body, err := json.Marshal(payload)
 	if err != nil {
 		logger.Error("Failed to marshal push payload", "error", err)
@@ -63,7 +76,7 @@ func Push(ctx context.Context, logger *log.Logger, mainURL, token string, metric
 	}
 }
 
-func buildPayload(metrics map[string]interface{}, hostName, hostIPv4 string) PushPayload {
+func buildPayload(snap *snapshot.Snapshot, hostName, hostIPv4 string) PushPayload {
 	payload := PushPayload{
 		Status:        "ok",
 		UptimeSeconds: 0,
@@ -71,19 +84,14 @@ func buildPayload(metrics map[string]interface{}, hostName, hostIPv4 string) Pus
 		HostIPv4:      hostIPv4,
 	}
 
-	if cpu, ok := metrics["cpu"].(map[string]interface{}); ok {
-		if v, ok := cpu["usage_percent"].(float64); ok {
-			payload.CPUUsagePercent = v
-		}
+	if snap.CPU != nil {
+		payload.CPUUsagePercent = snap.CPU.UsagePercent
 	}
-	if mem, ok := metrics["memory"].(map[string]interface{}); ok {
-		if v, ok := mem["usage_percent"].(float64); ok {
-			payload.MemoryUsagePercent = v
-		}
+	if snap.Memory != nil {
+		payload.MemoryUsagePercent = snap.Memory.UsagePercent
 	}
 	// Uptime from host - we don't have it in metrics directly; use 0 for now
 	// Could add from host.Info() if needed
-	_ = fmt.Sprint(payload.UptimeSeconds) // avoid unused
 
 	return payload
 }
@@ -0,0 +1,224 @@
+// Package registry lets metric modules plug into the container, migrator, router and
+// retention cleanup through one interface, and decides which of them are active.
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	eventservice "system-stats/internal/modules/events/application"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	hostservice "system-stats/internal/modules/hosts/application"
+)
+
+// Deps are the shared dependencies a module is built from.
+type Deps struct {
+	Logger *log.Logger
+	DB     *gorm.DB
+	Hosts  hostservice.Service
+	// Events records state changes (e.g. a watched process going down) that feed host health
+	Events eventservice.Service
+}
+
+// Module is a pluggable metric module. Each module declares it in its own module.go.
+type Module interface {
+	// Name is the lower-case identifier used by MODULES_DISABLED and COLLECT_*_<MODULE>.
+	Name() string
+	// Migrations returns the module's schema changes. Versions share the global sequence in
+	// database.Migrations() and are applied whether or not the module is enabled.
+	Migrations() []database.Migration
+	// RetentionTables lists the tables pruned by their timestamp column, enabled or not.
+	RetentionTables() []string
+	// HostTables lists the tables whose rows belong to one host (a host_id column), deleted in
+	// this order when the host is removed, enabled or not.
+	HostTables() []string
+	// Build creates the module's services; it is only called for enabled modules.
+	Build(deps Deps) (Instance, error)
+}
+
+// Instance is a built module.
+type Instance struct {
+	// Collector is run by the collection scheduler; nil for modules that only serve requests.
+	Collector *historycore.ModuleCollector
+	// Routes registers the module's endpoints on the authenticated /api/v1 group.
+	Routes func(r gin.IRoutes)
+	// PublicRoutes registers endpoints on /api/v1 without user authentication; the module authenticates them itself.
+	PublicRoutes func(r gin.IRoutes)
+	// Metrics is exported on /metrics when Prometheus is enabled; it must read collected state, never collect.
+	Metrics prometheus.Collector
+	// PushData returns what an agent sends to the main node under the module's name with each
+	// cluster push; nil sends nothing. It must return collected state, never collect.
+	PushData func() any
+	// ReceivePush stores data an agent pushed under the module's name, on the main node.
+	ReceivePush func(ctx context.Context, hostId uint, data json.RawMessage) error
+	// ReceivePushClock is given the agent's send time and this node's receive time of every
+	// cluster push (agents that predate sent_at give none), whether or not the agent runs the module.
+	ReceivePushClock func(ctx context.Context, hostId uint, sentAt, receivedAt time.Time) error
+}
+
+// PushLatest adapts a getter of collected state to Instance.PushData. A nil pointer becomes an
+// untyped nil, which keeps the module out of the push until its first collection.
+func PushLatest[T any](latest func() *T) func() any {
+	return func() any {
+		if v := latest(); v != nil {
+			return v
+		}
+		return nil
+	}
+}
+
+// RetentionLimiter is implemented by modules whose tables keep less history than METRICS_RETENTION_DAYS.
+type RetentionLimiter interface {
+	// RetentionLimit is the maximum age of rows in the module's RetentionTables.
+	RetentionLimit() time.Duration
+}
+
+// Built pairs an enabled module's name with its instance.
+type Built struct {
+	Name string
+	Instance
+}
+
+// Registry holds every known module and the set disabled by configuration.
+type Registry struct {
+	modules  []Module
+	disabled map[string]bool
+}
+
+// New validates modules and the disabled list. Unknown disabled names, duplicate module names
+// and migration versions that collide with another module or the core migrations are errors.
+func New(modules []Module, disabled []string) (*Registry, error) {
+	r := &Registry{modules: modules, disabled: make(map[string]bool, len(disabled))}
+
+	names := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		if names[m.Name()] {
+			return nil, fmt.Errorf("module %q registered twice", m.Name())
+		}
+		names[m.Name()] = true
+	}
+	for _, name := range disabled {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !names[name] {
+			return nil, fmt.Errorf("unknown module %q (known: %s)", name, strings.Join(r.Names(), ", "))
+		}
+		r.disabled[name] = true
+	}
+
+	owner := make(map[int]string)
+	for _, mig := range database.Migrations() {
+		owner[mig.Version] = "core"
+	}
+	for _, m := range modules {
+		for _, mig := range m.Migrations() {
+			if prev, ok := owner[mig.Version]; ok {
+				return nil, fmt.Errorf("module %q: migration version %d already used by %s", m.Name(), mig.Version, prev)
+			}
+			owner[mig.Version] = m.Name()
+		}
+	}
+	return r, nil
+}
+
+// Names returns every known module name in registration order.
+func (r *Registry) Names() []string {
+	out := make([]string, 0, len(r.modules))
+	for _, m := range r.modules {
+		out = append(out, m.Name())
+	}
+	return out
+}
+
+// Enabled reports whether the named module is known and not disabled.
+func (r *Registry) Enabled(name string) bool {
+	for _, m := range r.modules {
+		if m.Name() == name {
+			return !r.disabled[name]
+		}
+	}
+	return false
+}
+
+// Disabled returns the disabled module names in registration order.
+func (r *Registry) Disabled() []string {
+	var out []string
+	for _, m := range r.modules {
+		if r.disabled[m.Name()] {
+			out = append(out, m.Name())
+		}
+	}
+	return out
+}
+
+// Migrations returns the core migrations plus those of every module, sorted by version.
+func (r *Registry) Migrations() []database.Migration {
+	all := database.Migrations()
+	for _, m := range r.modules {
+		all = append(all, m.Migrations()...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all
+}
+
+// RetentionTables returns the tables of every module, so data of a disabled module still ages out.
+func (r *Registry) RetentionTables() []string {
+	var out []string
+	for _, m := range r.modules {
+		out = append(out, m.RetentionTables()...)
+	}
+	return out
+}
+
+// HostTables returns the per-host tables of every module, so removing a host also deletes the
+// data of a disabled module.
+func (r *Registry) HostTables() []string {
+	var out []string
+	for _, m := range r.modules {
+		out = append(out, m.HostTables()...)
+	}
+	return out
+}
+
+// RetentionLimits returns the maximum row age of tables whose module implements RetentionLimiter.
+func (r *Registry) RetentionLimits() map[string]time.Duration {
+	out := make(map[string]time.Duration)
+	for _, m := range r.modules {
+		limiter, ok := m.(RetentionLimiter)
+		if !ok {
+			continue
+		}
+		for _, table := range m.RetentionTables() {
+			out[table] = limiter.RetentionLimit()
+		}
+	}
+	return out
+}
+
+// Build builds the enabled modules in registration order.
+func (r *Registry) Build(deps Deps) ([]Built, error) {
+	var out []Built
+	for _, m := range r.modules {
+		if r.disabled[m.Name()] {
+			continue
+		}
+		inst, err := m.Build(deps)
+		if err != nil {
+			return nil, fmt.Errorf("build module %s: %w", m.Name(), err)
+		}
+		out = append(out, Built{Name: m.Name(), Instance: inst})
+	}
+	return out, nil
+}
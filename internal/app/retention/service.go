@@ -8,23 +8,26 @@ import (
 	"gorm.io/gorm"
 )
 
-var MetricTables = []string{
-	"cpu_metrics",
-	"memory_metrics",
-	"disk_metrics",
-	"network_metrics",
-	"docker_metrics",
-}
-
 // Service deletes metric rows older than RetentionDays on an hourly schedule.
 type Service struct {
 	db            *gorm.DB
 	logger        *log.Logger
 	retentionDays int
+	// tables are pruned by their timestamp column (registry.Registry.RetentionTables)
+	tables []string
+	// limits shorten the retention of individual tables (registry.Registry.RetentionLimits)
+	limits map[string]time.Duration
 }
 
-func NewService(db *gorm.DB, logger *log.Logger, retentionDays int) *Service {
-	return &Service{db: db, logger: logger, retentionDays: retentionDays}
+func NewService(db *gorm.DB, logger *log.Logger, retentionDays int, tables []string) *Service {
+	return &Service{db: db, logger: logger, retentionDays: retentionDays, tables: tables}
+}
+
+// WithLimits keeps rows of the given tables for at most their duration; longer limits than
+// RetentionDays have no effect.
+func (s *Service) WithLimits(limits map[string]time.Duration) *Service {
+	s.limits = limits
+	return s
 }
 
 // Start runs an immediate cleanup then repeats every hour until ctx is cancelled.
@@ -45,8 +48,12 @@ func (s *Service) Start(ctx context.Context) {
 }
 
 func (s *Service) Cleanup() {
-	cutoff := time.Now().AddDate(0, 0, -s.retentionDays)
-	for _, table := range MetricTables {
+	now := time.Now()
+	for _, table := range s.tables {
+		cutoff := now.AddDate(0, 0, -s.retentionDays)
+		if limit, ok := s.limits[table]; ok && limit > 0 && now.Add(-limit).After(cutoff) {
+			cutoff = now.Add(-limit)
+		}
 		result := s.db.Exec("DELETE FROM "+table+" WHERE timestamp < ?", cutoff)
 		if result.Error != nil {
 			s.logger.Error("Retention cleanup failed", "table", table, "error", result.Error)
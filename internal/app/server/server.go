@@ -5,7 +5,6 @@ package server
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
@@ -18,6 +17,7 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	swaggerFiles "github.com/swaggo/files"
 
@@ -26,21 +26,21 @@ import (
 	"system-stats/internal/app/di"
 	"system-stats/internal/app/help"
 	"system-stats/internal/app/middleware"
+	"system-stats/internal/app/migratecmd"
 	"system-stats/internal/app/prometheusmetrics"
 	"system-stats/internal/app/pusher"
+	"system-stats/internal/app/registry"
 	clusterconfig "system-stats/internal/modules/nodes/infrastructure/cluster_config"
 	"system-stats/internal/app/retention"
+	"system-stats/internal/app/snapshot"
+	"system-stats/internal/modules"
 	historyapp "system-stats/internal/modules/history_metrics/application"
-	cpumodule "system-stats/internal/modules/cpu/presentation"
-	diskmodule "system-stats/internal/modules/disk/presentation"
-	dockermodule "system-stats/internal/modules/docker/presentation"
+	collectorsmodule "system-stats/internal/modules/collectors/presentation"
+	eventsmodule "system-stats/internal/modules/events/presentation"
 	healthmodule "system-stats/internal/modules/health/presentation"
 	hostmodule "system-stats/internal/modules/hosts/presentation"
 	invmodule "system-stats/internal/modules/invitations/presentation"
 	nodesmodule "system-stats/internal/modules/nodes/presentation"
-	memorymodule "system-stats/internal/modules/memory/presentation"
-	networkmodule "system-stats/internal/modules/network/presentation"
-	sensorsmodule "system-stats/internal/modules/sensors/presentation"
 	setupapp "system-stats/internal/modules/setup/application"
 	setupmodule "system-stats/internal/modules/setup/presentation"
 	streammodule "system-stats/internal/modules/stream/presentation"
@@ -50,6 +50,10 @@ import (
 
 // Run starts the system statistics HTTP server.
 func Run() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(migratecmd.Run(os.Args[2:]))
+	}
+
 	showHelp := flag.Bool("help", false, "Show help message with environment variables description")
 	flag.Parse()
 
@@ -84,8 +88,16 @@ func Run() {
 
 	startTime := time.Now()
 
+	moduleRegistry, err := registry.New(modules.All(), cfg.ModulesDisabled)
+	if err != nil {
+		logger.Fatal("Invalid MODULES_DISABLED", "error", err)
+	}
+	if disabled := moduleRegistry.Disabled(); len(disabled) > 0 {
+		logger.Info("Metric modules disabled", "modules", strings.Join(disabled, ","))
+	}
+
 	logger.Info("Initializing dependency injection container...", "db_type", cfg.Database.Type, "db_dsn", config.MaskDSN(cfg.Database.DSN))
-	container, err := di.NewContainer(logger, cfg.Database, cfg.JWTSecret, cfg.RefreshSecret, startTime)
+	container, err := di.NewContainer(logger, cfg.Database, cfg.Collection, moduleRegistry, cfg.JWTSecret, cfg.RefreshSecret, startTime)
 	if err != nil {
 		logger.Fatal("Failed to initialize DI container", "error", err)
 	}
@@ -102,72 +114,64 @@ func Run() {
 
 	historicalMetricsService := container.GetHistoricalMetricsService()
 
-	// Wire SSE broker into the after-collect hook (harmless before collection starts).
+	// Wire SSE broker and cluster push into the after-collect hook (harmless before collection starts).
+	// The hook runs after every module update; every consumer shares the snapshot and its cached JSON encoding.
 	broker := container.GetBroker()
-	systemSvc := container.GetSystemService()
-	historicalMetricsService = historyapp.WithAfterCollect(historicalMetricsService, func() {
-		metrics, err := systemSvc.CollectAllCurrent(context.Background())
-		if err != nil {
-			return
-		}
-		data, err := json.Marshal(metrics)
+	var lastPush time.Time
+	historicalMetricsService = historyapp.WithAfterCollect(historicalMetricsService, func(snap *snapshot.Snapshot, module string) {
+		out, err := snap.JSON()
 		if err != nil {
+			logger.Error("Failed to encode metrics snapshot", "error", err)
 			return
 		}
-		var envelope map[string]interface{}
-		if err := json.Unmarshal(data, &envelope); err != nil {
-			return
-		}
-		if host, herr := container.GetHostService().GetCurrentHost(context.Background()); herr == nil && host != nil {
-			envelope["collecting_host_id"] = host.ID
-		}
-		out, err := json.Marshal(envelope)
-		if err != nil {
-			return
+		var cpuPct, memPct float64
+		var containers int
+		if snap.CPU != nil {
+			cpuPct = snap.CPU.UsagePercent
 		}
-		var s struct {
-			CPU    struct{ UsagePercent float64 `json:"usage_percent"` } `json:"cpu"`
-			Memory struct{ UsagePercent float64 `json:"usage_percent"` } `json:"memory"`
-			Docker struct{ RunningContainers int `json:"running_containers"` } `json:"docker"`
+		if snap.Memory != nil {
+			memPct = snap.Memory.UsagePercent
 		}
-		if err := json.Unmarshal(data, &s); err == nil {
-			logger.Info("Metrics collected",
-				"cpu", fmt.Sprintf("%.1f%%", s.CPU.UsagePercent),
-				"mem", fmt.Sprintf("%.1f%%", s.Memory.UsagePercent),
-				"containers", s.Docker.RunningContainers,
-			)
+		if snap.Docker != nil {
+			containers = snap.Docker.RunningContainers
 		}
+		logger.Debug("Metrics collected",
+			"module", module,
+			"cpu", fmt.Sprintf("%.1f%%", cpuPct),
+			"mem", fmt.Sprintf("%.1f%%", memPct),
+			"containers", containers,
+		)
 		broker.Publish(out)
 
-		// Push to main node if cluster config is set (from env at startup or after connect)
-		if mainURL, token := clusterconfig.Get(); mainURL != "" && token != "" {
-			pushCtx := context.Background()
-			hostName, hostIPv4 := "", ""
-			if hi, herr := container.GetHostService().GetCurrentHostInfo(pushCtx); herr == nil {
-				hostName = hi.Name
-				hostIPv4 = hi.IPv4
-			}
-			go pusher.Push(pushCtx, logger, mainURL, token, metrics, hostName, hostIPv4)
+		// Push to main node if cluster config is set (from env at startup or after connect).
+		// Heartbeats stay at the base interval however many modules update in between.
+		if mainURL, token := clusterconfig.Get(); mainURL != "" && token != "" && time.Since(lastPush) >= cfg.Collection.Default.Interval {
+			lastPush = time.Now()
+			go func() {
+				pushCtx := context.Background()
+				hostName, hostIPv4 := "", ""
+				if hi, herr := container.GetHostService().GetCurrentHostInfo(pushCtx); herr == nil {
+					hostName = hi.Name
+					hostIPv4 = hi.IPv4
+				}
+				pusher.Push(pushCtx, logger, mainURL, token, snap, container.GetCollectorHealth().Statuses(), modulePushData(container.GetModules()), hostName, hostIPv4)
+			}()
 		}
 	})
 
 	// startMetrics activates periodic collection and retention.
 	// Called immediately on normal startup, or as a callback once setup completes.
 	startMetrics := func() {
-		logger.Info("Checking system stats availability...")
-		if _, err := container.GetCPUService().Collect(context.Background()); err != nil {
-			logger.Warn("Failed to get initial CPU stats, continuing without check", "error", err)
-		} else {
-			logger.Info("System stats are available")
-		}
-
 		logger.Info("Starting periodic metrics collection...")
-		if err := historicalMetricsService.StartPeriodicCollection(context.Background(), 5*time.Second); err != nil {
+		if err := historicalMetricsService.StartPeriodicCollection(context.Background()); err != nil {
 			logger.Error("Failed to start periodic collection", "error", err)
 			return
 		}
 
-		retentionSvc := retention.NewService(container.GetDB(), logger, cfg.RetentionDays)
+		// host_events is core, not owned by a module
+		tables := append(container.GetRegistry().RetentionTables(), "host_events")
+		retentionSvc := retention.NewService(container.GetDB(), logger, cfg.RetentionDays, tables).
+			WithLimits(container.GetRegistry().RetentionLimits())
 		retentionSvc.Start(context.Background())
 	}
 
@@ -229,6 +233,23 @@ func Run() {
 	}
 }
 
+// modulePushData collects what enabled modules send to the main node with a cluster push.
+func modulePushData(modules []registry.Built) map[string]any {
+	var out map[string]any
+	for _, m := range modules {
+		if m.PushData == nil {
+			continue
+		}
+		if data := m.PushData(); data != nil {
+			if out == nil {
+				out = make(map[string]any)
+			}
+			out[m.Name] = data
+		}
+	}
+	return out
+}
+
 // setupRouter configures the Gin router with all routes, middleware, and handlers.
 func setupRouter(container *di.Container, startTime time.Time, logger *log.Logger, cfg *config.Config, onSetupComplete func()) *gin.Engine {
 	router := gin.New()
@@ -237,12 +258,13 @@ func setupRouter(container *di.Container, startTime time.Time, logger *log.Logge
 
 	var promHandler *prometheusmetrics.Metrics
 	if cfg.PrometheusEnabled {
-		promHandler = prometheusmetrics.New(
-			container.GetCPUService(),
-			container.GetMemoryService(),
-			container.GetDiskService(),
-			container.GetNetworkService(),
-		)
+		var moduleMetrics []prometheus.Collector
+		for _, m := range container.GetModules() {
+			if m.Metrics != nil {
+				moduleMetrics = append(moduleMetrics, m.Metrics)
+			}
+		}
+		promHandler = prometheusmetrics.New(container.GetSnapshotStore(), container.GetCollectorHealth(), moduleMetrics...)
 		router.Use(promHandler.GinMiddleware())
 	}
 
@@ -258,14 +280,10 @@ func setupRouter(container *di.Container, startTime time.Time, logger *log.Logge
 	logger.Info("Serving static files", "path", distPath)
 
 	systemHandler := systemmodule.NewSystemHandler(logger, container.GetSystemService(), container.GetHostService())
-	cpuHandler := cpumodule.NewCPUHandler(logger, container.GetCPUService(), container.GetHostService())
-	memoryHandler := memorymodule.NewMemoryHandler(logger, container.GetMemoryService(), container.GetHostService())
-	diskHandler := diskmodule.NewDiskHandler(logger, container.GetDiskService(), container.GetHostService())
-	networkHandler := networkmodule.NewNetworkHandler(logger, container.GetNetworkService(), container.GetHostService())
-	dockerHandler := dockermodule.NewDockerHandler(logger, container.GetDockerService(), container.GetHostService())
-	sensorsHandler := sensorsmodule.NewSensorsHandler(logger, container.GetSensorsService(), container.GetHostService())
 	hostHandler := hostmodule.NewHostHandler(logger, container.GetHostService())
 	healthHandler := healthmodule.NewHealthHandler(logger, container.GetHealthService())
+	collectorsHandler := collectorsmodule.NewCollectorsHandler(logger, container.GetCollectorsService(), container.GetHostService())
+	eventsHandler := eventsmodule.NewEventsHandler(logger, container.GetEventsService(), container.GetHostService())
 	authHandler := usermodule.NewAuthHandler(container.GetUserService(), container.GetTokenService(), cfg.CookieSecure)
 	usersHandler := usermodule.NewUsersHandler(container.GetUserService())
 	invitationHandler := invmodule.NewInvitationHandler(container.GetInvitationService())
@@ -343,17 +361,26 @@ func setupRouter(container *di.Container, startTime time.Time, logger *log.Logge
 
 		// Metrics current snapshot
 		api.GET("/metrics/current", middleware.AuthJWT(container.GetTokenService()), systemHandler.HandleCurrentMetrics)
+
+		// Module endpoints with their own authentication (e.g. custom metrics ingest tokens)
+		for _, m := range container.GetModules() {
+			if m.PublicRoutes != nil {
+				m.PublicRoutes(api)
+			}
+		}
 	}
 
 	// Individual metrics routes (all protected)
 	authAPI := api.Group("", middleware.AuthJWT(container.GetTokenService()))
 	{
-		authAPI.GET("/cpu", cpuHandler.HandleCPUStats)
-		authAPI.GET("/memory", memoryHandler.HandleMemoryStats)
-		authAPI.GET("/disk", diskHandler.HandleDiskStats)
-		authAPI.GET("/network", networkHandler.HandleNetworkStats)
-		authAPI.GET("/docker", dockerHandler.HandleDockerStats)
-		authAPI.GET("/sensors", sensorsHandler.HandleSensors)
+		// Metric module routes come from the registry; disabled modules register none
+		for _, m := range container.GetModules() {
+			if m.Routes != nil {
+				m.Routes(authAPI)
+			}
+		}
+		authAPI.GET("/collectors/status", collectorsHandler.HandleStatus)
+		authAPI.GET("/events", eventsHandler.HandleEvents)
 		authAPI.GET("/hosts", hostHandler.HandleGetAllHosts)
 		authAPI.GET("/hosts/current", hostHandler.HandleGetCurrentHost)
 		authAPI.POST("/hosts/register", hostHandler.HandleRegisterCurrentHost)
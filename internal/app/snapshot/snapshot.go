@@ -0,0 +1,93 @@
+// Package snapshot holds the typed result of the collection loop.
+// A snapshot is rebuilt whenever a module collects and is shared read-only by
+// the SSE broker, the cluster pusher, Prometheus and the current-metrics endpoint.
+package snapshot
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cpuentities "system-stats/internal/modules/cpu/infrastructure/entities"
+	diskentities "system-stats/internal/modules/disk/infrastructure/entities"
+	dockerentities "system-stats/internal/modules/docker/infrastructure/entities"
+	memoryentities "system-stats/internal/modules/memory/infrastructure/entities"
+	networkentities "system-stats/internal/modules/network/infrastructure/entities"
+	pressureentities "system-stats/internal/modules/pressure/infrastructure/entities"
+)
+
+// Snapshot is the latest collected metric of every module for one host. A nil module field
+// means that collector has not succeeded yet; it is omitted from JSON so SSE clients keep
+// their previous value. Snapshots must not be modified once published.
+type Snapshot struct {
+	Timestamp time.Time                      `json:"timestamp"`
+	HostID    uint                           `json:"collecting_host_id,omitempty"`
+	CPU       *cpuentities.CPUMetric         `json:"cpu,omitempty"`
+	Memory    *memoryentities.MemoryMetric   `json:"memory,omitempty"`
+	Disk      *diskentities.DiskMetric       `json:"disk,omitempty"`
+	Network   *networkentities.NetworkMetric `json:"network,omitempty"`
+	Docker    *dockerentities.DockerMetric   `json:"docker,omitempty"`
+	// Pressure is PSI and cgroup v2 statistics, shown next to CPU and memory
+	Pressure *pressureentities.PressureMetric `json:"pressure,omitempty"`
+
+	encodeOnce sync.Once
+	encoded    []byte
+	encodeErr  error
+}
+
+// JSON returns the snapshot encoded once and cached; every consumer receives the same bytes.
+func (s *Snapshot) JSON() ([]byte, error) {
+	s.encodeOnce.Do(func() {
+		type plain Snapshot
+		s.encoded, s.encodeErr = json.Marshal((*plain)(s))
+	})
+	return s.encoded, s.encodeErr
+}
+
+// Update sets one module's freshly collected metric on a snapshot being assembled.
+type Update func(s *Snapshot)
+
+// Store keeps the most recently published snapshot for readers outside the collection loop.
+type Store struct {
+	mu     sync.Mutex
+	latest atomic.Pointer[Snapshot]
+}
+
+// NewStore creates an empty snapshot store.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Latest returns the last published snapshot, or nil before the first cycle completes.
+func (s *Store) Latest() *Snapshot {
+	return s.latest.Load()
+}
+
+// Set publishes snap as the latest snapshot.
+func (s *Store) Set(snap *Snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest.Store(snap)
+}
+
+// Apply publishes a new snapshot that carries over the other modules of the latest one
+// for the same host and applies update on top. Modules collected on slower schedules
+// therefore keep their last value until their next run.
+func (s *Store) Apply(hostId uint, at time.Time, update Update) *Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := &Snapshot{Timestamp: at, HostID: hostId}
+	if prev := s.latest.Load(); prev != nil && prev.HostID == hostId {
+		next.CPU = prev.CPU
+		next.Memory = prev.Memory
+		next.Disk = prev.Disk
+		next.Network = prev.Network
+		next.Docker = prev.Docker
+		next.Pressure = prev.Pressure
+	}
+	update(next)
+	s.latest.Store(next)
+	return next
+}
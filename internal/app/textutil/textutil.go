@@ -0,0 +1,22 @@
+// Package textutil bounds text from hosts, scripts and agents to the size of the column it is
+// stored in. PostgreSQL rejects values that are too long or not valid UTF-8, failing the whole
+// insert, so every such value is passed through Truncate first.
+package textutil
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Truncate replaces invalid UTF-8 in s and cuts it to at most n bytes without splitting a character.
+func Truncate(s string, n int) string {
+	s = strings.ToValidUTF8(s, "\uFFFD")
+	if len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,49 @@
+package collectors
+
+import (
+	"time"
+
+	"system-stats/internal/app/collectorhealth"
+	"system-stats/internal/modules/collectors/infrastructure/entities"
+)
+
+type Service interface {
+	// LocalStatus returns the health of this instance's collectors.
+	LocalStatus(hostID uint) entities.CollectorsStatus
+	// RemoteStatus returns the collector health last pushed by an agent; ok is false before its first push.
+	RemoteStatus(hostID uint) (status entities.CollectorsStatus, ok bool)
+}
+
+type service struct {
+	local  *collectorhealth.Tracker
+	remote *collectorhealth.RemoteStore
+}
+
+func NewService(local *collectorhealth.Tracker, remote *collectorhealth.RemoteStore) Service {
+	return &service{local: local, remote: remote}
+}
+
+func (s *service) LocalStatus(hostID uint) entities.CollectorsStatus {
+	statuses := s.local.Statuses()
+	now := time.Now().UTC()
+	return entities.CollectorsStatus{
+		HostID:     hostID,
+		Healthy:    collectorhealth.Healthy(statuses),
+		ReportedAt: &now,
+		Collectors: statuses,
+	}
+}
+
+func (s *service) RemoteStatus(hostID uint) (entities.CollectorsStatus, bool) {
+	rep, ok := s.remote.Get(hostID)
+	if !ok {
+		return entities.CollectorsStatus{HostID: hostID, Collectors: []collectorhealth.Status{}}, false
+	}
+	at := rep.ReceivedAt
+	return entities.CollectorsStatus{
+		HostID:     hostID,
+		Healthy:    collectorhealth.Healthy(rep.Collectors),
+		ReportedAt: &at,
+		Collectors: rep.Collectors,
+	}, true
+}
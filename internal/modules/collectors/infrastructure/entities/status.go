@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+
+	"system-stats/internal/app/collectorhealth"
+)
+
+// CollectorsStatus is the collector health of one host.
+type CollectorsStatus struct {
+	HostID uint `json:"host_id"`
+
+	// Healthy is false when any collector is failing or stale
+	Healthy bool `json:"healthy"`
+
+	// ReportedAt is when the statuses were taken: now for this instance, the last push for agents
+	ReportedAt *time.Time `json:"reported_at"`
+
+	Collectors []collectorhealth.Status `json:"collectors"`
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	collectorsservice "system-stats/internal/modules/collectors/application"
+	hostservice "system-stats/internal/modules/hosts/application"
+)
+
+// CollectorsHandler handles HTTP requests for collector health.
+type CollectorsHandler struct {
+	logger  *log.Logger
+	service collectorsservice.Service
+	hosts   hostservice.Service
+}
+
+// NewCollectorsHandler creates a new HTTP handler for collector health endpoints.
+func NewCollectorsHandler(logger *log.Logger, service collectorsservice.Service, hosts hostservice.Service) *CollectorsHandler {
+	return &CollectorsHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleStatus returns the health of every metric collector of a host.
+//
+// @Summary     Collector health
+// @Description Returns per-module collector state (ok, failing, stale, pending), last success, last error, consecutive failures and run duration. Remote cluster hosts report the status from their last push (empty before the first one).
+// @Tags        metrics
+// @Produce     json
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /collectors/status [get]
+func (h *CollectorsHandler) HandleStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	queryHost := httputil.ParseHostIdQuery(c)
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyCollectorsStatusPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for collector status", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	remote, err := metricshost.IsRemoteHost(ctx, h.hosts, effective)
+	if err != nil {
+		h.logger.Error("Failed to classify host for collector status", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if remote {
+		status, _ := h.service.RemoteStatus(effective)
+		c.JSON(http.StatusOK, status)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.LocalStatus(effective))
+}
@@ -8,11 +8,11 @@ import (
  // This structure contains CPU usage statistics, system load averages, and temperature recorded at a specific time,
  // used for trend analysis and historical reporting.
 type HistoricalCPUMetric struct {
-	// HostID is the foreign key referencing the host that recorded this metric
-	HostID *uint `json:"host_id" gorm:"default:null;index;index:idx_cpu_host_ts"`
+	// HostID references the host that recorded this metric (primary key with Timestamp)
+	HostID uint `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_cpu_host_ts,priority:1"`
 
-	// Timestamp indicates when this CPU metric was recorded (primary key)
-	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_cpu_host_ts"`
+	// Timestamp indicates when this CPU metric was recorded (primary key with HostID)
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_cpu_host_ts,priority:2"`
 
 	// Usage shows the CPU utilization percentage at the time of recording
 	Usage float64 `json:"usage" gorm:"column:usage"`
@@ -30,7 +30,7 @@ func NewCPURepository(db *gorm.DB) CPURepository {
 
 func (r *cpuRepository) SaveCurrentMetric(ctx context.Context, metric localentities.CPUMetric, hostId uint) error {
 	historicalMetric := localentities.HistoricalCPUMetric{
-		HostID:      &hostId,
+		HostID:      hostId,
 		Timestamp:   time.Now().UTC(),
 		Usage:       metric.UsagePercent,
 		Cores:       metric.Cores,
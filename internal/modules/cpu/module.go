@@ -0,0 +1,40 @@
+// Package cpu registers the CPU metrics module.
+package cpu
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/app/snapshot"
+	cpuservice "system-stats/internal/modules/cpu/application"
+	"system-stats/internal/modules/cpu/infrastructure/entities"
+	"system-stats/internal/modules/cpu/infrastructure/repositories"
+	handlers "system-stats/internal/modules/cpu/presentation"
+	historycore "system-stats/internal/modules/history_metrics/core"
+)
+
+// Module collects CPU usage and serves /cpu.
+type Module struct{}
+
+func (Module) Name() string { return "cpu" }
+
+// Migrations is empty: cpu_metrics is part of the baseline schema.
+func (Module) Migrations() []database.Migration { return nil }
+
+func (Module) RetentionTables() []string { return []string{"cpu_metrics"} }
+
+func (Module) HostTables() []string { return []string{"cpu_metrics"} }
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := cpuservice.NewService(deps.Logger, repositories.NewCPURepository(deps.DB))
+	handler := handlers.NewCPUHandler(deps.Logger, service, deps.Hosts)
+	collector := historycore.NewModuleCollector("cpu", service.Collect, service.Save,
+		func(snap *snapshot.Snapshot, m *entities.CPUMetric) { snap.CPU = m })
+	return registry.Instance{
+		Collector: &collector,
+		Routes: func(r gin.IRoutes) {
+			r.GET("/cpu", handler.HandleCPUStats)
+		},
+	}, nil
+}
@@ -0,0 +1,37 @@
+package custommetrics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidToken   = errors.New("invalid custom metrics token")
+	ErrTokenNotFound  = errors.New("custom metrics token not found")
+	ErrHostNotAllowed = errors.New("token may not write to this host")
+)
+
+// ValidationError reports the first invalid sample of a request; nothing is stored.
+type ValidationError struct {
+	Index  int
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Index < 0 {
+		return e.Reason
+	}
+	return fmt.Sprintf("sample %d: %s", e.Index, e.Reason)
+}
+
+// QuotaError is returned when a write would exceed the token's quota; nothing is stored.
+type QuotaError struct {
+	QuotaPerMinute int
+	// RetryAfter is when the write would fit; zero when the request alone is larger than the quota
+	RetryAfter time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("write quota of %d samples per minute exceeded", e.QuotaPerMinute)
+}
@@ -0,0 +1,49 @@
+package custommetrics
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// quotas holds one token bucket per ingest token: quotaPerMinute samples refill over a minute
+// and a full minute's worth may be written at once. Buckets live in memory and start full.
+type quotas struct {
+	mu      sync.Mutex
+	buckets map[uint]*rate.Limiter
+}
+
+func newQuotas() *quotas {
+	return &quotas{buckets: make(map[uint]*rate.Limiter)}
+}
+
+// take consumes n samples from the token's bucket. When the bucket cannot cover n it consumes
+// nothing and returns how long until it can (0 when n exceeds the quota itself).
+func (q *quotas) take(tokenID uint, quotaPerMinute, n int, now time.Time) (ok bool, retryAfter time.Duration) {
+	q.mu.Lock()
+	lim, found := q.buckets[tokenID]
+	if !found || lim.Burst() != quotaPerMinute {
+		lim = rate.NewLimiter(rate.Limit(float64(quotaPerMinute)/60), quotaPerMinute)
+		q.buckets[tokenID] = lim
+	}
+	q.mu.Unlock()
+
+	r := lim.ReserveN(now, n)
+	if !r.OK() {
+		return false, 0
+	}
+	if d := r.DelayFrom(now); d > 0 {
+		r.CancelAt(now)
+		return false, time.Duration(math.Ceil(d.Seconds())) * time.Second
+	}
+	return true, 0
+}
+
+// forget drops the bucket of a deleted token.
+func (q *quotas) forget(tokenID uint) {
+	q.mu.Lock()
+	delete(q.buckets, tokenID)
+	q.mu.Unlock()
+}
@@ -0,0 +1,202 @@
+package custommetrics
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/modules/custom/infrastructure/entities"
+	"system-stats/internal/modules/custom/infrastructure/repositories"
+)
+
+const (
+	// DefaultQuotaPerMinute applies to tokens created without a quota
+	DefaultQuotaPerMinute = 6000
+	// MaxSamplesPerRequest bounds one ingest request
+	MaxSamplesPerRequest = 1000
+	MaxLabels            = 10
+	maxLabelValue        = 128
+	// Samples may be backfilled up to maxSampleAge and may be at most maxSampleSkew in the future
+	maxSampleAge  = 24 * time.Hour
+	maxSampleSkew = 5 * time.Minute
+	// lastUsedPrecision limits token last_used_at writes to one per token per minute
+	lastUsedPrecision = time.Minute
+)
+
+var (
+	metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:.]{0,127}$`)
+	labelKeyPattern   = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+)
+
+type Service interface {
+	// Authenticate returns the token matching a plaintext ingest token, or ErrInvalidToken.
+	Authenticate(ctx context.Context, plaintext string) (*entities.CustomMetricToken, error)
+	// Ingest validates samples, charges them to the token's quota and stores them for hostId.
+	// It returns a *ValidationError, a *QuotaError or ErrHostNotAllowed without storing anything.
+	Ingest(ctx context.Context, token *entities.CustomMetricToken, hostId uint, samples []entities.Sample) error
+	ListSeries(ctx context.Context, hostId uint, name string, match []entities.LabelMatcher) ([]entities.Series, error)
+	QuerySeries(ctx context.Context, hostId uint, hours float64, name string, match []entities.LabelMatcher) ([]entities.Series, error)
+
+	// CreateToken returns the stored token and its plaintext, which is not kept and shown only once.
+	CreateToken(ctx context.Context, createdBy uint, name string, hostId *uint, quotaPerMinute int) (entities.CustomMetricToken, string, error)
+	ListTokens(ctx context.Context) ([]entities.CustomMetricToken, error)
+	// DeleteToken revokes a token immediately; ErrTokenNotFound when it does not exist.
+	DeleteToken(ctx context.Context, id uint) error
+}
+
+type service struct {
+	logger *log.Logger
+	repo   repositories.CustomMetricRepository
+	tokens repositories.TokenRepository
+	quotas *quotas
+}
+
+func NewService(logger *log.Logger, repo repositories.CustomMetricRepository, tokens repositories.TokenRepository) Service {
+	return &service{
+		logger: logger,
+		repo:   repo,
+		tokens: tokens,
+		quotas: newQuotas(),
+	}
+}
+
+func hashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *service) Authenticate(ctx context.Context, plaintext string) (*entities.CustomMetricToken, error) {
+	if plaintext == "" {
+		return nil, ErrInvalidToken
+	}
+	t, err := s.tokens.FindByTokenHash(ctx, hashToken(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrInvalidToken
+	}
+	return t, nil
+}
+
+func (s *service) Ingest(ctx context.Context, token *entities.CustomMetricToken, hostId uint, samples []entities.Sample) error {
+	if token.HostID != nil && *token.HostID != hostId {
+		return ErrHostNotAllowed
+	}
+	now := time.Now().UTC()
+	if err := ValidateSamples(samples, now); err != nil {
+		return err
+	}
+	if ok, retry := s.quotas.take(token.ID, token.QuotaPerMinute, len(samples), now); !ok {
+		return &QuotaError{QuotaPerMinute: token.QuotaPerMinute, RetryAfter: retry}
+	}
+	if err := s.repo.SaveSamples(ctx, hostId, samples, now); err != nil {
+		s.logger.Error("Failed to save custom metrics", "error", err, "host_id", hostId, "token_id", token.ID)
+		return err
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedPrecision {
+		if err := s.tokens.TouchLastUsed(ctx, token.ID, now); err != nil {
+			s.logger.Warn("Failed to update custom metrics token last use", "token_id", token.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// ValidateSamples checks names, labels, values and timestamps of one ingest request.
+func ValidateSamples(samples []entities.Sample, now time.Time) error {
+	if len(samples) == 0 {
+		return &ValidationError{Index: -1, Reason: "samples must not be empty"}
+	}
+	if len(samples) > MaxSamplesPerRequest {
+		return &ValidationError{Index: -1, Reason: fmt.Sprintf("at most %d samples per request", MaxSamplesPerRequest)}
+	}
+	for i, sm := range samples {
+		if !metricNamePattern.MatchString(sm.Name) {
+			return &ValidationError{Index: i, Reason: fmt.Sprintf("name %q must match %s", sm.Name, metricNamePattern)}
+		}
+		if math.IsNaN(sm.Value) || math.IsInf(sm.Value, 0) {
+			return &ValidationError{Index: i, Reason: "value must be finite"}
+		}
+		if len(sm.Labels) > MaxLabels {
+			return &ValidationError{Index: i, Reason: fmt.Sprintf("at most %d labels", MaxLabels)}
+		}
+		for k, v := range sm.Labels {
+			if !labelKeyPattern.MatchString(k) {
+				return &ValidationError{Index: i, Reason: fmt.Sprintf("label %q must match %s", k, labelKeyPattern)}
+			}
+			if len(v) > maxLabelValue {
+				return &ValidationError{Index: i, Reason: fmt.Sprintf("label %s: value longer than %d bytes", k, maxLabelValue)}
+			}
+		}
+		if sm.Timestamp != nil {
+			if sm.Timestamp.Before(now.Add(-maxSampleAge)) || sm.Timestamp.After(now.Add(maxSampleSkew)) {
+				return &ValidationError{Index: i, Reason: fmt.Sprintf("timestamp must be within the last %s and at most %s ahead", maxSampleAge, maxSampleSkew)}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *service) ListSeries(ctx context.Context, hostId uint, name string, match []entities.LabelMatcher) ([]entities.Series, error) {
+	return s.repo.ListSeries(ctx, hostId, name, match)
+}
+
+func (s *service) QuerySeries(ctx context.Context, hostId uint, hours float64, name string, match []entities.LabelMatcher) ([]entities.Series, error) {
+	return s.repo.QuerySeries(ctx, hostId, hours, name, match)
+}
+
+func (s *service) CreateToken(ctx context.Context, createdBy uint, name string, hostId *uint, quotaPerMinute int) (entities.CustomMetricToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 128 {
+		return entities.CustomMetricToken{}, "", &ValidationError{Index: -1, Reason: "name must be 1-128 characters"}
+	}
+	if quotaPerMinute < 0 {
+		return entities.CustomMetricToken{}, "", &ValidationError{Index: -1, Reason: "quota_per_minute must not be negative"}
+	}
+	if quotaPerMinute == 0 {
+		quotaPerMinute = DefaultQuotaPerMinute
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return entities.CustomMetricToken{}, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	plaintext := hex.EncodeToString(b)
+	t := entities.CustomMetricToken{
+		Name:           name,
+		TokenHash:      hashToken(plaintext),
+		HostID:         hostId,
+		QuotaPerMinute: quotaPerMinute,
+		CreatedBy:      createdBy,
+	}
+	if err := s.tokens.Create(ctx, &t); err != nil {
+		return entities.CustomMetricToken{}, "", fmt.Errorf("failed to create token: %w", err)
+	}
+	s.logger.Info("Custom metrics token created", "token_id", t.ID, "name", t.Name, "created_by", createdBy)
+	return t, plaintext, nil
+}
+
+func (s *service) ListTokens(ctx context.Context) ([]entities.CustomMetricToken, error) {
+	return s.tokens.List(ctx)
+}
+
+func (s *service) DeleteToken(ctx context.Context, id uint) error {
+	found, err := s.tokens.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrTokenNotFound
+	}
+	s.quotas.forget(id)
+	s.logger.Info("Custom metrics token deleted", "token_id", id)
+	return nil
+}
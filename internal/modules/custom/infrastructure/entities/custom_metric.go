@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CustomMetricSeries is one named series of a host, identified by its name and label set.
+type CustomMetricSeries struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	HostID uint   `json:"host_id" gorm:"not null;uniqueIndex:idx_custom_series_key,priority:1"`
+	Name   string `json:"name" gorm:"size:128;not null;uniqueIndex:idx_custom_series_key,priority:2"`
+	// Key is the canonical k="v" list sorted by key ("" when unlabelled)
+	Key string `json:"-" gorm:"size:2048;not null;uniqueIndex:idx_custom_series_key,priority:3"`
+	// Timestamp and Value are the newest sample; series not written within the retention window age out
+	Timestamp time.Time `json:"last_timestamp" gorm:"index"`
+	Value     float64   `json:"last_value"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (CustomMetricSeries) TableName() string { return "custom_metric_series" }
+
+// CustomMetricLabel indexes one label of a series so series can be matched by label.
+type CustomMetricLabel struct {
+	SeriesID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Key      string `gorm:"primaryKey;size:64;index:idx_custom_label_kv,priority:1"`
+	Value    string `gorm:"size:128;not null;index:idx_custom_label_kv,priority:2"`
+
+	Series CustomMetricSeries `gorm:"foreignKey:SeriesID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (CustomMetricLabel) TableName() string { return "custom_metric_labels" }
+
+// CustomMetric is one stored sample of a series.
+type CustomMetric struct {
+	SeriesID  uint      `gorm:"primaryKey;autoIncrement:false"`
+	Timestamp time.Time `gorm:"primaryKey;index"`
+	Value     float64
+
+	Series CustomMetricSeries `gorm:"foreignKey:SeriesID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (CustomMetric) TableName() string { return "custom_metrics" }
+
+// CustomMetricToken authorizes a client to write samples. Only the SHA-256 of the token is stored.
+type CustomMetricToken struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	Name      string `json:"name" gorm:"size:128;not null"`
+	TokenHash string `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	// HostID restricts writes to one host; nil allows any host
+	HostID *uint `json:"host_id"`
+	// QuotaPerMinute is the number of samples the token may write per minute
+	QuotaPerMinute int        `json:"quota_per_minute" gorm:"not null"`
+	CreatedBy      uint       `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (CustomMetricToken) TableName() string { return "custom_metric_tokens" }
+
+// Sample is one posted value.
+type Sample struct {
+	Name   string            `json:"name"`
+	Value  float64           `json:"value"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// Timestamp defaults to the time the request is received
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// LabelMatcher selects series having Key=Value.
+type LabelMatcher struct {
+	Key   string
+	Value string
+}
+
+// Series is a series returned by list and query endpoints.
+type Series struct {
+	ID            uint              `json:"id"`
+	Name          string            `json:"name"`
+	Labels        map[string]string `json:"labels"`
+	LastTimestamp time.Time         `json:"last_timestamp"`
+	LastValue     float64           `json:"last_value"`
+	Points        []Point           `json:"points,omitempty"`
+}
+
+// Point is one sample of a queried series.
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// SeriesKey renders labels as k="v" pairs sorted by key, the form that identifies a series.
+func SeriesKey(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + strconv.Quote(labels[k])
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,211 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/modules/custom/infrastructure/entities"
+)
+
+type CustomMetricRepository interface {
+	// SaveSamples stores samples of one host, creating their series and label index entries.
+	// A second sample for the same series and timestamp replaces the first.
+	SaveSamples(ctx context.Context, hostId uint, samples []entities.Sample, now time.Time) error
+	// ListSeries returns the series of a host, optionally limited to one name and to series having every label.
+	ListSeries(ctx context.Context, hostId uint, name string, match []entities.LabelMatcher) ([]entities.Series, error)
+	// QuerySeries is ListSeries with the samples of the last hours in time order.
+	QuerySeries(ctx context.Context, hostId uint, hours float64, name string, match []entities.LabelMatcher) ([]entities.Series, error)
+}
+
+type customMetricRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomMetricRepository(db *gorm.DB) CustomMetricRepository {
+	return &customMetricRepository{db: db}
+}
+
+func (r *customMetricRepository) SaveSamples(ctx context.Context, hostId uint, samples []entities.Sample, now time.Time) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		series := make(map[[2]string]*entities.CustomMetricSeries)
+		rows := make([]entities.CustomMetric, 0, len(samples))
+		for _, s := range samples {
+			ts := now
+			if s.Timestamp != nil {
+				ts = *s.Timestamp
+			}
+			ts = ts.UTC().Truncate(time.Microsecond)
+
+			key := [2]string{s.Name, entities.SeriesKey(s.Labels)}
+			sr, ok := series[key]
+			if !ok {
+				var err error
+				if sr, err = r.ensureSeries(tx, hostId, s.Name, key[1], s.Labels); err != nil {
+					return err
+				}
+				series[key] = sr
+			}
+			if !ts.Before(sr.Timestamp) {
+				sr.Timestamp, sr.Value = ts, s.Value
+			}
+			rows = append(rows, entities.CustomMetric{SeriesID: sr.ID, Timestamp: ts, Value: s.Value})
+		}
+
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "series_id"}, {Name: "timestamp"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value"}),
+		}).CreateInBatches(dedupeSamples(rows), 500).Error; err != nil {
+			return err
+		}
+		for _, sr := range series {
+			// Only move the newest sample forward; backfilled samples leave it alone
+			if err := tx.Model(&entities.CustomMetricSeries{}).
+				Where("id = ? AND timestamp <= ?", sr.ID, sr.Timestamp).
+				Updates(map[string]interface{}{"timestamp": sr.Timestamp, "value": sr.Value}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ensureSeries returns the series row, creating it with its label index on first use. A concurrent
+// writer creating the same series is tolerated: the insert is skipped and the row read back.
+func (r *customMetricRepository) ensureSeries(tx *gorm.DB, hostId uint, name, key string, labels map[string]string) (*entities.CustomMetricSeries, error) {
+	var sr entities.CustomMetricSeries
+	err := tx.Where("host_id = ? AND name = ? AND key = ?", hostId, name, key).First(&sr).Error
+	if err == nil {
+		return &sr, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	sr = entities.CustomMetricSeries{HostID: hostId, Name: name, Key: key}
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sr)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		if err := tx.Where("host_id = ? AND name = ? AND key = ?", hostId, name, key).First(&sr).Error; err != nil {
+			return nil, err
+		}
+		return &sr, nil
+	}
+	if len(labels) > 0 {
+		rows := make([]entities.CustomMetricLabel, 0, len(labels))
+		for k, v := range labels {
+			rows = append(rows, entities.CustomMetricLabel{SeriesID: sr.ID, Key: k, Value: v})
+		}
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &sr, nil
+}
+
+// dedupeSamples keeps the last sample per (series, timestamp); one INSERT cannot touch a row twice.
+func dedupeSamples(rows []entities.CustomMetric) []entities.CustomMetric {
+	type key struct {
+		id uint
+		ts time.Time
+	}
+	index := make(map[key]int, len(rows))
+	out := rows[:0]
+	for _, row := range rows {
+		k := key{row.SeriesID, row.Timestamp}
+		if i, ok := index[k]; ok {
+			out[i] = row
+			continue
+		}
+		index[k] = len(out)
+		out = append(out, row)
+	}
+	return out
+}
+
+func (r *customMetricRepository) ListSeries(ctx context.Context, hostId uint, name string, match []entities.LabelMatcher) ([]entities.Series, error) {
+	db := r.db.WithContext(ctx)
+	q := db.Where("host_id = ?", hostId)
+	if name != "" {
+		q = q.Where("name = ?", name)
+	}
+	for _, m := range match {
+		q = q.Where("id IN (?)", db.Model(&entities.CustomMetricLabel{}).Select("series_id").Where("key = ? AND value = ?", m.Key, m.Value))
+	}
+	var rows []entities.CustomMetricSeries
+	if err := q.Order("name ASC, key ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []entities.Series{}, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var labels []entities.CustomMetricLabel
+	if err := db.Where("series_id IN ?", ids).Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]map[string]string, len(rows))
+	for _, l := range labels {
+		if byID[l.SeriesID] == nil {
+			byID[l.SeriesID] = make(map[string]string)
+		}
+		byID[l.SeriesID][l.Key] = l.Value
+	}
+
+	out := make([]entities.Series, len(rows))
+	for i, row := range rows {
+		ls := byID[row.ID]
+		if ls == nil {
+			ls = map[string]string{}
+		}
+		out[i] = entities.Series{ID: row.ID, Name: row.Name, Labels: ls, LastTimestamp: row.Timestamp, LastValue: row.Value}
+	}
+	return out, nil
+}
+
+func (r *customMetricRepository) QuerySeries(ctx context.Context, hostId uint, hours float64, name string, match []entities.LabelMatcher) ([]entities.Series, error) {
+	series, err := r.ListSeries(ctx, hostId, name, match)
+	if err != nil || len(series) == 0 {
+		return series, err
+	}
+	ids := make([]uint, len(series))
+	index := make(map[uint]int, len(series))
+	for i, s := range series {
+		ids[i] = s.ID
+		index[s.ID] = i
+	}
+
+	cutoff := time.Now().UTC().Add(-time.Duration(hours * float64(time.Hour)))
+	var rows []entities.CustomMetric
+	if err := r.db.WithContext(ctx).
+		Where("series_id IN ? AND timestamp >= ?", ids, cutoff).
+		Order("timestamp ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		s := &series[index[row.SeriesID]]
+		s.Points = append(s.Points, entities.Point{Timestamp: row.Timestamp, Value: row.Value})
+	}
+
+	// Series without samples in the window are left out
+	out := series[:0]
+	for _, s := range series {
+		if len(s.Points) > 0 {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
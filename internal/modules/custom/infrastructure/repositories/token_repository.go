@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"system-stats/internal/modules/custom/infrastructure/entities"
+)
+
+type TokenRepository interface {
+	Create(ctx context.Context, t *entities.CustomMetricToken) error
+	List(ctx context.Context) ([]entities.CustomMetricToken, error)
+	// Delete removes a token; it reports false when no token had that id.
+	Delete(ctx context.Context, id uint) (bool, error)
+	// FindByTokenHash returns nil when no token matches.
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entities.CustomMetricToken, error)
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) Create(ctx context.Context, t *entities.CustomMetricToken) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
+
+func (r *tokenRepository) List(ctx context.Context) ([]entities.CustomMetricToken, error) {
+	tokens := []entities.CustomMetricToken{}
+	err := r.db.WithContext(ctx).Order("id ASC").Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *tokenRepository) Delete(ctx context.Context, id uint) (bool, error) {
+	res := r.db.WithContext(ctx).Delete(&entities.CustomMetricToken{}, id)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *tokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entities.CustomMetricToken, error) {
+	var t entities.CustomMetricToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&t).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *tokenRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.CustomMetricToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
@@ -0,0 +1,72 @@
+// Package custom registers the custom metrics module: applications post named samples with
+// labels through a token-authenticated endpoint and query them next to host metrics.
+package custom
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/middleware"
+	"system-stats/internal/app/registry"
+	custommetrics "system-stats/internal/modules/custom/application"
+	"system-stats/internal/modules/custom/infrastructure/entities"
+	"system-stats/internal/modules/custom/infrastructure/repositories"
+	handlers "system-stats/internal/modules/custom/presentation"
+)
+
+// Module stores pushed custom metrics; it has no collector.
+type Module struct{}
+
+func (Module) Name() string { return "custom" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 5,
+		Name:    "custom_metrics_tables",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(
+				&entities.CustomMetricSeries{},
+				&entities.CustomMetricLabel{},
+				&entities.CustomMetric{},
+				&entities.CustomMetricToken{},
+			)
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(
+				&entities.CustomMetricToken{},
+				&entities.CustomMetric{},
+				&entities.CustomMetricLabel{},
+				&entities.CustomMetricSeries{},
+			)
+		}},
+	}}
+}
+
+// RetentionTables prunes samples first, then series whose newest sample is past retention
+// (their label index rows cascade).
+func (Module) RetentionTables() []string { return []string{"custom_metrics", "custom_metric_series"} }
+
+// HostTables deletes series (samples and label index rows cascade) and the tokens bound to the host.
+func (Module) HostTables() []string { return []string{"custom_metric_series", "custom_metric_tokens"} }
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := custommetrics.NewService(
+		deps.Logger,
+		repositories.NewCustomMetricRepository(deps.DB),
+		repositories.NewTokenRepository(deps.DB),
+	)
+	handler := handlers.NewCustomMetricsHandler(deps.Logger, service, deps.Hosts)
+	return registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/custom-metrics", handler.HandleQuery)
+			r.GET("/custom-metrics/series", handler.HandleListSeries)
+			r.GET("/custom-metrics/tokens", middleware.RequireAdmin(), handler.HandleListTokens)
+			r.POST("/custom-metrics/tokens", middleware.RequireAdmin(), handler.HandleCreateToken)
+			r.DELETE("/custom-metrics/tokens/:id", middleware.RequireAdmin(), handler.HandleDeleteToken)
+		},
+		PublicRoutes: func(r gin.IRoutes) {
+			r.POST("/custom-metrics/ingest", handler.AuthIngestToken, handler.HandleIngest)
+		},
+	}, nil
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/apperror"
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	custommetrics "system-stats/internal/modules/custom/application"
+	"system-stats/internal/modules/custom/infrastructure/entities"
+	hostservice "system-stats/internal/modules/hosts/application"
+)
+
+// tokenKey is the gin context key of the authenticated ingest token.
+const tokenKey = "customMetricsToken"
+
+// CustomMetricsHandler handles custom metrics ingestion, queries and token management.
+type CustomMetricsHandler struct {
+	logger  *log.Logger
+	service custommetrics.Service
+	hosts   hostservice.Service
+}
+
+// NewCustomMetricsHandler creates a new HTTP handler for custom metrics endpoints.
+func NewCustomMetricsHandler(logger *log.Logger, service custommetrics.Service, hosts hostservice.Service) *CustomMetricsHandler {
+	return &CustomMetricsHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// AuthIngestToken validates the custom metrics token in Authorization: Bearer {token}.
+func (h *CustomMetricsHandler) AuthIngestToken(c *gin.Context) {
+	plaintext, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || plaintext == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"code":  "unauthorized",
+			"error": "Custom metrics token required",
+		})
+		return
+	}
+	token, err := h.service.Authenticate(c.Request.Context(), plaintext)
+	if err != nil {
+		if !errors.Is(err, custommetrics.ErrInvalidToken) {
+			h.logger.Error("Failed to validate custom metrics token", "error", err)
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"code":  "unauthorized",
+			"error": "Invalid custom metrics token",
+		})
+		return
+	}
+	c.Set(tokenKey, token)
+	c.Next()
+}
+
+// IngestRequest is the body of a custom metrics write.
+type IngestRequest struct {
+	// HostID is the host the samples belong to (0 = this server, or the token's host when it is bound to one)
+	HostID  uint              `json:"host_id"`
+	Samples []entities.Sample `json:"samples"`
+}
+
+// HandleIngest stores posted samples.
+//
+// @Summary     Write custom metrics
+// @Description Stores named numeric samples with labels for a host. Auth via a custom metrics token; writes count against the token's per-minute quota.
+// @Tags        custom-metrics
+// @Accept      json
+// @Produce     json
+// @Param       body  body  IngestRequest  true  "Samples"
+// @Success     202  {object} map[string]interface{}
+// @Failure     400  {object} map[string]string
+// @Failure     401  {object} map[string]string
+// @Failure     403  {object} map[string]string
+// @Failure     429  {object} map[string]string
+// @Failure     500  {object} map[string]string
+// @Security    BearerAuth
+// @Router      /custom-metrics/ingest [post]
+func (h *CustomMetricsHandler) HandleIngest(c *gin.Context) {
+	token := c.MustGet(tokenKey).(*entities.CustomMetricToken)
+
+	var req IngestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.WithDetail(apperror.BadRequest("validation_error", "Invalid request data"), err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	hostID := req.HostID
+	if hostID == 0 && token.HostID != nil {
+		hostID = *token.HostID
+	} else {
+		effective, err := metricshost.EffectiveHostID(ctx, h.hosts, hostID)
+		if errors.Is(err, metricshost.ErrHostNotFound) {
+			_ = c.Error(apperror.NotFound("host_not_found", "Host not found"))
+			return
+		}
+		if err != nil {
+			_ = c.Error(apperror.Internal("internal_error", err.Error()))
+			return
+		}
+		hostID = effective
+	}
+
+	err := h.service.Ingest(ctx, token, hostID, req.Samples)
+	var validationErr *custommetrics.ValidationError
+	var quotaErr *custommetrics.QuotaError
+	switch {
+	case err == nil:
+		c.JSON(http.StatusAccepted, gin.H{"data": gin.H{"accepted": len(req.Samples), "host_id": hostID}})
+	case errors.As(err, &validationErr):
+		_ = c.Error(apperror.WithDetail(apperror.BadRequest("validation_error", "Invalid samples"), validationErr.Error()))
+	case errors.As(err, &quotaErr):
+		if quotaErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(quotaErr.RetryAfter.Seconds())))
+		}
+		_ = c.Error(&apperror.AppError{Code: "quota_exceeded", Message: quotaErr.Error(), HTTPStatus: http.StatusTooManyRequests})
+	case errors.Is(err, custommetrics.ErrHostNotAllowed):
+		_ = c.Error(apperror.Forbidden("host_not_allowed", "Token may not write to this host"))
+	default:
+		_ = c.Error(apperror.Internal("internal_error", err.Error()))
+	}
+}
+
+// parseLabelMatchers reads repeated label=key=value query parameters.
+func parseLabelMatchers(c *gin.Context) ([]entities.LabelMatcher, bool) {
+	var match []entities.LabelMatcher
+	for _, raw := range c.QueryArray("label") {
+		k, v, ok := strings.Cut(raw, "=")
+		if !ok || k == "" {
+			_ = c.Error(apperror.BadRequest("invalid_label", "label must be key=value"))
+			return nil, false
+		}
+		match = append(match, entities.LabelMatcher{Key: k, Value: v})
+	}
+	return match, true
+}
+
+// HandleListSeries returns the custom metric series of a host with their newest sample.
+//
+// @Summary     List custom metric series
+// @Description Returns the custom metric series of a host (name, labels, last value), optionally filtered by name and labels.
+// @Tags        custom-metrics
+// @Produce     json
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       name     query    string   false  "Metric name"
+// @Param       label    query    []string false  "Label filter key=value (repeatable, all must match)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     400      {object} map[string]string
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /custom-metrics/series [get]
+func (h *CustomMetricsHandler) HandleListSeries(c *gin.Context) {
+	h.handleSeries(c, false)
+}
+
+// HandleQuery returns the samples of matching custom metric series.
+//
+// @Summary     Query custom metrics
+// @Description Returns the samples of the last hours for every matching series of a host. Series without samples in the window are omitted.
+// @Tags        custom-metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       name     query    string   false  "Metric name"
+// @Param       label    query    []string false  "Label filter key=value (repeatable, all must match)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     400      {object} map[string]string
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /custom-metrics [get]
+func (h *CustomMetricsHandler) HandleQuery(c *gin.Context) {
+	h.handleSeries(c, true)
+}
+
+func (h *CustomMetricsHandler) handleSeries(c *gin.Context, withPoints bool) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	match, ok := parseLabelMatchers(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyCustomMetricsPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for custom metrics", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var series []entities.Series
+	if withPoints {
+		series, err = h.service.QuerySeries(ctx, effective, hours, c.Query("name"), match)
+	} else {
+		series, err = h.service.ListSeries(ctx, effective, c.Query("name"), match)
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching custom metrics")
+			return
+		}
+		h.logger.Error("Failed to fetch custom metrics", "error", err, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series": series,
+	})
+}
+
+// CreateTokenRequest is the body for creating a custom metrics token.
+type CreateTokenRequest struct {
+	Name string `json:"name" binding:"required"`
+	// HostID binds the token to one host; omit to allow writes for any host
+	HostID *uint `json:"host_id"`
+	// QuotaPerMinute is the number of samples per minute (default 6000)
+	QuotaPerMinute int `json:"quota_per_minute"`
+}
+
+// HandleCreateToken creates a custom metrics token and returns it once.
+//
+// @Summary     Create custom metrics token
+// @Description Creates a write token for the custom metrics ingest endpoint. The token is only returned in this response. Admin only.
+// @Tags        custom-metrics
+// @Accept      json
+// @Produce     json
+// @Param       body  body  CreateTokenRequest  true  "Token settings"
+// @Success     201  {object} map[string]interface{}
+// @Failure     400  {object} map[string]string
+// @Failure     401  {object} map[string]string
+// @Failure     403  {object} map[string]string
+// @Failure     500  {object} map[string]string
+// @Security    BearerAuth
+// @Router      /custom-metrics/tokens [post]
+func (h *CustomMetricsHandler) HandleCreateToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Authentication required"))
+		return
+	}
+
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.WithDetail(apperror.BadRequest("validation_error", "Invalid request data"), err.Error()))
+		return
+	}
+	ctx := c.Request.Context()
+	if req.HostID != nil {
+		if _, err := h.hosts.GetHostByID(ctx, *req.HostID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				_ = c.Error(apperror.NotFound("host_not_found", "Host not found"))
+				return
+			}
+			_ = c.Error(apperror.Internal("internal_error", err.Error()))
+			return
+		}
+	}
+
+	token, plaintext, err := h.service.CreateToken(ctx, userID.(uint), req.Name, req.HostID, req.QuotaPerMinute)
+	var validationErr *custommetrics.ValidationError
+	if errors.As(err, &validationErr) {
+		_ = c.Error(apperror.WithDetail(apperror.BadRequest("validation_error", "Invalid request data"), validationErr.Error()))
+		return
+	}
+	if err != nil {
+		_ = c.Error(apperror.Internal("internal_error", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": gin.H{
+			"token":    plaintext,
+			"metadata": token,
+		},
+	})
+}
+
+// HandleListTokens lists custom metrics tokens without their secrets.
+//
+// @Summary     List custom metrics tokens
+// @Description Returns every custom metrics token (name, host, quota, last use). Admin only.
+// @Tags        custom-metrics
+// @Produce     json
+// @Success     200  {object} map[string]interface{}
+// @Failure     401  {object} map[string]string
+// @Failure     403  {object} map[string]string
+// @Failure     500  {object} map[string]string
+// @Security    BearerAuth
+// @Router      /custom-metrics/tokens [get]
+func (h *CustomMetricsHandler) HandleListTokens(c *gin.Context) {
+	tokens, err := h.service.ListTokens(c.Request.Context())
+	if err != nil {
+		_ = c.Error(apperror.Internal("internal_error", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tokens})
+}
+
+// HandleDeleteToken revokes a custom metrics token.
+//
+// @Summary     Delete custom metrics token
+// @Description Revokes a custom metrics token; writes with it fail immediately. Stored samples are kept. Admin only.
+// @Tags        custom-metrics
+// @Param       id   path  integer  true  "Token ID"
+// @Success     204  "No Content"
+// @Failure     400  {object} map[string]string
+// @Failure     401  {object} map[string]string
+// @Failure     403  {object} map[string]string
+// @Failure     404  {object} map[string]string
+// @Failure     500  {object} map[string]string
+// @Security    BearerAuth
+// @Router      /custom-metrics/tokens/{id} [delete]
+func (h *CustomMetricsHandler) HandleDeleteToken(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id64 == 0 {
+		_ = c.Error(apperror.BadRequest("invalid_token_id", "Invalid token id"))
+		return
+	}
+	err = h.service.DeleteToken(c.Request.Context(), uint(id64))
+	if errors.Is(err, custommetrics.ErrTokenNotFound) {
+		_ = c.Error(apperror.NotFound("not_found", "Token not found"))
+		return
+	}
+	if err != nil {
+		_ = c.Error(apperror.Internal("internal_error", err.Error()))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -2,11 +2,17 @@ package diskmetrics
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"system-stats/internal/app/metrics"
 	"system-stats/internal/modules/disk/infrastructure/collectors"
 	"system-stats/internal/modules/disk/infrastructure/entities"
 	diskrepos "system-stats/internal/modules/disk/infrastructure/repositories"
+	"system-stats/internal/modules/disk/infrastructure/value_objects"
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
 
 	"github.com/charmbracelet/log"
 )
@@ -18,6 +24,9 @@ type Service interface {
 	GetLatestByHost(ctx context.Context, hostId uint) (*entities.DiskMetric, error)
 	GetHistorical(ctx context.Context, hours float64) ([]entities.HistoricalDiskMetric, error)
 	GetHistoricalByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalDiskMetric, error)
+	GetMountHistoryByHost(ctx context.Context, hostId uint, hours float64, path string) ([]entities.HistoricalDiskMountMetric, error)
+	GetIOHistoryByHost(ctx context.Context, hostId uint, hours float64, device string) ([]entities.HistoricalDiskIOMetric, error)
+	GetNFSHistoryByHost(ctx context.Context, hostId uint, hours float64, mountpoint string) ([]entities.HistoricalDiskNFSMetric, error)
 	CollectAndSave(ctx context.Context, hostId uint) error
 }
 
@@ -29,17 +38,201 @@ func (a *diskCollectorAdapter) Collect(ctx context.Context) (entities.DiskMetric
 	return a.c.CollectDiskMetrics(ctx)
 }
 
+// Mount events: a mount that stops answering statfs is stale (warning) until it answers again
+// or is unmounted (recovered).
+const (
+	EventSource    = "disk"
+	EventStale     = "stale"
+	EventRecovered = "recovered"
+)
+
 type service struct {
 	metrics.Service[entities.DiskMetric, entities.HistoricalDiskMetric]
+	repo          diskrepos.DiskRepository
+	events        eventsservice.Service
+	ioCalculator  *value_objects.DiskIOCalculator
+	nfsCalculator *value_objects.NFSCalculator
+
+	mu sync.Mutex
+	// stale holds the fstype of every mount that was stale in the previous collection
+	stale map[string]string
+	// resolved marks hosts whose stale-mount problems from before this start were checked
+	resolved map[uint]bool
 }
 
-func NewService(logger *log.Logger, diskRepository diskrepos.DiskRepository) Service {
+// NewService creates the disk service; events may be nil to record no mount events.
+func NewService(logger *log.Logger, diskRepository diskrepos.DiskRepository, events eventsservice.Service, mountTimeout time.Duration) Service {
 	return &service{
 		Service: metrics.Service[entities.DiskMetric, entities.HistoricalDiskMetric]{
 			Logger:    logger,
 			Name:      "disk",
-			Collector: &diskCollectorAdapter{c: collectors.NewDiskMetricsCollector(logger)},
+			Collector: &diskCollectorAdapter{c: collectors.NewDiskMetricsCollector(logger, mountTimeout)},
 			Repo:      diskRepository,
 		},
+		repo:          diskRepository,
+		events:        events,
+		ioCalculator:  value_objects.NewDiskIOCalculator(),
+		nfsCalculator: value_objects.NewNFSCalculator(),
+		stale:         make(map[string]string),
+		resolved:      make(map[uint]bool),
+	}
+}
+
+// Collect gathers disk metrics and fills per-device I/O rates from counter deltas.
+func (s *service) Collect(ctx context.Context) (entities.DiskMetric, error) {
+	metric, err := s.Service.Collect(ctx)
+	if err != nil {
+		return entities.DiskMetric{}, err
+	}
+
+	now := time.Now()
+	present := make(map[string]struct{}, len(metric.IOCounters))
+	for i := range metric.IOCounters {
+		io := &metric.IOCounters[i]
+		present[io.Name] = struct{}{}
+		rate := s.ioCalculator.Calculate(io.Name, now, value_objects.DiskIOCounters{
+			ReadCount:  io.ReadCount,
+			WriteCount: io.WriteCount,
+			ReadBytes:  io.ReadBytes,
+			WriteBytes: io.WriteBytes,
+			ReadTime:   io.ReadTime,
+			WriteTime:  io.WriteTime,
+			IoTime:     io.IoTime,
+		})
+		io.ReadIOPS = rate.ReadIOPS
+		io.WriteIOPS = rate.WriteIOPS
+		io.ReadBytesPerSec = rate.ReadBytesPerSec
+		io.WriteBytesPerSec = rate.WriteBytesPerSec
+		io.AvgReadLatencyMs = rate.AvgReadLatencyMs
+		io.AvgWriteLatencyMs = rate.AvgWriteLatencyMs
+		io.UtilPercent = rate.UtilPercent
+		io.RatesValid = rate.Valid
+	}
+	s.ioCalculator.Forget(present)
+
+	mounted := make(map[string]struct{}, len(metric.NFSMounts))
+	for i := range metric.NFSMounts {
+		m := &metric.NFSMounts[i]
+		mounted[m.Mountpoint] = struct{}{}
+		rate := s.nfsCalculator.Calculate(m.Mountpoint, now, value_objects.NFSCounters{
+			Ops:             m.Ops,
+			Retransmissions: m.Retransmissions,
+			RTTMs:           m.RTTMs,
+		})
+		m.OpsPerSec = rate.OpsPerSec
+		m.RetransPerSec = rate.RetransPerSec
+		m.AvgRTTMs = rate.AvgRTTMs
+	}
+	s.nfsCalculator.Forget(mounted)
+
+	metric.Events = s.mountEvents(now.UTC(), metric.Mounts)
+	return metric, nil
+}
+
+// mountEvents compares the stale mounts with the previous collection: a mount turning stale is a
+// warning, one answering again or unmounted is recovered.
+func (s *service) mountEvents(now time.Time, mounts []entities.UsageStat) []evententities.HostEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stale := make(map[string]string)
+	var events []evententities.HostEvent
+	for _, m := range mounts {
+		if !m.Stale {
+			continue
+		}
+		stale[m.Path] = m.Fstype
+		if _, was := s.stale[m.Path]; !was {
+			events = append(events, evententities.HostEvent{
+				Timestamp: now, Source: EventSource, Subject: m.Path, Kind: EventStale, Severity: evententities.SeverityWarning,
+				Message: fmt.Sprintf("%s mount %s did not answer statfs in time", m.Fstype, m.Path),
+			})
+		}
+	}
+	for path, fstype := range s.stale {
+		if _, still := stale[path]; !still {
+			events = append(events, evententities.HostEvent{
+				Timestamp: now, Source: EventSource, Subject: path, Kind: EventRecovered, Severity: evententities.SeverityOK,
+				Message: fmt.Sprintf("%s mount %s answers again or was unmounted", fstype, path),
+			})
+		}
+	}
+	s.stale = stale
+	return events
+}
+
+// Save stores the metric and records its mount events. The first save for a host also resolves
+// stale-mount problems left open by a previous run for mounts that are no longer stale.
+func (s *service) Save(ctx context.Context, metric entities.DiskMetric, hostId uint) error {
+	if err := s.Service.Save(ctx, metric, hostId); err != nil {
+		return err
+	}
+	if s.events == nil {
+		return nil
+	}
+	events := metric.Events
+	s.mu.Lock()
+	resolved := s.resolved[hostId]
+	s.resolved[hostId] = true
+	s.mu.Unlock()
+	if !resolved {
+		problems, err := s.events.Problems(ctx, hostId)
+		if err != nil {
+			return err
+		}
+		stale := make(map[string]bool)
+		for _, m := range metric.Mounts {
+			stale[m.Path] = m.Stale
+		}
+		now := time.Now().UTC()
+		for _, p := range problems {
+			if p.Source == EventSource && p.Kind == EventStale && !stale[p.Subject] {
+				events = append(events, evententities.HostEvent{
+					Timestamp: now, Source: EventSource, Subject: p.Subject, Kind: EventRecovered, Severity: evententities.SeverityOK,
+					Message: fmt.Sprintf("Mount %s answers again or was unmounted", p.Subject),
+				})
+			}
+		}
+	}
+	for _, e := range events {
+		if e.Kind == EventStale {
+			s.Logger.Warn("Mount is stale", "mount", e.Subject, "host_id", hostId)
+		}
+	}
+	return s.events.Record(ctx, hostId, events)
+}
+
+func (s *service) GetMountHistoryByHost(ctx context.Context, hostId uint, hours float64, path string) ([]entities.HistoricalDiskMountMetric, error) {
+	rows, err := s.repo.GetMountHistoryByHost(ctx, hostId, hours, path)
+	if err != nil {
+		s.Logger.Error("Failed to get disk mount history", "error", err, "host_id", hostId, "hours", hours, "path", path)
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *service) GetIOHistoryByHost(ctx context.Context, hostId uint, hours float64, device string) ([]entities.HistoricalDiskIOMetric, error) {
+	rows, err := s.repo.GetIOHistoryByHost(ctx, hostId, hours, device)
+	if err != nil {
+		s.Logger.Error("Failed to get disk I/O history", "error", err, "host_id", hostId, "hours", hours, "device", device)
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *service) GetNFSHistoryByHost(ctx context.Context, hostId uint, hours float64, mountpoint string) ([]entities.HistoricalDiskNFSMetric, error) {
+	rows, err := s.repo.GetNFSHistoryByHost(ctx, hostId, hours, mountpoint)
+	if err != nil {
+		s.Logger.Error("Failed to get NFS mount history", "error", err, "host_id", hostId, "hours", hours, "mountpoint", mountpoint)
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CollectAndSave overrides the embedded method to use the rate-enriched Collect.
+func (s *service) CollectAndSave(ctx context.Context, hostId uint) error {
+	metric, err := s.Collect(ctx)
+	if err != nil {
+		return err
 	}
+	return s.Save(ctx, metric, hostId)
 }
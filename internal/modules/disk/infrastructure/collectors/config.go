@@ -0,0 +1,28 @@
+package collectors
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// MountTimeoutEnv sets how long a mount may take to answer statfs before it is flagged stale
+	MountTimeoutEnv = "DISK_MOUNT_TIMEOUT"
+	// DefaultMountTimeout is long enough for a busy local disk, short enough not to stall a cycle
+	DefaultMountTimeout = 2 * time.Second
+)
+
+// MountTimeoutFromEnv reads DISK_MOUNT_TIMEOUT as a Go duration (default DefaultMountTimeout).
+func MountTimeoutFromEnv() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(MountTimeoutEnv))
+	if raw == "" {
+		return DefaultMountTimeout, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration like \"5s\", got %q", MountTimeoutEnv, raw)
+	}
+	return d, nil
+}
@@ -2,9 +2,11 @@ package collectors
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/shirou/gopsutil/v4/disk"
@@ -17,12 +19,13 @@ import (
  // system monitoring libraries (gopsutil).
 type DiskMetricsCollector struct {
 	logger *log.Logger
+	prober *MountProber
 }
 
  // NewDiskMetricsCollector creates a new disk metrics collector instance.
- // This constructor initializes the collector for gathering disk statistics.
-func NewDiskMetricsCollector(logger *log.Logger) *DiskMetricsCollector {
-	return &DiskMetricsCollector{logger: logger}
+ // A mount that does not answer statfs within mountTimeout is reported stale.
+func NewDiskMetricsCollector(logger *log.Logger, mountTimeout time.Duration) *DiskMetricsCollector {
+	return &DiskMetricsCollector{logger: logger, prober: NewMountProber(mountTimeout, nil)}
 }
 
 func hostDiskRoot() string {
@@ -81,14 +84,14 @@ func (c *DiskMetricsCollector) CollectDiskMetrics(ctx context.Context) (entities
 
 	// Prefer host bind-mount (e.g. Docker /:/host:ro) so totals match the real machine, not the container overlay.
 	if hr := hostDiskRoot(); hr != "" {
-		if ru, rerr := disk.UsageWithContext(ctx, hr); rerr == nil && ru != nil && ru.Total > 0 {
+		if ru, rerr := c.prober.Usage(ctx, hr); rerr == nil && ru != nil && ru.Total > 0 {
 			primary = ru
 			hostPrimary = true
 			c.logger.Debug("Disk primary from host root bind", "path", hr)
 		}
 	}
 	if primary == nil {
-		if ru, rerr := disk.UsageWithContext(ctx, "/"); rerr == nil && ru != nil {
+		if ru, rerr := c.prober.Usage(ctx, "/"); rerr == nil && ru != nil {
 			if ru.Total > 0 { // accept even if fstype is empty
 				primary = ru
 			}
@@ -99,7 +102,15 @@ func (c *DiskMetricsCollector) CollectDiskMetrics(ctx context.Context) (entities
 	// already use the host bind-mount for primary; expose a single row for host root instead.
 	if !hostPrimary {
 		for _, p := range parts {
-			u, uerr := disk.UsageWithContext(ctx, p.Mountpoint)
+			u, uerr := c.prober.Usage(ctx, p.Mountpoint)
+			if errors.Is(uerr, ErrMountStale) {
+				c.logger.Warn("Mount did not answer statfs in time", "mount", p.Mountpoint, "fstype", p.Fstype, "device", p.Device)
+				mounts = append(mounts, entities.UsageStat{Path: p.Mountpoint, Fstype: p.Fstype, Stale: true})
+				continue
+			}
+			if ctx.Err() != nil {
+				break
+			}
 			if uerr != nil {
 				// In Docker/containers many host mounts are inaccessible — skip silently
 				if strings.Contains(uerr.Error(), "no such file or directory") {
@@ -183,6 +194,11 @@ func (c *DiskMetricsCollector) CollectDiskMetrics(ctx context.Context) (entities
 		})
 	}
 
+	nfsMounts, err := ReadNFSMounts()
+	if err != nil {
+		c.logger.Warn("Failed to read NFS mount statistics", "error", err)
+	}
+
 	var usagePercent float64
 	if total > 0 {
 		usagePercent = (float64(used) / float64(total)) * 100.0
@@ -197,6 +213,7 @@ func (c *DiskMetricsCollector) CollectDiskMetrics(ctx context.Context) (entities
 		Partitions:   partitions,
 		Mounts:       mounts,
 		IOCounters:   ioCounters,
+		NFSMounts:    nfsMounts,
 	}, nil
 }
 
@@ -0,0 +1,86 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// ErrMountStale is returned for a mount that did not answer statfs within the timeout, or whose
+// previous statfs has still not returned.
+var ErrMountStale = errors.New("mount did not answer statfs in time")
+
+// UsageFunc reads the usage of a mountpoint (disk.UsageWithContext).
+type UsageFunc func(ctx context.Context, path string) (*disk.UsageStat, error)
+
+// MountProber reads mount usage without letting a hung filesystem block the collection. statfs
+// on a hung NFS or CIFS mount blocks in the kernel and cannot be cancelled, so every call runs in
+// its own goroutine; a call that outlives the timeout is left running, and the mount is reported
+// stale without a new call until it returns. This bounds the blocked goroutines to one per
+// mount.
+type MountProber struct {
+	timeout time.Duration
+	usage   UsageFunc
+
+	mu      sync.Mutex
+	pending map[string]*mountProbe
+}
+
+type mountProbe struct {
+	done  chan struct{}
+	usage *disk.UsageStat
+	err   error
+}
+
+// NewMountProber creates a prober; usage defaults to disk.UsageWithContext.
+func NewMountProber(timeout time.Duration, usage UsageFunc) *MountProber {
+	if usage == nil {
+		usage = disk.UsageWithContext
+	}
+	return &MountProber{timeout: timeout, usage: usage, pending: make(map[string]*mountProbe)}
+}
+
+// Usage returns the usage of path, ErrMountStale when it does not answer within the timeout, or
+// the context's error when the collection is cancelled first.
+func (p *MountProber) Usage(ctx context.Context, path string) (*disk.UsageStat, error) {
+	p.mu.Lock()
+	if prev, ok := p.pending[path]; ok {
+		select {
+		case <-prev.done:
+			// The hung call returned; the mount answers again, so probe it afresh
+			delete(p.pending, path)
+		default:
+			p.mu.Unlock()
+			return nil, ErrMountStale
+		}
+	}
+	probe := &mountProbe{done: make(chan struct{})}
+	p.pending[path] = probe
+	p.mu.Unlock()
+
+	go func() {
+		// Detached from ctx: the call cannot be interrupted, and its completion is what marks
+		// the mount as answering again
+		probe.usage, probe.err = p.usage(context.Background(), path)
+		close(probe.done)
+	}()
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	select {
+	case <-probe.done:
+		p.mu.Lock()
+		if p.pending[path] == probe {
+			delete(p.pending, path)
+		}
+		p.mu.Unlock()
+		return probe.usage, probe.err
+	case <-timer.C:
+		return nil, ErrMountStale
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
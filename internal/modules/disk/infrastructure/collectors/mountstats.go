@@ -0,0 +1,104 @@
+package collectors
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/disk/infrastructure/entities"
+)
+
+// ReadNFSMounts reads the NFS client statistics of every NFS mount from HOST_PROC/self/mountstats;
+// a host without the file has none.
+func ReadNFSMounts() ([]entities.NFSMountStat, error) {
+	f, err := os.Open(hostfs.Proc("self", "mountstats"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseMountstats(f)
+}
+
+// ParseMountstats parses /proc/<pid>/mountstats. Only NFS mounts carry statistics ("statvers=");
+// other mounts, CIFS included, are skipped. Per mount, the RPC per-op lines
+//
+//	READ: ops transmissions major_timeouts bytes_sent bytes_recv queue_ms rtt_ms execute_ms [errors]
+//
+// are summed, and read/write bytes come from the server columns of the "bytes:" line.
+func ParseMountstats(r io.Reader) ([]entities.NFSMountStat, error) {
+	var mounts []entities.NFSMountStat
+	var cur *entities.NFSMountStat
+	inOps := false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "device" {
+			cur, inOps = nil, false
+			// device <export> mounted on <mountpoint> with fstype <type> [statvers=1.1]
+			if len(fields) >= 8 && fields[2] == "mounted" && fields[5] == "with" && strings.HasPrefix(fields[7], "nfs") &&
+				strings.HasPrefix(fields[len(fields)-1], "statvers=") {
+				mounts = append(mounts, entities.NFSMountStat{Export: fields[1], Mountpoint: unescapeMount(fields[4]), Fstype: fields[7]})
+				cur = &mounts[len(mounts)-1]
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		switch {
+		case fields[0] == "bytes:" && len(fields) >= 7:
+			cur.ReadBytes = parseCounter(fields[5])
+			cur.WriteBytes = parseCounter(fields[6])
+		case fields[0] == "per-op":
+			inOps = true
+		case inOps && strings.HasSuffix(fields[0], ":") && len(fields) >= 9:
+			ops := parseCounter(fields[1])
+			trans := parseCounter(fields[2])
+			cur.Ops += ops
+			if trans > ops {
+				cur.Retransmissions += trans - ops
+			}
+			cur.MajorTimeouts += parseCounter(fields[3])
+			cur.RTTMs += parseCounter(fields[7])
+			cur.ExecuteMs += parseCounter(fields[8])
+		}
+	}
+	return mounts, scanner.Err()
+}
+
+func parseCounter(s string) uint64 {
+	v, _ := strconv.ParseUint(s, 10, 64)
+	return v
+}
+
+// unescapeMount decodes the octal escapes (\040 for a space) of paths in mount tables.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"time"
+
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
 )
 
  // DiskMetric represents disk storage utilization metrics.
@@ -28,6 +30,12 @@ type DiskMetric struct {
 
 	// Per-device IO counters
 	IOCounters []IOCounterStat `json:"io_counters" gorm:"-"`
+
+	// NFS client statistics per mount (from mountstats)
+	NFSMounts []NFSMountStat `json:"nfs_mounts" gorm:"-"`
+
+	// Events are the mounts that became stale or recovered in this collection
+	Events []evententities.HostEvent `json:"events,omitempty" gorm:"-"`
 }
 
  // GetTimestamp returns the current time for disk metrics.
@@ -56,6 +64,10 @@ type UsageStat struct {
 	InodesUsed        uint64  `json:"inodes_used"`
 	InodesFree        uint64  `json:"inodes_free"`
 	InodesUsedPercent float64 `json:"inodes_used_percent"`
+
+	// Stale is set when the mount did not answer statfs in time (e.g. a hung NFS server); the
+	// usage fields are zero then
+	Stale bool `json:"stale"`
 }
 
 // IOCounterStat describes IO counters for a block device
@@ -74,4 +86,15 @@ type IOCounterStat struct {
 	WeightedIO       uint64 `json:"weighted_io"`
 	SerialNumber     string `json:"serial_number"`
 	Label            string `json:"label"`
+
+	// Rates derived from counter deltas between two collections; RatesValid is false on the
+	// first sample, after a long gap or after a counter reset, in which case the rates are zero
+	ReadIOPS          float64 `json:"read_iops"`
+	WriteIOPS         float64 `json:"write_iops"`
+	ReadBytesPerSec   float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec  float64 `json:"write_bytes_per_sec"`
+	AvgReadLatencyMs  float64 `json:"avg_read_latency_ms"`
+	AvgWriteLatencyMs float64 `json:"avg_write_latency_ms"`
+	UtilPercent       float64 `json:"util_percent"`
+	RatesValid        bool    `json:"rates_valid"`
 }
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+)
+
+// HistoricalDiskIOMetric stores per-device I/O rates computed between two collections.
+// Raw cumulative counters are not persisted; only the derived rates are useful for charts.
+type HistoricalDiskIOMetric struct {
+	// HostID references the host that recorded this device sample
+	HostID uint `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_disk_io_host_dev_ts,priority:1"`
+
+	// Timestamp indicates when this sample was recorded
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_disk_io_host_dev_ts,priority:3"`
+
+	// Device is the block device name (e.g. "sda", "nvme0n1")
+	Device string `json:"device" gorm:"primaryKey;size:128;index:idx_disk_io_host_dev_ts,priority:2"`
+
+	// ReadIOPS and WriteIOPS are completed operations per second
+	ReadIOPS  float64 `json:"read_iops" gorm:"column:read_iops"`
+	WriteIOPS float64 `json:"write_iops" gorm:"column:write_iops"`
+
+	// ReadBytesPerSec and WriteBytesPerSec are throughput in bytes per second
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec" gorm:"column:read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec" gorm:"column:write_bytes_per_sec"`
+
+	// AvgReadLatencyMs and AvgWriteLatencyMs are the mean time per completed operation
+	AvgReadLatencyMs  float64 `json:"avg_read_latency_ms" gorm:"column:avg_read_latency_ms"`
+	AvgWriteLatencyMs float64 `json:"avg_write_latency_ms" gorm:"column:avg_write_latency_ms"`
+
+	// UtilPercent is the share of wall time the device had I/O in flight (like iostat %util)
+	UtilPercent float64 `json:"util_percent" gorm:"column:util_percent"`
+}
+
+// GetTimestamp returns the timestamp when this device sample was recorded.
+func (h HistoricalDiskIOMetric) GetTimestamp() time.Time { return h.Timestamp }
+
+// GetMetricType returns the metric type identifier for disk I/O metrics.
+func (h HistoricalDiskIOMetric) GetMetricType() string { return "disk_io" }
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalDiskIOMetric) TableName() string { return "disk_io_metrics" }
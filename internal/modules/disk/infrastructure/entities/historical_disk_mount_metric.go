@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+)
+
+// HistoricalDiskMountMetric stores usage of a single mountpoint at a point in time.
+// Rows share the timestamp of the aggregate HistoricalDiskMetric written in the same cycle.
+type HistoricalDiskMountMetric struct {
+	// HostID references the host that recorded this mount sample
+	HostID uint `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_disk_mount_host_path_ts,priority:1"`
+
+	// Timestamp indicates when this sample was recorded
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_disk_mount_host_path_ts,priority:3"`
+
+	// Path is the mountpoint (e.g. "/", "/var/lib/docker")
+	Path string `json:"path" gorm:"primaryKey;size:512;index:idx_disk_mount_host_path_ts,priority:2"`
+
+	// Fstype is the filesystem type of the mount
+	Fstype string `json:"fstype" gorm:"column:fstype"`
+
+	// TotalBytes, UsedBytes and FreeBytes describe space usage in bytes
+	TotalBytes uint64 `json:"total_bytes" gorm:"column:total_bytes"`
+	UsedBytes  uint64 `json:"used_bytes" gorm:"column:used_bytes"`
+	FreeBytes  uint64 `json:"free_bytes" gorm:"column:free_bytes"`
+
+	// UsedPercent shows space utilization as a percentage
+	UsedPercent float64 `json:"used_percent" gorm:"column:used_percent"`
+
+	// Inode counters for the mount
+	InodesTotal       uint64  `json:"inodes_total" gorm:"column:inodes_total"`
+	InodesUsed        uint64  `json:"inodes_used" gorm:"column:inodes_used"`
+	InodesUsedPercent float64 `json:"inodes_used_percent" gorm:"column:inodes_used_percent"`
+
+	// Stale marks a mount that did not answer statfs in time; its usage fields are zero
+	Stale bool `json:"stale" gorm:"column:stale;not null;default:false"`
+}
+
+// GetTimestamp returns the timestamp when this mount sample was recorded.
+func (h HistoricalDiskMountMetric) GetTimestamp() time.Time { return h.Timestamp }
+
+// GetMetricType returns the metric type identifier for per-mount disk metrics.
+func (h HistoricalDiskMountMetric) GetMetricType() string { return "disk_mount" }
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalDiskMountMetric) TableName() string { return "disk_mount_metrics" }
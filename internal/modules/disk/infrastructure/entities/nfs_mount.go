@@ -0,0 +1,52 @@
+package entities
+
+import "time"
+
+// NFSMountStat is the NFS client state of one mount, from mountstats. Counters are cumulative
+// since the mount; the rates are derived between two collections.
+type NFSMountStat struct {
+	Mountpoint string `json:"mountpoint"`
+	// Export is the mounted device, e.g. "server:/export"
+	Export string `json:"export"`
+	Fstype string `json:"fstype"`
+
+	// Ops counts RPC requests; Retransmissions counts transmissions beyond the first of a request
+	Ops             uint64 `json:"ops"`
+	Retransmissions uint64 `json:"retransmissions"`
+	// MajorTimeouts counts requests that hit a major timeout ("server not responding")
+	MajorTimeouts uint64 `json:"major_timeouts"`
+	// RTTMs and ExecuteMs sum the round-trip and total execution times of all requests
+	RTTMs     uint64 `json:"rtt_ms"`
+	ExecuteMs uint64 `json:"execute_ms"`
+	// ReadBytes and WriteBytes are the bytes read from and written to the server
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+
+	OpsPerSec     float64 `json:"ops_per_sec"`
+	RetransPerSec float64 `json:"retrans_per_sec"`
+	// AvgRTTMs is the mean round-trip time of the requests since the previous collection; nil
+	// without requests
+	AvgRTTMs *float64 `json:"avg_rtt_ms"`
+}
+
+// HistoricalDiskNFSMetric stores the NFS client state of one mount at a point in time.
+// Rows share the timestamp of the aggregate HistoricalDiskMetric written in the same cycle.
+type HistoricalDiskNFSMetric struct {
+	HostID     uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_disk_nfs_host_path_ts,priority:1"`
+	Timestamp  time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_disk_nfs_host_path_ts,priority:3"`
+	Mountpoint string    `json:"mountpoint" gorm:"primaryKey;size:512;index:idx_disk_nfs_host_path_ts,priority:2"`
+	Export     string    `json:"export" gorm:"size:512"`
+	Fstype     string    `json:"fstype" gorm:"size:16"`
+
+	Ops             uint64   `json:"ops"`
+	Retransmissions uint64   `json:"retransmissions"`
+	MajorTimeouts   uint64   `json:"major_timeouts"`
+	ReadBytes       uint64   `json:"read_bytes"`
+	WriteBytes      uint64   `json:"write_bytes"`
+	OpsPerSec       float64  `json:"ops_per_sec"`
+	RetransPerSec   float64  `json:"retrans_per_sec"`
+	AvgRTTMs        *float64 `json:"avg_rtt_ms" gorm:"column:avg_rtt_ms"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalDiskNFSMetric) TableName() string { return "disk_nfs_metrics" }
@@ -17,6 +17,12 @@ type DiskRepository interface {
 	GetLatestMetricByHost(ctx context.Context, hostId uint) (*localentities.DiskMetric, error)
 	GetHistoricalMetrics(ctx context.Context, hours float64) ([]localentities.HistoricalDiskMetric, error)
 	GetHistoricalMetricsByHost(ctx context.Context, hostId uint, hours float64) ([]localentities.HistoricalDiskMetric, error)
+	// GetMountHistoryByHost returns per-mount usage rows; an empty path returns every mount.
+	GetMountHistoryByHost(ctx context.Context, hostId uint, hours float64, path string) ([]localentities.HistoricalDiskMountMetric, error)
+	// GetIOHistoryByHost returns per-device I/O rate rows; an empty device returns every device.
+	GetIOHistoryByHost(ctx context.Context, hostId uint, hours float64, device string) ([]localentities.HistoricalDiskIOMetric, error)
+	// GetNFSHistoryByHost returns per-mount NFS client rows; an empty mountpoint returns every mount.
+	GetNFSHistoryByHost(ctx context.Context, hostId uint, hours float64, mountpoint string) ([]localentities.HistoricalDiskNFSMetric, error)
 }
 
 type diskRepository struct {
@@ -28,14 +34,105 @@ func NewDiskRepository(db *gorm.DB) DiskRepository {
 }
 
 func (r *diskRepository) SaveCurrentMetric(ctx context.Context, metric localentities.DiskMetric, hostId uint) error {
+	timestamp := time.Now().UTC()
 	historicalMetric := localentities.HistoricalDiskMetric{
-		HostID:       &hostId,
-		Timestamp:    time.Now().UTC(),
+		HostID:       hostId,
+		Timestamp:    timestamp,
 		UsagePercent: metric.UsagePercent,
 		UsedBytes:    metric.Used,
 		TotalBytes:   metric.Total,
 	}
-	return r.db.WithContext(ctx).Create(&historicalMetric).Error
+
+	mounts := make([]localentities.HistoricalDiskMountMetric, 0, len(metric.Mounts))
+	seenPaths := make(map[string]struct{}, len(metric.Mounts))
+	for _, m := range metric.Mounts {
+		// Bind mounts may list the same path twice; keep the first to respect the primary key
+		if _, dup := seenPaths[m.Path]; dup || m.Path == "" {
+			continue
+		}
+		seenPaths[m.Path] = struct{}{}
+		mounts = append(mounts, localentities.HistoricalDiskMountMetric{
+			HostID:            hostId,
+			Timestamp:         timestamp,
+			Path:              m.Path,
+			Fstype:            m.Fstype,
+			TotalBytes:        m.Total,
+			UsedBytes:         m.Used,
+			FreeBytes:         m.Free,
+			UsedPercent:       m.UsedPercent,
+			InodesTotal:       m.InodesTotal,
+			InodesUsed:        m.InodesUsed,
+			InodesUsedPercent: m.InodesUsedPercent,
+			Stale:             m.Stale,
+		})
+	}
+
+	ioRows := make([]localentities.HistoricalDiskIOMetric, 0, len(metric.IOCounters))
+	for _, io := range metric.IOCounters {
+		// Samples without a usable previous counter snapshot would chart as a false idle disk
+		if !io.RatesValid {
+			continue
+		}
+		ioRows = append(ioRows, localentities.HistoricalDiskIOMetric{
+			HostID:            hostId,
+			Timestamp:         timestamp,
+			Device:            io.Name,
+			ReadIOPS:          io.ReadIOPS,
+			WriteIOPS:         io.WriteIOPS,
+			ReadBytesPerSec:   io.ReadBytesPerSec,
+			WriteBytesPerSec:  io.WriteBytesPerSec,
+			AvgReadLatencyMs:  io.AvgReadLatencyMs,
+			AvgWriteLatencyMs: io.AvgWriteLatencyMs,
+			UtilPercent:       io.UtilPercent,
+		})
+	}
+
+	nfsRows := make([]localentities.HistoricalDiskNFSMetric, 0, len(metric.NFSMounts))
+	seenNFS := make(map[string]struct{}, len(metric.NFSMounts))
+	for _, m := range metric.NFSMounts {
+		// Stacked mounts on one mountpoint are listed in mount order; keep the first
+		if _, dup := seenNFS[m.Mountpoint]; dup {
+			continue
+		}
+		seenNFS[m.Mountpoint] = struct{}{}
+		nfsRows = append(nfsRows, localentities.HistoricalDiskNFSMetric{
+			HostID:          hostId,
+			Timestamp:       timestamp,
+			Mountpoint:      m.Mountpoint,
+			Export:          m.Export,
+			Fstype:          m.Fstype,
+			Ops:             m.Ops,
+			Retransmissions: m.Retransmissions,
+			MajorTimeouts:   m.MajorTimeouts,
+			ReadBytes:       m.ReadBytes,
+			WriteBytes:      m.WriteBytes,
+			OpsPerSec:       m.OpsPerSec,
+			RetransPerSec:   m.RetransPerSec,
+			AvgRTTMs:        m.AvgRTTMs,
+		})
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&historicalMetric).Error; err != nil {
+			return err
+		}
+		if len(mounts) > 0 {
+			if err := tx.Create(&mounts).Error; err != nil {
+				return err
+			}
+		}
+		if len(ioRows) > 0 {
+			if err := tx.Create(&ioRows).Error; err != nil {
+				return err
+			}
+		}
+		if len(nfsRows) > 0 {
+			if err := tx.Create(&nfsRows).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *diskRepository) GetLatestMetric(ctx context.Context) (localentities.DiskMetric, error) {
@@ -91,3 +188,33 @@ func (r *diskRepository) GetHistoricalMetricsByHost(ctx context.Context, hostId
 		Find(&metrics).Error
 	return metrics, err
 }
+
+func (r *diskRepository) GetMountHistoryByHost(ctx context.Context, hostId uint, hours float64, path string) ([]localentities.HistoricalDiskMountMetric, error) {
+	var metrics []localentities.HistoricalDiskMountMetric
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if path != "" {
+		q = q.Where("path = ?", path)
+	}
+	err := q.Order("timestamp ASC").Order("path ASC").Find(&metrics).Error
+	return metrics, err
+}
+
+func (r *diskRepository) GetIOHistoryByHost(ctx context.Context, hostId uint, hours float64, device string) ([]localentities.HistoricalDiskIOMetric, error) {
+	var metrics []localentities.HistoricalDiskIOMetric
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if device != "" {
+		q = q.Where("device = ?", device)
+	}
+	err := q.Order("timestamp ASC").Order("device ASC").Find(&metrics).Error
+	return metrics, err
+}
+
+func (r *diskRepository) GetNFSHistoryByHost(ctx context.Context, hostId uint, hours float64, mountpoint string) ([]localentities.HistoricalDiskNFSMetric, error) {
+	var metrics []localentities.HistoricalDiskNFSMetric
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if mountpoint != "" {
+		q = q.Where("mountpoint = ?", mountpoint)
+	}
+	err := q.Order("timestamp ASC").Order("mountpoint ASC").Find(&metrics).Error
+	return metrics, err
+}
@@ -0,0 +1,158 @@
+// Package value_objects provides domain value objects for derived disk metrics.
+// This package turns cumulative block device counters into per-second rates,
+// mirroring the approach used by the network speed calculator.
+package value_objects
+
+import (
+	"sync"
+	"time"
+
+	netrates "system-stats/internal/modules/network/infrastructure/value_objects"
+)
+
+// DefaultMaxSampleGap is the longest interval between two samples that still yields a rate.
+// Longer gaps (process paused, collection disabled) re-baseline instead of averaging over hours.
+const DefaultMaxSampleGap = 10 * time.Minute
+
+// maxInFlight bounds the requests a device has in flight at once, above any hardware queue depth;
+// it limits how far the read and write times can advance in an interval.
+const maxInFlight = 65536
+
+// DiskIOCounters is a snapshot of the cumulative counters the kernel exposes per block device.
+// Times are in milliseconds as reported by /proc/diskstats.
+type DiskIOCounters struct {
+	ReadCount  uint64
+	WriteCount uint64
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadTime   uint64
+	WriteTime  uint64
+	IoTime     uint64
+}
+
+// DiskIORate holds the per-second rates derived from two consecutive counter snapshots.
+type DiskIORate struct {
+	// ReadIOPS and WriteIOPS are completed operations per second
+	ReadIOPS  float64
+	WriteIOPS float64
+
+	// ReadBytesPerSec and WriteBytesPerSec are throughput in bytes per second
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+
+	// AvgReadLatencyMs and AvgWriteLatencyMs are the mean service time per completed operation
+	AvgReadLatencyMs  float64
+	AvgWriteLatencyMs float64
+
+	// UtilPercent is the share of elapsed time with I/O in flight, capped at 100
+	UtilPercent float64
+
+	// Valid is false on the first sample, after a long gap or after a counter reset
+	Valid bool
+}
+
+type diskIOSample struct {
+	at       time.Time
+	counters DiskIOCounters
+}
+
+// DiskIOCalculator keeps the previous counter snapshot per device and derives rates from deltas.
+// Counts and bytes are 64-bit in the kernel, so any decrease (device re-attached, host rebooted)
+// re-baselines that device. The times are 32-bit milliseconds in /proc/diskstats and wrap every
+// 49.7 days; a decrease is a wrap when the delta fits the interval (see netrates.Counter32Delta).
+type DiskIOCalculator struct {
+	mu      sync.Mutex
+	maxGap  time.Duration
+	samples map[string]diskIOSample
+}
+
+// NewDiskIOCalculator creates a calculator that ignores sample pairs further apart than DefaultMaxSampleGap.
+func NewDiskIOCalculator() *DiskIOCalculator {
+	return &DiskIOCalculator{
+		maxGap:  DefaultMaxSampleGap,
+		samples: make(map[string]diskIOSample),
+	}
+}
+
+// Calculate records counters for device at time at and returns the rates since the previous call.
+func (c *DiskIOCalculator) Calculate(device string, at time.Time, cur DiskIOCounters) DiskIORate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, exists := c.samples[device]
+	c.samples[device] = diskIOSample{at: at, counters: cur}
+	if !exists {
+		return DiskIORate{}
+	}
+
+	elapsed := at.Sub(prev.at)
+	if elapsed <= 0 || elapsed > c.maxGap {
+		return DiskIORate{}
+	}
+	p := prev.counters
+	deltas := [4][2]uint64{
+		{p.ReadCount, cur.ReadCount},
+		{p.WriteCount, cur.WriteCount},
+		{p.ReadBytes, cur.ReadBytes},
+		{p.WriteBytes, cur.WriteBytes},
+	}
+	var d [7]uint64
+	for i, pair := range deltas {
+		delta, ok := netrates.CounterDelta(pair[0], pair[1])
+		if !ok {
+			return DiskIORate{}
+		}
+		d[i] = delta
+	}
+	// Read and write times add up the time of every request in flight; io time is wall time
+	ms := uint64(elapsed.Milliseconds())
+	times := [3][3]uint64{
+		{p.ReadTime, cur.ReadTime, ms * maxInFlight},
+		{p.WriteTime, cur.WriteTime, ms * maxInFlight},
+		{p.IoTime, cur.IoTime, ms + ms/10},
+	}
+	for i, t := range times {
+		delta, ok := netrates.Counter32Delta(t[0], t[1], t[2])
+		if !ok {
+			return DiskIORate{}
+		}
+		d[len(deltas)+i] = delta
+	}
+
+	seconds := elapsed.Seconds()
+	reads := float64(d[0])
+	writes := float64(d[1])
+
+	rate := DiskIORate{
+		ReadIOPS:         reads / seconds,
+		WriteIOPS:        writes / seconds,
+		ReadBytesPerSec:  float64(d[2]) / seconds,
+		WriteBytesPerSec: float64(d[3]) / seconds,
+		Valid:            true,
+	}
+	if reads > 0 {
+		rate.AvgReadLatencyMs = float64(d[4]) / reads
+	}
+	if writes > 0 {
+		rate.AvgWriteLatencyMs = float64(d[5]) / writes
+	}
+
+	util := float64(d[6]) / float64(elapsed.Milliseconds()) * 100.0
+	if util > 100 {
+		util = 100
+	}
+	rate.UtilPercent = util
+
+	return rate
+}
+
+// Forget drops devices not present in keep so detached disks do not accumulate state.
+func (c *DiskIOCalculator) Forget(keep map[string]struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name := range c.samples {
+		if _, ok := keep[name]; !ok {
+			delete(c.samples, name)
+		}
+	}
+}
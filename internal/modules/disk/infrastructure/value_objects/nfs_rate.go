@@ -0,0 +1,84 @@
+package value_objects
+
+import (
+	"sync"
+	"time"
+)
+
+// NFSCounters is a snapshot of the cumulative RPC counters of one NFS mount.
+type NFSCounters struct {
+	Ops             uint64
+	Retransmissions uint64
+	RTTMs           uint64
+}
+
+// NFSRate holds the per-second rates derived from two consecutive NFS counter snapshots.
+type NFSRate struct {
+	OpsPerSec     float64
+	RetransPerSec float64
+	// AvgRTTMs is the mean round-trip time of the requests in between; nil without requests
+	AvgRTTMs *float64
+	// Valid is false when no previous sample existed or the counters were reset (remount)
+	Valid bool
+}
+
+type nfsSample struct {
+	at       time.Time
+	counters NFSCounters
+}
+
+// NFSCalculator keeps the previous counter snapshot per mountpoint and derives rates from deltas,
+// like DiskIOCalculator.
+type NFSCalculator struct {
+	mu      sync.Mutex
+	maxGap  time.Duration
+	samples map[string]nfsSample
+}
+
+// NewNFSCalculator creates a calculator that ignores sample pairs further apart than DefaultMaxSampleGap.
+func NewNFSCalculator() *NFSCalculator {
+	return &NFSCalculator{maxGap: DefaultMaxSampleGap, samples: make(map[string]nfsSample)}
+}
+
+// Calculate records counters for mountpoint at time at and returns the rates since the previous call.
+func (c *NFSCalculator) Calculate(mountpoint string, at time.Time, cur NFSCounters) NFSRate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, exists := c.samples[mountpoint]
+	c.samples[mountpoint] = nfsSample{at: at, counters: cur}
+	if !exists {
+		return NFSRate{}
+	}
+	elapsed := at.Sub(prev.at)
+	if elapsed <= 0 || elapsed > c.maxGap {
+		return NFSRate{}
+	}
+	if cur.Ops < prev.counters.Ops || cur.Retransmissions < prev.counters.Retransmissions || cur.RTTMs < prev.counters.RTTMs {
+		return NFSRate{}
+	}
+
+	seconds := elapsed.Seconds()
+	ops := cur.Ops - prev.counters.Ops
+	rate := NFSRate{
+		OpsPerSec:     float64(ops) / seconds,
+		RetransPerSec: float64(cur.Retransmissions-prev.counters.Retransmissions) / seconds,
+		Valid:         true,
+	}
+	if ops > 0 {
+		rtt := float64(cur.RTTMs-prev.counters.RTTMs) / float64(ops)
+		rate.AvgRTTMs = &rtt
+	}
+	return rate
+}
+
+// Forget drops mountpoints not present in keep so unmounted shares do not accumulate state.
+func (c *NFSCalculator) Forget(keep map[string]struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name := range c.samples {
+		if _, ok := keep[name]; !ok {
+			delete(c.samples, name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package disk registers the disk metrics module.
+package disk
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/app/snapshot"
+	diskservice "system-stats/internal/modules/disk/application"
+	"system-stats/internal/modules/disk/infrastructure/collectors"
+	"system-stats/internal/modules/disk/infrastructure/entities"
+	"system-stats/internal/modules/disk/infrastructure/repositories"
+	handlers "system-stats/internal/modules/disk/presentation"
+	historycore "system-stats/internal/modules/history_metrics/core"
+)
+
+// Module collects disk usage, per-mount usage and staleness, per-device I/O and NFS client
+// statistics and serves /disk.
+type Module struct{}
+
+func (Module) Name() string { return "disk" }
+
+// Migrations adds the stale flag to disk_mount_metrics and the NFS table; the other disk tables
+// are part of the baseline schema, which creates disk_mount_metrics from the current entity.
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 16,
+		Name:    "disk_mount_stale_and_nfs",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasColumn(&entities.HistoricalDiskMountMetric{}, "Stale") {
+				if err := tx.Migrator().AddColumn(&entities.HistoricalDiskMountMetric{}, "Stale"); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateTable(&entities.HistoricalDiskNFSMetric{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&entities.HistoricalDiskNFSMetric{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&entities.HistoricalDiskMountMetric{}, "Stale")
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string {
+	return []string{"disk_metrics", "disk_mount_metrics", "disk_io_metrics", "disk_nfs_metrics"}
+}
+
+func (Module) HostTables() []string {
+	return []string{"disk_metrics", "disk_mount_metrics", "disk_io_metrics", "disk_nfs_metrics"}
+}
+
+// Build fails on an invalid DISK_MOUNT_TIMEOUT.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	mountTimeout, err := collectors.MountTimeoutFromEnv()
+	if err != nil {
+		return registry.Instance{}, fmt.Errorf("disk: %w", err)
+	}
+	service := diskservice.NewService(deps.Logger, repositories.NewDiskRepository(deps.DB), deps.Events, mountTimeout)
+	handler := handlers.NewDiskHandler(deps.Logger, service, deps.Hosts)
+	collector := historycore.NewModuleCollector("disk", service.Collect, service.Save,
+		func(snap *snapshot.Snapshot, m *entities.DiskMetric) { snap.Disk = m })
+	return registry.Instance{
+		Collector: &collector,
+		Routes: func(r gin.IRoutes) {
+			r.GET("/disk", handler.HandleDiskStats)
+		},
+	}, nil
+}
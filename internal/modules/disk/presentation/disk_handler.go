@@ -32,11 +32,13 @@ func NewDiskHandler(logger *log.Logger, service diskservice.Service, hosts hosts
 // HandleDiskStats returns current disk metrics with latest and historical data.
 //
 // @Summary     Disk metrics
-// @Description Returns latest disk usage snapshot and historical data.
+// @Description Returns latest disk usage snapshot, aggregate history, per-mount usage history (mounts that did not answer statfs in time have `stale` set and zero usage), per-device I/O rate history and NFS client history (RPC ops, retransmissions, major timeouts, round-trip time per mount). Stale and recovered mounts are listed by /events.
 // @Tags        metrics
 // @Produce     json
 // @Param       hours    query    number   false  "History window in hours"  default(0.0833)
 // @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       mount    query    string   false  "Limit mount_history and nfs_history to one mountpoint"
+// @Param       device   query    string   false  "Limit io_history to one block device"
 // @Success     200      {object} map[string]interface{}
 // @Failure     401      {object} map[string]string
 // @Failure     500      {object} map[string]string
@@ -71,8 +73,32 @@ func (h *DiskHandler) HandleDiskStats(c *gin.Context) {
 		return
 	}
 
+	mountHistory, err := h.service.GetMountHistoryByHost(c.Request.Context(), effective, hours, c.Query("mount"))
+	if err != nil {
+		h.logger.Error("Failed to fetch disk mount history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ioHistory, err := h.service.GetIOHistoryByHost(c.Request.Context(), effective, hours, c.Query("device"))
+	if err != nil {
+		h.logger.Error("Failed to fetch disk I/O history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	nfsHistory, err := h.service.GetNFSHistoryByHost(c.Request.Context(), effective, hours, c.Query("mount"))
+	if err != nil {
+		h.logger.Error("Failed to fetch NFS mount history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"latest":  latestMetrics,
-		"history": historyMetrics,
+		"latest":        latestMetrics,
+		"history":       historyMetrics,
+		"mount_history": mountHistory,
+		"io_history":    ioHistory,
+		"nfs_history":   nfsHistory,
 	})
 }
@@ -3,7 +3,9 @@ package dockermetrics
 import (
 	"context"
 	"errors"
+	"time"
 
+	"system-stats/internal/modules/docker/domain"
 	"system-stats/internal/modules/docker/domain/repositories"
 	"system-stats/internal/modules/docker/infrastructure/entities"
 
@@ -17,9 +19,24 @@ type Service interface {
 	GetLatestByHost(ctx context.Context, hostId uint) (*entities.DockerMetric, error)
 	GetHistorical(ctx context.Context, hours float64) ([]repositories.HistoricalDockerMetric, error)
 	GetHistoricalByHost(ctx context.Context, hostId uint, hours float64) ([]repositories.HistoricalDockerMetric, error)
+	GetContainerSeries(ctx context.Context, query ContainerSeriesQuery) ([]domain.ContainerSeries, error)
 	CollectAndSave(ctx context.Context, hostId uint) error
 }
 
+// ContainerSeriesQuery describes a per-container or per-stack history request.
+type ContainerSeriesQuery struct {
+	repositories.ContainerHistoryQuery
+
+	// ByStack returns one series per stack instead of one per stack/service
+	ByStack bool
+
+	// Bucket is the aggregation width; zero returns raw collection points
+	Bucket time.Duration
+
+	// Aggregate is domain.AggregateAvg or domain.AggregateMax
+	Aggregate string
+}
+
 type service struct {
 	logger           *log.Logger
 	collector        repositories.DockerMetricsCollector
@@ -108,6 +125,37 @@ func (s *service) GetHistoricalByHost(ctx context.Context, hostId uint, hours fl
 	return metrics, nil
 }
 
+func (s *service) GetContainerSeries(ctx context.Context, query ContainerSeriesQuery) ([]domain.ContainerSeries, error) {
+	rows, err := s.dockerRepository.GetContainerHistoryByHost(ctx, query.ContainerHistoryQuery)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			s.logger.Debug("Context canceled while getting container history")
+		} else {
+			s.logger.Error("Failed to get container history", "error", err, "host_id", query.HostID)
+		}
+		return nil, err
+	}
+
+	samples := make([]domain.ContainerSample, 0, len(rows))
+	for _, r := range rows {
+		samples = append(samples, domain.ContainerSample{
+			Timestamp:     r.MetricTimestamp,
+			ContainerID:   r.ID,
+			Name:          r.Name,
+			Stack:         r.Stack,
+			Service:       r.Service,
+			CPUPercent:    r.CPUPercent,
+			MemoryUsage:   r.MemoryUsage,
+			MemoryPercent: r.MemoryPercent,
+			NetworkRx:     r.NetworkRx,
+			NetworkTx:     r.NetworkTx,
+			BlockRead:     r.BlockRead,
+			BlockWrite:    r.BlockWrite,
+		})
+	}
+	return domain.BuildContainerSeries(samples, query.ByStack, query.Bucket, query.Aggregate), nil
+}
+
 func (s *service) CollectAndSave(ctx context.Context, hostId uint) error {
 	metric, err := s.Collect(ctx)
 	if err != nil {
@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Aggregation modes for bucketed container series.
+const (
+	AggregateAvg = "avg"
+	AggregateMax = "max"
+)
+
+// ContainerSample is one stored container row reduced to the fields used for charting.
+// Network and block values are cumulative counters as reported by the Docker stats API.
+type ContainerSample struct {
+	Timestamp     time.Time
+	ContainerID   string
+	Name          string
+	Stack         string
+	Service       string
+	CPUPercent    float64
+	MemoryUsage   uint64
+	MemoryPercent float64
+	NetworkRx     uint64
+	NetworkTx     uint64
+	BlockRead     uint64
+	BlockWrite    uint64
+}
+
+// ContainerSeriesPoint is one point of a container or stack series. Counters are
+// converted to per-second rates; replicas of one service are summed.
+type ContainerSeriesPoint struct {
+	Timestamp             time.Time `json:"timestamp"`
+	CPUPercent            float64   `json:"cpu_percent"`
+	MemoryUsage           float64   `json:"memory_usage"`
+	MemoryPercent         float64   `json:"memory_percent"`
+	NetworkRxBytesPerSec  float64   `json:"network_rx_bytes_per_sec"`
+	NetworkTxBytesPerSec  float64   `json:"network_tx_bytes_per_sec"`
+	BlockReadBytesPerSec  float64   `json:"block_read_bytes_per_sec"`
+	BlockWriteBytesPerSec float64   `json:"block_write_bytes_per_sec"`
+}
+
+// ContainerSeries is the time series for one stable identity (stack/service or stack).
+type ContainerSeries struct {
+	Key     string                 `json:"key"`
+	Stack   string                 `json:"stack"`
+	Service string                 `json:"service,omitempty"`
+	Points  []ContainerSeriesPoint `json:"points"`
+}
+
+// ContainerIdentity returns the stack and service a sample belongs to. Compose/swarm
+// labels are preferred; older rows without labels fall back to the container name so
+// "myapp-web-1" recreated as "myapp-web-1" keeps the same identity.
+func ContainerIdentity(s ContainerSample) (stack, service string) {
+	stack = s.Stack
+	if stack == "" {
+		stack = ExtractStackNameFromContainerName(s.Name)
+	}
+	service = s.Service
+	if service == "" {
+		service = s.Name
+	}
+	return stack, service
+}
+
+// BuildContainerSeries groups samples by identity, derives counter rates per container
+// instance, sums replicas at each timestamp and optionally aggregates into buckets.
+// byStack collapses every service of a stack into one series. A bucket of zero keeps
+// every collected point.
+func BuildContainerSeries(samples []ContainerSample, byStack bool, bucket time.Duration, agg string) []ContainerSeries {
+	sorted := make([]ContainerSample, len(samples))
+	copy(sorted, samples)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	type seriesAcc struct {
+		series ContainerSeries
+		points map[int64]*ContainerSeriesPoint
+	}
+	groups := make(map[string]*seriesAcc)
+	prevByContainer := make(map[string]ContainerSample)
+
+	for _, s := range sorted {
+		stack, service := ContainerIdentity(s)
+		key := stack + "/" + service
+		if byStack {
+			key = stack
+			service = ""
+		}
+
+		acc, ok := groups[key]
+		if !ok {
+			acc = &seriesAcc{
+				series: ContainerSeries{Key: key, Stack: stack, Service: service},
+				points: make(map[int64]*ContainerSeriesPoint),
+			}
+			groups[key] = acc
+		}
+
+		ts := s.Timestamp.UnixNano()
+		p, ok := acc.points[ts]
+		if !ok {
+			p = &ContainerSeriesPoint{Timestamp: s.Timestamp}
+			acc.points[ts] = p
+		}
+		p.CPUPercent += s.CPUPercent
+		p.MemoryUsage += float64(s.MemoryUsage)
+		if s.MemoryPercent > p.MemoryPercent {
+			p.MemoryPercent = s.MemoryPercent
+		}
+
+		// Rates are per container instance: a recreated container starts new counters
+		if prev, seen := prevByContainer[s.ContainerID]; seen {
+			if secs := s.Timestamp.Sub(prev.Timestamp).Seconds(); secs > 0 {
+				p.NetworkRxBytesPerSec += counterRate(prev.NetworkRx, s.NetworkRx, secs)
+				p.NetworkTxBytesPerSec += counterRate(prev.NetworkTx, s.NetworkTx, secs)
+				p.BlockReadBytesPerSec += counterRate(prev.BlockRead, s.BlockRead, secs)
+				p.BlockWriteBytesPerSec += counterRate(prev.BlockWrite, s.BlockWrite, secs)
+			}
+		}
+		prevByContainer[s.ContainerID] = s
+	}
+
+	result := make([]ContainerSeries, 0, len(groups))
+	for _, acc := range groups {
+		points := make([]ContainerSeriesPoint, 0, len(acc.points))
+		for _, p := range acc.points {
+			points = append(points, *p)
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+		acc.series.Points = bucketContainerPoints(points, bucket, agg)
+		result = append(result, acc.series)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// counterRate returns the per-second increase of a cumulative counter, or zero after a reset.
+func counterRate(prev, cur uint64, seconds float64) float64 {
+	if cur < prev {
+		return 0
+	}
+	return float64(cur-prev) / seconds
+}
+
+// bucketContainerPoints folds sorted points into fixed-width buckets using avg or max.
+func bucketContainerPoints(points []ContainerSeriesPoint, bucket time.Duration, agg string) []ContainerSeriesPoint {
+	if bucket <= 0 || len(points) == 0 {
+		return points
+	}
+
+	out := make([]ContainerSeriesPoint, 0)
+	var cur ContainerSeriesPoint
+	var start time.Time
+	n := 0
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		if agg != AggregateMax {
+			f := float64(n)
+			cur.CPUPercent /= f
+			cur.MemoryUsage /= f
+			cur.MemoryPercent /= f
+			cur.NetworkRxBytesPerSec /= f
+			cur.NetworkTxBytesPerSec /= f
+			cur.BlockReadBytesPerSec /= f
+			cur.BlockWriteBytesPerSec /= f
+		}
+		cur.Timestamp = start
+		out = append(out, cur)
+	}
+
+	for _, p := range points {
+		bucketStart := p.Timestamp.Truncate(bucket)
+		if n == 0 || !bucketStart.Equal(start) {
+			flush()
+			cur = ContainerSeriesPoint{}
+			start = bucketStart
+			n = 0
+		}
+		if agg == AggregateMax {
+			cur.CPUPercent = max(cur.CPUPercent, p.CPUPercent)
+			cur.MemoryUsage = max(cur.MemoryUsage, p.MemoryUsage)
+			cur.MemoryPercent = max(cur.MemoryPercent, p.MemoryPercent)
+			cur.NetworkRxBytesPerSec = max(cur.NetworkRxBytesPerSec, p.NetworkRxBytesPerSec)
+			cur.NetworkTxBytesPerSec = max(cur.NetworkTxBytesPerSec, p.NetworkTxBytesPerSec)
+			cur.BlockReadBytesPerSec = max(cur.BlockReadBytesPerSec, p.BlockReadBytesPerSec)
+			cur.BlockWriteBytesPerSec = max(cur.BlockWriteBytesPerSec, p.BlockWriteBytesPerSec)
+		} else {
+			cur.CPUPercent += p.CPUPercent
+			cur.MemoryUsage += p.MemoryUsage
+			cur.MemoryPercent += p.MemoryPercent
+			cur.NetworkRxBytesPerSec += p.NetworkRxBytesPerSec
+			cur.NetworkTxBytesPerSec += p.NetworkTxBytesPerSec
+			cur.BlockReadBytesPerSec += p.BlockReadBytesPerSec
+			cur.BlockWriteBytesPerSec += p.BlockWriteBytesPerSec
+		}
+		n++
+	}
+	flush()
+	return out
+}
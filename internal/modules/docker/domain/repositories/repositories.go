@@ -20,16 +20,28 @@ type DockerRepository interface {
 	GetLatestMetricByHost(ctx context.Context, hostId uint) (*localentities.DockerMetric, error)
 	GetHistoricalMetrics(ctx context.Context, hours float64) ([]HistoricalDockerMetric, error)
 	GetHistoricalMetricsByHost(ctx context.Context, hostId uint, hours float64) ([]HistoricalDockerMetric, error)
+	GetContainerHistoryByHost(ctx context.Context, query ContainerHistoryQuery) ([]localentities.DockerContainerEntity, error)
+}
+
+// ContainerHistoryQuery selects stored container rows for one host and time range.
+// Empty Stack, Service and Container match everything; Container matches the name or short ID.
+type ContainerHistoryQuery struct {
+	HostID    uint
+	From      time.Time
+	To        time.Time
+	Stack     string
+	Service   string
+	Container string
 }
 
 // HistoricalDockerMetric represents a historical Docker daemon metric stored in the database.
 type HistoricalDockerMetric struct {
-	HostID            *uint                                  `json:"host_id" gorm:"default:null;index;index:idx_docker_host_ts"`
-	Timestamp         time.Time                              `json:"timestamp" gorm:"primaryKey;index;index:idx_docker_host_ts"`
-	TotalContainers   int                                    `json:"total_containers" gorm:"column:total_containers"`
-	RunningContainers int                                    `json:"running_containers" gorm:"column:running_containers"`
-	DockerAvailable   bool                                   `json:"docker_available" gorm:"column:docker_available"`
-	Containers        []localentities.DockerContainerEntity  `gorm:"foreignKey:MetricTimestamp"`
+	HostID            uint                                  `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_docker_host_ts,priority:1"`
+	Timestamp         time.Time                             `json:"timestamp" gorm:"primaryKey;index;index:idx_docker_host_ts,priority:2"`
+	TotalContainers   int                                   `json:"total_containers" gorm:"column:total_containers"`
+	RunningContainers int                                   `json:"running_containers" gorm:"column:running_containers"`
+	DockerAvailable   bool                                  `json:"docker_available" gorm:"column:docker_available"`
+	Containers        []localentities.DockerContainerEntity `gorm:"foreignKey:HostID,MetricTimestamp;references:HostID,Timestamp;constraint:OnDelete:CASCADE"`
 }
 
 func (h HistoricalDockerMetric) GetTimestamp() time.Time { return h.Timestamp }
@@ -163,6 +163,8 @@ func (c *dockerMetricsCollector) CollectDockerMetrics(ctx context.Context) (enti
 			stackName = domain.ExtractStackNameFromContainerName(name)
 		}
 
+		serviceName := c.extractServiceName(containerJSON.Config.Labels)
+
 		// Convert ports (with nil protection)
 		var ports []entities.DockerPort
 		if containerInfo.Ports != nil {
@@ -207,6 +209,8 @@ func (c *dockerMetricsCollector) CollectDockerMetrics(ctx context.Context) (enti
 			Stats:      containerStats,
 			Created:    c.parseContainerCreatedTime(containerJSON.Created),
 			FinishedAt: finishedAt,
+			Stack:      stackName,
+			Service:    serviceName,
 		}
 
 		results <- containerResult{
@@ -595,6 +599,26 @@ func (c *dockerMetricsCollector) extractStackName(labels map[string]string) stri
 	return ""
 }
 
+// extractServiceName returns the compose or swarm service name from container labels
+func (c *dockerMetricsCollector) extractServiceName(labels map[string]string) string {
+	if labels == nil {
+		return ""
+	}
+
+	if service, exists := labels["com.docker.compose.service"]; exists && service != "" {
+		return service
+	}
+
+	// Swarm service names are prefixed with the stack namespace ("stack_service")
+	if service, exists := labels["com.docker.swarm.service.name"]; exists && service != "" {
+		if ns := labels["com.docker.stack.namespace"]; ns != "" {
+			return strings.TrimPrefix(service, ns+"_")
+		}
+		return service
+	}
+
+	return ""
+}
 
 // mergeStacksByCommonPrefix merges stacks that have common prefixes in their names
 // For example: myapp-web, myapp-api, myapp-db -> merged into myapp stack
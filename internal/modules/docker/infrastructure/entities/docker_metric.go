@@ -76,11 +76,21 @@ type DockerContainer struct {
 
 	// FinishedAt shows when the container finished (ISO 8601 timestamp, for exited containers)
 	FinishedAt string `json:"finished_at,omitempty"`
+
+	// Stack is the compose project (or name-derived stack) the container belongs to
+	Stack string `json:"stack,omitempty"`
+
+	// Service is the compose/swarm service name; together with Stack it identifies
+	// the workload across container recreation
+	Service string `json:"service,omitempty"`
 }
 
  // DockerContainerEntity represents a Docker container stored in the database.
- // This entity is used for database storage with foreign key relationship to DockerMetric.
+ // This entity is used for database storage with a (host_id, metric_timestamp) foreign key to DockerMetric.
 type DockerContainerEntity struct {
+	// HostID references the host of the parent DockerMetric (primary key with ID and MetricTimestamp)
+	HostID uint `gorm:"primaryKey;autoIncrement:false;column:host_id"`
+
 	// ID is the unique Docker container identifier (primary key)
 	ID string `gorm:"primaryKey"`
 
@@ -137,6 +147,12 @@ type DockerContainerEntity struct {
 
 	// FinishedAt shows when the container finished (ISO 8601 timestamp, for exited containers)
 	FinishedAt string `gorm:"column:finished_at"`
+
+	// Stack is the compose project the container belonged to at collection time
+	Stack string `gorm:"column:stack;index:idx_docker_container_stack_service"`
+
+	// Service is the compose/swarm service name used as a stable identity across recreation
+	Service string `gorm:"column:service;index:idx_docker_container_stack_service"`
 }
 
  // DockerPort represents a port mapping for a Docker container.
@@ -218,6 +234,8 @@ func (e DockerContainerEntity) ToDockerContainer() (DockerContainer, error) {
 		},
 		Created:    e.Created,
 		FinishedAt: e.FinishedAt,
+		Stack:      e.Stack,
+		Service:    e.Service,
 	}, nil
 }
 
@@ -249,5 +267,7 @@ func (c DockerContainer) ToDockerContainerEntity(metricTimestamp time.Time) (Doc
 		BlockWrite:        c.Stats.BlockWrite,
 		Created:           c.Created,
 		FinishedAt:        c.FinishedAt,
+		Stack:             c.Stack,
+		Service:           c.Service,
 	}, nil
 }
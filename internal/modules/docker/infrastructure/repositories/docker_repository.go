@@ -26,7 +26,7 @@ func (r *dockerRepository) SaveCurrentMetric(ctx context.Context, metric localen
 	// Save as historical metric
 	timestamp := time.Now().UTC()
 	historicalMetric := repositories.HistoricalDockerMetric{
-		HostID:            &hostId,
+		HostID:            hostId,
 		Timestamp:         timestamp,
 		TotalContainers:   metric.TotalContainers,
 		RunningContainers: metric.RunningContainers,
@@ -41,6 +41,7 @@ func (r *dockerRepository) SaveCurrentMetric(ctx context.Context, metric localen
 			if err != nil {
 				return err
 			}
+			entity.HostID = hostId
 			containerEntities = append(containerEntities, entity)
 		}
 	}
@@ -168,3 +169,21 @@ func (r *dockerRepository) GetHistoricalMetricsByHost(ctx context.Context, hostI
 		Find(&metrics).Error
 	return metrics, err
 }
+
+func (r *dockerRepository) GetContainerHistoryByHost(ctx context.Context, query repositories.ContainerHistoryQuery) ([]localentities.DockerContainerEntity, error) {
+	q := r.db.WithContext(ctx).
+		Where("host_id = ? AND metric_timestamp >= ? AND metric_timestamp <= ?", query.HostID, query.From, query.To)
+	if query.Stack != "" {
+		q = q.Where("stack = ?", query.Stack)
+	}
+	if query.Service != "" {
+		q = q.Where("service = ?", query.Service)
+	}
+	if query.Container != "" {
+		q = q.Where("name = ? OR id = ?", query.Container, query.Container)
+	}
+
+	var rows []localentities.DockerContainerEntity
+	err := q.Order("metric_timestamp ASC").Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,45 @@
+// Package docker registers the Docker metrics module.
+package docker
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/app/snapshot"
+	dockerservice "system-stats/internal/modules/docker/application"
+	"system-stats/internal/modules/docker/infrastructure/collectors"
+	"system-stats/internal/modules/docker/infrastructure/entities"
+	"system-stats/internal/modules/docker/infrastructure/repositories"
+	handlers "system-stats/internal/modules/docker/presentation"
+	historycore "system-stats/internal/modules/history_metrics/core"
+)
+
+// Module collects container stats from the Docker daemon and serves /docker.
+// Disable it on hosts without Docker to skip the daemon probe at startup.
+type Module struct{}
+
+func (Module) Name() string { return "docker" }
+
+// Migrations is empty: the docker tables are part of the baseline schema.
+func (Module) Migrations() []database.Migration { return nil }
+
+// RetentionTables omits docker_container_entities, whose rows cascade from docker_metrics.
+func (Module) RetentionTables() []string { return []string{"docker_metrics"} }
+
+// HostTables deletes containers before the metrics they reference.
+func (Module) HostTables() []string { return []string{"docker_container_entities", "docker_metrics"} }
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := dockerservice.NewService(deps.Logger, collectors.NewDockerMetricsCollector(deps.Logger), repositories.NewDockerRepository(deps.DB))
+	handler := handlers.NewDockerHandler(deps.Logger, service, deps.Hosts)
+	collector := historycore.NewModuleCollector("docker", service.Collect, service.Save,
+		func(snap *snapshot.Snapshot, m *entities.DockerMetric) { snap.Docker = m })
+	return registry.Instance{
+		Collector: &collector,
+		Routes: func(r gin.IRoutes) {
+			r.GET("/docker", handler.HandleDockerStats)
+			r.GET("/docker/containers/history", handler.HandleContainerHistory)
+		},
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/gin-gonic/gin"
@@ -11,6 +12,8 @@ import (
 	"system-stats/internal/app/httputil"
 	"system-stats/internal/app/metricshost"
 	dockerservice "system-stats/internal/modules/docker/application"
+	"system-stats/internal/modules/docker/domain"
+	dockerrepos "system-stats/internal/modules/docker/domain/repositories"
 	hostservice "system-stats/internal/modules/hosts/application"
 )
 
@@ -101,3 +104,113 @@ func (h *DockerHandler) HandleDockerStats(c *gin.Context) {
 		"docker_available": dockerAvailable,
 	})
 }
+
+// HandleContainerHistory returns CPU, memory, network and block I/O series for containers or stacks.
+//
+// @Summary     Docker container history
+// @Description Returns per-service (or per-stack) time series. Identity is stack/service, so recreated containers continue the same series. Network and block I/O are per-second rates.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours      query    number   false  "History window in hours (ignored when from is set)"  default(0.0833)
+// @Param       from       query    string   false  "Range start (RFC3339)"
+// @Param       to         query    string   false  "Range end (RFC3339, default now)"
+// @Param       host_id    query    integer  false  "Host ID (0 = this server instance)"
+// @Param       stack      query    string   false  "Compose stack name"
+// @Param       service    query    string   false  "Compose service name"
+// @Param       container  query    string   false  "Container name or short ID"
+// @Param       group      query    string   false  "service (default) or stack"
+// @Param       bucket     query    string   false  "Aggregation bucket as Go duration (e.g. 1m, 1h); empty returns raw points"
+// @Param       agg        query    string   false  "avg (default) or max"
+// @Success     200        {object} map[string]interface{}
+// @Failure     400        {object} map[string]string
+// @Failure     401        {object} map[string]string
+// @Failure     500        {object} map[string]string
+// @Security    BearerAuth
+// @Router      /docker/containers/history [get]
+func (h *DockerHandler) HandleContainerHistory(c *gin.Context) {
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	to := time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to: expected RFC3339"})
+			return
+		}
+		to = t.UTC()
+	}
+	from := to.Add(-time.Duration(httputil.ParseHoursQuery(c) * float64(time.Hour)))
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from: expected RFC3339"})
+			return
+		}
+		from = t.UTC()
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+
+	var bucket time.Duration
+	if v := c.Query("bucket"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bucket: expected duration such as 1m"})
+			return
+		}
+		bucket = d
+	}
+
+	agg := c.DefaultQuery("agg", domain.AggregateAvg)
+	if agg != domain.AggregateAvg && agg != domain.AggregateMax {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agg: expected avg or max"})
+		return
+	}
+
+	group := c.DefaultQuery("group", "service")
+	if group != "service" && group != "stack" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group: expected service or stack"})
+		return
+	}
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyDockerContainerHistoryPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for container history", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := h.service.GetContainerSeries(ctx, dockerservice.ContainerSeriesQuery{
+		ContainerHistoryQuery: dockerrepos.ContainerHistoryQuery{
+			HostID:    effective,
+			From:      from,
+			To:        to,
+			Stack:     c.Query("stack"),
+			Service:   c.Query("service"),
+			Container: c.Query("container"),
+		},
+		ByStack:   group == "stack",
+		Bucket:    bucket,
+		Aggregate: agg,
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching container history")
+			return
+		}
+		h.logger.Error("Failed to fetch container history", "error", err, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series": series,
+	})
+}
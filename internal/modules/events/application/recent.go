@@ -0,0 +1,48 @@
+package events
+
+import (
+	"time"
+
+	"system-stats/internal/modules/events/infrastructure/entities"
+)
+
+// PushWindow is how long a module repeats what it collected in agent pushes, so nothing is lost
+// when a push fails; main stores each event once.
+const PushWindow = 10 * time.Minute
+
+// Recent keeps the items of a module's collections in the last PushWindow for its push data.
+// It is not safe for concurrent use; modules hold their own lock around it.
+type Recent[T any] struct {
+	max   int
+	at    func(T) time.Time
+	items []T
+}
+
+// NewRecent keeps at most max items; at returns the time of an item.
+func NewRecent[T any](max int, at func(T) time.Time) *Recent[T] {
+	return &Recent[T]{max: max, at: at}
+}
+
+// NewRecentEvents keeps at most max host events.
+func NewRecentEvents(max int) *Recent[entities.HostEvent] {
+	return NewRecent(max, func(e entities.HostEvent) time.Time { return e.Timestamp })
+}
+
+// Add drops the items older than PushWindow before now and appends items, which are kept however
+// old they are (e.g. backfilled records). Beyond max the oldest additions are dropped first.
+func (r *Recent[T]) Add(now time.Time, items []T) {
+	cutoff := now.Add(-PushWindow)
+	kept := r.items[:0]
+	for _, item := range r.items {
+		if r.at(item).After(cutoff) {
+			kept = append(kept, item)
+		}
+	}
+	kept = append(kept, items...)
+	r.items = kept[max(0, len(kept)-r.max):]
+}
+
+// Items returns a copy of the kept items.
+func (r *Recent[T]) Items() []T {
+	return append([]T(nil), r.items...)
+}
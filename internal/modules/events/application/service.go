@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/textutil"
+	"system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/events/infrastructure/repositories"
+)
+
+// Service stores the host events reported by modules and derives the open problems of a host.
+type Service interface {
+	// Record stores events of hostId; events already stored (e.g. pushed again) are skipped.
+	Record(ctx context.Context, hostId uint, events []entities.HostEvent) error
+	List(ctx context.Context, hostId uint, hours float64, source, subject string) ([]entities.HostEvent, error)
+	// Problems returns the open warning and critical events of a host, oldest first.
+	Problems(ctx context.Context, hostId uint) ([]entities.HostEvent, error)
+}
+
+type service struct {
+	logger *log.Logger
+	repo   repositories.HostEventRepository
+}
+
+func NewService(logger *log.Logger, repo repositories.HostEventRepository) Service {
+	return &service{logger: logger, repo: repo}
+}
+
+func (s *service) Record(ctx context.Context, hostId uint, events []entities.HostEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	for i := range events {
+		events[i].ID = 0
+		events[i].HostID = hostId
+		// Postgres keeps microseconds; truncating makes a repeated push match the stored row
+		events[i].Timestamp = events[i].Timestamp.UTC().Truncate(time.Microsecond)
+		events[i].Message = textutil.Truncate(events[i].Message, entities.MaxMessageLen)
+	}
+	if err := s.repo.Save(ctx, events); err != nil {
+		s.logger.Error("Failed to save host events", "host_id", hostId, "error", err)
+		return err
+	}
+	for _, e := range events {
+		s.logger.Debug("Host event", "host_id", hostId, "source", e.Source, "subject", e.Subject, "kind", e.Kind, "severity", e.Severity)
+	}
+	return nil
+}
+
+func (s *service) List(ctx context.Context, hostId uint, hours float64, source, subject string) ([]entities.HostEvent, error) {
+	return s.repo.List(ctx, hostId, hours, source, subject)
+}
+
+func (s *service) Problems(ctx context.Context, hostId uint) ([]entities.HostEvent, error) {
+	return s.repo.Open(ctx, hostId)
+}
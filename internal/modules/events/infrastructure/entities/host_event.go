@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Event severities. A warning or critical event opens a problem for its subject until a later
+// ok event of the same source and subject; info events are only recorded.
+const (
+	SeverityInfo     = "info"
+	SeverityOK       = "ok"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// MaxMessageLen is the size of the message column in bytes.
+const MaxMessageLen = 1024
+
+// HostEvent is a state change reported by a module, e.g. a watched process going down.
+// Events pushed twice by an agent are stored once (unique host, time, source, subject, kind).
+type HostEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	HostID    uint      `json:"host_id" gorm:"not null;uniqueIndex:idx_host_events_unique,priority:1;index:idx_host_events_subject,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"not null;index;uniqueIndex:idx_host_events_unique,priority:2"`
+	// Source is the module that reported the event
+	Source string `json:"source" gorm:"size:32;not null;uniqueIndex:idx_host_events_unique,priority:3;index:idx_host_events_subject,priority:2"`
+	// Subject is what changed within the source, e.g. a watch name or a port
+	Subject string `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_host_events_unique,priority:4;index:idx_host_events_subject,priority:3"`
+	// Kind is the source-specific change, e.g. down, up or restart
+	Kind     string `json:"kind" gorm:"size:32;not null;uniqueIndex:idx_host_events_unique,priority:5"`
+	Severity string `json:"severity" gorm:"size:16;not null"`
+	Message  string `json:"message" gorm:"size:1024"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HostEvent) TableName() string { return "host_events" }
+
+// Opens reports whether the event opens a problem for its subject.
+func (e HostEvent) Opens() bool {
+	return e.Severity == SeverityWarning || e.Severity == SeverityCritical
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/events/infrastructure/entities"
+)
+
+type HostEventRepository interface {
+	// Save stores events; events already stored are skipped.
+	Save(ctx context.Context, events []entities.HostEvent) error
+	// List returns the events of a host in the last hours, newest first, optionally for one source and subject.
+	List(ctx context.Context, hostId uint, hours float64, source, subject string) ([]entities.HostEvent, error)
+	// Open returns, per source and subject, the latest warning or critical event not followed
+	// by an ok or another problem event.
+	Open(ctx context.Context, hostId uint) ([]entities.HostEvent, error)
+}
+
+type hostEventRepository struct {
+	db *gorm.DB
+}
+
+func NewHostEventRepository(db *gorm.DB) HostEventRepository {
+	return &hostEventRepository{db: db}
+}
+
+func (r *hostEventRepository) Save(ctx context.Context, events []entities.HostEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&events).Error
+}
+
+func (r *hostEventRepository) List(ctx context.Context, hostId uint, hours float64, source, subject string) ([]entities.HostEvent, error) {
+	events := []entities.HostEvent{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if source != "" {
+		q = q.Where("source = ?", source)
+	}
+	if subject != "" {
+		q = q.Where("subject = ?", subject)
+	}
+	err := q.Order("timestamp DESC, id DESC").Find(&events).Error
+	return events, err
+}
+
+func (r *hostEventRepository) Open(ctx context.Context, hostId uint) ([]entities.HostEvent, error) {
+	events := []entities.HostEvent{}
+	later := r.db.Table("host_events AS l").Select("1").
+		Where("l.host_id = e.host_id AND l.source = e.source AND l.subject = e.subject AND l.severity <> ?", entities.SeverityInfo).
+		Where("l.timestamp > e.timestamp OR (l.timestamp = e.timestamp AND l.id > e.id)")
+	err := r.db.WithContext(ctx).Table("host_events AS e").
+		Where("e.host_id = ? AND e.severity IN ?", hostId, []string{entities.SeverityWarning, entities.SeverityCritical}).
+		Where("NOT EXISTS (?)", later).
+		Order("e.timestamp ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	eventsservice "system-stats/internal/modules/events/application"
+	"system-stats/internal/modules/events/infrastructure/entities"
+	hostservice "system-stats/internal/modules/hosts/application"
+)
+
+// defaultEventsHours is the event window when ?hours is not given; events are rare, so the
+// metric default of five minutes would usually be empty.
+const defaultEventsHours = 24
+
+// EventsHandler handles HTTP requests for host events.
+type EventsHandler struct {
+	logger  *log.Logger
+	service eventsservice.Service
+	hosts   hostservice.Service
+}
+
+// NewEventsHandler creates a new HTTP handler for host event endpoints.
+func NewEventsHandler(logger *log.Logger, service eventsservice.Service, hosts hostservice.Service) *EventsHandler {
+	return &EventsHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleEvents returns the events of a host and its open problems.
+//
+// @Summary     Host events
+// @Description Returns state changes reported by modules (e.g. a watched process going down or up), newest first, and the open problems: warning or critical events not yet followed by an ok event for the same source and subject.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "Event window in hours"  default(24)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       source   query    string   false  "Limit to one module"
+// @Param       subject  query    string   false  "Limit to one subject"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /events [get]
+func (h *EventsHandler) HandleEvents(c *gin.Context) {
+	hours := float64(defaultEventsHours)
+	if c.Query("hours") != "" {
+		hours = httputil.ParseHoursQuery(c)
+	}
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyEventsPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for events", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.service.List(ctx, effective, hours, c.Query("source"), c.Query("subject"))
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching events")
+			return
+		}
+		h.logger.Error("Failed to fetch events", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	problems, err := h.service.Problems(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching problems")
+			return
+		}
+		h.logger.Error("Failed to fetch problems", "error", err, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if source := c.Query("source"); source != "" {
+		problems = filterSource(problems, source)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":   events,
+		"problems": problems,
+	})
+}
+
+func filterSource(events []entities.HostEvent, source string) []entities.HostEvent {
+	out := make([]entities.HostEvent, 0, len(events))
+	for _, e := range events {
+		if e.Source == source {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package execmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/modules/exec/infrastructure/collectors"
+)
+
+var (
+	descExecStatus   = prometheus.NewDesc("exec_check_status", "Status of the last exec check run: 0 OK, 1 WARNING, 2 CRITICAL, 3 UNKNOWN.", []string{"check"}, nil)
+	descExecDuration = prometheus.NewDesc("exec_check_duration_seconds", "Duration of the last exec check run.", []string{"check"}, nil)
+	descExecLastRun  = prometheus.NewDesc("exec_check_last_run_timestamp_seconds", "Unix time of the last exec check run.", []string{"check"}, nil)
+	descExecGauge    = prometheus.NewDesc("exec_gauge", "Gauge reported by an exec check; labels holds the gauge's own labels as k=\"v\" pairs.", []string{"check", "name", "labels"}, nil)
+)
+
+// PrometheusCollector exports the latest in-memory check results; scrapes never run checks.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descExecStatus
+	ch <- descExecDuration
+	ch <- descExecLastRun
+	ch <- descExecGauge
+}
+
+// Collect sends the status and gauges of every check that has run.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, r := range c.service.Latest() {
+		ch <- prometheus.MustNewConstMetric(descExecStatus, prometheus.GaugeValue, float64(r.Status), r.Check)
+		ch <- prometheus.MustNewConstMetric(descExecDuration, prometheus.GaugeValue, r.DurationSeconds, r.Check)
+		ch <- prometheus.MustNewConstMetric(descExecLastRun, prometheus.GaugeValue, float64(r.RanAt.UnixNano())/1e9, r.Check)
+		seen := make(map[[2]string]bool, len(r.Gauges))
+		for _, g := range r.Gauges {
+			labels := collectors.CanonicalLabels(g.Labels)
+			// A duplicate series would make the whole scrape fail
+			if key := [2]string{g.Name, labels}; !seen[key] {
+				seen[key] = true
+				ch <- prometheus.MustNewConstMetric(descExecGauge, prometheus.GaugeValue, g.Value, r.Check, g.Name, labels)
+			}
+		}
+	}
+}
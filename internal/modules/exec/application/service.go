@@ -0,0 +1,65 @@
+package execmetrics
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/modules/exec/infrastructure/collectors"
+	"system-stats/internal/modules/exec/infrastructure/entities"
+	"system-stats/internal/modules/exec/infrastructure/repositories"
+)
+
+type Service interface {
+	// Collect runs the checks that are due and returns the latest result of every check.
+	Collect(ctx context.Context) (entities.ExecMetric, error)
+	Save(ctx context.Context, metric entities.ExecMetric, hostId uint) error
+	// Latest returns the in-memory results of this instance's checks (no database access).
+	Latest() []entities.CheckResult
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalExecCheck, error)
+	GetCheckHistoryByHost(ctx context.Context, hostId uint, hours float64, check string) ([]entities.HistoricalExecCheck, error)
+	GetGaugeHistoryByHost(ctx context.Context, hostId uint, hours float64, check, name string) ([]entities.HistoricalExecGauge, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.ExecMetricsCollector
+	repo      repositories.ExecRepository
+}
+
+func NewService(logger *log.Logger, checks []collectors.CheckConfig, repo repositories.ExecRepository) Service {
+	return &service{
+		logger:    logger,
+		collector: collectors.NewExecMetricsCollector(logger, checks),
+		repo:      repo,
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.ExecMetric, error) {
+	s.logger.Debug("Collecting metrics", "module", "exec")
+	return s.collector.Collect(ctx)
+}
+
+func (s *service) Save(ctx context.Context, metric entities.ExecMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "exec", "error", err, "host_id", hostId)
+		return err
+	}
+	return nil
+}
+
+func (s *service) Latest() []entities.CheckResult {
+	return s.collector.Latest()
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalExecCheck, error) {
+	return s.repo.GetLatestByHost(ctx, hostId)
+}
+
+func (s *service) GetCheckHistoryByHost(ctx context.Context, hostId uint, hours float64, check string) ([]entities.HistoricalExecCheck, error) {
+	return s.repo.GetCheckHistoryByHost(ctx, hostId, hours, check)
+}
+
+func (s *service) GetGaugeHistoryByHost(ctx context.Context, hostId uint, hours float64, check, name string) ([]entities.HistoricalExecGauge, error) {
+	return s.repo.GetGaugeHistoryByHost(ctx, hostId, hours, check, name)
+}
@@ -0,0 +1,109 @@
+package collectors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+)
+
+// ChecksFileEnv names the JSON file listing the commands to run.
+const ChecksFileEnv = "EXEC_CHECKS_FILE"
+
+// DefaultCheckTimeout bounds a check that sets no timeout.
+const DefaultCheckTimeout = 10 * time.Second
+
+var checkNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// CheckConfig is one configured command. Commands run directly, without a shell; use
+// ["sh", "-c", "..."] explicitly when shell features are needed.
+type CheckConfig struct {
+	Name    string   `json:"name"`
+	Command []string `json:"command"`
+	// Format is json, prometheus or nagios
+	Format string `json:"format"`
+	// Interval is the minimum time between runs (default: every exec collection)
+	Interval Duration `json:"interval"`
+	// Timeout kills the command after this long (default 10s)
+	Timeout Duration `json:"timeout"`
+}
+
+// Duration accepts Go duration strings ("30s", "5m") in JSON.
+type Duration time.Duration
+
+// UnmarshalJSON parses a Go duration string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+type checksFile struct {
+	Checks []CheckConfig `json:"checks"`
+}
+
+// LoadChecksFromEnv reads the file named by EXEC_CHECKS_FILE; no checks are configured when it is unset.
+func LoadChecksFromEnv() ([]CheckConfig, error) {
+	path := os.Getenv(ChecksFileEnv)
+	if path == "" {
+		return nil, nil
+	}
+	return LoadChecks(path)
+}
+
+// LoadChecks reads and validates a checks file:
+//
+//	{"checks": [{"name": "queue_depth", "command": ["/usr/local/bin/queue-depth"], "format": "json", "interval": "1m"}]}
+func LoadChecks(path string) ([]CheckConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	var file checksFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(file.Checks))
+	for i := range file.Checks {
+		c := &file.Checks[i]
+		if err := validateCheck(c); err != nil {
+			return nil, fmt.Errorf("%s: check %d: %w", path, i+1, err)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("%s: check %q defined twice", path, c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return file.Checks, nil
+}
+
+func validateCheck(c *CheckConfig) error {
+	if !checkNamePattern.MatchString(c.Name) {
+		return fmt.Errorf("name %q must be 1-128 letters, digits, '_', '.' or '-'", c.Name)
+	}
+	if len(c.Command) == 0 || c.Command[0] == "" {
+		return errors.New("command is required")
+	}
+	switch c.Format {
+	case FormatJSON, FormatPrometheus, FormatNagios:
+	default:
+		return fmt.Errorf("format %q must be json, prometheus or nagios", c.Format)
+	}
+	if c.Interval < 0 || c.Timeout < 0 {
+		return errors.New("interval and timeout must not be negative")
+	}
+	if c.Timeout == 0 {
+		c.Timeout = Duration(DefaultCheckTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package collectors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/textutil"
+	"system-stats/internal/modules/exec/infrastructure/entities"
+)
+
+const (
+	// maxStdout caps what is read from a command; longer output is truncated before parsing
+	maxStdout = 64 << 10
+	maxStderr = 4 << 10
+	maxOutput = 1024
+	// maxParallel bounds how many checks run at once
+	maxParallel = 4
+	// waitDelay bounds how long a killed command may keep its pipes open (e.g. via children)
+	waitDelay = time.Second
+	// intervalSlack lets a check run on the tick that is due a little early because of jitter
+	intervalSlack = 500 * time.Millisecond
+)
+
+// ExecMetricsCollector runs the configured checks and keeps the latest result of each.
+type ExecMetricsCollector struct {
+	logger *log.Logger
+	checks []CheckConfig
+
+	mu     sync.Mutex
+	latest map[string]entities.CheckResult
+}
+
+// NewExecMetricsCollector creates a collector for checks (see LoadChecks).
+func NewExecMetricsCollector(logger *log.Logger, checks []CheckConfig) *ExecMetricsCollector {
+	return &ExecMetricsCollector{
+		logger: logger,
+		checks: checks,
+		latest: make(map[string]entities.CheckResult, len(checks)),
+	}
+}
+
+// Collect runs every check whose interval has elapsed and returns the latest result of all
+// checks. A failing check is reported in its status, not as an error.
+func (c *ExecMetricsCollector) Collect(ctx context.Context) (entities.ExecMetric, error) {
+	now := time.Now().UTC()
+
+	c.mu.Lock()
+	var due []CheckConfig
+	for _, check := range c.checks {
+		last, ok := c.latest[check.Name]
+		if !ok || now.Sub(last.RanAt)+intervalSlack >= time.Duration(check.Interval) {
+			due = append(due, check)
+		}
+	}
+	c.mu.Unlock()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallel)
+	results := make([]entities.CheckResult, len(due))
+	for i, check := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = c.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range results {
+		c.latest[r.Check] = r
+	}
+	metric := entities.ExecMetric{Timestamp: now, Checks: make([]entities.CheckResult, 0, len(c.checks))}
+	for _, check := range c.checks {
+		if r, ok := c.latest[check.Name]; ok {
+			metric.Checks = append(metric.Checks, r)
+		}
+	}
+	return metric, ctx.Err()
+}
+
+// Latest returns the most recent result of every check that has run, in configuration order.
+func (c *ExecMetricsCollector) Latest() []entities.CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]entities.CheckResult, 0, len(c.latest))
+	for _, check := range c.checks {
+		if r, ok := c.latest[check.Name]; ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// run executes one check and parses its output.
+func (c *ExecMetricsCollector) run(parent context.Context, check CheckConfig) entities.CheckResult {
+	res := entities.CheckResult{Check: check.Name, Format: check.Format, RanAt: time.Now().UTC(), ExitCode: -1, Gauges: []entities.Gauge{}}
+	timeout := time.Duration(check.Timeout)
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, check.Command[0], check.Command[1:]...)
+	stdout := &limitedBuffer{max: maxStdout}
+	stderr := &limitedBuffer{max: maxStderr}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = waitDelay
+
+	start := time.Now()
+	runErr := cmd.Run()
+	res.DurationSeconds = time.Since(start).Seconds()
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		res.Status = entities.StatusUnknown
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+			res.Error = fmt.Sprintf("timed out after %s", timeout)
+		} else {
+			res.Error = "collection cancelled"
+		}
+		c.logger.Warn("Exec check did not finish", "check", check.Name, "error", res.Error)
+		return c.finish(res, stdout.String())
+	case runErr != nil && !errors.As(runErr, &exitErr):
+		res.Status = entities.StatusUnknown
+		res.Error = runErr.Error()
+		c.logger.Warn("Exec check failed to run", "check", check.Name, "error", runErr)
+		return c.finish(res, "")
+	}
+	res.ExitCode = cmd.ProcessState.ExitCode()
+
+	out := stdout.Bytes()
+	var parseErr error
+	switch check.Format {
+	case FormatNagios:
+		var message string
+		message, res.Gauges, parseErr = ParseNagios(out)
+		res.Output = message
+		res.Status = res.ExitCode
+		if res.Status < entities.StatusOK || res.Status > entities.StatusUnknown {
+			res.Status = entities.StatusUnknown
+		}
+	case FormatJSON, FormatPrometheus:
+		if check.Format == FormatJSON {
+			res.Gauges, parseErr = ParseJSON(out)
+		} else {
+			res.Gauges, parseErr = ParsePrometheus(out)
+		}
+		if res.ExitCode != 0 {
+			res.Status = entities.StatusCritical
+			res.Error = fmt.Sprintf("exit status %d: %s", res.ExitCode, strings.TrimSpace(stderr.String()))
+		}
+	}
+	if parseErr != nil {
+		if res.Status == entities.StatusOK {
+			res.Status = entities.StatusUnknown
+		}
+		if res.Error == "" {
+			res.Error = parseErr.Error()
+		}
+	}
+	if stdout.truncated && res.Error == "" {
+		res.Error = fmt.Sprintf("output truncated to %d bytes", maxStdout)
+	}
+	if res.Output == "" {
+		res.Output = stdout.String()
+	}
+	return c.finish(res, res.Output)
+}
+
+// finish trims the output to its first line, drops non-finite gauges and gauges that do not fit
+// the columns, and sets the state name.
+func (c *ExecMetricsCollector) finish(res entities.CheckResult, output string) entities.CheckResult {
+	first, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	res.Output = textutil.Truncate(first, maxOutput)
+	res.Error = textutil.Truncate(res.Error, maxOutput)
+
+	gauges := res.Gauges[:0]
+	invalid := 0
+	for _, g := range res.Gauges {
+		switch {
+		case math.IsNaN(g.Value) || math.IsInf(g.Value, 0):
+		case !ValidGauge(g):
+			invalid++
+		default:
+			gauges = append(gauges, g)
+		}
+	}
+	if invalid > 0 {
+		c.logger.Warn("dropped exec gauges with invalid names or labels", "check", res.Check, "count", invalid)
+	}
+	res.Gauges = gauges
+	res.State = entities.StatusText(res.Status)
+	return res
+}
+
+// limitedBuffer keeps the first max bytes written and discards the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
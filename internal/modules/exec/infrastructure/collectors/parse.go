@@ -0,0 +1,292 @@
+package collectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+
+	"system-stats/internal/modules/exec/infrastructure/entities"
+)
+
+// Output formats accepted in the checks file.
+const (
+	FormatJSON       = "json"
+	FormatPrometheus = "prometheus"
+	FormatNagios     = "nagios"
+)
+
+// Gauge bounds follow the exec_gauges columns and the custom metrics ingest limits.
+const (
+	maxGaugeName   = 255
+	maxGaugeLabels = 10
+	maxLabelValue  = 128
+	maxLabelsText  = 512
+	maxUnit        = 16
+)
+
+var labelKeyPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+
+// ParseJSON reads gauges from either a list of {"name", "value", "labels"} objects or an object
+// of numbers. Nested objects are flattened with "." and booleans become 1 or 0; other values are ignored.
+func ParseJSON(out []byte) ([]entities.Gauge, error) {
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON output: %w", err)
+	}
+
+	var gauges []entities.Gauge
+	switch v := doc.(type) {
+	case []any:
+		for i, item := range v {
+			obj, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("item %d is not an object", i)
+			}
+			name, _ := obj["name"].(string)
+			value, ok := jsonNumber(obj["value"])
+			if name == "" || !ok {
+				return nil, fmt.Errorf("item %d needs a name and a numeric value", i)
+			}
+			g := entities.Gauge{Name: name, Value: value}
+			if labels, ok := obj["labels"].(map[string]any); ok && len(labels) > 0 {
+				g.Labels = make(map[string]string, len(labels))
+				for k, lv := range labels {
+					g.Labels[k] = fmt.Sprint(lv)
+				}
+			}
+			gauges = append(gauges, g)
+		}
+	case map[string]any:
+		flattenJSON("", v, &gauges)
+		sort.Slice(gauges, func(i, j int) bool { return gauges[i].Name < gauges[j].Name })
+	default:
+		return nil, fmt.Errorf("JSON output must be an object or a list, got %T", doc)
+	}
+	return gauges, nil
+}
+
+func flattenJSON(prefix string, obj map[string]any, out *[]entities.Gauge) {
+	for k, v := range obj {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]any); ok {
+			flattenJSON(name, nested, out)
+			continue
+		}
+		if value, ok := jsonNumber(v); ok {
+			*out = append(*out, entities.Gauge{Name: name, Value: value})
+		}
+	}
+}
+
+func jsonNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// ParsePrometheus reads gauges from the Prometheus text exposition format. Counters, gauges and
+// untyped samples keep their name; summaries and histograms contribute their _sum and _count.
+func ParsePrometheus(out []byte) ([]entities.Gauge, error) {
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(out))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Prometheus output: %w", err)
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var gauges []entities.Gauge
+	for _, name := range names {
+		for _, m := range families[name].GetMetric() {
+			labels := promLabels(m.GetLabel())
+			add := func(n string, v float64) {
+				gauges = append(gauges, entities.Gauge{Name: n, Labels: labels, Value: v})
+			}
+			switch {
+			case m.Gauge != nil:
+				add(name, m.GetGauge().GetValue())
+			case m.Counter != nil:
+				add(name, m.GetCounter().GetValue())
+			case m.Untyped != nil:
+				add(name, m.GetUntyped().GetValue())
+			case m.Summary != nil:
+				add(name+"_sum", m.GetSummary().GetSampleSum())
+				add(name+"_count", float64(m.GetSummary().GetSampleCount()))
+			case m.Histogram != nil:
+				add(name+"_sum", m.GetHistogram().GetSampleSum())
+				add(name+"_count", float64(m.GetHistogram().GetSampleCount()))
+			}
+		}
+	}
+	return gauges, nil
+}
+
+func promLabels(pairs []*dto.LabelPair) map[string]string {
+	if len(pairs) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		labels[p.GetName()] = p.GetValue()
+	}
+	return labels
+}
+
+// ParseNagios splits Nagios plugin output into the status message and perfdata gauges.
+// Perfdata follows the first "|" on the first line and on any line after a "|" in the long text:
+//
+//	DISK OK - free space: / 3326 MB | /=2643MB;5948;5958;0;5968
+func ParseNagios(out []byte) (message string, gauges []entities.Gauge, err error) {
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	first, perf, _ := strings.Cut(lines[0], "|")
+	message = strings.TrimSpace(first)
+
+	perfdata := []string{perf}
+	inPerf := false
+	for _, line := range lines[1:] {
+		if inPerf {
+			perfdata = append(perfdata, line)
+			continue
+		}
+		if _, after, ok := strings.Cut(line, "|"); ok {
+			inPerf = true
+			perfdata = append(perfdata, after)
+		}
+	}
+
+	for _, field := range splitPerfdata(strings.Join(perfdata, " ")) {
+		g, ok, perr := parsePerfdatum(field)
+		if perr != nil {
+			return message, gauges, perr
+		}
+		if ok {
+			gauges = append(gauges, g)
+		}
+	}
+	return message, gauges, nil
+}
+
+// splitPerfdata splits on spaces outside single-quoted labels.
+func splitPerfdata(s string) []string {
+	var fields []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
+
+// parsePerfdatum parses 'label'=value[UOM];[warn];[crit];[min];[max]. Thresholds are not stored;
+// ok is false for the "U" (undeterminable) value.
+func parsePerfdatum(field string) (g entities.Gauge, ok bool, err error) {
+	eq := strings.LastIndex(field, "=")
+	if eq <= 0 {
+		return g, false, fmt.Errorf("invalid perfdata %q", field)
+	}
+	label := strings.Trim(field[:eq], "'")
+	raw, _, _ := strings.Cut(field[eq+1:], ";")
+
+	end := len(raw)
+	for end > 0 && !strings.ContainsRune("0123456789.", rune(raw[end-1])) {
+		end--
+	}
+	if raw == "U" {
+		return g, false, nil
+	}
+	value, err := strconv.ParseFloat(raw[:end], 64)
+	if err != nil {
+		return g, false, fmt.Errorf("invalid perfdata value %q for %s", raw, label)
+	}
+	return entities.Gauge{Name: label, Value: value, Unit: raw[end:]}, true, nil
+}
+
+// CanonicalLabels renders labels as k="v" pairs sorted by key, the form stored and exported.
+func CanonicalLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + strconv.Quote(labels[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+// ValidGauge reports whether a gauge fits the exec_gauges columns: a printable UTF-8 name of at
+// most 255 bytes, a unit of at most 16 and at most 10 labels with Prometheus-style keys and values
+// of at most 128 bytes.
+// Names are not cut, as two long names with the same prefix would become one series.
+func ValidGauge(g entities.Gauge) bool {
+	if g.Name == "" || len(g.Name) > maxGaugeName || !printable(g.Name) || len(g.Unit) > maxUnit || !printable(g.Unit) {
+		return false
+	}
+	if len(g.Labels) > maxGaugeLabels {
+		return false
+	}
+	for k, v := range g.Labels {
+		if !labelKeyPattern.MatchString(k) || len(v) > maxLabelValue || !utf8.ValidString(v) {
+			return false
+		}
+	}
+	return len(CanonicalLabels(g.Labels)) <= maxLabelsText
+}
+
+func printable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package entities
+
+import "time"
+
+// Check states derived from the Nagios plugin exit codes; JSON and Prometheus checks use them too.
+const (
+	StatusOK       = 0
+	StatusWarning  = 1
+	StatusCritical = 2
+	StatusUnknown  = 3
+)
+
+// StatusText returns the lower-case Nagios state name of a status code.
+func StatusText(status int) string {
+	switch status {
+	case StatusOK:
+		return "ok"
+	case StatusWarning:
+		return "warning"
+	case StatusCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// ExecMetric holds the latest result of every configured check.
+type ExecMetric struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// CheckResult is the outcome of one command run.
+type CheckResult struct {
+	// Check is the configured check name
+	Check string `json:"check"`
+
+	// Format is the output parser used: json, prometheus or nagios
+	Format string `json:"format"`
+
+	// RanAt is when the command was started
+	RanAt time.Time `json:"ran_at"`
+
+	// Status is 0 OK, 1 WARNING, 2 CRITICAL or 3 UNKNOWN (see StatusText)
+	Status int    `json:"status"`
+	State  string `json:"state"`
+
+	// ExitCode is the command's exit code, -1 when it did not exit on its own
+	ExitCode int `json:"exit_code"`
+
+	DurationSeconds float64 `json:"duration_seconds"`
+
+	// Output is the first line of the plugin output (Nagios) or of stdout, truncated
+	Output string `json:"output,omitempty"`
+
+	// Error explains an UNKNOWN status: start failure, timeout or unparsable output
+	Error string `json:"error,omitempty"`
+
+	Gauges []Gauge `json:"gauges"`
+}
+
+// Gauge is one named numeric value reported by a check.
+type Gauge struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+	// Unit is the Nagios perfdata unit of measure (s, ms, %, B, KB, c, ...), if any
+	Unit string `json:"unit,omitempty"`
+}
@@ -0,0 +1,40 @@
+package entities
+
+import "time"
+
+// HistoricalExecCheck is one stored check run.
+type HistoricalExecCheck struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_exec_check_host_ts,priority:1"`
+	CheckName string    `json:"check" gorm:"primaryKey;size:128"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_exec_check_host_ts,priority:2"`
+
+	Format          string  `json:"format" gorm:"size:16"`
+	Status          int     `json:"status"`
+	State           string  `json:"state" gorm:"size:16"`
+	ExitCode        int     `json:"exit_code"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Output          string  `json:"output,omitempty" gorm:"size:1024"`
+	Error           string  `json:"error,omitempty" gorm:"size:1024"`
+
+	// Gauges keeps the full result so the latest run can be returned as reported
+	Gauges []Gauge `json:"gauges" gorm:"serializer:json"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalExecCheck) TableName() string { return "exec_check_results" }
+
+// HistoricalExecGauge is one gauge sample of a check, stored per row for series queries.
+type HistoricalExecGauge struct {
+	HostID    uint   `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_exec_gauge_series,priority:1"`
+	CheckName string `json:"check" gorm:"primaryKey;size:128;index:idx_exec_gauge_series,priority:2"`
+	Name      string `json:"name" gorm:"primaryKey;size:255;index:idx_exec_gauge_series,priority:3"`
+	// Labels is the canonical k="v" list sorted by key ("" when unlabelled)
+	Labels    string    `json:"labels" gorm:"primaryKey;size:512"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_exec_gauge_series,priority:4"`
+
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty" gorm:"size:16"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalExecGauge) TableName() string { return "exec_gauges" }
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/exec/infrastructure/collectors"
+	"system-stats/internal/modules/exec/infrastructure/entities"
+)
+
+type ExecRepository interface {
+	// SaveCurrentMetric stores every check result with its gauges. Results already stored
+	// (checks that did not run again since the last save) are skipped.
+	SaveCurrentMetric(ctx context.Context, metric entities.ExecMetric, hostId uint) error
+	// GetLatestByHost returns the most recent stored run of every check of a host.
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalExecCheck, error)
+	// GetCheckHistoryByHost returns check runs in time order; an empty check returns all checks.
+	GetCheckHistoryByHost(ctx context.Context, hostId uint, hours float64, check string) ([]entities.HistoricalExecCheck, error)
+	// GetGaugeHistoryByHost returns gauge samples in time order, optionally limited to one check and gauge name.
+	GetGaugeHistoryByHost(ctx context.Context, hostId uint, hours float64, check, name string) ([]entities.HistoricalExecGauge, error)
+}
+
+type execRepository struct {
+	db *gorm.DB
+}
+
+func NewExecRepository(db *gorm.DB) ExecRepository {
+	return &execRepository{db: db}
+}
+
+func (r *execRepository) SaveCurrentMetric(ctx context.Context, metric entities.ExecMetric, hostId uint) error {
+	if len(metric.Checks) == 0 {
+		return nil
+	}
+	checks := make([]entities.HistoricalExecCheck, 0, len(metric.Checks))
+	var gauges []entities.HistoricalExecGauge
+	for _, c := range metric.Checks {
+		ts := c.RanAt.UTC().Truncate(time.Microsecond)
+		checks = append(checks, entities.HistoricalExecCheck{
+			HostID:          hostId,
+			CheckName:       c.Check,
+			Timestamp:       ts,
+			Format:          c.Format,
+			Status:          c.Status,
+			State:           c.State,
+			ExitCode:        c.ExitCode,
+			DurationSeconds: c.DurationSeconds,
+			Output:          c.Output,
+			Error:           c.Error,
+			Gauges:          c.Gauges,
+		})
+		seen := make(map[[2]string]bool, len(c.Gauges))
+		for _, g := range c.Gauges {
+			labels := collectors.CanonicalLabels(g.Labels)
+			// Duplicate series in one output would violate the primary key; the first value wins
+			if key := [2]string{g.Name, labels}; !seen[key] {
+				seen[key] = true
+				gauges = append(gauges, entities.HistoricalExecGauge{
+					HostID:    hostId,
+					CheckName: c.Check,
+					Name:      g.Name,
+					Labels:    labels,
+					Timestamp: ts,
+					Value:     g.Value,
+					Unit:      g.Unit,
+				})
+			}
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&checks).Error; err != nil {
+			return err
+		}
+		if len(gauges) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&gauges, 200).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *execRepository) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalExecCheck, error) {
+	var rows []entities.HistoricalExecCheck
+	latest := r.db.Model(&entities.HistoricalExecCheck{}).
+		Select("check_name, MAX(timestamp) AS timestamp").
+		Where("host_id = ?", hostId).
+		Group("check_name")
+	err := r.db.WithContext(ctx).
+		Joins("JOIN (?) AS latest ON latest.check_name = exec_check_results.check_name AND latest.timestamp = exec_check_results.timestamp", latest).
+		Where("exec_check_results.host_id = ?", hostId).
+		Order("exec_check_results.check_name ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *execRepository) GetCheckHistoryByHost(ctx context.Context, hostId uint, hours float64, check string) ([]entities.HistoricalExecCheck, error) {
+	var rows []entities.HistoricalExecCheck
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if check != "" {
+		q = q.Where("check_name = ?", check)
+	}
+	err := q.Order("timestamp ASC").Order("check_name ASC").Find(&rows).Error
+	return rows, err
+}
+
+func (r *execRepository) GetGaugeHistoryByHost(ctx context.Context, hostId uint, hours float64, check, name string) ([]entities.HistoricalExecGauge, error) {
+	var rows []entities.HistoricalExecGauge
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if check != "" {
+		q = q.Where("check_name = ?", check)
+	}
+	if name != "" {
+		q = q.Where("name = ?", name)
+	}
+	err := q.Order("timestamp ASC").Order("check_name ASC").Order("name ASC").Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,63 @@
+// Package exec registers the exec module, which runs local check commands
+// (JSON, Prometheus text or Nagios plugin output) and stores their gauges.
+package exec
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	execservice "system-stats/internal/modules/exec/application"
+	"system-stats/internal/modules/exec/infrastructure/collectors"
+	"system-stats/internal/modules/exec/infrastructure/entities"
+	"system-stats/internal/modules/exec/infrastructure/repositories"
+	handlers "system-stats/internal/modules/exec/presentation"
+	historycore "system-stats/internal/modules/history_metrics/core"
+)
+
+// Module runs the checks listed in EXEC_CHECKS_FILE and serves /exec and /exec/gauges.
+type Module struct{}
+
+func (Module) Name() string { return "exec" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 4,
+		Name:    "exec_check_tables",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalExecCheck{}, &entities.HistoricalExecGauge{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalExecGauge{}, &entities.HistoricalExecCheck{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"exec_check_results", "exec_gauges"} }
+
+func (Module) HostTables() []string { return []string{"exec_gauges", "exec_check_results"} }
+
+// Build fails on an invalid checks file. Without checks there is nothing to schedule or export,
+// but stored results stay queryable.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	checks, err := collectors.LoadChecksFromEnv()
+	if err != nil {
+		return registry.Instance{}, err
+	}
+	service := execservice.NewService(deps.Logger, checks, repositories.NewExecRepository(deps.DB))
+	handler := handlers.NewExecHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/exec", handler.HandleExecStats)
+			r.GET("/exec/gauges", handler.HandleGaugeHistory)
+		},
+	}
+	if len(checks) > 0 {
+		// Check results are not part of the live snapshot
+		collector := historycore.NewModuleCollector[entities.ExecMetric]("exec", service.Collect, service.Save, nil)
+		inst.Collector = &collector
+		inst.Metrics = execservice.NewPrometheusCollector(service)
+	}
+	return inst, nil
+}
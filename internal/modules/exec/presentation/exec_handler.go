@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	execservice "system-stats/internal/modules/exec/application"
+	hostservice "system-stats/internal/modules/hosts/application"
+)
+
+// ExecHandler handles HTTP requests for exec check results.
+type ExecHandler struct {
+	logger  *log.Logger
+	service execservice.Service
+	hosts   hostservice.Service
+}
+
+// NewExecHandler creates a new HTTP handler for exec check endpoints.
+func NewExecHandler(logger *log.Logger, service execservice.Service, hosts hostservice.Service) *ExecHandler {
+	return &ExecHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleExecStats returns the latest run of every check and the run history.
+//
+// @Summary     Exec checks
+// @Description Returns the latest result of every configured exec check (status, exit code, output, gauges) and the history of check runs.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       check    query    string   false  "Limit history to one check"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /exec [get]
+func (h *ExecHandler) HandleExecStats(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	check := c.Query("check")
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyExecPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for exec checks", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	latest, err := h.service.GetLatestByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest exec checks")
+			return
+		}
+		h.logger.Error("Failed to fetch latest exec checks", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetCheckHistoryByHost(ctx, effective, hours, check)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching exec check history")
+			return
+		}
+		h.logger.Error("Failed to fetch exec check history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"latest":  latest,
+		"history": history,
+	})
+}
+
+// HandleGaugeHistory returns the gauge series reported by exec checks.
+//
+// @Summary     Exec check gauges
+// @Description Returns gauge samples (name, canonical labels, value, unit) reported by exec checks.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       check    query    string   false  "Limit to one check"
+// @Param       name     query    string   false  "Limit to one gauge name"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /exec/gauges [get]
+func (h *ExecHandler) HandleGaugeHistory(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyExecGaugesPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for exec gauges", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetGaugeHistoryByHost(ctx, effective, hours, c.Query("check"), c.Query("name"))
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching exec gauges")
+			return
+		}
+		h.logger.Error("Failed to fetch exec gauges", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": history,
+	})
+}
@@ -6,7 +6,10 @@ import (
 
 	"github.com/charmbracelet/log"
 
+	"system-stats/internal/app/collectorhealth"
+	eventsservice "system-stats/internal/modules/events/application"
 	"system-stats/internal/modules/health/infrastructure/entities"
+	hostentities "system-stats/internal/modules/hosts/infrastructure/entities"
 	hostrepos "system-stats/internal/modules/hosts/infrastructure/repositories"
 	noderepos "system-stats/internal/modules/nodes/infrastructure/repositories"
 )
@@ -28,6 +31,8 @@ type service struct {
 	logger         *log.Logger
 	hostRepository hostrepos.HostRepository
 	nodeCredRepo   noderepos.NodeCredentialRepository
+	collectors     *collectorhealth.Tracker
+	events         eventsservice.Service
 	startTime      time.Time
 }
 
@@ -35,16 +40,48 @@ func NewService(
 	logger *log.Logger,
 	hostRepository hostrepos.HostRepository,
 	nodeCredRepo noderepos.NodeCredentialRepository,
+	collectors *collectorhealth.Tracker,
+	events eventsservice.Service,
 	startTime time.Time,
 ) Service {
 	return &service{
 		logger:         logger,
 		hostRepository: hostRepository,
 		nodeCredRepo:   nodeCredRepo,
+		collectors:     collectors,
+		events:         events,
 		startTime:      startTime,
 	}
 }
 
+// withCollectors adds a summary of the local collector health to resp. /health is public,
+// so error messages are left out.
+func (s *service) withCollectors(resp *entities.HealthResponse) *entities.HealthResponse {
+	statuses := s.collectors.Statuses()
+	if len(statuses) == 0 {
+		return resp
+	}
+	summary := collectorhealth.Summarize(statuses)
+	healthy := len(summary.Failing) == 0
+	resp.Collectors = &summary
+	resp.CollectorsHealthy = &healthy
+	return resp
+}
+
+// withProblems adds the number of open problems (warning or critical host events) of hostID
+// to resp; their messages stay on the authenticated /events. A failed lookup is logged and
+// leaves them out rather than failing the health check.
+func (s *service) withProblems(ctx context.Context, hostID uint, resp *entities.HealthResponse) *entities.HealthResponse {
+	problems, err := s.events.Problems(ctx, hostID)
+	if err != nil {
+		s.logger.Warn("Failed to look up host problems", "error", err, "host_id", hostID)
+		return resp
+	}
+	counts := entities.CountProblems(problems)
+	resp.Problems = &counts
+	return resp
+}
+
 func (s *service) GetHealth(ctx context.Context, hostID *uint) (*entities.HealthResponse, error) {
 	s.logger.Debug("Getting health information", "host_id", hostID)
 
@@ -52,11 +89,11 @@ func (s *service) GetHealth(ctx context.Context, hostID *uint) (*entities.Health
 	serverUptime := formatSessionUptime(time.Since(s.startTime))
 
 	if hostID == nil {
-		return &entities.HealthResponse{
+		return s.withProblems(ctx, hostentities.LocalCollectorHostID, s.withCollectors(&entities.HealthResponse{
 			Status:    "ok",
 			Timestamp: now,
 			Uptime:    serverUptime,
-		}, nil
+		})), nil
 	}
 
 	host, err := s.hostRepository.GetHostByID(ctx, *hostID)
@@ -117,6 +154,11 @@ func (s *service) GetHealth(ctx context.Context, hostID *uint) (*entities.Health
 		resp.HostUptime = 0
 	}
 
+	if host.ID == hostentities.LocalCollectorHostID {
+		s.withCollectors(resp)
+	}
+	s.withProblems(ctx, host.ID, resp)
+
 	s.logger.Debug("Health information retrieved", "host_id", hostID, "status", status, "is_agent", isAgent)
 	return resp, nil
 }
@@ -1,6 +1,11 @@
 package entities
 
-import "time"
+import (
+	"time"
+
+	"system-stats/internal/app/collectorhealth"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+)
 
  // HealthResponse represents health check information.
 type HealthResponse struct {
@@ -30,4 +35,35 @@ type HealthResponse struct {
 
 	// LastSeen indicates when the host was last active (optional)
 	LastSeen time.Time `json:"last_seen,omitempty"`
+
+	// Collectors summarizes this instance's collectors (local host only); error details
+	// are on the authenticated /collectors/status
+	Collectors *collectorhealth.Summary `json:"collectors,omitempty"`
+
+	// CollectorsHealthy is false when a local collector is failing or stale (local host only)
+	CollectorsHealthy *bool `json:"collectors_healthy,omitempty"`
+
+	// Problems counts the host's open warning and critical events (e.g. a watched process is down);
+	// the events themselves are on the authenticated /events
+	Problems *ProblemCounts `json:"problems,omitempty"`
+}
+
+// ProblemCounts is the number of open problems per severity.
+type ProblemCounts struct {
+	Warning  int `json:"warning"`
+	Critical int `json:"critical"`
+}
+
+// CountProblems counts open problems by severity.
+func CountProblems(problems []evententities.HostEvent) ProblemCounts {
+	var counts ProblemCounts
+	for _, p := range problems {
+		switch p.Severity {
+		case evententities.SeverityWarning:
+			counts.Warning++
+		case evententities.SeverityCritical:
+			counts.Critical++
+		}
+	}
+	return counts
 }
@@ -2,57 +2,70 @@ package application
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"system-stats/internal/app/collectorhealth"
+	"system-stats/internal/app/config"
+	"system-stats/internal/app/snapshot"
 	"system-stats/internal/modules/history_metrics/core"
 	hostservice "system-stats/internal/modules/hosts/application"
 
 	"github.com/charmbracelet/log"
 )
 
-// MetricsSaver defines the interface for module services
-type MetricsSaver interface {
-	CollectAndSave(ctx context.Context, hostId uint) error
-}
-
-type metricsCollector struct {
-	services []MetricsSaver
-}
-
-func NewMetricsCollector(services ...MetricsSaver) *metricsCollector {
-	return &metricsCollector{
-		services: services,
-	}
+// moduleRunner is the scheduling state of one module collector.
+type moduleRunner struct {
+	collector core.ModuleCollector
+	schedule  config.CollectorSchedule
+	running   atomic.Bool
 }
 
 type historicalMetricsService struct {
-	logger           *log.Logger
-	metricsCollector *metricsCollector
-	hostService      hostservice.Service
-	afterCollect     func()
-	ticker           *time.Ticker
-	stopChan         chan struct{}
-	isRunning        bool
-	stopMutex        sync.Mutex
+	logger       *log.Logger
+	modules      []*moduleRunner
+	hostInterval time.Duration
+	store        *snapshot.Store
+	health       *collectorhealth.Tracker
+	hostService  hostservice.Service
+	afterCollect func(snap *snapshot.Snapshot, module string)
+	hostID       atomic.Uint64
+	publishMutex sync.Mutex
+	stopChan     chan struct{}
+	isRunning    bool
+	stopMutex    sync.Mutex
 }
 
 func NewHistoricalMetricsService(
 	logger *log.Logger,
-	metricsCollector *metricsCollector,
+	collectors []core.ModuleCollector,
+	collection config.CollectionConfig,
+	store *snapshot.Store,
+	health *collectorhealth.Tracker,
 	hostService hostservice.Service,
 ) core.HistoricalMetricsService {
+	modules := make([]*moduleRunner, 0, len(collectors))
+	for _, c := range collectors {
+		sched := collection.For(c.Name)
+		health.Register(c.Name, sched.Interval, sched.Timeout)
+		modules = append(modules, &moduleRunner{collector: c, schedule: sched})
+	}
 	return &historicalMetricsService{
-		logger:           logger,
-		metricsCollector: metricsCollector,
-		hostService:      hostService,
-		stopChan:         make(chan struct{}),
+		logger:       logger,
+		modules:      modules,
+		hostInterval: collection.Default.Interval,
+		store:        store,
+		health:       health,
+		hostService:  hostService,
+		stopChan:     make(chan struct{}),
 	}
 }
 
-// WithAfterCollect sets a hook called after every successful collection cycle.
-// Used to publish metrics to the SSE broker without coupling this service to the stream package.
-func WithAfterCollect(svc core.HistoricalMetricsService, fn func()) core.HistoricalMetricsService {
+// WithAfterCollect sets a hook called with the published snapshot after every module run.
+// Calls are serialized. Used to publish metrics to the SSE broker without coupling this service to the stream package.
+func WithAfterCollect(svc core.HistoricalMetricsService, fn func(snap *snapshot.Snapshot, module string)) core.HistoricalMetricsService {
 	s := svc.(*historicalMetricsService)
 	s.afterCollect = fn
 	return s
@@ -61,69 +74,136 @@ func WithAfterCollect(svc core.HistoricalMetricsService, fn func()) core.Histori
 func (s *historicalMetricsService) CollectAndSaveMetrics(ctx context.Context) error {
 	s.logger.Debug("Starting metrics collection cycle for all modules")
 
-	// First, register or update current host
+	if err := s.registerHost(ctx); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, m := range s.modules {
+		if !m.running.CompareAndSwap(false, true) {
+			s.skip(m)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer m.running.Store(false)
+			s.runModule(ctx, m)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// registerHost registers or updates the local host and caches its ID for module runs.
+func (s *historicalMetricsService) registerHost(ctx context.Context) error {
 	host, err := s.hostService.RegisterOrUpdateCurrentHost(ctx)
 	if err != nil {
 		s.logger.Error("Failed to register/update current host", "error", err)
 		return err
 	}
+	s.hostID.Store(uint64(host.ID))
+	s.logger.Debug("Current host registered/updated", "host_id", host.ID, "name", host.Name)
+	return nil
+}
 
-	hostId := host.ID
-	s.logger.Debug("Current host registered/updated", "host_id", hostId, "name", host.Name)
+// runModule collects and saves one module under its timeout and publishes the result.
+func (s *historicalMetricsService) runModule(ctx context.Context, m *moduleRunner) {
+	hostId := uint(s.hostID.Load())
+	if hostId == 0 {
+		s.logger.Debug("Skipping collection until the local host is registered", "module", m.collector.Name)
+		return
+	}
 
-	// Collect and save metrics for all modules with host_id
-	for _, service := range s.metricsCollector.services {
-		// Collect and save metrics
-		err := service.CollectAndSave(ctx, hostId)
-		if err != nil {
-			s.logger.Error("Failed to collect and save metrics", "error", err, "host_id", hostId)
-			continue // Continue with other services even if one fails
-		}
+	runCtx, cancel := context.WithTimeout(ctx, m.schedule.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	update, err := m.collector.CollectAndSave(runCtx, hostId)
+	elapsed := time.Since(start)
+
+	timedOut := err != nil && errors.Is(runCtx.Err(), context.DeadlineExceeded)
+	s.health.RecordRun(m.collector.Name, elapsed, err, timedOut)
+	if timedOut {
+		s.logger.Warn("Collector timed out", "module", m.collector.Name, "timeout", m.schedule.Timeout, "host_id", hostId)
+	} else if err != nil {
+		s.logger.Error("Failed to collect and save metrics", "module", m.collector.Name, "error", err, "host_id", hostId)
+	}
+	if elapsed > m.schedule.Interval {
+		s.health.RecordOverrun(m.collector.Name)
+		s.logger.Warn("Collector overran its interval", "module", m.collector.Name, "elapsed", elapsed.Round(time.Millisecond), "interval", m.schedule.Interval)
 	}
 
-	if s.afterCollect != nil {
-		s.afterCollect()
+	if update != nil {
+		s.publishMutex.Lock()
+		snap := s.store.Apply(hostId, time.Now().UTC(), update)
+		if s.afterCollect != nil {
+			s.afterCollect(snap, m.collector.Name)
+		}
+		s.publishMutex.Unlock()
 	}
-	return nil
 }
 
-func (s *historicalMetricsService) StartPeriodicCollection(ctx context.Context, interval time.Duration) error {
-	s.stopMutex.Lock()
-	defer s.stopMutex.Unlock()
+func (s *historicalMetricsService) skip(m *moduleRunner) {
+	s.health.RecordSkip(m.collector.Name)
+	s.logger.Warn("Collection skipped, previous run still in progress", "module", m.collector.Name, "interval", m.schedule.Interval)
+}
 
+func (s *historicalMetricsService) StartPeriodicCollection(ctx context.Context) error {
+	s.stopMutex.Lock()
 	if s.isRunning {
+		s.stopMutex.Unlock()
 		return nil
 	}
-
-	s.ticker = time.NewTicker(interval)
 	s.stopChan = make(chan struct{})
 	s.isRunning = true
+	stop := s.stopChan
+	// A slow first collection must not block StopPeriodicCollection; the tickers below
+	// see the closed stop channel and exit if it was called meanwhile
+	s.stopMutex.Unlock()
 
 	if err := s.CollectAndSaveMetrics(ctx); err != nil {
 		s.logger.Error("Initial metrics collection failed", "error", err)
 	}
 
-	go func() {
-		defer s.ticker.Stop()
+	// Host registration keeps the local host row fresh independently of module schedules
+	go s.every(ctx, stop, s.hostInterval, func() {
+		_ = s.registerHost(ctx)
+	})
 
-		for {
-			select {
-			case <-s.ticker.C:
-				if err := s.CollectAndSaveMetrics(ctx); err != nil {
-					s.logger.Error("Periodic metrics collection failed", "error", err)
-				}
-			case <-s.stopChan:
-				return
-			case <-ctx.Done():
+	for _, m := range s.modules {
+		go s.every(ctx, stop, m.schedule.Interval, func() {
+			if !m.running.CompareAndSwap(false, true) {
+				s.skip(m)
 				return
 			}
-		}
-	}()
-
-	s.logger.Info("Started periodic metrics collection", "interval", interval)
+			go func() {
+				defer m.running.Store(false)
+				s.runModule(ctx, m)
+			}()
+		})
+		s.logger.Info("Started periodic metrics collection", "module", m.collector.Name, "interval", m.schedule.Interval, "timeout", m.schedule.Timeout)
+	}
 	return nil
 }
 
+// every calls fn on each tick until stop is closed or ctx is done.
+func (s *historicalMetricsService) every(ctx context.Context, stop <-chan struct{}, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			fn()
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (s *historicalMetricsService) StopPeriodicCollection() {
 	s.stopMutex.Lock()
 	defer s.stopMutex.Unlock()
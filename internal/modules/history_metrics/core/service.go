@@ -5,24 +5,69 @@ package core
 
 import (
 	"context"
-	"time"
+
+	"system-stats/internal/app/snapshot"
 )
 
+ // ModuleCollector is one module run by the scheduler on its own interval.
+ // CollectAndSave collects and persists the module's metric and returns the update that places it
+ // into the shared snapshot; a non-nil update is published even when saving failed.
+ // Collect does the same without persisting, for live snapshots outside the schedule.
+type ModuleCollector struct {
+	Name           string
+	Collect        func(ctx context.Context) (snapshot.Update, error)
+	CollectAndSave func(ctx context.Context, hostId uint) (snapshot.Update, error)
+}
+
+ // NewModuleCollector adapts a typed collect/save pair to the scheduler. set places the metric
+ // into its snapshot field; modules outside the live snapshot pass nil and publish no update.
+func NewModuleCollector[M any](
+	name string,
+	collect func(ctx context.Context) (M, error),
+	save func(ctx context.Context, metric M, hostId uint) error,
+	set func(snap *snapshot.Snapshot, metric *M),
+) ModuleCollector {
+	return ModuleCollector{
+		Name: name,
+		Collect: func(ctx context.Context) (snapshot.Update, error) {
+			metric, err := collect(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return snapshotUpdate(set, metric), nil
+		},
+		CollectAndSave: func(ctx context.Context, hostId uint) (snapshot.Update, error) {
+			metric, err := collect(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return snapshotUpdate(set, metric), save(ctx, metric, hostId)
+		},
+	}
+}
+
+func snapshotUpdate[M any](set func(snap *snapshot.Snapshot, metric *M), metric M) snapshot.Update {
+	if set == nil {
+		return nil
+	}
+	return func(snap *snapshot.Snapshot) { set(snap, &metric) }
+}
+
  // HistoricalMetricsService provides a high-level interface for working with historical metrics.
  // This interface defines the contract for collecting, storing, and retrieving
  // system performance metrics including CPU, memory, disk, network statistics.
 type HistoricalMetricsService interface {
-	 // CollectAndSaveMetrics collects and persists all current system metrics.
-	 // This method orchestrates the collection of system metrics,
-	 // then saves them to the configured repository and updates the cache.
+	 // CollectAndSaveMetrics collects and persists all current system metrics once.
+	 // This method registers the local host, runs every module collector concurrently
+	 // and waits for them, publishing each result to the snapshot store.
 	CollectAndSaveMetrics(ctx context.Context) error
 
 	 // StartPeriodicCollection begins automatic periodic collection of metrics.
-	 // This method starts a background goroutine that collects metrics at regular intervals
-	 // and can be stopped using StopPeriodicCollection.
-	StartPeriodicCollection(ctx context.Context, interval time.Duration) error
+	 // Every module runs on its own ticker with the interval and timeout from the collection
+	 // config; it can be stopped using StopPeriodicCollection.
+	StartPeriodicCollection(ctx context.Context) error
 
 	 // StopPeriodicCollection stops the periodic metric collection process.
-	 // This method safely terminates the background collection goroutine and cleans up resources.
+	 // This method safely terminates the background collection goroutines and cleans up resources.
 	StopPeriodicCollection()
 }
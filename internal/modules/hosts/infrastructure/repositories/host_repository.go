@@ -9,13 +9,8 @@ import (
 
 	"gorm.io/gorm"
 
-	cpuentities "system-stats/internal/modules/cpu/infrastructure/entities"
-	diskentities "system-stats/internal/modules/disk/infrastructure/entities"
-	dockerdomain "system-stats/internal/modules/docker/domain/repositories"
-	dockerentities "system-stats/internal/modules/docker/infrastructure/entities"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
 	localentities "system-stats/internal/modules/hosts/infrastructure/entities"
-	memoryentities "system-stats/internal/modules/memory/infrastructure/entities"
-	networkentities "system-stats/internal/modules/network/infrastructure/entities"
 	nodeentities "system-stats/internal/modules/nodes/infrastructure/entities"
 )
 
@@ -37,10 +32,14 @@ type HostRepository interface {
 
 type hostRepository struct {
 	db *gorm.DB
+	// hostTables are the metric modules' per-host tables, emptied by DeleteHostCascade
+	hostTables []string
 }
 
-func NewHostRepository(db *gorm.DB) HostRepository {
-	return &hostRepository{db: db}
+// NewHostRepository creates the host repository. hostTables are the per-host tables of every
+// metric module (registry.Registry.HostTables), deleted with the host.
+func NewHostRepository(db *gorm.DB, hostTables []string) HostRepository {
+	return &hostRepository{db: db, hostTables: hostTables}
 }
 
 // reclaimDuplicateLocalRows removes legacy host rows (same MAC or name, no push credential) so id=1 can be created.
@@ -275,24 +274,12 @@ func (r *hostRepository) DeleteHostCascade(ctx context.Context, hostID uint) err
 		}
 		_ = tx.Model(&nodeentities.NodeJoinToken{}).Where("host_id = ?", hostID).Update("host_id", nil).Error
 
-		if err := tx.Where("host_id = ?", hostID).Delete(&cpuentities.HistoricalCPUMetric{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&memoryentities.HistoricalMemoryMetric{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&diskentities.HistoricalDiskMetric{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&networkentities.HistoricalNetworkMetric{}).Error; err != nil {
-			return err
-		}
-
-		sub := tx.Model(&dockerdomain.HistoricalDockerMetric{}).Select("timestamp").Where("host_id = ?", hostID)
-		if err := tx.Where("metric_timestamp IN (?)", sub).Delete(&dockerentities.DockerContainerEntity{}).Error; err != nil {
-			return err
+		for _, table := range r.hostTables {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE host_id = ?", hostID).Error; err != nil {
+				return fmt.Errorf("delete %s rows: %w", table, err)
+			}
 		}
-		if err := tx.Where("host_id = ?", hostID).Delete(&dockerdomain.HistoricalDockerMetric{}).Error; err != nil {
+		if err := tx.Where("host_id = ?", hostID).Delete(&evententities.HostEvent{}).Error; err != nil {
 			return err
 		}
 
@@ -0,0 +1,32 @@
+package kernellog
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/modules/kernellog/infrastructure/entities"
+)
+
+var descKernelLogMessages = prometheus.NewDesc("system_kernel_log_messages_total", "Classified kernel log messages read since start; alert on its increase.", []string{"category"}, nil)
+
+// PrometheusCollector exports the kernel log messages counted per category since start.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descKernelLogMessages
+}
+
+// Collect sends the in-memory counts; nothing is read from the database. Every category is
+// exported so an increase can be taken before its first message.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	totals := c.service.MessageTotals()
+	for _, category := range entities.Categories {
+		ch <- prometheus.MustNewConstMetric(descKernelLogMessages, prometheus.CounterValue, float64(totals[category]), category)
+	}
+}
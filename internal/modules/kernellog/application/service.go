@@ -0,0 +1,259 @@
+package kernellog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/textutil"
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/kernellog/infrastructure/collectors"
+	"system-stats/internal/modules/kernellog/infrastructure/entities"
+	"system-stats/internal/modules/kernellog/infrastructure/repositories"
+)
+
+// Kernel log events: each read with messages of a category records a warning or critical
+// event for it (subject the category), which stays a problem until the category has been
+// quiet for the quiet period.
+const (
+	EventSource = "kernellog"
+	EventLogged = "logged"
+	EventQuiet  = "quiet"
+)
+
+const (
+	// maxPushEvents bounds the events kept for, and accepted from, a push
+	maxPushEvents = 256
+	// maxPushSamples bounds the samples accepted from a push
+	maxPushSamples = 64
+)
+
+type Service interface {
+	// Collect reads the kernel log from the cursor, classifies the messages and derives the events.
+	Collect(ctx context.Context) (entities.KernelLogMetric, error)
+	// Save stores the samples, records the events and, for a local read, saves the cursor.
+	Save(ctx context.Context, metric entities.KernelLogMetric, hostId uint) error
+	// Latest returns this instance's last read (no database access); nil before the first one.
+	Latest() *entities.KernelLogMetric
+	// PushData returns the last read with the events of recent reads; nil before the first one.
+	PushData() *entities.KernelLogMetric
+	// ReceivePush stores a read pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	// MessageTotals returns the classified messages read per category since start.
+	MessageTotals() map[string]uint64
+	GetSamplesByHost(ctx context.Context, hostId uint, hours float64, category string) ([]entities.HistoricalKernelLogSample, error)
+	GetCountsByHost(ctx context.Context, hostId uint, hours float64) ([]repositories.CategoryCount, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.KernelLogCollector
+	repo      repositories.KernelLogRepository
+	events    eventsservice.Service
+	quiet     time.Duration
+
+	mu     sync.RWMutex
+	latest *entities.KernelLogMetric
+	recent *eventsservice.Recent[evententities.HostEvent]
+	totals map[string]uint64
+	// cursorLoaded is set once the saved cursor was restored
+	cursorLoaded bool
+	// open holds the last message time of each category with an open problem
+	open map[string]time.Time
+	// seeded is set once problems left open by a previous run were taken over
+	seeded bool
+}
+
+// NewService creates the kernel log service; events may be nil to record no events.
+func NewService(logger *log.Logger, repo repositories.KernelLogRepository, events eventsservice.Service, file string, quiet time.Duration) Service {
+	return &service{
+		logger:    logger,
+		collector: collectors.NewKernelLogCollector(logger, file),
+		repo:      repo,
+		events:    events,
+		quiet:     quiet,
+		totals:    make(map[string]uint64),
+		open:      make(map[string]time.Time),
+		recent:    eventsservice.NewRecentEvents(maxPushEvents),
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.KernelLogMetric, error) {
+	s.mu.Lock()
+	loaded := s.cursorLoaded
+	s.mu.Unlock()
+	if !loaded {
+		cursor, err := s.repo.GetCursor(ctx, s.collector.Source())
+		if err != nil {
+			return entities.KernelLogMetric{}, err
+		}
+		s.collector.SetCursor(cursor)
+		s.mu.Lock()
+		s.cursorLoaded = true
+		s.mu.Unlock()
+	}
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	metric.Events = s.categoryEvents(metric)
+	for _, sample := range metric.Samples {
+		s.totals[sample.Category] += sample.Count
+	}
+	s.latest = &metric
+	s.recent.Add(metric.Timestamp, metric.Events)
+	return metric, nil
+}
+
+// categoryEvents records an event per category with messages and resolves the problems of
+// categories quiet for the quiet period. The caller holds s.mu.
+func (s *service) categoryEvents(metric entities.KernelLogMetric) []evententities.HostEvent {
+	var events []evententities.HostEvent
+	logged := make(map[string]bool)
+	for _, sample := range metric.Samples {
+		logged[sample.Category] = true
+		if last, ok := s.open[sample.Category]; !ok || sample.LastAt.After(last) {
+			s.open[sample.Category] = sample.LastAt
+		}
+		events = append(events, evententities.HostEvent{
+			Timestamp: sample.LastAt, Source: EventSource, Subject: sample.Category, Kind: EventLogged, Severity: sample.Severity,
+			Message: textutil.Truncate(fmt.Sprintf("%d %s message(s) in the kernel log, last: %s", sample.Count, sample.Category, sample.Message), evententities.MaxMessageLen),
+		})
+	}
+	for _, category := range entities.Categories {
+		last, ok := s.open[category]
+		if !ok || logged[category] || metric.Timestamp.Sub(last) < s.quiet {
+			continue
+		}
+		delete(s.open, category)
+		events = append(events, evententities.HostEvent{
+			Timestamp: metric.Timestamp, Source: EventSource, Subject: category, Kind: EventQuiet, Severity: evententities.SeverityOK,
+			Message: fmt.Sprintf("No %s messages in the kernel log for %s", category, s.quiet),
+		})
+	}
+	return events
+}
+
+// Save stores the metric and records its events. The first local save takes over the problems
+// left open by a previous run, so they are resolved once their category is quiet. The cursor
+// is saved last: a failed save reads the messages again after a restart.
+func (s *service) Save(ctx context.Context, metric entities.KernelLogMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "kernellog", "error", err, "host_id", hostId)
+		return err
+	}
+	for _, sample := range metric.Samples {
+		if sample.Severity == evententities.SeverityCritical {
+			s.logger.Warn("Kernel log "+sample.Category+" messages", "count", sample.Count, "host_id", hostId, "message", sample.Message)
+		}
+	}
+	if s.events != nil {
+		if metric.Cursor != nil {
+			if err := s.takeOverProblems(ctx, hostId); err != nil {
+				return err
+			}
+		}
+		if err := s.events.Record(ctx, hostId, metric.Events); err != nil {
+			return err
+		}
+	}
+	if metric.Cursor == nil {
+		return nil
+	}
+	if err := s.repo.SaveCursor(ctx, *metric.Cursor); err != nil {
+		s.logger.Error("Failed to save kernel log cursor", "source", metric.Cursor.Source, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) takeOverProblems(ctx context.Context, hostId uint) error {
+	s.mu.Lock()
+	seeded := s.seeded
+	s.seeded = true
+	s.mu.Unlock()
+	if seeded {
+		return nil
+	}
+	problems, err := s.events.Problems(ctx, hostId)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range problems {
+		if _, ok := s.open[p.Subject]; p.Source == EventSource && !ok {
+			s.open[p.Subject] = p.Timestamp
+		}
+	}
+	return nil
+}
+
+func (s *service) Latest() *entities.KernelLogMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+func (s *service) PushData() *entities.KernelLogMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.latest == nil {
+		return nil
+	}
+	out := *s.latest
+	out.Events = s.recent.Items()
+	return &out
+}
+
+// ReceivePush bounds what an agent may send and records only kernel log events.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.KernelLogMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	samples := make([]entities.KernelLogSample, 0, len(metric.Samples))
+	for _, sample := range metric.Samples[:min(len(metric.Samples), maxPushSamples)] {
+		if !sample.LastAt.IsZero() {
+			samples = append(samples, sample)
+		}
+	}
+	metric.Samples = samples
+	events := make([]evententities.HostEvent, 0, len(metric.Events))
+	for _, e := range metric.Events[:min(len(metric.Events), maxPushEvents)] {
+		if e.Source == EventSource && !e.Timestamp.IsZero() {
+			events = append(events, e)
+		}
+	}
+	metric.Events = events
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) MessageTotals() map[string]uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]uint64, len(s.totals))
+	for category, n := range s.totals {
+		out[category] = n
+	}
+	return out
+}
+
+func (s *service) GetSamplesByHost(ctx context.Context, hostId uint, hours float64, category string) ([]entities.HistoricalKernelLogSample, error) {
+	return s.repo.GetSamplesByHost(ctx, hostId, hours, category)
+}
+
+func (s *service) GetCountsByHost(ctx context.Context, hostId uint, hours float64) ([]repositories.CategoryCount, error) {
+	return s.repo.GetCountsByHost(ctx, hostId, hours)
+}
@@ -0,0 +1,52 @@
+package collectors
+
+import (
+	"regexp"
+
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/kernellog/infrastructure/entities"
+)
+
+type rule struct {
+	category string
+	severity string
+	pattern  *regexp.Regexp
+}
+
+// rules are tried in order; the first match classifies a message. Hardware comes first so a
+// panic on a fatal machine check is a hardware error, segfaults before kernel faults so a user
+// process trapping on a general protection fault is not a kernel one, and filesystems before
+// I/O so filesystem metadata I/O errors count once.
+var rules = []rule{
+	{entities.CategoryHardware, evententities.SeverityCritical, regexp.MustCompile(
+		`Machine Check Exception|[Ff]atal [Mm]achine check|[Pp]rocessor context corrupt|EDAC .*\bUE\b|severity=Uncorrected|[Uu]ncorrect(?:ed|able) (?:\(Fatal\)|\(Non-Fatal\)|.*[Ee]rror)`)},
+	{entities.CategoryHardware, evententities.SeverityWarning, regexp.MustCompile(
+		`\[Hardware Error\]|\bmce: |EDAC .*\bCE\b|severity=Corrected|AER: .*[Cc]orrected|temperature above threshold`)},
+	{entities.CategorySegfault, evententities.SeverityWarning, regexp.MustCompile(
+		`segfault at|^traps: \S+ .*(?:general protection|trap invalid opcode|trap divide error|trap int3)`)},
+	{entities.CategoryKernel, evententities.SeverityCritical, regexp.MustCompile(
+		`Kernel panic|\bBUG: |\bOops\b|general protection fault[:,]|soft lockup|hard LOCKUP|detected stalls? on CPU|self-detected stall`)},
+	{entities.CategoryKernel, evententities.SeverityWarning, regexp.MustCompile(
+		`blocked for more than \d+ seconds|WARNING: CPU: \d+`)},
+	{entities.CategoryOOM, evententities.SeverityCritical, regexp.MustCompile(
+		`^Out of memory: Kill(?:ed)? process`)},
+	{entities.CategoryOOM, evententities.SeverityWarning, regexp.MustCompile(
+		`^Memory cgroup out of memory: Kill(?:ed)? process`)},
+	{entities.CategoryFilesystem, evententities.SeverityCritical, regexp.MustCompile(
+		`EXT4-fs error|EXT4-fs \(\S+\): (?:error|Remounting filesystem read-only)|XFS \(\S+\): (?:Corruption|Metadata corruption|metadata I/O error|[Ff]ilesystem has been shut down|log I/O error)|BTRFS (?:error|critical)|F2FS-fs \(\S+\): .*error|JBD2: .*error|[Rr]emounting filesystem read-only`)},
+	{entities.CategoryIO, evententities.SeverityCritical, regexp.MustCompile(
+		`I/O error|[Cc]ritical medium error|[Uu]nrecovered read error|Sense Key : Medium Error`)},
+	{entities.CategoryIO, evententities.SeverityWarning, regexp.MustCompile(
+		`ata\d+(?:\.\d+)?: (?:exception Emask|failed command|hard resetting link|SError)|nvme\d+\S*: (?:I/O \d+ .*timeout|.*timeout, (?:aborting|reset controller)|controller is down)|DID_TIME_OUT|SCSI error`)},
+}
+
+// Classify returns the category and severity of a kernel message without its syslog or kmsg
+// prefix; ok is false for messages of no category.
+func Classify(message string) (category, severity string, ok bool) {
+	for _, r := range rules {
+		if r.pattern.MatchString(message) {
+			return r.category, r.severity, true
+		}
+	}
+	return "", "", false
+}
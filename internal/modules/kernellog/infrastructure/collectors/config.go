@@ -0,0 +1,36 @@
+package collectors
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// FileEnv is a kernel log file to follow instead of /dev/kmsg, as a path on the host (read
+	// under HOST_ROOT), e.g. /var/log/kern.log
+	FileEnv = "KERNELLOG_FILE"
+	// QuietEnv is how long a category must stay quiet before its problem is resolved
+	QuietEnv = "KERNELLOG_QUIET"
+	// DefaultQuiet is the quiet period when KERNELLOG_QUIET is unset
+	DefaultQuiet = time.Hour
+)
+
+// FileFromEnv reads KERNELLOG_FILE; empty means /dev/kmsg.
+func FileFromEnv() string {
+	return strings.TrimSpace(os.Getenv(FileEnv))
+}
+
+// QuietFromEnv reads KERNELLOG_QUIET.
+func QuietFromEnv() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(QuietEnv))
+	if raw == "" {
+		return DefaultQuiet, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration like \"30m\", got %q", QuietEnv, raw)
+	}
+	return d, nil
+}
@@ -0,0 +1,198 @@
+package collectors
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/app/textutil"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/kernellog/infrastructure/entities"
+)
+
+// SourceKmsg is the cursor source of /dev/kmsg.
+const SourceKmsg = "kmsg"
+
+// maxMessageLen bounds the example message kept per category (the column holds 1024 bytes)
+const maxMessageLen = 1000
+
+// Available reports whether there is a kernel log to read: file (a host path) when set, else
+// /dev/kmsg.
+func Available(file string) bool {
+	if file != "" {
+		return fileExists(hostfs.Root(file))
+	}
+	return kmsgAvailable()
+}
+
+// KernelLogCollector follows /dev/kmsg or a kernel log file from a cursor and classifies the
+// messages logged since.
+type KernelLogCollector struct {
+	logger *log.Logger
+	// file is the followed log file (a host path); empty for /dev/kmsg
+	file string
+
+	mu     sync.Mutex
+	cursor *entities.KernelLogCursor
+}
+
+// NewKernelLogCollector creates a collector following file, or /dev/kmsg when file is empty.
+func NewKernelLogCollector(logger *log.Logger, file string) *KernelLogCollector {
+	return &KernelLogCollector{logger: logger, file: file}
+}
+
+// Source returns the cursor source: SourceKmsg or the followed file.
+func (c *KernelLogCollector) Source() string {
+	if c.file != "" {
+		return c.file
+	}
+	return SourceKmsg
+}
+
+// SetCursor restores the position saved by a previous run; call it before the first read.
+func (c *KernelLogCollector) SetCursor(cursor *entities.KernelLogCursor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cursor = cursor
+}
+
+type kernelMessage struct {
+	at   time.Time
+	text string
+}
+
+// Collect returns the messages logged since the cursor, counted per category, with the cursor
+// after them. Without a cursor /dev/kmsg is read from the oldest buffered record and a file
+// from its end; after a reboot /dev/kmsg is read from the new boot's first record.
+func (c *KernelLogCollector) Collect(ctx context.Context) (entities.KernelLogMetric, error) {
+	c.logger.Debug("Reading kernel log", "source", c.Source())
+	now := time.Now().UTC()
+	metric := entities.KernelLogMetric{Timestamp: now, Samples: []entities.KernelLogSample{}}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var (
+		messages []kernelMessage
+		cursor   *entities.KernelLogCursor
+		err      error
+	)
+	if c.file != "" {
+		messages, cursor, err = c.readFile(now)
+	} else {
+		messages, cursor, err = c.readKmsg()
+	}
+	if err != nil {
+		return metric, err
+	}
+	cursor.UpdatedAt = now
+	c.cursor = cursor
+	copied := *cursor
+	metric.Cursor = &copied
+
+	samples := make(map[string]*entities.KernelLogSample)
+	for _, m := range messages {
+		category, severity, ok := Classify(m.text)
+		if !ok {
+			continue
+		}
+		s := samples[category]
+		if s == nil {
+			s = &entities.KernelLogSample{Category: category, Severity: severity, FirstAt: m.at}
+			samples[category] = s
+		}
+		s.Count++
+		s.LastAt = m.at
+		s.Message = textutil.Truncate(m.text, maxMessageLen)
+		if severity == evententities.SeverityCritical {
+			s.Severity = severity
+		}
+	}
+	for _, category := range entities.Categories {
+		if s := samples[category]; s != nil {
+			metric.Samples = append(metric.Samples, *s)
+		}
+	}
+	return metric, ctx.Err()
+}
+
+func (c *KernelLogCollector) readFile(now time.Time) ([]kernelMessage, *entities.KernelLogCursor, error) {
+	cursor := &entities.KernelLogCursor{Source: c.file, Offset: -1}
+	if c.cursor != nil {
+		cursor.FileID, cursor.Offset = c.cursor.FileID, c.cursor.Offset
+	}
+	data, id, offset, err := readLogFile(hostfs.Root(c.file), cursor.FileID, cursor.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor.FileID, cursor.Offset = id, offset
+	// Syslog timestamps lack the year and often the zone, so lines are stamped with the read
+	var messages []kernelMessage
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), maxFileRead)
+	for sc.Scan() {
+		messages = append(messages, kernelMessage{at: now, text: KernelMessage(sc.Bytes())})
+	}
+	return messages, cursor, nil
+}
+
+func (c *KernelLogCollector) readKmsg() ([]kernelMessage, *entities.KernelLogCursor, error) {
+	bootID, err := os.ReadFile(hostfs.Proc("sys", "kernel", "random", "boot_id"))
+	if err != nil {
+		return nil, nil, err
+	}
+	boot, err := bootTime()
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor := &entities.KernelLogCursor{Source: SourceKmsg, BootID: strings.TrimSpace(string(bootID))}
+	// Sequence numbers restart at 0 with each boot, so a new boot is read from its first record
+	var from uint64
+	if c.cursor != nil && c.cursor.BootID == cursor.BootID {
+		cursor.Seq = c.cursor.Seq
+		from = c.cursor.Seq + 1
+	}
+	records, err := readKmsg(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	var messages []kernelMessage
+	for _, r := range records {
+		cursor.Seq = max(cursor.Seq, r.Seq)
+		if r.Facility == facilityKernel {
+			messages = append(messages, kernelMessage{at: boot.Add(r.Monotonic), text: r.Message})
+		}
+	}
+	return messages, cursor, nil
+}
+
+// bootTime reads the host's boot time (btime) from HOST_PROC/stat.
+func bootTime() (time.Time, error) {
+	data, err := os.ReadFile(hostfs.Proc("stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, "btime "); ok {
+			sec, err := strconv.ParseInt(strings.TrimSpace(rest), 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(sec, 0).UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("no btime in " + hostfs.Proc("stat"))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,48 @@
+package collectors
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+// facilityKernel is the syslog facility of messages logged by the kernel itself; other
+// facilities are userspace writes to /dev/kmsg (systemd, udev).
+const facilityKernel = 0
+
+// KmsgRecord is one record read from /dev/kmsg.
+type KmsgRecord struct {
+	Priority int
+	Facility int
+	Seq      uint64
+	// Monotonic is the time since boot the message was logged at
+	Monotonic time.Duration
+	Message   string
+}
+
+// ParseKmsgRecord decodes a /dev/kmsg record: "<prio>,<seq>,<usec>,<flags>[,...];<message>"
+// followed by optional " KEY=value" continuation lines, which are dropped.
+func ParseKmsgRecord(b []byte) (KmsgRecord, bool) {
+	header, message, ok := bytes.Cut(b, []byte{';'})
+	if !ok {
+		return KmsgRecord{}, false
+	}
+	fields := bytes.Split(header, []byte{','})
+	if len(fields) < 3 {
+		return KmsgRecord{}, false
+	}
+	prio, err1 := strconv.Atoi(string(fields[0]))
+	seq, err2 := strconv.ParseUint(string(fields[1]), 10, 64)
+	usec, err3 := strconv.ParseInt(string(fields[2]), 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return KmsgRecord{}, false
+	}
+	message, _, _ = bytes.Cut(message, []byte{'\n'})
+	return KmsgRecord{
+		Priority:  prio & 7,
+		Facility:  prio >> 3,
+		Seq:       seq,
+		Monotonic: time.Duration(usec) * time.Microsecond,
+		Message:   string(message),
+	}, true
+}
@@ -0,0 +1,63 @@
+package collectors
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+)
+
+// maxFileRead bounds what is read from the log file per read; the rest is read next time
+const maxFileRead = 16 << 20
+
+// syslogPrefix matches what syslog daemons write before a kernel message: a traditional or
+// RFC 3339 timestamp, the host name and the "kernel:" tag, then the kernel's own "[seconds]"
+var syslogPrefix = regexp.MustCompile(`^.*?\bkernel: (?:\[\s*\d+\.\d+\] )?`)
+
+// KernelMessage strips the syslog prefix from a line of a kernel log file; a line without
+// one (a dmesg dump) is returned as it is.
+func KernelMessage(line []byte) string {
+	if loc := syslogPrefix.FindIndex(line); loc != nil {
+		line = line[loc[1]:]
+	}
+	return string(bytes.TrimRight(line, "\r"))
+}
+
+// readLogFile returns the complete lines of path after offset in the file fileID, and the
+// position it stopped at. Without a previous position (fileID 0 and offset -1) only the end of
+// the file is recorded, so history is not counted; a file that was replaced or truncated
+// (rotated) is read from its start.
+func readLogFile(path string, id uint64, offset int64) (data []byte, newID uint64, newOffset int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, id, offset, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, id, offset, err
+	}
+	newID = fileID(info)
+	switch {
+	case offset < 0:
+		return nil, newID, info.Size(), nil
+	case newID != id || info.Size() < offset:
+		offset = 0
+	}
+	if info.Size() == offset {
+		return nil, newID, offset, nil
+	}
+	buf := make([]byte, min(info.Size()-offset, maxFileRead))
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, id, offset, err
+	}
+	buf = buf[:n]
+	// A line still being written is read next time
+	end := bytes.LastIndexByte(buf, '\n') + 1
+	if end == 0 && len(buf) == maxFileRead {
+		// A single line longer than the read limit is skipped
+		end = len(buf)
+	}
+	return buf[:end], newID, offset + int64(end), nil
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package collectors
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// kmsgPath is the kernel's log device; the ring buffer is not namespaced, so it is the host's
+// log in a container too (given the device and CAP_SYSLOG when dmesg_restrict is set).
+const kmsgPath = "/dev/kmsg"
+
+// maxKmsgRecords bounds the records taken per read; the rest follow at the next one
+const maxKmsgRecords = 10000
+
+// kmsgAvailable reports whether /dev/kmsg can be opened for reading.
+func kmsgAvailable() bool {
+	fd, err := unix.Open(kmsgPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return false
+	}
+	unix.Close(fd)
+	return true
+}
+
+// readKmsg returns the records in the ring buffer from sequence number from on. Every
+// read opens the device anew, which starts at the oldest record still buffered; records
+// overwritten before they are read (EPIPE) are skipped.
+func readKmsg(from uint64) ([]KmsgRecord, error) {
+	fd, err := unix.Open(kmsgPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: kmsgPath, Err: err}
+	}
+	defer unix.Close(fd)
+	var out []KmsgRecord
+	// A record is at most 8 KiB (CONSOLE_EXT_LOG_MAX)
+	buf := make([]byte, 8192)
+	for len(out) < maxKmsgRecords {
+		n, err := unix.Read(fd, buf)
+		if errors.Is(err, unix.EAGAIN) {
+			break
+		}
+		if errors.Is(err, unix.EPIPE) || errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return out, &os.PathError{Op: "read", Path: kmsgPath, Err: err}
+		}
+		if r, ok := ParseKmsgRecord(buf[:n]); ok && r.Seq >= from {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// fileID returns the inode of a file, to tell a rotated log from the one the cursor is in.
+func fileID(info os.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return st.Ino
+	}
+	return 0
+}
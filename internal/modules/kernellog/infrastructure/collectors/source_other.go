@@ -0,0 +1,15 @@
+//go:build !linux
+
+package collectors
+
+import (
+	"errors"
+	"os"
+)
+
+func kmsgAvailable() bool { return false }
+
+func readKmsg(from uint64) ([]KmsgRecord, error) { return nil, errors.ErrUnsupported }
+
+// fileID is unknown; a rotated log is only noticed when it is smaller than the cursor.
+func fileID(info os.FileInfo) uint64 { return 0 }
@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"time"
+
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+)
+
+// Categories kernel log messages are classified into.
+const (
+	CategoryOOM        = "oom"
+	CategoryIO         = "io"
+	CategoryFilesystem = "filesystem"
+	CategoryHardware   = "hardware"
+	CategorySegfault   = "segfault"
+	CategoryKernel     = "kernel"
+)
+
+// Categories lists every category, in the order the classifier tries them.
+var Categories = []string{CategoryHardware, CategorySegfault, CategoryKernel, CategoryOOM, CategoryFilesystem, CategoryIO}
+
+// KernelLogMetric is one read of the kernel log: the classified messages since the previous one.
+type KernelLogMetric struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Samples has one entry per category with messages; empty when nothing was logged
+	Samples []KernelLogSample `json:"samples"`
+	// Events are the host events of this read and, in a push, those of recent reads
+	Events []evententities.HostEvent `json:"events,omitempty"`
+	// Cursor is where this read stopped; saved with the read, never pushed
+	Cursor *KernelLogCursor `json:"-"`
+}
+
+// KernelLogSample counts the messages of one category in a read.
+type KernelLogSample struct {
+	Category string `json:"category"`
+	// Severity is the highest severity of the messages (warning or critical)
+	Severity string    `json:"severity"`
+	Count    uint64    `json:"count"`
+	FirstAt  time.Time `json:"first_at"`
+	LastAt   time.Time `json:"last_at"`
+	// Message is the last message of the category
+	Message string `json:"message"`
+}
+
+// HistoricalKernelLogSample is a KernelLogSample of a host, stamped with its last message.
+type HistoricalKernelLogSample struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_kernel_log_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_kernel_log_host_ts,priority:2"`
+	Category  string    `json:"category" gorm:"primaryKey;size:16"`
+	Severity  string    `json:"severity" gorm:"size:16"`
+	Count     uint64    `json:"count"`
+	FirstAt   time.Time `json:"first_at"`
+	Message   string    `json:"message" gorm:"size:1024"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalKernelLogSample) TableName() string { return "kernel_log_samples" }
+
+// ToSamples converts the metric's samples into rows.
+func (m KernelLogMetric) ToSamples(hostId uint) []HistoricalKernelLogSample {
+	rows := make([]HistoricalKernelLogSample, 0, len(m.Samples))
+	for _, s := range m.Samples {
+		rows = append(rows, HistoricalKernelLogSample{
+			HostID:    hostId,
+			Timestamp: s.LastAt.UTC().Truncate(time.Microsecond),
+			Category:  s.Category,
+			Severity:  s.Severity,
+			Count:     s.Count,
+			FirstAt:   s.FirstAt.UTC().Truncate(time.Microsecond),
+			Message:   s.Message,
+		})
+	}
+	return rows
+}
+
+// KernelLogCursor is where this instance stopped reading a kernel log source, so a restart
+// neither repeats nor skips messages. It belongs to the instance, not to a host.
+type KernelLogCursor struct {
+	// Source is "kmsg" or the path of the followed log file
+	Source string `json:"source" gorm:"primaryKey;size:255"`
+	// BootID and Seq are the boot and the last sequence number read from /dev/kmsg
+	BootID string `json:"boot_id" gorm:"size:64"`
+	Seq    uint64 `json:"seq"`
+	// FileID and Offset are the inode and the read position of a log file
+	FileID    uint64    `json:"file_id"`
+	Offset    int64     `json:"offset"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (KernelLogCursor) TableName() string { return "kernel_log_cursors" }
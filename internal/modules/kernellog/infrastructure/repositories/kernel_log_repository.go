@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/kernellog/infrastructure/entities"
+)
+
+// CategoryCount is the number of messages of a category in a window.
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    uint64 `json:"count"`
+}
+
+type KernelLogRepository interface {
+	// SaveCurrentMetric stores the samples not stored yet.
+	SaveCurrentMetric(ctx context.Context, metric entities.KernelLogMetric, hostId uint) error
+	// GetSamplesByHost returns samples in time order, optionally for one category.
+	GetSamplesByHost(ctx context.Context, hostId uint, hours float64, category string) ([]entities.HistoricalKernelLogSample, error)
+	// GetCountsByHost returns the messages per category in the window, by category.
+	GetCountsByHost(ctx context.Context, hostId uint, hours float64) ([]CategoryCount, error)
+	// GetCursor returns the saved position in source; nil when there is none.
+	GetCursor(ctx context.Context, source string) (*entities.KernelLogCursor, error)
+	// SaveCursor inserts or replaces the position in the cursor's source.
+	SaveCursor(ctx context.Context, cursor entities.KernelLogCursor) error
+}
+
+type kernelLogRepository struct {
+	db *gorm.DB
+}
+
+func NewKernelLogRepository(db *gorm.DB) KernelLogRepository {
+	return &kernelLogRepository{db: db}
+}
+
+func (r *kernelLogRepository) SaveCurrentMetric(ctx context.Context, metric entities.KernelLogMetric, hostId uint) error {
+	rows := metric.ToSamples(hostId)
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *kernelLogRepository) GetSamplesByHost(ctx context.Context, hostId uint, hours float64, category string) ([]entities.HistoricalKernelLogSample, error) {
+	rows := []entities.HistoricalKernelLogSample{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if category != "" {
+		q = q.Where("category = ?", category)
+	}
+	err := q.Order("timestamp ASC, category ASC").Find(&rows).Error
+	return rows, err
+}
+
+func (r *kernelLogRepository) GetCountsByHost(ctx context.Context, hostId uint, hours float64) ([]CategoryCount, error) {
+	rows := []CategoryCount{}
+	err := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx).Model(&entities.HistoricalKernelLogSample{}), hostId, hours).
+		Select("category, SUM(count) AS count").
+		Group("category").
+		Order("category ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *kernelLogRepository) GetCursor(ctx context.Context, source string) (*entities.KernelLogCursor, error) {
+	var cursor entities.KernelLogCursor
+	err := r.db.WithContext(ctx).Where("source = ?", source).First(&cursor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func (r *kernelLogRepository) SaveCursor(ctx context.Context, cursor entities.KernelLogCursor) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&cursor).Error
+}
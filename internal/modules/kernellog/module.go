@@ -0,0 +1,75 @@
+// Package kernellog registers the kernel log module, which follows /dev/kmsg or a kernel log
+// file and counts OOM kills, I/O and filesystem errors, hardware faults, segfaults and kernel
+// faults as host events.
+package kernellog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	kernellogservice "system-stats/internal/modules/kernellog/application"
+	"system-stats/internal/modules/kernellog/infrastructure/collectors"
+	"system-stats/internal/modules/kernellog/infrastructure/entities"
+	"system-stats/internal/modules/kernellog/infrastructure/repositories"
+	handlers "system-stats/internal/modules/kernellog/presentation"
+)
+
+// Module stores classified kernel log messages in kernel_log_samples, its read position in
+// kernel_log_cursors, and serves /kernellog.
+type Module struct{}
+
+func (Module) Name() string { return "kernellog" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 20,
+		Name:    "kernel_log_samples_and_cursors",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalKernelLogSample{}, &entities.KernelLogCursor{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.KernelLogCursor{}, &entities.HistoricalKernelLogSample{})
+		}},
+	}}
+}
+
+// RetentionTables leaves kernel_log_cursors alone: it has one row per source, not per host.
+func (Module) RetentionTables() []string { return []string{"kernel_log_samples"} }
+
+func (Module) HostTables() []string { return []string{"kernel_log_samples"} }
+
+// Build fails on an invalid KERNELLOG_QUIET.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	quiet, err := collectors.QuietFromEnv()
+	if err != nil {
+		return registry.Instance{}, fmt.Errorf("kernellog: %w", err)
+	}
+	file := collectors.FileFromEnv()
+	service := kernellogservice.NewService(deps.Logger, repositories.NewKernelLogRepository(deps.DB), deps.Events, file, quiet)
+	handler := handlers.NewKernelLogHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/kernellog", handler.HandleKernelLog)
+		},
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}
+	if !collectors.Available(file) {
+		deps.Logger.Info("No readable kernel log (/dev/kmsg or "+collectors.FileEnv+"), not collecting kernel log messages", "file", file)
+		return inst, nil
+	}
+	// Kernel log messages are not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.KernelLogMetric]("kernellog", service.Collect, service.Save, nil)
+	inst.Collector = &collector
+	inst.Metrics = kernellogservice.NewPrometheusCollector(service)
+	inst.PushData = registry.PushLatest(service.PushData)
+	return inst, nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	kernellogservice "system-stats/internal/modules/kernellog/application"
+)
+
+// KernelLogHandler handles HTTP requests for classified kernel log messages.
+type KernelLogHandler struct {
+	logger  *log.Logger
+	service kernellogservice.Service
+	hosts   hostservice.Service
+}
+
+// NewKernelLogHandler creates a new HTTP handler for kernel log endpoints.
+func NewKernelLogHandler(logger *log.Logger, service kernellogservice.Service, hosts hostservice.Service) *KernelLogHandler {
+	return &KernelLogHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleKernelLog returns the classified kernel log messages of a host.
+//
+// @Summary     Kernel log errors
+// @Description Returns the kernel log messages counted per read and category (oom, io, filesystem, hardware, segfault, kernel) with the highest severity, first / last message time and the last message as `samples`, and the messages per category in the window as `counts`. `category` filters the samples. The matching host events have source `kernellog`.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours     query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id   query    integer  false  "Host ID (0 = this server instance)"
+// @Param       category  query    string   false  "Only samples of this category"
+// @Success     200       {object} map[string]interface{}
+// @Failure     401       {object} map[string]string
+// @Failure     500       {object} map[string]string
+// @Security    BearerAuth
+// @Router      /kernellog [get]
+func (h *KernelLogHandler) HandleKernelLog(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	category := c.Query("category")
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyKernelLogPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for kernel log data", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	samples, err := h.service.GetSamplesByHost(ctx, effective, hours, category)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching kernel log samples")
+			return
+		}
+		h.logger.Error("Failed to fetch kernel log samples", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	counts, err := h.service.GetCountsByHost(ctx, effective, hours)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching kernel log counts")
+			return
+		}
+		h.logger.Error("Failed to fetch kernel log counts", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"samples": samples,
+		"counts":  counts,
+	})
+}
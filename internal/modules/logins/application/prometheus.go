@@ -0,0 +1,40 @@
+package logins
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/modules/logins/infrastructure/entities"
+)
+
+var (
+	descLoginSessions = prometheus.NewDesc("system_login_sessions", "Users logged in now (utmp).", nil, nil)
+	descAuthFailures  = prometheus.NewDesc("system_auth_failures_total", "Failed authentications read from the auth logs since start; alert on its rate.", []string{"service"}, nil)
+)
+
+// PrometheusCollector exports the session count of the last collection and the failed
+// authentications counted since start.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descLoginSessions
+	ch <- descAuthFailures
+}
+
+// Collect sends the in-memory counts; nothing is read from the database. Both services are
+// always exported so a rate can be taken before the first failure.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	totals := c.service.FailureTotals()
+	for _, service := range []string{entities.ServiceSSH, entities.ServiceSudo} {
+		ch <- prometheus.MustNewConstMetric(descAuthFailures, prometheus.CounterValue, float64(totals[service]), service)
+	}
+	if latest := c.service.Latest(); latest != nil && latest.Sessions != nil {
+		ch <- prometheus.MustNewConstMetric(descLoginSessions, prometheus.GaugeValue, float64(len(latest.Sessions)))
+	}
+}
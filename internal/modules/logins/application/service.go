@@ -0,0 +1,148 @@
+package logins
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	"system-stats/internal/modules/logins/infrastructure/collectors"
+	"system-stats/internal/modules/logins/infrastructure/entities"
+	"system-stats/internal/modules/logins/infrastructure/repositories"
+)
+
+const (
+	// maxPushEvents bounds the events kept for, and accepted from, a push
+	maxPushEvents = 1024
+	// maxPushFailures bounds the failure rows kept for, and accepted from, a push
+	maxPushFailures = 1024
+	// maxPushSessions bounds the sessions accepted from a push
+	maxPushSessions = 256
+)
+
+type Service interface {
+	// Collect reads sessions, login events and failed authentications and keeps the result in memory.
+	Collect(ctx context.Context) (entities.LoginMetric, error)
+	Save(ctx context.Context, metric entities.LoginMetric, hostId uint) error
+	// Latest returns this instance's last collection (no database access); nil before the first one.
+	Latest() *entities.LoginMetric
+	// PushData returns the last collection with the events and failures of recent collections;
+	// nil before the first one.
+	PushData() *entities.LoginMetric
+	// ReceivePush stores a collection pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	// FailureTotals returns the failed authentications counted per service since start.
+	FailureTotals() map[string]uint64
+	GetSessionsByHost(ctx context.Context, hostId uint) ([]entities.LoginSessionEntity, error)
+	GetEventsByHost(ctx context.Context, hostId uint, hours float64, user string) ([]entities.HistoricalLoginEvent, error)
+	GetFailuresByHost(ctx context.Context, hostId uint, hours float64, service string) ([]entities.HistoricalAuthFailure, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.LoginCollector
+	repo      repositories.LoginRepository
+
+	mu             sync.RWMutex
+	latest         *entities.LoginMetric
+	recentEvents   *eventsservice.Recent[entities.LoginEvent]
+	recentFailures *eventsservice.Recent[entities.AuthFailure]
+	failureTotals  map[string]uint64
+}
+
+func NewService(logger *log.Logger, repo repositories.LoginRepository, authLogs []string) Service {
+	return &service{
+		logger:         logger,
+		collector:      collectors.NewLoginCollector(logger, authLogs),
+		repo:           repo,
+		failureTotals:  make(map[string]uint64),
+		recentEvents:   eventsservice.NewRecent(maxPushEvents, func(e entities.LoginEvent) time.Time { return e.Timestamp }),
+		recentFailures: eventsservice.NewRecent(maxPushFailures, func(f entities.AuthFailure) time.Time { return f.Timestamp }),
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.LoginMetric, error) {
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest = &metric
+	// Backfilled wtmp records are older than the window but still pushed once
+	s.recentEvents.Add(metric.Timestamp, metric.Events)
+	s.recentFailures.Add(metric.Timestamp, metric.Failures)
+	for _, f := range metric.Failures {
+		s.failureTotals[f.Service] += f.Count
+	}
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.LoginMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "logins", "error", err, "host_id", hostId)
+		return err
+	}
+	return nil
+}
+
+func (s *service) Latest() *entities.LoginMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+func (s *service) PushData() *entities.LoginMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.latest == nil {
+		return nil
+	}
+	out := *s.latest
+	out.Events = s.recentEvents.Items()
+	out.Failures = s.recentFailures.Items()
+	return &out
+}
+
+// ReceivePush bounds what an agent may send; events and failures pushed again are skipped on save.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.LoginMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	if metric.Sessions != nil {
+		metric.Sessions = metric.Sessions[:min(len(metric.Sessions), maxPushSessions)]
+	}
+	metric.Events = metric.Events[:min(len(metric.Events), maxPushEvents)]
+	metric.Failures = metric.Failures[:min(len(metric.Failures), maxPushFailures)]
+	// Pushed strings are cut to the column sizes when stored, like collected ones
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) FailureTotals() map[string]uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]uint64, len(s.failureTotals))
+	for service, n := range s.failureTotals {
+		out[service] = n
+	}
+	return out
+}
+
+func (s *service) GetSessionsByHost(ctx context.Context, hostId uint) ([]entities.LoginSessionEntity, error) {
+	return s.repo.GetSessionsByHost(ctx, hostId)
+}
+
+func (s *service) GetEventsByHost(ctx context.Context, hostId uint, hours float64, user string) ([]entities.HistoricalLoginEvent, error) {
+	return s.repo.GetEventsByHost(ctx, hostId, hours, user)
+}
+
+func (s *service) GetFailuresByHost(ctx context.Context, hostId uint, hours float64, service string) ([]entities.HistoricalAuthFailure, error) {
+	return s.repo.GetFailuresByHost(ctx, hostId, hours, service)
+}
@@ -0,0 +1,84 @@
+package collectors
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+
+	"system-stats/internal/app/textutil"
+	"system-stats/internal/modules/logins/infrastructure/entities"
+)
+
+// maxAuthLogRead bounds what is read from one log per collection; the rest is read next time
+const maxAuthLogRead = 16 << 20
+
+var (
+	// sshdFailed matches "Failed password for [invalid user ]<user> from <addr> port ..." and
+	// the same for publickey, keyboard-interactive/pam and none
+	sshdFailed = regexp.MustCompile(`sshd(?:-session)?\[\d+\]: Failed \S+ for (?:invalid user )?(\S*) from (\S+)`)
+	// sudoFailed matches PAM's line for each wrong sudo password
+	sudoFailed = regexp.MustCompile(`sudo(?:\[\d+\])?: pam_unix\(sudo:auth\): authentication failure;.*\blogname=(\S*)`)
+)
+
+// ParseAuthLine returns the failed authentication an auth log line reports, if any: failed
+// sshd logins and wrong sudo passwords. Summary lines (sudo's "incorrect password attempts",
+// PAM's line for sshd) repeat these and are not counted. The user name of a failed sshd login
+// is whatever the client sent, so user and source are cut to their column sizes here, before
+// attempts are counted per user and source.
+func ParseAuthLine(line []byte) (service, user, source string, ok bool) {
+	if m := sshdFailed.FindSubmatch(line); m != nil {
+		return entities.ServiceSSH, textutil.Truncate(string(m[1]), entities.MaxUserLen),
+			textutil.Truncate(string(m[2]), entities.MaxFailureSourceLen), true
+	}
+	if m := sudoFailed.FindSubmatch(line); m != nil {
+		return entities.ServiceSudo, textutil.Truncate(string(m[1]), entities.MaxUserLen), "", true
+	}
+	return "", "", "", false
+}
+
+// logTail follows an append-only log file across collections.
+type logTail struct {
+	info   os.FileInfo
+	offset int64
+}
+
+// readNew returns the complete lines appended to path since the previous call. The first call
+// only records the end of the file, so history is not counted; a file that was replaced or
+// truncated (rotated) is read from its start.
+func (t *logTail) readNew(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if t.info == nil {
+		t.info, t.offset = info, info.Size()
+		return nil, nil
+	}
+	if !os.SameFile(t.info, info) || info.Size() < t.offset {
+		t.offset = 0
+	}
+	t.info = info
+	if info.Size() == t.offset {
+		return nil, nil
+	}
+	buf := make([]byte, min(info.Size()-t.offset, maxAuthLogRead))
+	n, err := f.ReadAt(buf, t.offset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	buf = buf[:n]
+	// A line still being written is read next time
+	end := bytes.LastIndexByte(buf, '\n') + 1
+	if end == 0 && len(buf) == maxAuthLogRead {
+		// A single line longer than the read limit is skipped
+		end = len(buf)
+	}
+	t.offset += int64(end)
+	return buf[:end], nil
+}
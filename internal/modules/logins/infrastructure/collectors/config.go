@@ -0,0 +1,32 @@
+package collectors
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	// AuthLogsEnv lists the auth log files to count failed logins in, comma-separated, as paths
+	// on the host (read under HOST_ROOT)
+	AuthLogsEnv = "LOGINS_AUTH_LOGS"
+	// DefaultAuthLogs are the Debian / Ubuntu and RHEL / Fedora auth logs
+	DefaultAuthLogs = "/var/log/auth.log,/var/log/secure"
+)
+
+// AuthLogsFromEnv reads LOGINS_AUTH_LOGS; "none" counts no failures.
+func AuthLogsFromEnv() []string {
+	raw, ok := os.LookupEnv(AuthLogsEnv)
+	if !ok || strings.TrimSpace(raw) == "" {
+		raw = DefaultAuthLogs
+	}
+	if strings.EqualFold(strings.TrimSpace(raw), "none") {
+		return nil
+	}
+	var paths []string
+	for _, p := range strings.Split(raw, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
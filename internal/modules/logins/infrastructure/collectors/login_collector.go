@@ -0,0 +1,258 @@
+package collectors
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/logins/infrastructure/entities"
+)
+
+const (
+	// backfillWindow is how far back wtmp is read at the first collection after start; records
+	// already stored are skipped on save
+	backfillWindow = 24 * time.Hour
+	// maxBackfillRead bounds the tail of wtmp read at the first collection
+	maxBackfillRead = 4 << 20
+	// maxFailureRows bounds the service / user / source combinations kept per collection; the
+	// remainder is counted per service with an empty user and source
+	maxFailureRows = 100
+)
+
+// UtmpPath returns the host's utmp. /var/run is usually an absolute symlink to /run, which would
+// resolve inside the container, so HOST_ROOT/run is tried first.
+func UtmpPath() string {
+	if p := hostfs.Root("run", "utmp"); fileExists(p) {
+		return p
+	}
+	return hostfs.Root("var", "run", "utmp")
+}
+
+// WtmpPath returns the host's wtmp.
+func WtmpPath() string { return hostfs.Root("var", "log", "wtmp") }
+
+// Available reports whether the host has utmp or wtmp. Distributions that replaced them with
+// wtmpdb (not read) have neither; failed logins are still counted when an auth log exists.
+func Available(authLogs []string) bool {
+	if fileExists(UtmpPath()) || fileExists(WtmpPath()) {
+		return true
+	}
+	for _, p := range authLogs {
+		if fileExists(hostfs.Root(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoginCollector reads the current sessions from utmp, new records from wtmp and failed
+// authentications from the auth logs, all under HOST_ROOT.
+type LoginCollector struct {
+	logger   *log.Logger
+	authLogs []string
+
+	mu sync.Mutex
+	// wtmpInfo and wtmpOffset are where the previous collection stopped reading wtmp
+	wtmpInfo   os.FileInfo
+	wtmpOffset int64
+	// lineUsers is the user logged in on each terminal, to name the user of a logout record
+	lineUsers map[string]string
+	tails     map[string]*logTail
+}
+
+// NewLoginCollector creates a login collector counting failures in authLogs (host paths).
+func NewLoginCollector(logger *log.Logger, authLogs []string) *LoginCollector {
+	return &LoginCollector{
+		logger:    logger,
+		authLogs:  authLogs,
+		lineUsers: make(map[string]string),
+		tails:     make(map[string]*logTail),
+	}
+}
+
+// Collect returns the current sessions, the wtmp records since the previous collection (those of
+// the last 24 hours at the first one) and the failed authentications logged since the previous
+// collection (none at the first one).
+func (c *LoginCollector) Collect(ctx context.Context) (entities.LoginMetric, error) {
+	c.logger.Debug("Collecting login sessions")
+	now := time.Now().UTC()
+	metric := entities.LoginMetric{Timestamp: now}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := os.ReadFile(UtmpPath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return metric, err
+	}
+	if err == nil {
+		metric.Sessions = []entities.Session{}
+		for _, r := range ParseUtmp(data) {
+			if r.Type != UtmpUserProcess || r.User == "" {
+				continue
+			}
+			metric.Sessions = append(metric.Sessions, entities.Session{User: r.User, Line: r.Line, Source: r.Source(), LoginAt: r.Time, PID: r.PID})
+			c.lineUsers[r.Line] = r.User
+		}
+	}
+
+	first := c.wtmpInfo == nil
+	records, err := c.readWtmp()
+	if err != nil {
+		return metric, err
+	}
+	cutoff := now.Add(-backfillWindow)
+	for _, r := range records {
+		e, ok := c.event(r)
+		if ok && (!first || e.Timestamp.After(cutoff)) {
+			metric.Events = append(metric.Events, e)
+		}
+	}
+
+	counts := make(map[entities.AuthFailure]uint64)
+	for _, path := range c.authLogs {
+		tail := c.tails[path]
+		if tail == nil {
+			tail = &logTail{}
+			c.tails[path] = tail
+		}
+		data, err := tail.readNew(hostfs.Root(path))
+		if errors.Is(err, fs.ErrNotExist) {
+			delete(c.tails, path)
+			continue
+		}
+		if err != nil {
+			c.logger.Warn("Failed to read auth log", "path", path, "error", err)
+			continue
+		}
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		sc.Buffer(make([]byte, 0, 64*1024), maxAuthLogRead)
+		for sc.Scan() {
+			if service, user, source, ok := ParseAuthLine(sc.Bytes()); ok {
+				counts[entities.AuthFailure{Service: service, User: user, Source: source}]++
+			}
+		}
+	}
+	metric.Failures = failureRows(counts, now)
+	return metric, ctx.Err()
+}
+
+// readWtmp returns the complete records appended to wtmp since the previous call; the first
+// call reads the last records up to maxBackfillRead, and a rotated wtmp is read from its start.
+func (c *LoginCollector) readWtmp() ([]UtmpRecord, error) {
+	f, err := os.Open(WtmpPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size() - info.Size()%utmpRecordSize
+	switch {
+	case c.wtmpInfo == nil:
+		c.wtmpOffset = max(0, size-(maxBackfillRead-maxBackfillRead%utmpRecordSize))
+	case !os.SameFile(c.wtmpInfo, info) || size < c.wtmpOffset:
+		c.wtmpOffset = 0
+	}
+	c.wtmpInfo = info
+	if size <= c.wtmpOffset {
+		return nil, nil
+	}
+	buf := make([]byte, size-c.wtmpOffset)
+	n, err := f.ReadAt(buf, c.wtmpOffset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	n -= n % utmpRecordSize
+	c.wtmpOffset += int64(n)
+	return ParseUtmp(buf[:n]), nil
+}
+
+// event turns a wtmp record into a login event. Logout records usually carry no user name, so
+// the user last logged in on the terminal is used; logouts of unknown users (getty processes
+// that never had a login) are left out, as are other record types.
+func (c *LoginCollector) event(r UtmpRecord) (entities.LoginEvent, bool) {
+	e := entities.LoginEvent{Timestamp: r.Time, Line: r.Line}
+	switch {
+	case r.Type == UtmpUserProcess && r.User != "":
+		e.Kind, e.User, e.Source = entities.KindLogin, r.User, r.Source()
+		c.lineUsers[r.Line] = r.User
+	case r.Type == UtmpDeadProcess && r.Line != "":
+		user := c.lineUsers[r.Line]
+		if r.User != "" {
+			user = r.User
+		}
+		if user == "" {
+			return e, false
+		}
+		delete(c.lineUsers, r.Line)
+		e.Kind, e.User = entities.KindLogout, user
+	case r.Type == UtmpBootTime:
+		e.Kind = entities.KindReboot
+	case r.Type == UtmpRunLevel && r.User == "shutdown":
+		e.Kind = entities.KindShutdown
+	default:
+		return e, false
+	}
+	return e, true
+}
+
+// failureRows sorts the counted failures by count and keeps at most maxFailureRows of them.
+func failureRows(counts map[entities.AuthFailure]uint64, at time.Time) []entities.AuthFailure {
+	rows := make([]entities.AuthFailure, 0, len(counts))
+	for key, n := range counts {
+		key.Timestamp, key.Count = at, n
+		rows = append(rows, key)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		if rows[i].Service != rows[j].Service {
+			return rows[i].Service < rows[j].Service
+		}
+		if rows[i].User != rows[j].User {
+			return rows[i].User < rows[j].User
+		}
+		return rows[i].Source < rows[j].Source
+	})
+	if len(rows) <= maxFailureRows {
+		return rows
+	}
+	// Fold the remainder into one row per service; a kept row with an empty user and source
+	// would have the same key, so it is folded too
+	rest := make(map[string]uint64)
+	kept := rows[:0]
+	for i, r := range rows {
+		if i >= maxFailureRows-2 || (r.User == "" && r.Source == "") {
+			rest[r.Service] += r.Count
+			continue
+		}
+		kept = append(kept, r)
+	}
+	for _, service := range []string{entities.ServiceSSH, entities.ServiceSudo} {
+		if n := rest[service]; n > 0 {
+			kept = append(kept, entities.AuthFailure{Timestamp: at, Service: service, Count: n})
+		}
+	}
+	return kept
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
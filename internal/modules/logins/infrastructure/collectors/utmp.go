@@ -0,0 +1,80 @@
+package collectors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+// utmp record types (utmp.h).
+const (
+	UtmpRunLevel    = 1
+	UtmpBootTime    = 2
+	UtmpUserProcess = 7
+	UtmpDeadProcess = 8
+)
+
+// utmpRecordSize is sizeof(struct utmp) on Linux with glibc, 32- and 64-bit alike: 64-bit glibc
+// keeps 32-bit times in it for compatibility.
+const utmpRecordSize = 384
+
+// UtmpRecord is a decoded utmp / wtmp record.
+type UtmpRecord struct {
+	Type int16
+	PID  int32
+	Line string
+	User string
+	Host string
+	Time time.Time
+	// Addr is the remote address, nil when none was recorded
+	Addr net.IP
+}
+
+// ParseUtmp decodes the records of a utmp or wtmp file in the host's byte order; a trailing
+// partial record is ignored.
+func ParseUtmp(data []byte) []UtmpRecord {
+	out := make([]UtmpRecord, 0, len(data)/utmpRecordSize)
+	for off := 0; off+utmpRecordSize <= len(data); off += utmpRecordSize {
+		b := data[off : off+utmpRecordSize]
+		r := UtmpRecord{
+			Type: int16(binary.NativeEndian.Uint16(b[0:2])),
+			PID:  int32(binary.NativeEndian.Uint32(b[4:8])),
+			Line: cString(b[8:40]),
+			User: cString(b[44:76]),
+			Host: cString(b[76:332]),
+		}
+		// tv_sec is read unsigned so records after 2038 still decode
+		sec := binary.NativeEndian.Uint32(b[340:344])
+		usec := binary.NativeEndian.Uint32(b[344:348])
+		r.Time = time.Unix(int64(sec), int64(usec)*1000).UTC()
+		addr := b[348:364]
+		switch {
+		case bytes.Count(addr, []byte{0}) == len(addr):
+		case bytes.Count(addr[4:], []byte{0}) == len(addr)-4:
+			r.Addr = net.IP(bytes.Clone(addr[:4]))
+		default:
+			r.Addr = net.IP(bytes.Clone(addr))
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// Source returns the remote host of the record: ut_host, else the recorded address.
+func (r UtmpRecord) Source() string {
+	if r.Host != "" {
+		return r.Host
+	}
+	if r.Addr != nil {
+		return r.Addr.String()
+	}
+	return ""
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
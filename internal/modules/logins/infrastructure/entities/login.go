@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"time"
+
+	"system-stats/internal/app/textutil"
+)
+
+// Kinds of login events read from wtmp.
+const (
+	KindLogin    = "login"
+	KindLogout   = "logout"
+	KindReboot   = "reboot"
+	KindShutdown = "shutdown"
+)
+
+// Services whose failed authentications are counted.
+const (
+	ServiceSSH  = "sshd"
+	ServiceSudo = "sudo"
+)
+
+// Column sizes of the login tables. User names in auth logs are chosen by whoever attempts the
+// login and utmp hosts can be 256 bytes, so values are cut to fit rather than failing the save.
+const (
+	MaxUserLen          = 64
+	MaxLineLen          = 64
+	MaxSourceLen        = 255
+	MaxFailureSourceLen = 64
+	maxKindLen          = 16
+)
+
+// LoginMetric is one collection of the login sessions, the wtmp records written since the
+// previous one and the failed authentications logged since the previous one.
+type LoginMetric struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Sessions are the users logged in now (utmp); nil when utmp cannot be read
+	Sessions []Session `json:"sessions"`
+	// Events are new wtmp records; a pushed metric carries those of recent collections so none
+	// is lost between pushes
+	Events []LoginEvent `json:"events,omitempty"`
+	// Failures are the failed authentications counted since the previous collection, pushed like Events
+	Failures []AuthFailure `json:"failures,omitempty"`
+}
+
+// Session is a user logged in on a terminal.
+type Session struct {
+	User string `json:"user"`
+	// Line is the terminal (pts/0, tty1)
+	Line string `json:"line"`
+	// Source is the remote host or address; empty for local logins
+	Source  string    `json:"source"`
+	LoginAt time.Time `json:"login_at"`
+	PID     int32     `json:"pid"`
+}
+
+// LoginEvent is a login, logout, reboot or shutdown recorded in wtmp.
+type LoginEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	User      string    `json:"user"`
+	Line      string    `json:"line"`
+	Source    string    `json:"source"`
+}
+
+// AuthFailure counts the failed authentications of one service, user and source in a collection.
+type AuthFailure struct {
+	// Timestamp is the collection the attempts were counted in
+	Timestamp time.Time `json:"timestamp"`
+	Service   string    `json:"service"`
+	// User is the account tried (the invoking user for sudo); empty for the remainder of a
+	// collection with more combinations than are stored
+	User string `json:"user"`
+	// Source is the remote address (sshd); empty for sudo
+	Source string `json:"source"`
+	Count  uint64 `json:"count"`
+}
+
+// LoginSessionEntity is a current session in a host's session inventory. User columns are
+// named user_name in all tables; user is reserved in PostgreSQL.
+type LoginSessionEntity struct {
+	HostID  uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false"`
+	Line    string    `json:"line" gorm:"primaryKey;size:64"`
+	LoginAt time.Time `json:"login_at" gorm:"primaryKey"`
+	User    string    `json:"user" gorm:"column:user_name;size:64"`
+	Source  string    `json:"source" gorm:"size:255"`
+	PID     int32     `json:"pid"`
+	// Timestamp is the collection that reported the session
+	Timestamp time.Time `json:"timestamp" gorm:"index"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (LoginSessionEntity) TableName() string { return "login_sessions" }
+
+// HistoricalLoginEvent is a wtmp record of a host. Records read or pushed twice are stored once.
+type HistoricalLoginEvent struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_login_events_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_login_events_host_ts,priority:2"`
+	Kind      string    `json:"kind" gorm:"primaryKey;size:16"`
+	Line      string    `json:"line" gorm:"primaryKey;size:64"`
+	User      string    `json:"user" gorm:"column:user_name;primaryKey;size:64"`
+	Source    string    `json:"source" gorm:"size:255"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalLoginEvent) TableName() string { return "login_events" }
+
+// HistoricalAuthFailure is an AuthFailure of a host.
+type HistoricalAuthFailure struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_auth_failures_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_auth_failures_host_ts,priority:2"`
+	Service   string    `json:"service" gorm:"primaryKey;size:16"`
+	User      string    `json:"user" gorm:"column:user_name;primaryKey;size:64"`
+	Source    string    `json:"source" gorm:"primaryKey;size:64"`
+	Count     uint64    `json:"count"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalAuthFailure) TableName() string { return "auth_failure_samples" }
+
+// ToRows converts the metric's events and failures into their rows, cut to the column sizes.
+func (m LoginMetric) ToRows(hostId uint) ([]HistoricalLoginEvent, []HistoricalAuthFailure) {
+	events := make([]HistoricalLoginEvent, 0, len(m.Events))
+	for _, e := range m.Events {
+		events = append(events, HistoricalLoginEvent{
+			HostID:    hostId,
+			Timestamp: e.Timestamp.UTC().Truncate(time.Microsecond),
+			Kind:      textutil.Truncate(e.Kind, maxKindLen),
+			Line:      textutil.Truncate(e.Line, MaxLineLen),
+			User:      textutil.Truncate(e.User, MaxUserLen),
+			Source:    textutil.Truncate(e.Source, MaxSourceLen),
+		})
+	}
+	failures := make([]HistoricalAuthFailure, 0, len(m.Failures))
+	for _, f := range m.Failures {
+		failures = append(failures, HistoricalAuthFailure{
+			HostID:    hostId,
+			Timestamp: f.Timestamp.UTC().Truncate(time.Microsecond),
+			Service:   textutil.Truncate(f.Service, maxKindLen),
+			User:      textutil.Truncate(f.User, MaxUserLen),
+			Source:    textutil.Truncate(f.Source, MaxFailureSourceLen),
+			Count:     f.Count,
+		})
+	}
+	return events, failures
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/textutil"
+	"system-stats/internal/modules/logins/infrastructure/entities"
+)
+
+// insertBatch bounds the rows per INSERT; the first collection can hold a day of wtmp records
+const insertBatch = 500
+
+type LoginRepository interface {
+	// SaveCurrentMetric replaces the host's sessions (unless the metric has none, or is older than
+	// the stored sessions) and stores the events and failures not stored yet.
+	SaveCurrentMetric(ctx context.Context, metric entities.LoginMetric, hostId uint) error
+	// GetSessionsByHost returns the host's current sessions by login time.
+	GetSessionsByHost(ctx context.Context, hostId uint) ([]entities.LoginSessionEntity, error)
+	// GetEventsByHost returns login events in time order, optionally for one user.
+	GetEventsByHost(ctx context.Context, hostId uint, hours float64, user string) ([]entities.HistoricalLoginEvent, error)
+	// GetFailuresByHost returns failed authentication counts in time order, optionally for one service.
+	GetFailuresByHost(ctx context.Context, hostId uint, hours float64, service string) ([]entities.HistoricalAuthFailure, error)
+}
+
+type loginRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginRepository(db *gorm.DB) LoginRepository {
+	return &loginRepository{db: db}
+}
+
+func (r *loginRepository) SaveCurrentMetric(ctx context.Context, metric entities.LoginMetric, hostId uint) error {
+	events, failures := metric.ToRows(hostId)
+	at := metric.Timestamp.UTC().Truncate(time.Microsecond)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if metric.Sessions != nil {
+			var newer int64
+			if err := tx.Model(&entities.LoginSessionEntity{}).Where("host_id = ? AND timestamp >= ?", hostId, at).Count(&newer).Error; err != nil {
+				return err
+			}
+			// An agent repeating an older push must not undo a newer collection
+			if newer == 0 {
+				if err := tx.Where("host_id = ?", hostId).Delete(&entities.LoginSessionEntity{}).Error; err != nil {
+					return err
+				}
+				rows := make([]entities.LoginSessionEntity, 0, len(metric.Sessions))
+				for _, s := range metric.Sessions {
+					rows = append(rows, entities.LoginSessionEntity{
+						HostID:    hostId,
+						Line:      textutil.Truncate(s.Line, entities.MaxLineLen),
+						LoginAt:   s.LoginAt.UTC().Truncate(time.Microsecond),
+						User:      textutil.Truncate(s.User, entities.MaxUserLen),
+						Source:    textutil.Truncate(s.Source, entities.MaxSourceLen),
+						PID:       s.PID,
+						Timestamp: at,
+					})
+				}
+				if len(rows) > 0 {
+					if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&rows, insertBatch).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(events) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&events, insertBatch).Error; err != nil {
+				return err
+			}
+		}
+		if len(failures) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&failures, insertBatch).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *loginRepository) GetSessionsByHost(ctx context.Context, hostId uint) ([]entities.LoginSessionEntity, error) {
+	rows := []entities.LoginSessionEntity{}
+	err := r.db.WithContext(ctx).Where("host_id = ?", hostId).Order("login_at ASC, line ASC").Find(&rows).Error
+	return rows, err
+}
+
+func (r *loginRepository) GetEventsByHost(ctx context.Context, hostId uint, hours float64, user string) ([]entities.HistoricalLoginEvent, error) {
+	rows := []entities.HistoricalLoginEvent{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if user != "" {
+		q = q.Where("user_name = ?", user)
+	}
+	err := q.Order("timestamp ASC, kind ASC, line ASC").Find(&rows).Error
+	return rows, err
+}
+
+func (r *loginRepository) GetFailuresByHost(ctx context.Context, hostId uint, hours float64, service string) ([]entities.HistoricalAuthFailure, error) {
+	rows := []entities.HistoricalAuthFailure{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if service != "" {
+		q = q.Where("service = ?", service)
+	}
+	err := q.Order("timestamp ASC, count DESC").Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,72 @@
+// Package logins registers the logins module, which reads login sessions and events from
+// utmp / wtmp and counts failed SSH and sudo authentications in the auth logs.
+package logins
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	loginservice "system-stats/internal/modules/logins/application"
+	"system-stats/internal/modules/logins/infrastructure/collectors"
+	"system-stats/internal/modules/logins/infrastructure/entities"
+	"system-stats/internal/modules/logins/infrastructure/repositories"
+	handlers "system-stats/internal/modules/logins/presentation"
+)
+
+// Module keeps the session inventory in login_sessions, wtmp records in login_events and
+// failed authentications in auth_failure_samples, and serves /logins.
+type Module struct{}
+
+func (Module) Name() string { return "logins" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 19,
+		Name:    "login_sessions_events_and_auth_failures",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.LoginSessionEntity{}, &entities.HistoricalLoginEvent{}, &entities.HistoricalAuthFailure{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalAuthFailure{}, &entities.HistoricalLoginEvent{}, &entities.LoginSessionEntity{})
+		}},
+	}}
+}
+
+// RetentionTables prunes login_sessions too: a host that stopped reporting keeps no sessions.
+func (Module) RetentionTables() []string {
+	return []string{"login_events", "auth_failure_samples", "login_sessions"}
+}
+
+func (Module) HostTables() []string {
+	return []string{"login_sessions", "login_events", "auth_failure_samples"}
+}
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	authLogs := collectors.AuthLogsFromEnv()
+	service := loginservice.NewService(deps.Logger, repositories.NewLoginRepository(deps.DB), authLogs)
+	handler := handlers.NewLoginsHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/logins", handler.HandleLogins)
+		},
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}
+	if !collectors.Available(authLogs) {
+		deps.Logger.Info("No utmp, wtmp or auth log to read, not collecting logins")
+		return inst, nil
+	}
+	// Logins are not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.LoginMetric]("logins", service.Collect, service.Save, nil)
+	inst.Collector = &collector
+	inst.Metrics = loginservice.NewPrometheusCollector(service)
+	inst.PushData = registry.PushLatest(service.PushData)
+	return inst, nil
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	loginservice "system-stats/internal/modules/logins/application"
+)
+
+// LoginsHandler handles HTTP requests for login sessions and failed authentications.
+type LoginsHandler struct {
+	logger  *log.Logger
+	service loginservice.Service
+	hosts   hostservice.Service
+}
+
+// NewLoginsHandler creates a new HTTP handler for login endpoints.
+func NewLoginsHandler(logger *log.Logger, service loginservice.Service, hosts hostservice.Service) *LoginsHandler {
+	return &LoginsHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleLogins returns the sessions of a host, its login events and its failed authentications.
+//
+// @Summary     Login sessions and failed authentications
+// @Description Returns the users logged in now (utmp), the logins, logouts, reboots and shutdowns recorded in wtmp with user, terminal and source address, and the failed SSH and sudo authentications counted per collection, user and source from the auth logs. `user` filters events and `service` (sshd, sudo) filters failures.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       user     query    string   false  "Only events of this user"
+// @Param       service  query    string   false  "Only failures of this service (sshd, sudo)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /logins [get]
+func (h *LoginsHandler) HandleLogins(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	user := c.Query("user")
+	service := c.Query("service")
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyLoginsPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for login data", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	sessions, err := h.service.GetSessionsByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching login sessions")
+			return
+		}
+		h.logger.Error("Failed to fetch login sessions", "error", err, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.service.GetEventsByHost(ctx, effective, hours, user)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching login events")
+			return
+		}
+		h.logger.Error("Failed to fetch login events", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	failures, err := h.service.GetFailuresByHost(ctx, effective, hours, service)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching failed authentications")
+			return
+		}
+		h.logger.Error("Failed to fetch failed authentications", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+		"events":   events,
+		"failures": failures,
+	})
+}
@@ -2,11 +2,14 @@ package rammetrics
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
 
 	"system-stats/internal/app/metrics"
 	"system-stats/internal/modules/memory/infrastructure/collectors"
 	"system-stats/internal/modules/memory/infrastructure/entities"
 	memoryrepos "system-stats/internal/modules/memory/infrastructure/repositories"
+	"system-stats/internal/modules/memory/infrastructure/value_objects"
 
 	"github.com/charmbracelet/log"
 )
@@ -18,6 +21,11 @@ type Service interface {
 	GetLatestByHost(ctx context.Context, hostId uint) (*entities.MemoryMetric, error)
 	GetHistorical(ctx context.Context, hours float64) ([]entities.HistoricalMemoryMetric, error)
 	GetHistoricalByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalMemoryMetric, error)
+	GetExtendedHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalMemoryExtendedMetric, error)
+	// LatestExtended returns this instance's last extended collection (no database access); nil before the first one.
+	LatestExtended() *entities.MemoryExtended
+	// ReceivePush stores an extended collection pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
 	CollectAndSave(ctx context.Context, hostId uint) error
 }
 
@@ -31,6 +39,11 @@ func (a *memoryCollectorAdapter) Collect(ctx context.Context) (entities.MemoryMe
 
 type service struct {
 	metrics.Service[entities.MemoryMetric, entities.HistoricalMemoryMetric]
+	repo             memoryrepos.MemoryRepository
+	vmstatCalculator *value_objects.VmstatCalculator
+
+	mu             sync.RWMutex
+	latestExtended *entities.MemoryExtended
 }
 
 // GetLatest collects fresh metrics to ensure all current fields are populated.
@@ -46,5 +59,61 @@ func NewService(logger *log.Logger, memoryRepository memoryrepos.MemoryRepositor
 			Collector: &memoryCollectorAdapter{c: collectors.NewMemoryMetricsCollector(logger)},
 			Repo:      memoryRepository,
 		},
+		repo:             memoryRepository,
+		vmstatCalculator: value_objects.NewVmstatCalculator(),
+	}
+}
+
+// Collect gathers memory metrics and fills the vmstat rates and OOM kills from counter deltas.
+func (s *service) Collect(ctx context.Context) (entities.MemoryMetric, error) {
+	metric, err := s.Service.Collect(ctx)
+	if err != nil {
+		return entities.MemoryMetric{}, err
+	}
+	ext := metric.Extended
+	if ext == nil {
+		return metric, nil
+	}
+
+	rate := s.vmstatCalculator.Calculate(ext.Timestamp, ext.Counters)
+	if rate.Valid {
+		ext.SwapInPerSec = &rate.SwapInPerSec
+		ext.SwapOutPerSec = &rate.SwapOutPerSec
+		ext.PageFaultsPerSec = &rate.PageFaultsPerSec
+		ext.MajorFaultsPerSec = &rate.MajorFaultsPerSec
+		ext.OOMKills = &rate.OOMKills
+		if rate.OOMKills > 0 {
+			s.Logger.Warn("OOM killer invoked", "kills", rate.OOMKills)
+		}
+	}
+	s.mu.Lock()
+	s.latestExtended = ext
+	s.mu.Unlock()
+	return metric, nil
+}
+
+func (s *service) LatestExtended() *entities.MemoryExtended {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latestExtended
+}
+
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.MemoryExtended
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	return s.repo.SaveExtendedMetric(ctx, metric, hostId)
+}
+
+func (s *service) GetExtendedHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalMemoryExtendedMetric, error) {
+	rows, err := s.repo.GetExtendedHistoryByHost(ctx, hostId, hours)
+	if err != nil {
+		s.Logger.Error("Failed to get extended memory history", "error", err, "host_id", hostId, "hours", hours)
+		return nil, err
 	}
+	return rows, nil
 }
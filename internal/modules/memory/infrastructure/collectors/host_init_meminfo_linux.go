@@ -193,6 +193,13 @@ type meminfoKBParsed struct {
 	active, inactive, shared              uint64
 	swapTotal, swapFree                   uint64
 	memAvail                              bool
+
+	// Extended fields (see memory_extended.go); the HugePages_ fields are page counts
+	sunreclaim, dirty, writeback        uint64
+	committedAS, commitLimit            uint64
+	anonHugePages, hugePageSize         uint64
+	hugePagesTotal, hugePagesFree       uint64
+	hugePagesReserved, hugePagesSurplus uint64
 }
 
 func parseMeminfoKBytes(r interface{ Read([]byte) (int, error) }) (meminfoKBParsed, error) {
@@ -240,6 +247,28 @@ func parseMeminfoKBytes(r interface{ Read([]byte) (int, error) }) (meminfoKBPars
 			out.swapTotal = kb
 		case "SwapFree":
 			out.swapFree = kb
+		case "SUnreclaim":
+			out.sunreclaim = kb
+		case "Dirty":
+			out.dirty = kb
+		case "Writeback":
+			out.writeback = kb
+		case "Committed_AS":
+			out.committedAS = kb
+		case "CommitLimit":
+			out.commitLimit = kb
+		case "AnonHugePages":
+			out.anonHugePages = kb
+		case "Hugepagesize":
+			out.hugePageSize = kb
+		case "HugePages_Total":
+			out.hugePagesTotal = val
+		case "HugePages_Free":
+			out.hugePagesFree = val
+		case "HugePages_Rsvd":
+			out.hugePagesReserved = val
+		case "HugePages_Surp":
+			out.hugePagesSurplus = val
 		}
 	}
 	return out, sc.Err()
@@ -11,3 +11,8 @@ import (
 func tryVirtualMemoryFromHostInit(_ *log.Logger) (entities.MemoryMetric, bool) {
 	return entities.MemoryMetric{}, false
 }
+
+// ReadMemoryExtended returns nil: meminfo and vmstat exist on Linux only.
+func ReadMemoryExtended() (*entities.MemoryExtended, error) {
+	return nil, nil
+}
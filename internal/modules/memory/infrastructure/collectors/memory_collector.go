@@ -24,10 +24,26 @@ func NewMemoryMetricsCollector(logger *log.Logger) *MemoryMetricsCollector {
 
  // CollectMemoryMetrics gathers current memory performance statistics.
  // This method collects memory usage, available memory, usage percentages,
- // cached memory, buffers, and swap information.
+ // cached memory, buffers, and swap information, plus the extended meminfo and
+ // vmstat detail where /proc is readable.
 func (c *MemoryMetricsCollector) CollectMemoryMetrics(ctx context.Context) (entities.MemoryMetric, error) {
 	c.logger.Debug("Collecting memory statistics")
 
+	metric, err := c.collectUsage(ctx)
+	if err != nil {
+		return metric, err
+	}
+	// The extended detail is optional: a failure keeps the basic figures
+	extended, err := ReadMemoryExtended()
+	if err != nil {
+		c.logger.Warn("Failed to collect extended memory statistics", "error", err)
+	}
+	metric.Extended = extended
+	return metric, nil
+}
+
+func (c *MemoryMetricsCollector) collectUsage(ctx context.Context) (entities.MemoryMetric, error) {
+
 	// Docker + /host: prefer PID-1 meminfo when MemTotal matches sysfs RAM; if MemTotal looks like a
 	// cgroup cap (LXC/Docker), use sysfs block total + host cgroup v2 memory.current (see host_init_meminfo_linux.go).
 	if m, ok := tryVirtualMemoryFromHostInit(c.logger); ok {
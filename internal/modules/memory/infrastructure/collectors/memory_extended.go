@@ -0,0 +1,92 @@
+//go:build linux
+
+package collectors
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/memory/infrastructure/entities"
+)
+
+// ReadMemoryExtended reads meminfo as seen from PID 1's mount namespace (see hostInitMeminfoPath),
+// else HOST_PROC/meminfo, and HOST_PROC/vmstat. It returns nil without an error where meminfo
+// does not exist; rates are left for the caller to derive.
+func ReadMemoryExtended() (*entities.MemoryExtended, error) {
+	f, err := os.Open(hostInitMeminfoPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		f, err = os.Open(hostfs.Proc("meminfo"))
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseMeminfoKBytes(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	m := parsed.extended()
+	m.Timestamp = time.Now().UTC()
+
+	vmstat, err := os.ReadFile(hostfs.Proc("vmstat"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	m.Counters = VmstatCountersFrom(parseVmstat(vmstat))
+	return &m, nil
+}
+
+// extended returns the extended fields of parsed meminfo.
+func (p meminfoKBParsed) extended() entities.MemoryExtended {
+	return entities.MemoryExtended{
+		SlabReclaimable:   p.sreclaimable,
+		SlabUnreclaimable: p.sunreclaim,
+		Dirty:             p.dirty,
+		Writeback:         p.writeback,
+		CommittedAS:       p.committedAS,
+		CommitLimit:       p.commitLimit,
+		HugePagesTotal:    p.hugePagesTotal,
+		HugePagesFree:     p.hugePagesFree,
+		HugePagesReserved: p.hugePagesReserved,
+		HugePagesSurplus:  p.hugePagesSurplus,
+		HugePageSize:      p.hugePageSize,
+		AnonHugePages:     p.anonHugePages,
+	}
+}
+
+// parseVmstat parses the "key value" lines of vmstat; unparsable lines are skipped.
+func parseVmstat(raw []byte) map[string]uint64 {
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+	return values
+}
+
+// VmstatCountersFrom picks the counters the rates are derived from out of parsed vmstat values.
+func VmstatCountersFrom(v map[string]uint64) entities.VmstatCounters {
+	return entities.VmstatCounters{
+		SwapIn:      v["pswpin"],
+		SwapOut:     v["pswpout"],
+		PageFaults:  v["pgfault"],
+		MajorFaults: v["pgmajfault"],
+		OOMKill:     v["oom_kill"],
+	}
+}
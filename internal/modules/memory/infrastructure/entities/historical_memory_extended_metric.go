@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"time"
+)
+
+// HistoricalMemoryExtendedMetric stores one MemoryExtended collection. Rates and OOM kills are
+// null when they could not be derived; the raw vmstat counters are not persisted.
+type HistoricalMemoryExtendedMetric struct {
+	// HostID references the host that recorded this metric (primary key with Timestamp)
+	HostID uint `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_mem_ext_host_ts,priority:1"`
+
+	// Timestamp indicates when this metric was recorded (primary key with HostID)
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_mem_ext_host_ts,priority:2"`
+
+	SlabReclaimableBytes   uint64 `json:"slab_reclaimable_bytes" gorm:"column:slab_reclaimable_bytes"`
+	SlabUnreclaimableBytes uint64 `json:"slab_unreclaimable_bytes" gorm:"column:slab_unreclaimable_bytes"`
+	DirtyBytes             uint64 `json:"dirty_bytes" gorm:"column:dirty_bytes"`
+	WritebackBytes         uint64 `json:"writeback_bytes" gorm:"column:writeback_bytes"`
+	CommittedASBytes       uint64 `json:"committed_as_bytes" gorm:"column:committed_as_bytes"`
+	CommitLimitBytes       uint64 `json:"commit_limit_bytes" gorm:"column:commit_limit_bytes"`
+
+	HugePagesTotal     uint64 `json:"hugepages_total" gorm:"column:hugepages_total"`
+	HugePagesFree      uint64 `json:"hugepages_free" gorm:"column:hugepages_free"`
+	HugePagesReserved  uint64 `json:"hugepages_reserved" gorm:"column:hugepages_reserved"`
+	HugePagesSurplus   uint64 `json:"hugepages_surplus" gorm:"column:hugepages_surplus"`
+	HugePageSizeBytes  uint64 `json:"hugepage_size_bytes" gorm:"column:hugepage_size_bytes"`
+	AnonHugePagesBytes uint64 `json:"anon_hugepages_bytes" gorm:"column:anon_hugepages_bytes"`
+
+	SwapInPerSec      *float64 `json:"swap_in_per_sec" gorm:"column:swap_in_per_sec"`
+	SwapOutPerSec     *float64 `json:"swap_out_per_sec" gorm:"column:swap_out_per_sec"`
+	PageFaultsPerSec  *float64 `json:"page_faults_per_sec" gorm:"column:page_faults_per_sec"`
+	MajorFaultsPerSec *float64 `json:"major_faults_per_sec" gorm:"column:major_faults_per_sec"`
+	OOMKills          *uint64  `json:"oom_kills" gorm:"column:oom_kills"`
+}
+
+// NewHistoricalMemoryExtendedMetric converts a collection into its stored row.
+func NewHistoricalMemoryExtendedMetric(m MemoryExtended, hostId uint) HistoricalMemoryExtendedMetric {
+	return HistoricalMemoryExtendedMetric{
+		HostID:                 hostId,
+		Timestamp:              m.Timestamp.UTC(),
+		SlabReclaimableBytes:   m.SlabReclaimable,
+		SlabUnreclaimableBytes: m.SlabUnreclaimable,
+		DirtyBytes:             m.Dirty,
+		WritebackBytes:         m.Writeback,
+		CommittedASBytes:       m.CommittedAS,
+		CommitLimitBytes:       m.CommitLimit,
+		HugePagesTotal:         m.HugePagesTotal,
+		HugePagesFree:          m.HugePagesFree,
+		HugePagesReserved:      m.HugePagesReserved,
+		HugePagesSurplus:       m.HugePagesSurplus,
+		HugePageSizeBytes:      m.HugePageSize,
+		AnonHugePagesBytes:     m.AnonHugePages,
+		SwapInPerSec:           m.SwapInPerSec,
+		SwapOutPerSec:          m.SwapOutPerSec,
+		PageFaultsPerSec:       m.PageFaultsPerSec,
+		MajorFaultsPerSec:      m.MajorFaultsPerSec,
+		OOMKills:               m.OOMKills,
+	}
+}
+
+// GetTimestamp returns the timestamp when this metric was recorded.
+func (h HistoricalMemoryExtendedMetric) GetTimestamp() time.Time { return h.Timestamp }
+
+// GetMetricType returns the metric type identifier for extended memory metrics.
+func (h HistoricalMemoryExtendedMetric) GetMetricType() string { return "memory_extended" }
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalMemoryExtendedMetric) TableName() string { return "memory_extended_metrics" }
@@ -8,11 +8,11 @@ import (
  // This structure contains memory utilization statistics recorded at a specific time,
  // including both percentage and absolute byte values.
 type HistoricalMemoryMetric struct {
-	// HostID is the foreign key referencing the host that recorded this metric
-	HostID *uint `json:"host_id" gorm:"default:null;index;index:idx_mem_host_ts"`
+	// HostID references the host that recorded this metric (primary key with Timestamp)
+	HostID uint `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_mem_host_ts,priority:1"`
 
-	// Timestamp indicates when this memory metric was recorded (primary key)
-	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_mem_host_ts"`
+	// Timestamp indicates when this memory metric was recorded (primary key with HostID)
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_mem_host_ts,priority:2"`
 
 	// UsagePercent shows the memory utilization percentage at the time of recording
 	UsagePercent float64 `json:"usage_percent" gorm:"column:usage_percent"`
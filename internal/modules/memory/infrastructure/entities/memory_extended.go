@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+// MemoryExtended holds the /proc/meminfo and /proc/vmstat detail behind the basic memory figures.
+// Rates and OOMKills are derived from the previous collection and are nil on the first one or
+// after a counter reset.
+type MemoryExtended struct {
+	// Timestamp is when the files were read; it keys the stored row
+	Timestamp time.Time `json:"timestamp"`
+
+	// SlabReclaimable and SlabUnreclaimable split kernel slab memory, in bytes
+	SlabReclaimable   uint64 `json:"slab_reclaimable"`
+	SlabUnreclaimable uint64 `json:"slab_unreclaimable"`
+
+	// Dirty is memory waiting to be written back; Writeback is being written now, in bytes
+	Dirty     uint64 `json:"dirty"`
+	Writeback uint64 `json:"writeback"`
+
+	// CommittedAS is the memory allocated by processes; CommitLimit what the overcommit policy allows
+	CommittedAS uint64 `json:"committed_as"`
+	CommitLimit uint64 `json:"commit_limit"`
+
+	// HugePages* count pages of HugePageSize bytes; AnonHugePages is transparent huge page memory in bytes
+	HugePagesTotal    uint64 `json:"hugepages_total"`
+	HugePagesFree     uint64 `json:"hugepages_free"`
+	HugePagesReserved uint64 `json:"hugepages_reserved"`
+	HugePagesSurplus  uint64 `json:"hugepages_surplus"`
+	HugePageSize      uint64 `json:"hugepage_size"`
+	AnonHugePages     uint64 `json:"anon_hugepages"`
+
+	// Counters are the cumulative /proc/vmstat values the rates are computed from
+	Counters VmstatCounters `json:"counters"`
+
+	// SwapInPerSec and SwapOutPerSec are pages swapped in and out per second
+	SwapInPerSec  *float64 `json:"swap_in_per_sec"`
+	SwapOutPerSec *float64 `json:"swap_out_per_sec"`
+
+	// PageFaultsPerSec counts all page faults, MajorFaultsPerSec those that needed I/O
+	PageFaultsPerSec  *float64 `json:"page_faults_per_sec"`
+	MajorFaultsPerSec *float64 `json:"major_faults_per_sec"`
+
+	// OOMKills is the number of OOM killer invocations since the previous collection
+	OOMKills *uint64 `json:"oom_kills"`
+}
+
+// VmstatCounters are cumulative counters from /proc/vmstat since boot.
+type VmstatCounters struct {
+	SwapIn      uint64 `json:"pswpin"`
+	SwapOut     uint64 `json:"pswpout"`
+	PageFaults  uint64 `json:"pgfault"`
+	MajorFaults uint64 `json:"pgmajfault"`
+	// OOMKill is absent before Linux 4.13 and then stays zero
+	OOMKill uint64 `json:"oom_kill"`
+}
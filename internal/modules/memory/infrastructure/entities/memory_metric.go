@@ -40,6 +40,9 @@ type MemoryMetric struct {
 	Inactive uint64 `json:"inactive"`
 	Shared   uint64 `json:"shared"`
 	SwapFree uint64 `json:"swap_free"`
+
+	// Extended is the slab, hugepage, commit and vmstat detail; nil where /proc is not readable
+	Extended *MemoryExtended `json:"extended,omitempty"`
 }
 
  // GetTimestamp returns the current time for memory metrics.
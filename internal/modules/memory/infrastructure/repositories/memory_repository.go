@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"system-stats/internal/app/database"
 	localentities "system-stats/internal/modules/memory/infrastructure/entities"
@@ -17,6 +18,10 @@ type MemoryRepository interface {
 	GetLatestMetricByHost(ctx context.Context, hostId uint) (*localentities.MemoryMetric, error)
 	GetHistoricalMetrics(ctx context.Context, hours float64) ([]localentities.HistoricalMemoryMetric, error)
 	GetHistoricalMetricsByHost(ctx context.Context, hostId uint, hours float64) ([]localentities.HistoricalMemoryMetric, error)
+	// SaveExtendedMetric stores an extended collection on its own (agent pushes); one already
+	// stored is skipped.
+	SaveExtendedMetric(ctx context.Context, metric localentities.MemoryExtended, hostId uint) error
+	GetExtendedHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]localentities.HistoricalMemoryExtendedMetric, error)
 }
 
 type memoryRepository struct {
@@ -29,13 +34,30 @@ func NewMemoryRepository(db *gorm.DB) MemoryRepository {
 
 func (r *memoryRepository) SaveCurrentMetric(ctx context.Context, metric localentities.MemoryMetric, hostId uint) error {
 	historicalMetric := localentities.HistoricalMemoryMetric{
-		HostID:       &hostId,
+		HostID:       hostId,
 		Timestamp:    time.Now().UTC(),
 		UsagePercent: metric.UsagePercent,
 		UsedBytes:    metric.Used,
 		TotalBytes:   metric.Total,
 	}
-	return r.db.WithContext(ctx).Create(&historicalMetric).Error
+	if metric.Extended == nil {
+		return r.db.WithContext(ctx).Create(&historicalMetric).Error
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&historicalMetric).Error; err != nil {
+			return err
+		}
+		return r.saveExtended(tx, *metric.Extended, hostId)
+	})
+}
+
+func (r *memoryRepository) SaveExtendedMetric(ctx context.Context, metric localentities.MemoryExtended, hostId uint) error {
+	return r.saveExtended(r.db.WithContext(ctx), metric, hostId)
+}
+
+func (r *memoryRepository) saveExtended(db *gorm.DB, metric localentities.MemoryExtended, hostId uint) error {
+	row := localentities.NewHistoricalMemoryExtendedMetric(metric, hostId)
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error
 }
 
 func (r *memoryRepository) GetLatestMetric(ctx context.Context) (localentities.MemoryMetric, error) {
@@ -93,3 +115,11 @@ func (r *memoryRepository) GetHistoricalMetricsByHost(ctx context.Context, hostI
 		Find(&metrics).Error
 	return metrics, err
 }
+
+func (r *memoryRepository) GetExtendedHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]localentities.HistoricalMemoryExtendedMetric, error) {
+	metrics := []localentities.HistoricalMemoryExtendedMetric{}
+	err := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours).
+		Order("timestamp ASC").
+		Find(&metrics).Error
+	return metrics, err
+}
@@ -0,0 +1,72 @@
+// Package value_objects provides domain value objects for derived memory metrics.
+// This package turns cumulative /proc/vmstat counters into per-second rates and
+// per-interval OOM kill counts, mirroring the disk I/O rate calculator.
+package value_objects
+
+import (
+	"sync"
+	"time"
+
+	"system-stats/internal/modules/memory/infrastructure/entities"
+)
+
+// DefaultMaxSampleGap is the longest interval between two samples that still yields a rate.
+const DefaultMaxSampleGap = 10 * time.Minute
+
+// VmstatRate holds what two consecutive counter snapshots yield.
+type VmstatRate struct {
+	SwapInPerSec      float64
+	SwapOutPerSec     float64
+	PageFaultsPerSec  float64
+	MajorFaultsPerSec float64
+
+	// OOMKills is the number of OOM kills between the two snapshots
+	OOMKills uint64
+
+	// Valid is false when no previous sample existed, the gap was too long or the counters were reset
+	Valid bool
+}
+
+// VmstatCalculator keeps the previous counter snapshot and derives rates from deltas.
+type VmstatCalculator struct {
+	mu     sync.Mutex
+	maxGap time.Duration
+	at     time.Time
+	prev   *entities.VmstatCounters
+}
+
+// NewVmstatCalculator creates a calculator that ignores sample pairs further apart than DefaultMaxSampleGap.
+func NewVmstatCalculator() *VmstatCalculator {
+	return &VmstatCalculator{maxGap: DefaultMaxSampleGap}
+}
+
+// Calculate records counters at time at and returns the rates since the previous call.
+func (c *VmstatCalculator) Calculate(at time.Time, cur entities.VmstatCounters) VmstatRate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, prevAt := c.prev, c.at
+	c.prev, c.at = &cur, at
+	if prev == nil {
+		return VmstatRate{}
+	}
+	elapsed := at.Sub(prevAt)
+	if elapsed <= 0 || elapsed > c.maxGap {
+		return VmstatRate{}
+	}
+	// The counters only move backwards when the host rebooted between two samples
+	if cur.SwapIn < prev.SwapIn || cur.SwapOut < prev.SwapOut || cur.PageFaults < prev.PageFaults ||
+		cur.MajorFaults < prev.MajorFaults || cur.OOMKill < prev.OOMKill {
+		return VmstatRate{}
+	}
+
+	seconds := elapsed.Seconds()
+	return VmstatRate{
+		SwapInPerSec:      float64(cur.SwapIn-prev.SwapIn) / seconds,
+		SwapOutPerSec:     float64(cur.SwapOut-prev.SwapOut) / seconds,
+		PageFaultsPerSec:  float64(cur.PageFaults-prev.PageFaults) / seconds,
+		MajorFaultsPerSec: float64(cur.MajorFaults-prev.MajorFaults) / seconds,
+		OOMKills:          cur.OOMKill - prev.OOMKill,
+		Valid:             true,
+	}
+}
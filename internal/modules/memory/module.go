@@ -0,0 +1,64 @@
+// Package memory registers the memory metrics module.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/app/snapshot"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	memoryservice "system-stats/internal/modules/memory/application"
+	"system-stats/internal/modules/memory/infrastructure/entities"
+	"system-stats/internal/modules/memory/infrastructure/repositories"
+	handlers "system-stats/internal/modules/memory/presentation"
+)
+
+// Module collects RAM and swap usage plus the extended meminfo / vmstat detail and serves /memory.
+type Module struct{}
+
+func (Module) Name() string { return "memory" }
+
+// Migrations adds memory_extended_metrics; memory_metrics is part of the baseline schema.
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 10,
+		Name:    "memory_extended_metrics_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalMemoryExtendedMetric{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalMemoryExtendedMetric{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string {
+	return []string{"memory_metrics", "memory_extended_metrics"}
+}
+
+func (Module) HostTables() []string {
+	return []string{"memory_metrics", "memory_extended_metrics"}
+}
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := memoryservice.NewService(deps.Logger, repositories.NewMemoryRepository(deps.DB))
+	handler := handlers.NewMemoryHandler(deps.Logger, service, deps.Hosts)
+	collector := historycore.NewModuleCollector("memory", service.Collect, service.Save,
+		func(snap *snapshot.Snapshot, m *entities.MemoryMetric) { snap.Memory = m })
+	return registry.Instance{
+		Collector: &collector,
+		Routes: func(r gin.IRoutes) {
+			r.GET("/memory", handler.HandleMemoryStats)
+		},
+		// Agents push only the extended detail; the basic usage travels in the push payload itself
+		PushData: registry.PushLatest(service.LatestExtended),
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}, nil
+}
@@ -32,7 +32,8 @@ func NewMemoryHandler(logger *log.Logger, service memoryservice.Service, hosts h
 // HandleMemoryStats returns current memory metrics with latest and historical data.
 //
 // @Summary     Memory metrics
-// @Description Returns latest RAM snapshot and historical usage data.
+// @Description Returns latest RAM snapshot, historical usage data and the history of extended
+// @Description statistics (slab, hugepages, dirty/writeback, commit, swap and page fault rates, OOM kills).
 // @Tags        metrics
 // @Produce     json
 // @Param       hours    query    number   false  "History window in hours"  default(0.0833)
@@ -71,8 +72,15 @@ func (h *MemoryHandler) HandleMemoryStats(c *gin.Context) {
 		return
 	}
 
+	extendedHistory, err := h.service.GetExtendedHistoryByHost(c.Request.Context(), effective, hours)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"latest":  latestMetrics,
-		"history": historyMetrics,
+		"latest":           latestMetrics,
+		"history":          historyMetrics,
+		"extended_history": extendedHistory,
 	})
 }
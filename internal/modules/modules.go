@@ -0,0 +1,49 @@
+// Package modules lists the built-in metric modules in registration order.
+package modules
+
+import (
+	"system-stats/internal/app/registry"
+	"system-stats/internal/modules/cpu"
+	"system-stats/internal/modules/custom"
+	"system-stats/internal/modules/disk"
+	"system-stats/internal/modules/docker"
+	"system-stats/internal/modules/exec"
+	"system-stats/internal/modules/kernellog"
+	"system-stats/internal/modules/logins"
+	"system-stats/internal/modules/memory"
+	"system-stats/internal/modules/network"
+	"system-stats/internal/modules/ports"
+	"system-stats/internal/modules/pressure"
+	"system-stats/internal/modules/processes"
+	"system-stats/internal/modules/sensors"
+	"system-stats/internal/modules/smart"
+	"system-stats/internal/modules/sockets"
+	"system-stats/internal/modules/storage"
+	"system-stats/internal/modules/timesync"
+	"system-stats/internal/modules/watchlist"
+)
+
+// All returns every built-in module. Add new modules here; the container, migrator, router
+// and retention cleanup pick them up from the registry.
+func All() []registry.Module {
+	return []registry.Module{
+		cpu.Module{},
+		memory.Module{},
+		disk.Module{},
+		network.Module{},
+		pressure.Module{},
+		sockets.Module{},
+		ports.Module{},
+		storage.Module{},
+		smart.Module{},
+		docker.Module{},
+		sensors.Module{},
+		timesync.Module{},
+		logins.Module{},
+		kernellog.Module{},
+		exec.Module{},
+		custom.Module{},
+		processes.Module{},
+		watchlist.Module{},
+	}
+}
@@ -3,6 +3,7 @@ package networkmetrics
 import (
 	"context"
 	"errors"
+	"time"
 
 	"system-stats/internal/app/metrics"
 	"system-stats/internal/modules/network/infrastructure/collectors"
@@ -20,6 +21,7 @@ type Service interface {
 	GetLatestByHost(ctx context.Context, hostId uint) (*entities.NetworkMetric, error)
 	GetHistorical(ctx context.Context, hours float64) ([]entities.NetworkMetric, error)
 	GetHistoricalByHost(ctx context.Context, hostId uint, hours float64) ([]entities.NetworkMetric, error)
+	GetInterfaceHistoryByHost(ctx context.Context, hostId uint, hours float64, iface string, primaryOnly bool) ([]entities.HistoricalNetworkInterfaceMetric, error)
 	CollectAndSave(ctx context.Context, hostId uint) error
 }
 
@@ -33,7 +35,9 @@ func (a *networkCollectorAdapter) Collect(ctx context.Context) (entities.Network
 
 type service struct {
 	metrics.Service[entities.NetworkMetric, entities.NetworkMetric]
+	repo            networkrepos.NetworkRepository
 	speedCalculator *value_objects.NetworkSpeedCalculator
+	rateCalculator  *value_objects.InterfaceRateCalculator
 }
 
 func NewService(
@@ -47,7 +51,9 @@ func NewService(
 			Collector: &networkCollectorAdapter{c: collectors.NewNetworkMetricsCollector(logger)},
 			Repo:      networkRepository,
 		},
+		repo:            networkRepository,
 		speedCalculator: value_objects.NewNetworkSpeedCalculator(),
+		rateCalculator:  value_objects.NewInterfaceRateCalculator(),
 	}
 }
 
@@ -72,9 +78,55 @@ func (s *service) Collect(ctx context.Context) (entities.NetworkMetric, error) {
 	}
 	s.speedCalculator.EndCalculationBatch()
 
+	s.applyInterfaceRates(metric.Interfaces, time.Now())
+
 	return metric, nil
 }
 
+// applyInterfaceRates fills per-interface rate fields, handling counter wrap and resets.
+func (s *service) applyInterfaceRates(interfaces []entities.NetworkInterface, at time.Time) {
+	present := make(map[string]struct{}, len(interfaces))
+	for i := range interfaces {
+		iface := &interfaces[i]
+		present[iface.Name] = struct{}{}
+		rate := s.rateCalculator.Calculate(iface.Name, at, value_objects.InterfaceCounters{
+			BytesRecv:   iface.BytesRecv,
+			BytesSent:   iface.BytesSent,
+			PacketsRecv: iface.PacketsRecv,
+			PacketsSent: iface.PacketsSent,
+			Errin:       iface.Errin,
+			Errout:      iface.Errout,
+			Dropin:      iface.Dropin,
+			Dropout:     iface.Dropout,
+			// A kernel without carrier_changes reads as zero, which never looks like a reset
+			CarrierChanges: derefUint64(iface.CarrierChanges),
+		})
+		iface.RxBytesPerSec = rate.RxBytesPerSec
+		iface.TxBytesPerSec = rate.TxBytesPerSec
+		iface.RxPacketsPerSec = rate.RxPacketsPerSec
+		iface.TxPacketsPerSec = rate.TxPacketsPerSec
+		iface.ErrinDelta = rate.RxErrors
+		iface.ErroutDelta = rate.TxErrors
+		iface.DropinDelta = rate.RxDrops
+		iface.DropoutDelta = rate.TxDrops
+		iface.RatesValid = rate.Valid
+		iface.CarrierChangesDelta = rate.CarrierChanges
+		if rate.Valid && iface.LinkSpeedMbps != nil {
+			rx := value_objects.LinkUtilPercent(rate.RxBytesPerSec, *iface.LinkSpeedMbps)
+			tx := value_objects.LinkUtilPercent(rate.TxBytesPerSec, *iface.LinkSpeedMbps)
+			iface.RxUtilPercent, iface.TxUtilPercent = &rx, &tx
+		}
+	}
+	s.rateCalculator.Forget(present)
+}
+
+func derefUint64(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
 func (s *service) GetLatest(ctx context.Context) (entities.NetworkMetric, error) {
 	metric, err := s.Service.GetLatest(ctx)
 	if err != nil {
@@ -134,6 +186,19 @@ func (s *service) GetHistoricalByHost(ctx context.Context, hostId uint, hours fl
 	return m, err
 }
 
+func (s *service) GetInterfaceHistoryByHost(ctx context.Context, hostId uint, hours float64, iface string, primaryOnly bool) ([]entities.HistoricalNetworkInterfaceMetric, error) {
+	rows, err := s.repo.GetInterfaceHistoryByHost(ctx, hostId, hours, iface, primaryOnly)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			s.Logger.Debug("Context canceled while getting network interface history")
+		} else {
+			s.Logger.Error("Failed to get network interface history", "error", err, "host_id", hostId, "interface", iface)
+		}
+		return nil, err
+	}
+	return rows, nil
+}
+
 // CollectAndSave overrides the embedded method to use the speed-enriched Collect.
 func (s *service) CollectAndSave(ctx context.Context, hostId uint) error {
 	metric, err := s.Collect(ctx)
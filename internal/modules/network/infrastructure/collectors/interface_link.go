@@ -0,0 +1,53 @@
+package collectors
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/network/infrastructure/entities"
+)
+
+// ReadInterfaceLink reads the link state of an interface from HOST_SYS/class/net/<name>. Files
+// the kernel refuses to read (speed, duplex and carrier return EINVAL while the interface is
+// down) or that do not exist leave their field empty.
+func ReadInterfaceLink(name string) entities.InterfaceLink {
+	var link entities.InterfaceLink
+	read := func(file string) (string, bool) {
+		raw, err := os.ReadFile(hostfs.Sys("class", "net", name, file))
+		if err != nil {
+			return "", false
+		}
+		return strings.TrimSpace(string(raw)), true
+	}
+
+	// Virtual interfaces report -1 (or an error) for an unknown speed
+	if v, ok := read("speed"); ok {
+		if speed, err := strconv.ParseInt(v, 10, 64); err == nil && speed > 0 {
+			mbps := uint64(speed)
+			link.LinkSpeedMbps = &mbps
+		}
+	}
+	if v, ok := read("duplex"); ok {
+		link.Duplex = v
+	}
+	if v, ok := read("mtu"); ok {
+		if mtu, err := strconv.ParseUint(v, 10, 32); err == nil {
+			link.MTU = uint32(mtu)
+		}
+	}
+	if v, ok := read("operstate"); ok {
+		link.OperState = v
+	}
+	if v, ok := read("carrier"); ok && (v == "0" || v == "1") {
+		carrier := v == "1"
+		link.Carrier = &carrier
+	}
+	if v, ok := read("carrier_changes"); ok {
+		if changes, err := strconv.ParseUint(v, 10, 64); err == nil {
+			link.CarrierChanges = &changes
+		}
+	}
+	return link
+}
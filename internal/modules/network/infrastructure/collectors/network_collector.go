@@ -88,18 +88,19 @@ func (c *NetworkMetricsCollector) CollectNetworkMetrics(ctx context.Context) (en
 		}
 
 		interfaces = append(interfaces, entities.NetworkInterface{
-			Name:        stat.Name,
-			IPs:         ips,
-			Mac:         mac,
-			BytesSent:   stat.BytesSent,
-			BytesRecv:   stat.BytesRecv,
-			PacketsSent: stat.PacketsSent,
-			PacketsRecv: stat.PacketsRecv,
-			Errin:       stat.Errin,
-			Errout:      stat.Errout,
-			Dropin:      stat.Dropin,
-			Dropout:     stat.Dropout,
-			IsPrimary:   isPrimary,
+			Name:          stat.Name,
+			IPs:           ips,
+			Mac:           mac,
+			BytesSent:     stat.BytesSent,
+			BytesRecv:     stat.BytesRecv,
+			PacketsSent:   stat.PacketsSent,
+			PacketsRecv:   stat.PacketsRecv,
+			Errin:         stat.Errin,
+			Errout:        stat.Errout,
+			Dropin:        stat.Dropin,
+			Dropout:       stat.Dropout,
+			IsPrimary:     isPrimary,
+			InterfaceLink: ReadInterfaceLink(stat.Name),
 		})
 	}
 
@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+)
+
+// HistoricalNetworkInterfaceMetric stores rates for a single interface at a point in time.
+// Unlike HistoricalNetworkMetric it is one row per interface, so one interface can be
+// charted over long windows without decoding every JSON blob.
+type HistoricalNetworkInterfaceMetric struct {
+	// HostID references the host that recorded this interface sample
+	HostID uint `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_net_iface_host_name_ts,priority:1"`
+
+	// Timestamp indicates when this sample was recorded
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_net_iface_host_name_ts,priority:3"`
+
+	// Interface is the interface name (e.g. "eth0")
+	Interface string `json:"interface" gorm:"primaryKey;size:128;index:idx_net_iface_host_name_ts,priority:2"`
+
+	// IsPrimary marks the system's primary outbound interface at collection time
+	IsPrimary bool `json:"is_primary" gorm:"index"`
+
+	// Byte and packet rates per second
+	RxBytesPerSec   float64 `json:"rx_bytes_per_sec" gorm:"column:rx_bytes_per_sec"`
+	TxBytesPerSec   float64 `json:"tx_bytes_per_sec" gorm:"column:tx_bytes_per_sec"`
+	RxPacketsPerSec float64 `json:"rx_packets_per_sec" gorm:"column:rx_packets_per_sec"`
+	TxPacketsPerSec float64 `json:"tx_packets_per_sec" gorm:"column:tx_packets_per_sec"`
+
+	// Errors and drops observed since the previous sample
+	RxErrors uint64 `json:"rx_errors" gorm:"column:rx_errors"`
+	TxErrors uint64 `json:"tx_errors" gorm:"column:tx_errors"`
+	RxDrops  uint64 `json:"rx_drops" gorm:"column:rx_drops"`
+	TxDrops  uint64 `json:"tx_drops" gorm:"column:tx_drops"`
+
+	// Link state at collection time; CarrierChanges counts link flaps since the previous sample
+	LinkSpeedMbps  *uint64 `json:"link_speed_mbps" gorm:"column:link_speed_mbps"`
+	OperState      string  `json:"oper_state" gorm:"column:oper_state;size:16"`
+	CarrierChanges uint64  `json:"carrier_changes" gorm:"column:carrier_changes"`
+
+	// Utilisation of the link speed; nil when the speed is unknown
+	RxUtilPercent *float64 `json:"rx_util_percent" gorm:"column:rx_util_percent"`
+	TxUtilPercent *float64 `json:"tx_util_percent" gorm:"column:tx_util_percent"`
+}
+
+// GetTimestamp returns the timestamp when this interface sample was recorded.
+func (h HistoricalNetworkInterfaceMetric) GetTimestamp() time.Time { return h.Timestamp }
+
+// GetMetricType returns the metric type identifier for per-interface network metrics.
+func (h HistoricalNetworkInterfaceMetric) GetMetricType() string { return "network_interface" }
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalNetworkInterfaceMetric) TableName() string { return "network_interface_metrics" }
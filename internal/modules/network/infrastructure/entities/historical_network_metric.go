@@ -7,11 +7,11 @@ import (
  // HistoricalNetworkMetric represents a historical network metric stored in the database.
  // This structure contains complete network interface statistics recorded at a specific time.
 type HistoricalNetworkMetric struct {
-	// HostID is the foreign key referencing the host that recorded this metric
-	HostID *uint `json:"host_id" gorm:"default:null;index;index:idx_net_host_ts"`
+	// HostID references the host that recorded this metric (primary key with Timestamp)
+	HostID uint `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_net_host_ts,priority:1"`
 
-	// Timestamp indicates when this network metric was recorded (primary key)
-	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_net_host_ts"`
+	// Timestamp indicates when this network metric was recorded (primary key with HostID)
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_net_host_ts,priority:2"`
 
 	// Interfaces contains metrics for each network interface at this timestamp
 	Interfaces []NetworkInterface `json:"interfaces" gorm:"serializer:json"`
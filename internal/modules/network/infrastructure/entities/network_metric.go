@@ -51,11 +51,55 @@ type NetworkInterface struct {
 	// IsPrimary indicates whether this interface is the system's primary outbound interface
 	IsPrimary bool `json:"is_primary"`
 
+	// InterfaceLink is the link state; its fields are empty where HOST_SYS is not readable
+	InterfaceLink
+
 	// Error/drop counters
 	Errin   uint64 `json:"errin"`
 	Errout  uint64 `json:"errout"`
 	Dropin  uint64 `json:"dropin"`
 	Dropout uint64 `json:"dropout"`
+
+	// Rates derived from counter deltas between two collections; RatesValid is false on the
+	// first sample or after a counter reset, in which case the rate fields are zero
+	RxBytesPerSec   float64 `json:"rx_bytes_per_sec"`
+	TxBytesPerSec   float64 `json:"tx_bytes_per_sec"`
+	RxPacketsPerSec float64 `json:"rx_packets_per_sec"`
+	TxPacketsPerSec float64 `json:"tx_packets_per_sec"`
+	ErrinDelta      uint64  `json:"errin_delta"`
+	ErroutDelta     uint64  `json:"errout_delta"`
+	DropinDelta     uint64  `json:"dropin_delta"`
+	DropoutDelta    uint64  `json:"dropout_delta"`
+	RatesValid      bool    `json:"rates_valid"`
+
+	// CarrierChangesDelta counts link up/down transitions (flaps) since the previous collection
+	CarrierChangesDelta uint64 `json:"carrier_changes_delta"`
+
+	// RxUtilPercent and TxUtilPercent are the byte rates as a share of the link speed; nil when
+	// the rates are not valid or the speed is unknown
+	RxUtilPercent *float64 `json:"rx_util_percent"`
+	TxUtilPercent *float64 `json:"tx_util_percent"`
+}
+
+// InterfaceLink is the link state of an interface as read from HOST_SYS/class/net/<name>.
+type InterfaceLink struct {
+	// LinkSpeedMbps is the negotiated speed; nil for virtual interfaces and links that are down
+	LinkSpeedMbps *uint64 `json:"link_speed_mbps"`
+
+	// Duplex is "full", "half" or "unknown"; empty when the link is down
+	Duplex string `json:"duplex,omitempty"`
+
+	// MTU is the maximum transmission unit in bytes
+	MTU uint32 `json:"mtu"`
+
+	// OperState is the RFC 2863 operational state ("up", "down", "dormant", "lowerlayerdown", "unknown")
+	OperState string `json:"oper_state,omitempty"`
+
+	// Carrier reports whether the physical link is detected; nil while the interface is administratively down
+	Carrier *bool `json:"carrier"`
+
+	// CarrierChanges counts link up/down transitions since boot; nil on kernels without carrier_changes
+	CarrierChanges *uint64 `json:"carrier_changes"`
 }
 
  // NetworkSpeed represents calculated network interface speeds.
@@ -17,6 +17,9 @@ type NetworkRepository interface {
 	GetLatestMetricByHost(ctx context.Context, hostId uint) (*localentities.NetworkMetric, error)
 	GetHistoricalMetrics(ctx context.Context, hours float64) ([]localentities.NetworkMetric, error)
 	GetHistoricalMetricsByHost(ctx context.Context, hostId uint, hours float64) ([]localentities.NetworkMetric, error)
+	// GetInterfaceHistoryByHost returns per-interface rate rows. An empty iface returns every
+	// interface; primaryOnly restricts rows to whichever interface was primary at the time.
+	GetInterfaceHistoryByHost(ctx context.Context, hostId uint, hours float64, iface string, primaryOnly bool) ([]localentities.HistoricalNetworkInterfaceMetric, error)
 }
 
 type networkRepository struct {
@@ -28,12 +31,51 @@ func NewNetworkRepository(db *gorm.DB) NetworkRepository {
 }
 
 func (r *networkRepository) SaveCurrentMetric(ctx context.Context, metric localentities.NetworkMetric, hostId uint) error {
+	timestamp := time.Now().UTC()
 	historicalMetric := localentities.HistoricalNetworkMetric{
-		HostID:     &hostId,
-		Timestamp:  time.Now().UTC(),
+		HostID:     hostId,
+		Timestamp:  timestamp,
 		Interfaces: metric.Interfaces,
 	}
-	return r.db.WithContext(ctx).Create(&historicalMetric).Error
+
+	rows := make([]localentities.HistoricalNetworkInterfaceMetric, 0, len(metric.Interfaces))
+	for _, iface := range metric.Interfaces {
+		// Samples without a usable previous counter snapshot would chart as a false zero
+		if !iface.RatesValid {
+			continue
+		}
+		rows = append(rows, localentities.HistoricalNetworkInterfaceMetric{
+			HostID:          hostId,
+			Timestamp:       timestamp,
+			Interface:       iface.Name,
+			IsPrimary:       iface.IsPrimary,
+			RxBytesPerSec:   iface.RxBytesPerSec,
+			TxBytesPerSec:   iface.TxBytesPerSec,
+			RxPacketsPerSec: iface.RxPacketsPerSec,
+			TxPacketsPerSec: iface.TxPacketsPerSec,
+			RxErrors:        iface.ErrinDelta,
+			TxErrors:        iface.ErroutDelta,
+			RxDrops:         iface.DropinDelta,
+			TxDrops:         iface.DropoutDelta,
+			LinkSpeedMbps:   iface.LinkSpeedMbps,
+			OperState:       iface.OperState,
+			CarrierChanges:  iface.CarrierChangesDelta,
+			RxUtilPercent:   iface.RxUtilPercent,
+			TxUtilPercent:   iface.TxUtilPercent,
+		})
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&historicalMetric).Error; err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *networkRepository) GetLatestMetric(ctx context.Context) (localentities.NetworkMetric, error) {
@@ -99,3 +141,16 @@ func (r *networkRepository) GetHistoricalMetricsByHost(ctx context.Context, host
 	}
 	return metrics, nil
 }
+
+func (r *networkRepository) GetInterfaceHistoryByHost(ctx context.Context, hostId uint, hours float64, iface string, primaryOnly bool) ([]localentities.HistoricalNetworkInterfaceMetric, error) {
+	var metrics []localentities.HistoricalNetworkInterfaceMetric
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if iface != "" {
+		q = q.Where("interface = ?", iface)
+	}
+	if primaryOnly {
+		q = q.Where("is_primary = ?", true)
+	}
+	err := q.Order("timestamp ASC").Order("interface ASC").Find(&metrics).Error
+	return metrics, err
+}
@@ -0,0 +1,162 @@
+package value_objects
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// InterfaceRateMaxGap is the longest interval between two samples that still yields a rate.
+const InterfaceRateMaxGap = 10 * time.Minute
+
+// InterfaceCounters is a snapshot of the cumulative counters the kernel exposes per interface.
+type InterfaceCounters struct {
+	BytesRecv   uint64
+	BytesSent   uint64
+	PacketsRecv uint64
+	PacketsSent uint64
+	Errin       uint64
+	Errout      uint64
+	Dropin      uint64
+	Dropout     uint64
+	// CarrierChanges counts link up/down transitions; zero where the kernel does not expose it
+	CarrierChanges uint64
+}
+
+// InterfaceRate holds rates derived from two consecutive counter snapshots of one interface.
+type InterfaceRate struct {
+	// Byte and packet rates per second
+	RxBytesPerSec   float64
+	TxBytesPerSec   float64
+	RxPacketsPerSec float64
+	TxPacketsPerSec float64
+
+	// Error and drop counts observed during the interval
+	RxErrors uint64
+	TxErrors uint64
+	RxDrops  uint64
+	TxDrops  uint64
+
+	// CarrierChanges counts link flaps during the interval
+	CarrierChanges uint64
+
+	// Valid is false on the first sample, after a long gap or after a counter reset
+	Valid bool
+}
+
+type interfaceSample struct {
+	at       time.Time
+	counters InterfaceCounters
+}
+
+// InterfaceRateCalculator derives per-interface rates from cumulative counters.
+// It re-baselines after a counter decrease (reboot, driver reload).
+type InterfaceRateCalculator struct {
+	mu      sync.Mutex
+	maxGap  time.Duration
+	samples map[string]interfaceSample
+}
+
+// NewInterfaceRateCalculator creates a calculator with an empty sample history.
+func NewInterfaceRateCalculator() *InterfaceRateCalculator {
+	return &InterfaceRateCalculator{
+		maxGap:  InterfaceRateMaxGap,
+		samples: make(map[string]interfaceSample),
+	}
+}
+
+// Calculate records counters for the interface at time at and returns the rates since the previous call.
+func (c *InterfaceRateCalculator) Calculate(name string, at time.Time, cur InterfaceCounters) InterfaceRate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, exists := c.samples[name]
+	c.samples[name] = interfaceSample{at: at, counters: cur}
+	if !exists {
+		return InterfaceRate{}
+	}
+
+	elapsed := at.Sub(prev.at)
+	if elapsed <= 0 || elapsed > c.maxGap {
+		return InterfaceRate{}
+	}
+
+	p := prev.counters
+	deltas := [9][2]uint64{
+		{p.BytesRecv, cur.BytesRecv},
+		{p.BytesSent, cur.BytesSent},
+		{p.PacketsRecv, cur.PacketsRecv},
+		{p.PacketsSent, cur.PacketsSent},
+		{p.Errin, cur.Errin},
+		{p.Errout, cur.Errout},
+		{p.Dropin, cur.Dropin},
+		{p.Dropout, cur.Dropout},
+		{p.CarrierChanges, cur.CarrierChanges},
+	}
+	var d [9]uint64
+	for i, pair := range deltas {
+		delta, ok := CounterDelta(pair[0], pair[1])
+		if !ok {
+			// One counter restarted; the whole interface was most likely reset
+			return InterfaceRate{}
+		}
+		d[i] = delta
+	}
+
+	seconds := elapsed.Seconds()
+	return InterfaceRate{
+		RxBytesPerSec:   float64(d[0]) / seconds,
+		TxBytesPerSec:   float64(d[1]) / seconds,
+		RxPacketsPerSec: float64(d[2]) / seconds,
+		TxPacketsPerSec: float64(d[3]) / seconds,
+		RxErrors:        d[4],
+		TxErrors:        d[5],
+		RxDrops:         d[6],
+		TxDrops:         d[7],
+		CarrierChanges:  d[8],
+		Valid:           true,
+	}
+}
+
+// LinkUtilPercent returns a byte rate as a percentage of a link speed in Mbit/s.
+func LinkUtilPercent(bytesPerSec float64, speedMbps uint64) float64 {
+	return bytesPerSec * 8 / (float64(speedMbps) * 1e6) * 100
+}
+
+// Forget drops interfaces not present in keep so removed veth/tun devices do not accumulate state.
+func (c *InterfaceRateCalculator) Forget(keep map[string]struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name := range c.samples {
+		if _, ok := keep[name]; !ok {
+			delete(c.samples, name)
+		}
+	}
+}
+
+// CounterDelta returns cur-prev for a monotonically increasing counter. Any decrease is a reset
+// (reboot, driver reload) and reports ok=false: the kernel keeps interface and block device
+// counts in 64 bits, where a wrap cannot happen and must not be mistaken for a reset.
+func CounterDelta(prev, cur uint64) (delta uint64, ok bool) {
+	if cur >= prev {
+		return cur - prev, true
+	}
+	return 0, false
+}
+
+// Counter32Delta is CounterDelta for a counter the kernel keeps in 32 bits. A decrease is taken
+// as a wrap only when the wrapped delta is at most maxDelta, the most the counter can advance in
+// the elapsed interval; a larger one is a reset.
+func Counter32Delta(prev, cur, maxDelta uint64) (delta uint64, ok bool) {
+	if cur >= prev {
+		return cur - prev, true
+	}
+	if prev > math.MaxUint32 {
+		return 0, false
+	}
+	delta = (math.MaxUint32 - prev) + cur + 1
+	if delta > maxDelta {
+		return 0, false
+	}
+	return delta, true
+}
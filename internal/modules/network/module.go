@@ -0,0 +1,73 @@
+// Package network registers the network metrics module.
+package network
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/app/snapshot"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	networkservice "system-stats/internal/modules/network/application"
+	"system-stats/internal/modules/network/infrastructure/entities"
+	"system-stats/internal/modules/network/infrastructure/repositories"
+	handlers "system-stats/internal/modules/network/presentation"
+)
+
+// Module collects network throughput, per-interface rates and link state and serves /network.
+type Module struct{}
+
+func (Module) Name() string { return "network" }
+
+// Migrations adds the link columns to network_interface_metrics. The baseline schema creates
+// the table from the current entity, so new databases already have them.
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 13,
+		Name:    "network_interface_link_columns",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			for _, field := range linkColumns {
+				if tx.Migrator().HasColumn(&entities.HistoricalNetworkInterfaceMetric{}, field) {
+					continue
+				}
+				if err := tx.Migrator().AddColumn(&entities.HistoricalNetworkInterfaceMetric{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			for _, field := range linkColumns {
+				if err := tx.Migrator().DropColumn(&entities.HistoricalNetworkInterfaceMetric{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+	}}
+}
+
+var linkColumns = []string{"LinkSpeedMbps", "OperState", "CarrierChanges", "RxUtilPercent", "TxUtilPercent"}
+
+func (Module) RetentionTables() []string {
+	return []string{"network_metrics", "network_interface_metrics"}
+}
+
+func (Module) HostTables() []string {
+	return []string{"network_metrics", "network_interface_metrics"}
+}
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := networkservice.NewService(deps.Logger, repositories.NewNetworkRepository(deps.DB))
+	handler := handlers.NewNetworkHandler(deps.Logger, service, deps.Hosts)
+	collector := historycore.NewModuleCollector("network", service.Collect, service.Save,
+		func(snap *snapshot.Snapshot, m *entities.NetworkMetric) { snap.Network = m })
+	return registry.Instance{
+		Collector: &collector,
+		Routes: func(r gin.IRoutes) {
+			r.GET("/network", handler.HandleNetworkStats)
+			r.GET("/network/interfaces", handler.HandleInterfaceHistory)
+		},
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/charmbracelet/log"
 	"github.com/gin-gonic/gin"
@@ -33,7 +34,7 @@ func NewNetworkHandler(logger *log.Logger, service networkservice.Service, hosts
 // HandleNetworkStats returns current network metrics with latest and historical data.
 //
 // @Summary     Network metrics
-// @Description Returns latest network interface stats and historical traffic data.
+// @Description Returns latest network interface stats and historical traffic data, with each interface's link speed, duplex, MTU, operational state, carrier and link flap counts and rx/tx utilisation of the link speed.
 // @Tags        metrics
 // @Produce     json
 // @Param       hours    query    number   false  "History window in hours"  default(0.0833)
@@ -86,3 +87,52 @@ func (h *NetworkHandler) HandleNetworkStats(c *gin.Context) {
 		"history": historyMetrics,
 	})
 }
+
+// HandleInterfaceHistory returns per-interface rate history from the normalized table.
+//
+// @Summary     Network interface history
+// @Description Returns rx/tx bytes and packets per second plus error and drop counts per interface sample, with link speed, operational state, link flaps since the previous sample and rx/tx utilisation (null when the speed is unknown).
+// @Tags        metrics
+// @Produce     json
+// @Param       hours      query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id    query    integer  false  "Host ID (0 = this server instance)"
+// @Param       interface  query    string   false  "Limit history to one interface name"
+// @Param       primary    query    boolean  false  "Only rows for the primary interface"
+// @Success     200        {object} map[string]interface{}
+// @Failure     401        {object} map[string]string
+// @Failure     500        {object} map[string]string
+// @Security    BearerAuth
+// @Router      /network/interfaces [get]
+func (h *NetworkHandler) HandleInterfaceHistory(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	iface := c.Query("interface")
+	primaryOnly, _ := strconv.ParseBool(c.DefaultQuery("primary", "false"))
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyNetworkInterfacesPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for network interface history", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetInterfaceHistoryByHost(ctx, effective, hours, iface, primaryOnly)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching network interface history")
+			return
+		}
+		h.logger.Error("Failed to fetch network interface history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": history,
+	})
+}
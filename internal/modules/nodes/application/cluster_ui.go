@@ -62,5 +62,9 @@ func (s *service) DeleteRemoteHost(ctx context.Context, hostID, currentHostID ui
 	if _, err := s.hostRepo.GetHostByID(ctx, hostID); err != nil {
 		return err
 	}
-	return s.hostRepo.DeleteHostCascade(ctx, hostID)
+	if err := s.hostRepo.DeleteHostCascade(ctx, hostID); err != nil {
+		return err
+	}
+	s.remoteHealth.Delete(hostID)
+	return nil
 }
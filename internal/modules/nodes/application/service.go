@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/charmbracelet/log"
 
+	"system-stats/internal/app/collectorhealth"
 	healthapp "system-stats/internal/modules/health/application"
 	hostentities "system-stats/internal/modules/hosts/infrastructure/entities"
 	hostrepos "system-stats/internal/modules/hosts/infrastructure/repositories"
@@ -25,7 +27,7 @@ type Service interface {
 	CreateNodeInvite(ctx context.Context, adminUserID uint, baseURL string) (link string, err error)
 	Join(ctx context.Context, token string, hostInfo hostentities.HostInfo) (hostID uint, nodeAccessToken string, err error)
 	ValidateNodeToken(ctx context.Context, token string) (hostID uint, err error)
-	HandlePush(ctx context.Context, hostID uint, hostName, hostIPv4 string) error
+	HandlePush(ctx context.Context, hostID uint, hostName, hostIPv4 string, sentAt *time.Time, collectors []collectorhealth.Status, modules map[string]json.RawMessage) error
 	// RegenerateNodeAccessToken replaces the push token; returns plaintext once (old token stops working immediately).
 	RegenerateNodeAccessToken(ctx context.Context, hostID uint) (nodeAccessToken string, err error)
 	GetClusterUIStatus(ctx context.Context, currentHostID uint, publicBaseURL string) (ClusterUIStatus, error)
@@ -34,11 +36,20 @@ type Service interface {
 	ClearAgentClusterConfig() error
 }
 
+// PushReceiver stores the data an agent pushed for one module (registry.Instance.ReceivePush).
+type PushReceiver func(ctx context.Context, hostID uint, data json.RawMessage) error
+
+// PushClockReceiver is given an agent's send time and this node's receive time of a push (registry.Instance.ReceivePushClock).
+type PushClockReceiver func(ctx context.Context, hostID uint, sentAt, receivedAt time.Time) error
+
 type service struct {
 	logger        *log.Logger
 	joinTokenRepo noderepos.NodeJoinTokenRepository
 	credRepo      noderepos.NodeCredentialRepository
 	hostRepo      hostrepos.HostRepository
+	remoteHealth  *collectorhealth.RemoteStore
+	receivers     map[string]PushReceiver
+	clocks        []PushClockReceiver
 }
 
 // NewService creates a new nodes service.
@@ -47,12 +58,18 @@ func NewService(
 	joinTokenRepo noderepos.NodeJoinTokenRepository,
 	credRepo noderepos.NodeCredentialRepository,
 	hostRepo hostrepos.HostRepository,
+	remoteHealth *collectorhealth.RemoteStore,
+	receivers map[string]PushReceiver,
+	clocks []PushClockReceiver,
 ) Service {
 	return &service{
 		logger:        logger,
 		joinTokenRepo: joinTokenRepo,
 		credRepo:      credRepo,
 		hostRepo:      hostRepo,
+		remoteHealth:  remoteHealth,
+		receivers:     receivers,
+		clocks:        clocks,
 	}
 }
 
@@ -121,7 +138,31 @@ func (s *service) Join(ctx context.Context, token string, hostInfo hostentities.
 
 // HandlePush updates last_seen and agent_session_started_at (new session if gap > health.AgentPushGapSessionReset).
 // hostName/hostIPv4 come from the agent's current CollectHostInfo (includes NODE_STATS_*); main stores them on the host row.
-func (s *service) HandlePush(ctx context.Context, hostID uint, hostName, hostIPv4 string) error {
+// collectors is the agent's collector health, kept in memory for /collectors/status and /health; older agents send none.
+// sentAt is the agent's clock at sending, handed with the receive time to the clock receivers; older agents send none.
+// modules is handed to the receiver of each module enabled here; a failing receiver does not fail the heartbeat.
+func (s *service) HandlePush(ctx context.Context, hostID uint, hostName, hostIPv4 string, sentAt *time.Time, collectors []collectorhealth.Status, modules map[string]json.RawMessage) error {
+	receivedAt := time.Now().UTC()
+	if sentAt != nil && !sentAt.IsZero() {
+		for _, receive := range s.clocks {
+			if err := receive(ctx, hostID, *sentAt, receivedAt); err != nil {
+				s.logger.Warn("Failed to store agent clock skew", "host_id", hostID, "error", err)
+			}
+		}
+	}
+	if collectors != nil {
+		s.remoteHealth.Set(hostID, collectors)
+	}
+	for name, data := range modules {
+		receive, ok := s.receivers[name]
+		if !ok {
+			s.logger.Debug("Ignoring pushed data of a module not enabled here", "host_id", hostID, "module", name)
+			continue
+		}
+		if err := receive(ctx, hostID, data); err != nil {
+			s.logger.Warn("Failed to store pushed module data", "host_id", hostID, "module", name, "error", err)
+		}
+	}
 	if hostName != "" || hostIPv4 != "" {
 		if err := s.hostRepo.UpdateHostLabelsFromAgentPush(ctx, hostID, hostName, hostIPv4); err != nil {
 			s.logger.Warn("Failed to sync host name/IPv4 from agent push", "host_id", hostID, "error", err)
@@ -10,11 +10,13 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"system-stats/internal/app/apperror"
+	"system-stats/internal/app/collectorhealth"
 	hostservice "system-stats/internal/modules/hosts/application"
 	hostentities "system-stats/internal/modules/hosts/infrastructure/entities"
 	nodeservice "system-stats/internal/modules/nodes/application"
@@ -178,6 +180,12 @@ type PushRequest struct {
 	// HostName / HostIPv4: effective labels from the agent (CollectHostInfo, includes NODE_STATS_*), kept in sync on main.
 	HostName string `json:"host_name,omitempty"`
 	HostIPv4 string `json:"host_ipv4,omitempty"`
+	// Collectors is the agent's collector health (omitted by agents that predate it).
+	Collectors []collectorhealth.Status `json:"collectors,omitempty"`
+	// SentAt is the agent's clock when it sent the push (omitted by agents that predate it).
+	SentAt *time.Time `json:"sent_at,omitempty"`
+	// Modules is data of agent modules that push (e.g. processes), keyed by module name.
+	Modules map[string]json.RawMessage `json:"modules,omitempty"`
 }
 
 // Push handles metrics push from agent nodes.
@@ -206,7 +214,7 @@ func (h *NodesHandler) Push(c *gin.Context) {
 		return
 	}
 
-	if err := h.nodeService.HandlePush(c.Request.Context(), hostID.(uint), req.HostName, req.HostIPv4); err != nil {
+	if err := h.nodeService.HandlePush(c.Request.Context(), hostID.(uint), req.HostName, req.HostIPv4, req.SentAt, req.Collectors, req.Modules); err != nil {
 		_ = c.Error(apperror.Internal("internal_error", err.Error()))
 		return
 	}
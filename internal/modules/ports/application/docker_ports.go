@@ -0,0 +1,76 @@
+package portmetrics
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+
+	"system-stats/internal/modules/ports/infrastructure/entities"
+)
+
+// ContainerPort is a host port published by a container.
+type ContainerPort struct {
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+	Protocol      string `json:"protocol"`
+	// IP is the host address the port is published on; empty or unspecified for all addresses
+	IP          string `json:"ip,omitempty"`
+	PublicPort  uint16 `json:"public_port"`
+	PrivatePort uint16 `json:"private_port"`
+}
+
+// ContainerPortSource returns the ports published by a host's running containers, as of the
+// latest Docker collection.
+type ContainerPortSource func(ctx context.Context, hostId uint) ([]ContainerPort, error)
+
+// InventoryPort is an inventory row with the containers publishing it.
+type InventoryPort struct {
+	entities.ListeningPortEntity
+	Containers []ContainerPort `json:"containers,omitempty"`
+}
+
+// Inventory is a host's listening ports with the container ports that have no listening socket
+// (published through NAT rules only, e.g. with Docker's userland proxy disabled).
+type Inventory struct {
+	Ports                   []InventoryPort `json:"ports"`
+	UnmatchedContainerPorts []ContainerPort `json:"unmatched_container_ports"`
+}
+
+// crossReference attaches each container port to the listening ports it can be reached on.
+func crossReference(rows []entities.ListeningPortEntity, containerPorts []ContainerPort) Inventory {
+	inv := Inventory{Ports: make([]InventoryPort, 0, len(rows)), UnmatchedContainerPorts: []ContainerPort{}}
+	for _, row := range rows {
+		inv.Ports = append(inv.Ports, InventoryPort{ListeningPortEntity: row})
+	}
+	for _, cp := range containerPorts {
+		matched := false
+		for i := range inv.Ports {
+			p := &inv.Ports[i]
+			if p.Protocol != cp.Protocol || p.Port != cp.PublicPort || !addressesOverlap(p.Address, cp.IP) {
+				continue
+			}
+			matched = true
+			// Docker lists a port published on all addresses once per family
+			if !slices.ContainsFunc(p.Containers, func(c ContainerPort) bool {
+				return c.ContainerID == cp.ContainerID && c.PrivatePort == cp.PrivatePort
+			}) {
+				p.Containers = append(p.Containers, cp)
+			}
+		}
+		if !matched {
+			inv.UnmatchedContainerPorts = append(inv.UnmatchedContainerPorts, cp)
+		}
+	}
+	return inv
+}
+
+// addressesOverlap reports whether a socket bound to listen and a port published on published
+// can serve the same address. Docker lists a port published on all addresses as 0.0.0.0 and ::.
+func addressesOverlap(listen, published string) bool {
+	l, errL := netip.ParseAddr(listen)
+	p, errP := netip.ParseAddr(published)
+	if errL != nil || errP != nil || l.IsUnspecified() || p.IsUnspecified() {
+		return true
+	}
+	return l == p
+}
@@ -0,0 +1,38 @@
+package portmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/modules/ports/infrastructure/entities"
+)
+
+var descListeningPorts = prometheus.NewDesc("system_listening_ports", "Listening TCP sockets and bound UDP sockets by protocol.", []string{"protocol"}, nil)
+
+// PrometheusCollector exports the latest in-memory collection; scrapes never read /proc.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descListeningPorts
+}
+
+// Collect sends the port counts of the last collection.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	latest := c.service.Latest()
+	if latest == nil {
+		return
+	}
+	counts := map[string]int{entities.ProtocolTCP: 0, entities.ProtocolUDP: 0}
+	for _, p := range latest.Ports {
+		counts[p.Protocol]++
+	}
+	for protocol, n := range counts {
+		ch <- prometheus.MustNewConstMetric(descListeningPorts, prometheus.GaugeValue, float64(n), protocol)
+	}
+}
@@ -0,0 +1,141 @@
+package portmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/ports/infrastructure/collectors"
+	"system-stats/internal/modules/ports/infrastructure/entities"
+	"system-stats/internal/modules/ports/infrastructure/repositories"
+)
+
+// Host event source and kinds of inventory changes.
+const (
+	EventSource  = "ports"
+	EventAdded   = "added"
+	EventRemoved = "removed"
+)
+
+type Service interface {
+	// Collect lists the listening ports and keeps the result as the latest in-memory collection.
+	Collect(ctx context.Context) (entities.PortMetric, error)
+	// Save replaces the host's inventory and records added and removed ports as host events.
+	// The first inventory of a host is recorded without events.
+	Save(ctx context.Context, metric entities.PortMetric, hostId uint) error
+	// Latest returns this instance's last collection (no database access); nil before the first one.
+	Latest() *entities.PortMetric
+	// ReceivePush stores the inventory pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	// GetInventory returns a host's inventory cross-referenced with its published container ports.
+	GetInventory(ctx context.Context, hostId uint) (Inventory, error)
+}
+
+type service struct {
+	logger     *log.Logger
+	collector  *collectors.PortCollector
+	repo       repositories.PortRepository
+	events     eventsservice.Service
+	containers ContainerPortSource
+
+	mu     sync.RWMutex
+	latest *entities.PortMetric
+}
+
+func NewService(logger *log.Logger, repo repositories.PortRepository, events eventsservice.Service, containers ContainerPortSource) Service {
+	return &service{
+		logger:     logger,
+		collector:  collectors.NewPortCollector(logger),
+		repo:       repo,
+		events:     events,
+		containers: containers,
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.PortMetric, error) {
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	s.latest = &metric
+	s.mu.Unlock()
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.PortMetric, hostId uint) error {
+	// Postgres keeps microseconds; truncating makes a repeated push compare equal to the stored one
+	at := metric.Timestamp.UTC().Truncate(time.Microsecond)
+	change, err := s.repo.ReplaceInventory(ctx, hostId, metric.Ports, at)
+	if err != nil {
+		s.logger.Error("Failed to save metrics", "module", "ports", "error", err, "host_id", hostId)
+		return err
+	}
+	if change.Initial {
+		s.logger.Debug("Recorded initial listening ports inventory", "host_id", hostId, "ports", len(change.Added))
+		return nil
+	}
+
+	events := make([]evententities.HostEvent, 0, len(change.Added)+len(change.Removed))
+	for _, row := range change.Added {
+		p := row.ListeningPort()
+		s.logger.Info("New listening port", "host_id", hostId, "port", p.Key(), "process", p.Process)
+		events = append(events, portEvent(at, p, EventAdded, "started listening"))
+	}
+	for _, row := range change.Removed {
+		events = append(events, portEvent(at, row.ListeningPort(), EventRemoved, "stopped listening"))
+	}
+	return s.events.Record(ctx, hostId, events)
+}
+
+func portEvent(at time.Time, p entities.ListeningPort, kind, what string) evententities.HostEvent {
+	message := fmt.Sprintf("%s %s", p.Key(), what)
+	if p.Process != "" && p.PID != nil {
+		message = fmt.Sprintf("%s (%s, pid %d)", message, p.Process, *p.PID)
+	}
+	return evententities.HostEvent{
+		Timestamp: at,
+		Source:    EventSource,
+		Subject:   p.Key(),
+		Kind:      kind,
+		Severity:  evententities.SeverityInfo,
+		Message:   message,
+	}
+}
+
+func (s *service) Latest() *entities.PortMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+// ReceivePush caps the pushed ports at MaxPorts.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.PortMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	metric.Ports = metric.Ports[:min(len(metric.Ports), collectors.MaxPorts)]
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) GetInventory(ctx context.Context, hostId uint) (Inventory, error) {
+	rows, err := s.repo.ListByHost(ctx, hostId)
+	if err != nil {
+		return Inventory{}, err
+	}
+	containerPorts, err := s.containers(ctx, hostId)
+	if err != nil {
+		return Inventory{}, err
+	}
+	return crossReference(rows, containerPorts), nil
+}
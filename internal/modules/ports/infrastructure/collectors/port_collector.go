@@ -0,0 +1,175 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/app/procnet"
+	"system-stats/internal/modules/ports/infrastructure/entities"
+)
+
+// MaxPorts bounds the ports of one collection (and of an agent push).
+const MaxPorts = 1024
+
+// PortCollector lists the listening sockets of HOST_PROC/net/{tcp,tcp6,udp,udp6} and finds their
+// owning processes through HOST_PROC/<pid>/fd.
+type PortCollector struct {
+	logger *log.Logger
+}
+
+// NewPortCollector creates a listening ports collector.
+func NewPortCollector(logger *log.Logger) *PortCollector {
+	return &PortCollector{logger: logger}
+}
+
+// Collect returns the listening ports sorted by protocol, port and address. Bound UDP sockets in
+// the ephemeral port range are left out: they are mostly client sockets (DNS lookups, NTP) whose
+// ports change all the time.
+func (c *PortCollector) Collect(ctx context.Context) (entities.PortMetric, error) {
+	c.logger.Debug("Collecting listening ports")
+	metric := entities.PortMetric{Timestamp: time.Now().UTC(), Ports: []entities.ListeningPort{}}
+	ephemeralLow, ephemeralHigh := ephemeralPortRange()
+
+	seen := make(map[string]int)
+	inodes := make(map[uint64][]int)
+	for _, table := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		sockets, err := procnet.ReadSockets(table)
+		if err != nil {
+			return metric, err
+		}
+		protocol := entities.ProtocolTCP
+		if strings.HasPrefix(table, "udp") {
+			protocol = entities.ProtocolUDP
+		}
+		for _, s := range sockets {
+			if !listening(protocol, s) {
+				continue
+			}
+			port := s.Local.Port()
+			if protocol == entities.ProtocolUDP && port >= ephemeralLow && port <= ephemeralHigh {
+				continue
+			}
+			p := entities.ListeningPort{Protocol: protocol, Address: s.Local.Addr().String(), Port: port}
+			// SO_REUSEPORT workers share a port; it is listed once
+			idx, dup := seen[p.Key()]
+			if !dup {
+				if len(metric.Ports) >= MaxPorts {
+					continue
+				}
+				idx = len(metric.Ports)
+				seen[p.Key()] = idx
+				metric.Ports = append(metric.Ports, p)
+			}
+			if s.Inode != 0 {
+				inodes[s.Inode] = append(inodes[s.Inode], idx)
+			}
+		}
+	}
+
+	if err := c.resolveOwners(ctx, metric.Ports, inodes); err != nil {
+		return metric, err
+	}
+	sort.Slice(metric.Ports, func(i, j int) bool {
+		a, b := metric.Ports[i], metric.Ports[j]
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.Address < b.Address
+	})
+	return metric, nil
+}
+
+// listening reports whether a socket accepts connections or datagrams from anyone: a TCP socket
+// in LISTEN state or a UDP socket that is not connected to a peer.
+func listening(protocol string, s procnet.Socket) bool {
+	if s.Local.Port() == 0 {
+		return false
+	}
+	if protocol == entities.ProtocolTCP {
+		return s.State == procnet.StateListen
+	}
+	return s.State == procnet.StateClose && s.Remote.Port() == 0
+}
+
+// resolveOwners maps socket inodes to processes by reading the fd links of every process. Sockets
+// of processes that cannot be read (other users without root) keep a nil PID.
+func (c *PortCollector) resolveOwners(ctx context.Context, ports []entities.ListeningPort, inodes map[uint64][]int) error {
+	if len(inodes) == 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(hostfs.Proc())
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		pid, err := strconv.ParseInt(e.Name(), 10, 32)
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fdDir := hostfs.Proc(e.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		var name string
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if err != nil {
+				continue
+			}
+			idxs, ok := inodes[inode]
+			if !ok {
+				continue
+			}
+			if name == "" {
+				comm, _ := os.ReadFile(hostfs.Proc(e.Name(), "comm"))
+				name = strings.TrimSpace(string(comm))
+			}
+			owner := int32(pid)
+			for _, idx := range idxs {
+				if ports[idx].PID == nil {
+					ports[idx].PID = &owner
+					ports[idx].Process = name
+				}
+			}
+			delete(inodes, inode)
+			if len(inodes) == 0 {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// ephemeralPortRange reads ip_local_port_range, falling back to the kernel default.
+func ephemeralPortRange() (uint16, uint16) {
+	raw, err := os.ReadFile(hostfs.Proc("sys", "net", "ipv4", "ip_local_port_range"))
+	if err == nil {
+		if fields := strings.Fields(string(raw)); len(fields) == 2 {
+			low, errLow := strconv.ParseUint(fields[0], 10, 16)
+			high, errHigh := strconv.ParseUint(fields[1], 10, 16)
+			if errLow == nil && errHigh == nil && low <= high {
+				return uint16(low), uint16(high)
+			}
+		}
+	}
+	return 32768, 60999
+}
@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
+// Protocols of listening ports.
+const (
+	ProtocolTCP = "tcp"
+	ProtocolUDP = "udp"
+)
+
+// ListeningPort is a TCP socket in LISTEN state or a bound, unconnected UDP socket.
+type ListeningPort struct {
+	Protocol string `json:"protocol"`
+	// Address is the bound address, "0.0.0.0" or "::" for all addresses
+	Address string `json:"address"`
+	Port    uint16 `json:"port"`
+	// PID and Process own the socket; nil / empty when /proc/<pid>/fd is not readable (not root)
+	PID     *int32 `json:"pid"`
+	Process string `json:"process,omitempty"`
+}
+
+// Key identifies a port within a host's inventory, e.g. "tcp/0.0.0.0:22" or "udp/[::]:53".
+func (p ListeningPort) Key() string {
+	return p.Protocol + "/" + net.JoinHostPort(p.Address, strconv.Itoa(int(p.Port)))
+}
+
+// PortMetric is one collection of a host's listening ports.
+type PortMetric struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Ports     []ListeningPort `json:"ports"`
+}
+
+// ListeningPortEntity is a port in a host's current inventory. Rows are replaced on every
+// collection: ports no longer listening are deleted (and reported as removed events).
+type ListeningPortEntity struct {
+	HostID   uint   `json:"host_id" gorm:"primaryKey;autoIncrement:false"`
+	Protocol string `json:"protocol" gorm:"primaryKey;size:8"`
+	Address  string `json:"address" gorm:"primaryKey;size:64"`
+	Port     uint16 `json:"port" gorm:"primaryKey;autoIncrement:false"`
+	PID      *int32 `json:"pid"`
+	Process  string `json:"process,omitempty" gorm:"size:255"`
+	// FirstSeen is when the port started listening as far as known; LastSeen the latest collection
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (ListeningPortEntity) TableName() string { return "listening_ports" }
+
+// ListeningPort returns the inventory row as a ListeningPort.
+func (e ListeningPortEntity) ListeningPort() ListeningPort {
+	return ListeningPort{Protocol: e.Protocol, Address: e.Address, Port: e.Port, PID: e.PID, Process: e.Process}
+}
+
+// PortInventoryEntity records when a host's inventory was last replaced. It tells a host whose
+// inventory is empty apart from one that was never collected.
+type PortInventoryEntity struct {
+	HostID      uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false"`
+	CollectedAt time.Time `json:"collected_at" gorm:"not null"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (PortInventoryEntity) TableName() string { return "listening_port_inventories" }
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/modules/ports/infrastructure/entities"
+)
+
+// InventoryChange is the difference between a host's stored inventory and a new collection.
+type InventoryChange struct {
+	Added   []entities.ListeningPortEntity
+	Removed []entities.ListeningPortEntity
+	// Initial is true when the host was never collected before (an emptied inventory is not)
+	Initial bool
+}
+
+type PortRepository interface {
+	// ReplaceInventory makes ports the host's inventory as of at and returns what changed. Kept
+	// ports keep their first_seen; a collection older than the stored one changes nothing.
+	// Repeated ports (e.g. in an agent push) are stored once.
+	ReplaceInventory(ctx context.Context, hostId uint, ports []entities.ListeningPort, at time.Time) (InventoryChange, error)
+	// ListByHost returns a host's inventory ordered by protocol, port and address.
+	ListByHost(ctx context.Context, hostId uint) ([]entities.ListeningPortEntity, error)
+}
+
+type portRepository struct {
+	db *gorm.DB
+}
+
+func NewPortRepository(db *gorm.DB) PortRepository {
+	return &portRepository{db: db}
+}
+
+func (r *portRepository) ReplaceInventory(ctx context.Context, hostId uint, ports []entities.ListeningPort, at time.Time) (InventoryChange, error) {
+	var change InventoryChange
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var inventory entities.PortInventoryEntity
+		err := tx.Where("host_id = ?", hostId).First(&inventory).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			change.Initial = true
+		} else if err != nil {
+			return err
+		} else if !inventory.CollectedAt.Before(at) {
+			// An agent repeating an older push must not undo a newer collection
+			return nil
+		}
+
+		var stored []entities.ListeningPortEntity
+		if err := tx.Where("host_id = ?", hostId).Find(&stored).Error; err != nil {
+			return err
+		}
+		current := make(map[string]entities.ListeningPortEntity, len(stored))
+		for _, row := range stored {
+			current[row.ListeningPort().Key()] = row
+		}
+
+		seen := make(map[string]struct{}, len(ports))
+		for _, p := range ports {
+			if _, dup := seen[p.Key()]; dup {
+				continue
+			}
+			seen[p.Key()] = struct{}{}
+			row, kept := current[p.Key()]
+			delete(current, p.Key())
+			if !kept {
+				row = entities.ListeningPortEntity{HostID: hostId, Protocol: p.Protocol, Address: p.Address, Port: p.Port, FirstSeen: at}
+			}
+			row.PID, row.Process, row.LastSeen = p.PID, p.Process, at
+			if kept {
+				if err := tx.Model(&row).Select("pid", "process", "last_seen").Updates(&row).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Create(&row).Error; err != nil {
+				return err
+			}
+			change.Added = append(change.Added, row)
+		}
+		for _, row := range current {
+			if err := tx.Delete(&row).Error; err != nil {
+				return err
+			}
+			change.Removed = append(change.Removed, row)
+		}
+		inventory = entities.PortInventoryEntity{HostID: hostId, CollectedAt: at}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&inventory).Error
+	})
+	return change, err
+}
+
+func (r *portRepository) ListByHost(ctx context.Context, hostId uint) ([]entities.ListeningPortEntity, error) {
+	rows := []entities.ListeningPortEntity{}
+	err := r.db.WithContext(ctx).
+		Where("host_id = ?", hostId).
+		Order("protocol ASC, port ASC, address ASC").
+		Find(&rows).Error
+	return rows, err
+}
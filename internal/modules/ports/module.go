@@ -0,0 +1,123 @@
+// Package ports registers the listening ports inventory module.
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/app/registry"
+	dockerrepos "system-stats/internal/modules/docker/domain/repositories"
+	dockerinfra "system-stats/internal/modules/docker/infrastructure/repositories"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	portservice "system-stats/internal/modules/ports/application"
+	"system-stats/internal/modules/ports/infrastructure/entities"
+	"system-stats/internal/modules/ports/infrastructure/repositories"
+	handlers "system-stats/internal/modules/ports/presentation"
+)
+
+// Module keeps an inventory of listening TCP / UDP ports per host from HOST_PROC/net, records
+// ports appearing and disappearing as host events and serves /ports.
+type Module struct{}
+
+func (Module) Name() string { return "ports" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 12,
+		Name:    "listening_ports_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.ListeningPortEntity{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.ListeningPortEntity{})
+		}},
+	}, {
+		// Hosts with stored ports have been collected; their newest last_seen is the collection time
+		Version: 21,
+		Name:    "listening_port_inventories_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&entities.PortInventoryEntity{}); err != nil {
+				return err
+			}
+			return tx.Exec(`INSERT INTO listening_port_inventories (host_id, collected_at)
+				SELECT host_id, MAX(last_seen) FROM listening_ports GROUP BY host_id`).Error
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.PortInventoryEntity{})
+		}},
+	}}
+}
+
+// RetentionTables is empty: the inventory holds only current ports and the change history
+// lives in host_events.
+func (Module) RetentionTables() []string { return nil }
+
+func (Module) HostTables() []string {
+	return []string{"listening_ports", "listening_port_inventories"}
+}
+
+// Build leaves out the collector where HOST_PROC/net/tcp does not exist (non-Linux); stored and
+// pushed inventories stay queryable.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := portservice.NewService(deps.Logger, repositories.NewPortRepository(deps.DB), deps.Events,
+		containerPorts(dockerinfra.NewDockerRepository(deps.DB)))
+	handler := handlers.NewPortHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/ports", handler.HandlePorts)
+		},
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}
+	if _, err := os.Stat(hostfs.Proc("net", "tcp")); err != nil {
+		deps.Logger.Info("Socket tables not available, not collecting listening ports", "path", hostfs.Proc("net", "tcp"))
+		return inst, nil
+	}
+	// The inventory is not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.PortMetric]("ports", service.Collect, service.Save, nil)
+	inst.Collector = &collector
+	inst.Metrics = portservice.NewPrometheusCollector(service)
+	inst.PushData = registry.PushLatest(service.Latest)
+	return inst, nil
+}
+
+// containerPorts reads the published ports of running containers from the latest stored Docker
+// collection; the docker tables are part of the baseline schema, so this works with the docker
+// module disabled (there is just no data).
+func containerPorts(repo dockerrepos.DockerRepository) portservice.ContainerPortSource {
+	return func(ctx context.Context, hostId uint) ([]portservice.ContainerPort, error) {
+		metric, err := repo.GetLatestMetricByHost(ctx, hostId)
+		if err != nil || metric == nil {
+			return nil, err
+		}
+		var out []portservice.ContainerPort
+		for _, stack := range metric.Stacks {
+			for _, c := range stack.Containers {
+				if c.State != "running" {
+					continue
+				}
+				for _, p := range c.Ports {
+					if p.PublicPort <= 0 {
+						continue
+					}
+					out = append(out, portservice.ContainerPort{
+						ContainerID:   c.ID,
+						ContainerName: c.Name,
+						Protocol:      p.Type,
+						IP:            p.IP,
+						PublicPort:    uint16(p.PublicPort),
+						PrivatePort:   uint16(p.PrivatePort),
+					})
+				}
+			}
+		}
+		return out, nil
+	}
+}
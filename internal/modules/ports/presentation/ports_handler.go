@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	portservice "system-stats/internal/modules/ports/application"
+)
+
+// PortHandler handles HTTP requests for the listening ports inventory.
+type PortHandler struct {
+	logger  *log.Logger
+	service portservice.Service
+	hosts   hostservice.Service
+}
+
+// NewPortHandler creates a new HTTP handler for port endpoints.
+func NewPortHandler(logger *log.Logger, service portservice.Service, hosts hostservice.Service) *PortHandler {
+	return &PortHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandlePorts returns a host's listening ports.
+//
+// @Summary     Listening ports
+// @Description Returns the listening TCP and bound UDP ports of a host with the owning process (when readable) and when each port was first and last seen. Ports published by Docker containers list the containers; published ports without a listening socket (NAT only) are returned in unmatched_container_ports. Ports appearing and disappearing are recorded as host events.
+// @Tags        metrics
+// @Produce     json
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /ports [get]
+func (h *PortHandler) HandlePorts(c *gin.Context) {
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyPortsPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for listening ports", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	inventory, err := h.service.GetInventory(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching listening ports")
+			return
+		}
+		h.logger.Error("Failed to fetch listening ports", "error", err, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, inventory)
+}
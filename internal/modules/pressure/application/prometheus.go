@@ -0,0 +1,67 @@
+package pressuremetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/modules/pressure/infrastructure/entities"
+)
+
+var (
+	descPressureAvg   = prometheus.NewDesc("system_pressure_stall_percent", "Share of time tasks stalled on a resource (PSI), averaged over window.", []string{"resource", "kind", "window"}, nil)
+	descPressureTotal = prometheus.NewDesc("system_pressure_stall_seconds_total", "Total time tasks stalled on a resource (PSI).", []string{"resource", "kind"}, nil)
+
+	descCgroupCPU       = prometheus.NewDesc("system_cgroup_cpu_usage_seconds_total", "CPU time used by a cgroup v2 cgroup.", []string{"cgroup"}, nil)
+	descCgroupThrottled = prometheus.NewDesc("system_cgroup_cpu_throttled_seconds_total", "Time a cgroup v2 cgroup was throttled by its CPU limit.", []string{"cgroup"}, nil)
+	descCgroupMemory    = prometheus.NewDesc("system_cgroup_memory_current_bytes", "Memory used by a cgroup v2 cgroup.", []string{"cgroup"}, nil)
+	descCgroupIORead    = prometheus.NewDesc("system_cgroup_io_read_bytes_total", "Bytes read by a cgroup v2 cgroup.", []string{"cgroup"}, nil)
+	descCgroupIOWrite   = prometheus.NewDesc("system_cgroup_io_write_bytes_total", "Bytes written by a cgroup v2 cgroup.", []string{"cgroup"}, nil)
+)
+
+// PrometheusCollector exports the latest in-memory collection; scrapes never read /proc.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descPressureAvg
+	ch <- descPressureTotal
+	ch <- descCgroupCPU
+	ch <- descCgroupThrottled
+	ch <- descCgroupMemory
+	ch <- descCgroupIORead
+	ch <- descCgroupIOWrite
+}
+
+// Collect sends the PSI and cgroup values of the last collection.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	latest := c.service.Latest()
+	if latest == nil {
+		return
+	}
+	for resource, p := range latest.Resources() {
+		lines := map[string]*entities.PressureLine{"some": &p.Some, "full": p.Full}
+		for kind, line := range lines {
+			if line == nil {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(descPressureAvg, prometheus.GaugeValue, line.Avg10, resource, kind, "10s")
+			ch <- prometheus.MustNewConstMetric(descPressureAvg, prometheus.GaugeValue, line.Avg60, resource, kind, "60s")
+			ch <- prometheus.MustNewConstMetric(descPressureAvg, prometheus.GaugeValue, line.Avg300, resource, kind, "300s")
+			ch <- prometheus.MustNewConstMetric(descPressureTotal, prometheus.CounterValue, float64(line.TotalUs)/1e6, resource, kind)
+		}
+	}
+	for _, cg := range latest.Cgroups {
+		ch <- prometheus.MustNewConstMetric(descCgroupCPU, prometheus.CounterValue, cg.CPUUsageSeconds, cg.Cgroup)
+		ch <- prometheus.MustNewConstMetric(descCgroupThrottled, prometheus.CounterValue, cg.CPUThrottledSeconds, cg.Cgroup)
+		if cg.MemoryCurrentBytes != nil {
+			ch <- prometheus.MustNewConstMetric(descCgroupMemory, prometheus.GaugeValue, float64(*cg.MemoryCurrentBytes), cg.Cgroup)
+		}
+		ch <- prometheus.MustNewConstMetric(descCgroupIORead, prometheus.CounterValue, float64(cg.IOReadBytes), cg.Cgroup)
+		ch <- prometheus.MustNewConstMetric(descCgroupIOWrite, prometheus.CounterValue, float64(cg.IOWriteBytes), cg.Cgroup)
+	}
+}
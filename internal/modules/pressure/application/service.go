@@ -0,0 +1,94 @@
+package pressuremetrics
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/modules/pressure/infrastructure/collectors"
+	"system-stats/internal/modules/pressure/infrastructure/entities"
+	"system-stats/internal/modules/pressure/infrastructure/repositories"
+)
+
+type Service interface {
+	// Collect reads PSI and cgroup statistics and keeps the result as the latest in-memory collection.
+	Collect(ctx context.Context) (entities.PressureMetric, error)
+	Save(ctx context.Context, metric entities.PressureMetric, hostId uint) error
+	// Latest returns this instance's last collection (no database access); nil before the first one.
+	Latest() *entities.PressureMetric
+	// ReceivePush stores a collection pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	GetLatestByHost(ctx context.Context, hostId uint) (*entities.PressureMetric, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalPressureMetric, error)
+	GetCgroupHistoryByHost(ctx context.Context, hostId uint, hours float64, cgroup string) ([]entities.HistoricalCgroupMetric, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.PressureCollector
+	repo      repositories.PressureRepository
+
+	mu     sync.RWMutex
+	latest *entities.PressureMetric
+}
+
+func NewService(logger *log.Logger, repo repositories.PressureRepository) Service {
+	return &service{
+		logger:    logger,
+		collector: collectors.NewPressureCollector(logger),
+		repo:      repo,
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.PressureMetric, error) {
+	s.logger.Debug("Collecting metrics", "module", "pressure")
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	s.latest = &metric
+	s.mu.Unlock()
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.PressureMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "pressure", "error", err, "host_id", hostId)
+		return err
+	}
+	return nil
+}
+
+func (s *service) Latest() *entities.PressureMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+// ReceivePush caps the pushed cgroups at MaxCgroups.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.PressureMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	metric.Cgroups = metric.Cgroups[:min(len(metric.Cgroups), collectors.MaxCgroups)]
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) (*entities.PressureMetric, error) {
+	return s.repo.GetLatestByHost(ctx, hostId)
+}
+
+func (s *service) GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalPressureMetric, error) {
+	return s.repo.GetHistoryByHost(ctx, hostId, hours)
+}
+
+func (s *service) GetCgroupHistoryByHost(ctx context.Context, hostId uint, hours float64, cgroup string) ([]entities.HistoricalCgroupMetric, error) {
+	return s.repo.GetCgroupHistoryByHost(ctx, hostId, hours, cgroup)
+}
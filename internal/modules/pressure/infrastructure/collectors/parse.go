@@ -0,0 +1,116 @@
+package collectors
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"system-stats/internal/modules/pressure/infrastructure/entities"
+)
+
+// ParsePressure parses a PSI file:
+//
+//	some avg10=0.12 avg60=0.05 avg300=0.01 total=123456
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func ParsePressure(raw []byte) (*entities.Pressure, error) {
+	var p entities.Pressure
+	var some bool
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		line, err := parsePressureLine(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fields[0], err)
+		}
+		switch fields[0] {
+		case "some":
+			p.Some, some = line, true
+		case "full":
+			p.Full = &line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !some {
+		return nil, errors.New("no \"some\" line")
+	}
+	return &p, nil
+}
+
+func parsePressureLine(fields []string) (entities.PressureLine, error) {
+	var line entities.PressureLine
+	for _, f := range fields {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok {
+			return line, fmt.Errorf("malformed field %q", f)
+		}
+		var err error
+		switch key {
+		case "avg10":
+			line.Avg10, err = strconv.ParseFloat(value, 64)
+		case "avg60":
+			line.Avg60, err = strconv.ParseFloat(value, 64)
+		case "avg300":
+			line.Avg300, err = strconv.ParseFloat(value, 64)
+		case "total":
+			line.TotalUs, err = strconv.ParseUint(value, 10, 64)
+		}
+		if err != nil {
+			return line, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return line, nil
+}
+
+// parseKeyValues parses flat-keyed cgroup files such as cpu.stat ("usage_usec 123").
+func parseKeyValues(raw []byte) map[string]uint64 {
+	out := make(map[string]uint64)
+	for _, line := range strings.Split(string(raw), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64); err == nil {
+			out[key] = n
+		}
+	}
+	return out
+}
+
+// parseIOStat sums rbytes and wbytes over the devices of an io.stat file
+// ("8:0 rbytes=1 wbytes=2 rios=3 wios=4 dbytes=0 dios=0").
+func parseIOStat(raw []byte) (read, write uint64) {
+	for _, line := range strings.Split(string(raw), "\n") {
+		fields := strings.Fields(line)
+		for _, f := range fields[min(1, len(fields)):] {
+			key, value, _ := strings.Cut(f, "=")
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				read += n
+			case "wbytes":
+				write += n
+			}
+		}
+	}
+	return read, write
+}
+
+// parseMemoryMax parses memory.max; "max" (unlimited) returns nil.
+func parseMemoryMax(raw []byte) *uint64 {
+	n, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
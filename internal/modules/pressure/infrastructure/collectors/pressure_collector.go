@@ -0,0 +1,160 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/pressure/infrastructure/entities"
+)
+
+// MaxCgroups bounds the top-level cgroups collected (and accepted from agents), root included.
+const MaxCgroups = 64
+
+// cpuSample is a cgroup's CPU usage when last collected.
+type cpuSample struct {
+	seconds float64
+	at      time.Time
+}
+
+// PressureCollector reads HOST_PROC/pressure and the cgroup v2 hierarchy at HOST_SYS/fs/cgroup.
+// Missing files are not errors: kernels without PSI or cgroup v2 report empty metrics.
+type PressureCollector struct {
+	logger *log.Logger
+
+	mu   sync.Mutex
+	prev map[string]cpuSample
+	// warned logs the absence of PSI and cgroup v2 once
+	warned sync.Once
+}
+
+// NewPressureCollector creates a PSI and cgroup collector.
+func NewPressureCollector(logger *log.Logger) *PressureCollector {
+	return &PressureCollector{logger: logger, prev: make(map[string]cpuSample)}
+}
+
+// Collect reads the host PSI and the root and top-level cgroups.
+func (c *PressureCollector) Collect(ctx context.Context) (entities.PressureMetric, error) {
+	c.logger.Debug("Collecting pressure")
+	now := time.Now().UTC()
+	metric := entities.PressureMetric{
+		Timestamp: now,
+		CPU:       readPressure(hostfs.Proc("pressure", entities.ResourceCPU)),
+		Memory:    readPressure(hostfs.Proc("pressure", entities.ResourceMemory)),
+		IO:        readPressure(hostfs.Proc("pressure", entities.ResourceIO)),
+		Cgroups:   []entities.CgroupStats{},
+	}
+
+	root := hostfs.Sys("fs", "cgroup")
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		dirs := []string{"/"}
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			return metric, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				dirs = append(dirs, e.Name())
+			}
+		}
+		sort.Strings(dirs[1:])
+		if len(dirs) > MaxCgroups {
+			c.logger.Debug("Too many top-level cgroups, collecting the first ones", "count", len(dirs), "max", MaxCgroups)
+			dirs = dirs[:MaxCgroups]
+		}
+
+		c.mu.Lock()
+		next := make(map[string]cpuSample, len(dirs))
+		for _, name := range dirs {
+			if ctx.Err() != nil {
+				c.mu.Unlock()
+				return metric, ctx.Err()
+			}
+			stats := readCgroup(root, name)
+			if prev, ok := c.prev[name]; ok {
+				if elapsed := now.Sub(prev.at).Seconds(); elapsed > 0 && stats.CPUUsageSeconds >= prev.seconds {
+					stats.CPUPercent = (stats.CPUUsageSeconds - prev.seconds) / elapsed * 100
+				}
+			}
+			next[name] = cpuSample{seconds: stats.CPUUsageSeconds, at: now}
+			metric.Cgroups = append(metric.Cgroups, stats)
+		}
+		c.prev = next
+		c.mu.Unlock()
+	}
+
+	if !metric.Available() && len(metric.Cgroups) == 0 {
+		c.warned.Do(func() {
+			c.logger.Info("Neither PSI nor cgroup v2 is available; pressure metrics stay empty", "proc", hostfs.Proc("pressure"), "cgroup", root)
+		})
+	}
+	return metric, nil
+}
+
+// readPressure returns nil when the file is missing or unreadable (no PSI, psi=0 gives EOPNOTSUPP).
+func readPressure(path string) *entities.Pressure {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	p, err := ParsePressure(raw)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// readCgroup reads the counters of one cgroup; files a cgroup lacks (e.g. memory.current at
+// the root, or a disabled controller) leave their fields empty.
+func readCgroup(root, name string) entities.CgroupStats {
+	dir := root
+	if name != "/" {
+		dir = filepath.Join(root, name)
+	}
+	read := func(file string) []byte {
+		raw, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil
+		}
+		return raw
+	}
+
+	stats := entities.CgroupStats{Cgroup: name}
+	cpu := parseKeyValues(read("cpu.stat"))
+	stats.CPUUsageSeconds = float64(cpu["usage_usec"]) / 1e6
+	stats.CPUThrottledSeconds = float64(cpu["throttled_usec"]) / 1e6
+	if raw := read("memory.current"); raw != nil {
+		if n, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64); err == nil {
+			stats.MemoryCurrentBytes = &n
+		}
+	}
+	if raw := read("memory.max"); raw != nil {
+		stats.MemoryMaxBytes = parseMemoryMax(raw)
+	}
+	stats.IOReadBytes, stats.IOWriteBytes = parseIOStat(read("io.stat"))
+	if name != "/" {
+		stats.CPUPressure = someAvg10(read("cpu.pressure"))
+		stats.MemoryPressure = someAvg10(read("memory.pressure"))
+		stats.IOPressure = someAvg10(read("io.pressure"))
+	}
+	return stats
+}
+
+func someAvg10(raw []byte) *float64 {
+	if raw == nil {
+		return nil
+	}
+	p, err := ParsePressure(raw)
+	if err != nil {
+		return nil
+	}
+	return &p.Some.Avg10
+}
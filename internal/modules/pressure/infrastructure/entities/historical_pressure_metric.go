@@ -0,0 +1,129 @@
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+// HistoricalPressureMetric is one PSI resource at one collection.
+type HistoricalPressureMetric struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_pressure_metrics_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_pressure_metrics_host_ts,priority:2"`
+	Resource  string    `json:"resource" gorm:"primaryKey;size:8"`
+
+	SomeAvg10   float64 `json:"some_avg10"`
+	SomeAvg60   float64 `json:"some_avg60"`
+	SomeAvg300  float64 `json:"some_avg300"`
+	SomeTotalUs uint64  `json:"some_total_us"`
+	// Full* are nil when the kernel reports no "full" line (CPU before 5.13)
+	FullAvg10   *float64 `json:"full_avg10"`
+	FullAvg60   *float64 `json:"full_avg60"`
+	FullAvg300  *float64 `json:"full_avg300"`
+	FullTotalUs *uint64  `json:"full_total_us"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalPressureMetric) TableName() string { return "pressure_metrics" }
+
+// HistoricalCgroupMetric is one cgroup at one collection.
+type HistoricalCgroupMetric struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_cgroup_metrics_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_cgroup_metrics_host_ts,priority:2"`
+	Cgroup    string    `json:"cgroup" gorm:"primaryKey;size:255"`
+
+	CPUUsageSeconds     float64  `json:"cpu_usage_seconds"`
+	CPUPercent          float64  `json:"cpu_percent"`
+	CPUThrottledSeconds float64  `json:"cpu_throttled_seconds"`
+	MemoryCurrentBytes  *uint64  `json:"memory_current_bytes"`
+	MemoryMaxBytes      *uint64  `json:"memory_max_bytes"`
+	IOReadBytes         uint64   `json:"io_read_bytes"`
+	IOWriteBytes        uint64   `json:"io_write_bytes"`
+	CPUPressure         *float64 `json:"cpu_pressure"`
+	MemoryPressure      *float64 `json:"memory_pressure"`
+	IOPressure          *float64 `json:"io_pressure"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalCgroupMetric) TableName() string { return "cgroup_metrics" }
+
+// ToRows flattens a metric into PSI and cgroup rows.
+func (m PressureMetric) ToRows(hostId uint) ([]HistoricalPressureMetric, []HistoricalCgroupMetric) {
+	ts := m.Timestamp.UTC().Truncate(time.Microsecond)
+	var psi []HistoricalPressureMetric
+	for resource, p := range m.Resources() {
+		row := HistoricalPressureMetric{
+			HostID:      hostId,
+			Timestamp:   ts,
+			Resource:    resource,
+			SomeAvg10:   p.Some.Avg10,
+			SomeAvg60:   p.Some.Avg60,
+			SomeAvg300:  p.Some.Avg300,
+			SomeTotalUs: p.Some.TotalUs,
+		}
+		if f := p.Full; f != nil {
+			row.FullAvg10, row.FullAvg60, row.FullAvg300, row.FullTotalUs = &f.Avg10, &f.Avg60, &f.Avg300, &f.TotalUs
+		}
+		psi = append(psi, row)
+	}
+	sort.Slice(psi, func(i, j int) bool { return psi[i].Resource < psi[j].Resource })
+
+	cgroups := make([]HistoricalCgroupMetric, 0, len(m.Cgroups))
+	for _, c := range m.Cgroups {
+		cgroups = append(cgroups, HistoricalCgroupMetric{
+			HostID:              hostId,
+			Timestamp:           ts,
+			Cgroup:              c.Cgroup,
+			CPUUsageSeconds:     c.CPUUsageSeconds,
+			CPUPercent:          c.CPUPercent,
+			CPUThrottledSeconds: c.CPUThrottledSeconds,
+			MemoryCurrentBytes:  c.MemoryCurrentBytes,
+			MemoryMaxBytes:      c.MemoryMaxBytes,
+			IOReadBytes:         c.IOReadBytes,
+			IOWriteBytes:        c.IOWriteBytes,
+			CPUPressure:         c.CPUPressure,
+			MemoryPressure:      c.MemoryPressure,
+			IOPressure:          c.IOPressure,
+		})
+	}
+	return psi, cgroups
+}
+
+// MetricFromRows rebuilds the metric of one collection; nil when there are no rows.
+func MetricFromRows(psi []HistoricalPressureMetric, cgroups []HistoricalCgroupMetric) *PressureMetric {
+	if len(psi) == 0 && len(cgroups) == 0 {
+		return nil
+	}
+	m := &PressureMetric{Cgroups: make([]CgroupStats, 0, len(cgroups))}
+	for _, row := range psi {
+		m.Timestamp = row.Timestamp
+		p := &Pressure{Some: PressureLine{Avg10: row.SomeAvg10, Avg60: row.SomeAvg60, Avg300: row.SomeAvg300, TotalUs: row.SomeTotalUs}}
+		if row.FullAvg10 != nil && row.FullAvg60 != nil && row.FullAvg300 != nil && row.FullTotalUs != nil {
+			p.Full = &PressureLine{Avg10: *row.FullAvg10, Avg60: *row.FullAvg60, Avg300: *row.FullAvg300, TotalUs: *row.FullTotalUs}
+		}
+		switch row.Resource {
+		case ResourceCPU:
+			m.CPU = p
+		case ResourceMemory:
+			m.Memory = p
+		case ResourceIO:
+			m.IO = p
+		}
+	}
+	for _, row := range cgroups {
+		m.Timestamp = row.Timestamp
+		m.Cgroups = append(m.Cgroups, CgroupStats{
+			Cgroup:              row.Cgroup,
+			CPUUsageSeconds:     row.CPUUsageSeconds,
+			CPUPercent:          row.CPUPercent,
+			CPUThrottledSeconds: row.CPUThrottledSeconds,
+			MemoryCurrentBytes:  row.MemoryCurrentBytes,
+			MemoryMaxBytes:      row.MemoryMaxBytes,
+			IOReadBytes:         row.IOReadBytes,
+			IOWriteBytes:        row.IOWriteBytes,
+			CPUPressure:         row.CPUPressure,
+			MemoryPressure:      row.MemoryPressure,
+			IOPressure:          row.IOPressure,
+		})
+	}
+	return m
+}
@@ -0,0 +1,73 @@
+package entities
+
+import "time"
+
+// PSI resources as named in /proc/pressure.
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+	ResourceIO     = "io"
+)
+
+// PressureMetric is one collection of Pressure Stall Information and cgroup v2 statistics.
+type PressureMetric struct {
+	Timestamp time.Time `json:"timestamp"`
+	// CPU, Memory and IO are nil when the kernel has no PSI (before 4.20, or booted with psi=0)
+	CPU    *Pressure `json:"cpu"`
+	Memory *Pressure `json:"memory"`
+	IO     *Pressure `json:"io"`
+	// Cgroups holds the root cgroup ("/") and the top-level cgroups; empty without cgroup v2
+	Cgroups []CgroupStats `json:"cgroups"`
+}
+
+// Available reports whether any PSI resource was read.
+func (m PressureMetric) Available() bool {
+	return m.CPU != nil || m.Memory != nil || m.IO != nil
+}
+
+// Resources returns the PSI resources that were read, keyed by resource name.
+func (m PressureMetric) Resources() map[string]*Pressure {
+	out := make(map[string]*Pressure, 3)
+	for name, p := range map[string]*Pressure{ResourceCPU: m.CPU, ResourceMemory: m.Memory, ResourceIO: m.IO} {
+		if p != nil {
+			out[name] = p
+		}
+	}
+	return out
+}
+
+// Pressure is the content of one /proc/pressure file.
+type Pressure struct {
+	// Some is the share of time at least one task stalled on the resource
+	Some PressureLine `json:"some"`
+	// Full is the share of time all non-idle tasks stalled; nil for CPU on kernels before 5.13
+	Full *PressureLine `json:"full"`
+}
+
+// PressureLine holds the stall percentages over 10s, 60s and 300s and the total stall time.
+type PressureLine struct {
+	Avg10   float64 `json:"avg10"`
+	Avg60   float64 `json:"avg60"`
+	Avg300  float64 `json:"avg300"`
+	TotalUs uint64  `json:"total_us"`
+}
+
+// CgroupStats are the cgroup v2 counters of one cgroup.
+type CgroupStats struct {
+	// Cgroup is "/" for the root cgroup, else the top-level cgroup name (e.g. system.slice)
+	Cgroup          string  `json:"cgroup"`
+	CPUUsageSeconds float64 `json:"cpu_usage_seconds"`
+	// CPUPercent is the CPU used since the previous collection (100 = one core)
+	CPUPercent          float64 `json:"cpu_percent"`
+	CPUThrottledSeconds float64 `json:"cpu_throttled_seconds"`
+	// MemoryCurrentBytes is nil for the root cgroup; MemoryMaxBytes is nil when unlimited
+	MemoryCurrentBytes *uint64 `json:"memory_current_bytes"`
+	MemoryMaxBytes     *uint64 `json:"memory_max_bytes"`
+	IOReadBytes        uint64  `json:"io_read_bytes"`
+	IOWriteBytes       uint64  `json:"io_write_bytes"`
+	// CPUPressure, MemoryPressure and IOPressure are the cgroup's "some" avg10; nil for the root
+	// cgroup (see the host PSI) and without PSI
+	CPUPressure    *float64 `json:"cpu_pressure"`
+	MemoryPressure *float64 `json:"memory_pressure"`
+	IOPressure     *float64 `json:"io_pressure"`
+}
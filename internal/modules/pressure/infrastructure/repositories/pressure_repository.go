@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/pressure/infrastructure/entities"
+)
+
+type PressureRepository interface {
+	// SaveCurrentMetric stores the PSI and cgroup rows of one collection; a collection already
+	// stored (e.g. pushed twice) is skipped.
+	SaveCurrentMetric(ctx context.Context, metric entities.PressureMetric, hostId uint) error
+	// GetLatestByHost rebuilds the most recent collection of a host; nil when there is none.
+	GetLatestByHost(ctx context.Context, hostId uint) (*entities.PressureMetric, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalPressureMetric, error)
+	// GetCgroupHistoryByHost returns cgroup rows in time order, optionally for one cgroup.
+	GetCgroupHistoryByHost(ctx context.Context, hostId uint, hours float64, cgroup string) ([]entities.HistoricalCgroupMetric, error)
+}
+
+type pressureRepository struct {
+	db *gorm.DB
+}
+
+func NewPressureRepository(db *gorm.DB) PressureRepository {
+	return &pressureRepository{db: db}
+}
+
+func (r *pressureRepository) SaveCurrentMetric(ctx context.Context, metric entities.PressureMetric, hostId uint) error {
+	psi, cgroups := metric.ToRows(hostId)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(psi) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&psi).Error; err != nil {
+				return err
+			}
+		}
+		if len(cgroups) > 0 {
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&cgroups).Error
+		}
+		return nil
+	})
+}
+
+func (r *pressureRepository) GetLatestByHost(ctx context.Context, hostId uint) (*entities.PressureMetric, error) {
+	db := r.db.WithContext(ctx)
+	var psi []entities.HistoricalPressureMetric
+	latestPSI := r.db.Model(&entities.HistoricalPressureMetric{}).Select("MAX(timestamp)").Where("host_id = ?", hostId)
+	if err := db.Where("host_id = ? AND timestamp = (?)", hostId, latestPSI).Find(&psi).Error; err != nil {
+		return nil, err
+	}
+	var cgroups []entities.HistoricalCgroupMetric
+	latestCgroups := r.db.Model(&entities.HistoricalCgroupMetric{}).Select("MAX(timestamp)").Where("host_id = ?", hostId)
+	if err := db.Where("host_id = ? AND timestamp = (?)", hostId, latestCgroups).Order("cgroup ASC").Find(&cgroups).Error; err != nil {
+		return nil, err
+	}
+	return entities.MetricFromRows(psi, cgroups), nil
+}
+
+func (r *pressureRepository) GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalPressureMetric, error) {
+	rows := []entities.HistoricalPressureMetric{}
+	err := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours).
+		Order("timestamp ASC, resource ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *pressureRepository) GetCgroupHistoryByHost(ctx context.Context, hostId uint, hours float64, cgroup string) ([]entities.HistoricalCgroupMetric, error) {
+	rows := []entities.HistoricalCgroupMetric{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if cgroup != "" {
+		q = q.Where("cgroup = ?", cgroup)
+	}
+	err := q.Order("timestamp ASC, cgroup ASC").Find(&rows).Error
+	return rows, err
+}
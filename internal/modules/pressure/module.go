@@ -0,0 +1,60 @@
+// Package pressure registers the pressure module, which collects Pressure Stall Information
+// and cgroup v2 statistics.
+package pressure
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/app/snapshot"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	pressureservice "system-stats/internal/modules/pressure/application"
+	"system-stats/internal/modules/pressure/infrastructure/entities"
+	"system-stats/internal/modules/pressure/infrastructure/repositories"
+	handlers "system-stats/internal/modules/pressure/presentation"
+)
+
+// Module collects /proc/pressure and the top-level cgroups and serves /pressure.
+type Module struct{}
+
+func (Module) Name() string { return "pressure" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 9,
+		Name:    "pressure_and_cgroup_tables",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalPressureMetric{}, &entities.HistoricalCgroupMetric{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalCgroupMetric{}, &entities.HistoricalPressureMetric{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"pressure_metrics", "cgroup_metrics"} }
+
+func (Module) HostTables() []string { return []string{"pressure_metrics", "cgroup_metrics"} }
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := pressureservice.NewService(deps.Logger, repositories.NewPressureRepository(deps.DB))
+	handler := handlers.NewPressureHandler(deps.Logger, service, deps.Hosts)
+	collector := historycore.NewModuleCollector("pressure", service.Collect, service.Save,
+		func(snap *snapshot.Snapshot, m *entities.PressureMetric) { snap.Pressure = m })
+	return registry.Instance{
+		Collector: &collector,
+		Routes: func(r gin.IRoutes) {
+			r.GET("/pressure", handler.HandlePressureStats)
+		},
+		Metrics:  pressureservice.NewPrometheusCollector(service),
+		PushData: registry.PushLatest(service.Latest),
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}, nil
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	pressureservice "system-stats/internal/modules/pressure/application"
+)
+
+// PressureHandler handles HTTP requests for PSI and cgroup metrics.
+type PressureHandler struct {
+	logger  *log.Logger
+	service pressureservice.Service
+	hosts   hostservice.Service
+}
+
+// NewPressureHandler creates a new HTTP handler for pressure endpoints.
+func NewPressureHandler(logger *log.Logger, service pressureservice.Service, hosts hostservice.Service) *PressureHandler {
+	return &PressureHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandlePressureStats returns the latest PSI and cgroup collection with PSI and cgroup history.
+//
+// @Summary     Pressure stall information
+// @Description Returns CPU, memory and IO pressure (some/full avg10/60/300 and total stall time) and cgroup v2 statistics of the root and top-level cgroups. Resources are null on kernels without PSI; cgroups are empty without cgroup v2.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       cgroup   query    string   false  "Limit cgroup history to one cgroup (\"/\" for the root)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /pressure [get]
+func (h *PressureHandler) HandlePressureStats(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyPressurePayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for pressure metrics", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	latest, err := h.service.GetLatestByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest pressure metrics")
+			return
+		}
+		h.logger.Error("Failed to fetch latest pressure metrics", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetHistoryByHost(ctx, effective, hours)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching pressure history")
+			return
+		}
+		h.logger.Error("Failed to fetch pressure history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	cgroupHistory, err := h.service.GetCgroupHistoryByHost(ctx, effective, hours, c.Query("cgroup"))
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching cgroup history")
+			return
+		}
+		h.logger.Error("Failed to fetch cgroup history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"latest":         latest,
+		"history":        history,
+		"cgroup_history": cgroupHistory,
+	})
+}
@@ -0,0 +1,91 @@
+package processmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/modules/processes/infrastructure/collectors"
+	"system-stats/internal/modules/processes/infrastructure/entities"
+	"system-stats/internal/modules/processes/infrastructure/repositories"
+)
+
+type Service interface {
+	// Collect lists the top processes and keeps the result as the latest in-memory collection.
+	Collect(ctx context.Context) (entities.ProcessMetric, error)
+	Save(ctx context.Context, metric entities.ProcessMetric, hostId uint) error
+	// Latest returns this instance's last collection (no database access); nil before the first one.
+	Latest() *entities.ProcessMetric
+	// ReceivePush stores a collection pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	GetLatestByHost(ctx context.Context, hostId uint) (*entities.ProcessMetric, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, pid int32, name string) ([]entities.HistoricalProcessSample, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.ProcessCollector
+	repo      repositories.ProcessRepository
+
+	mu     sync.RWMutex
+	latest *entities.ProcessMetric
+}
+
+func NewService(logger *log.Logger, topN int, repo repositories.ProcessRepository) Service {
+	return &service{
+		logger:    logger,
+		collector: collectors.NewProcessCollector(logger, topN),
+		repo:      repo,
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.ProcessMetric, error) {
+	s.logger.Debug("Collecting metrics", "module", "processes")
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	s.latest = &metric
+	s.mu.Unlock()
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.ProcessMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "processes", "error", err, "host_id", hostId)
+		return err
+	}
+	return nil
+}
+
+func (s *service) Latest() *entities.ProcessMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+// ReceivePush caps the pushed lists at MaxTopN so an agent cannot flood the table; strings are
+// cut to the column sizes when stored.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.ProcessMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	metric.TopCPU = metric.TopCPU[:min(len(metric.TopCPU), collectors.MaxTopN)]
+	metric.TopMemory = metric.TopMemory[:min(len(metric.TopMemory), collectors.MaxTopN)]
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) (*entities.ProcessMetric, error) {
+	return s.repo.GetLatestByHost(ctx, hostId)
+}
+
+func (s *service) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, pid int32, name string) ([]entities.HistoricalProcessSample, error) {
+	return s.repo.GetHistoryByHost(ctx, hostId, hours, pid, name)
+}
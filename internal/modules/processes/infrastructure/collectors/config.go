@@ -0,0 +1,44 @@
+package collectors
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TopNEnv sets how many processes each top list holds
+	TopNEnv = "PROCESSES_TOP_N"
+	// RetentionEnv sets how long process samples are kept (capped by METRICS_RETENTION_DAYS)
+	RetentionEnv = "PROCESSES_RETENTION"
+	// DefaultRetention keeps one day of process history
+	DefaultRetention = 24 * time.Hour
+)
+
+// TopNFromEnv reads PROCESSES_TOP_N (1-MaxTopN, default DefaultTopN).
+func TopNFromEnv() (int, error) {
+	raw := strings.TrimSpace(os.Getenv(TopNEnv))
+	if raw == "" {
+		return DefaultTopN, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > MaxTopN {
+		return 0, fmt.Errorf("%s must be a number between 1 and %d, got %q", TopNEnv, MaxTopN, raw)
+	}
+	return n, nil
+}
+
+// RetentionFromEnv reads PROCESSES_RETENTION as a Go duration (default DefaultRetention).
+func RetentionFromEnv() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(RetentionEnv))
+	if raw == "" {
+		return DefaultRetention, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration like \"12h\", got %q", RetentionEnv, raw)
+	}
+	return d, nil
+}
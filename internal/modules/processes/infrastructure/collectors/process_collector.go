@@ -0,0 +1,193 @@
+package collectors
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/app/proccpu"
+	"system-stats/internal/app/textutil"
+	"system-stats/internal/modules/processes/infrastructure/entities"
+)
+
+const (
+	// DefaultTopN is the length of each top list unless PROCESSES_TOP_N is set
+	DefaultTopN = 10
+	// MaxTopN bounds PROCESSES_TOP_N and the lists accepted from agents
+	MaxTopN = 100
+)
+
+// ProcessCollector lists the processes using the most CPU and memory. Processes are read
+// through gopsutil, which honours HOST_PROC; users are resolved from HOST_ETC/passwd.
+type ProcessCollector struct {
+	logger *log.Logger
+	topN   int
+
+	mu   sync.Mutex
+	prev map[int32]proccpu.Sample
+}
+
+// NewProcessCollector creates a collector listing topN processes per ranking.
+func NewProcessCollector(logger *log.Logger, topN int) *ProcessCollector {
+	return &ProcessCollector{logger: logger, topN: topN, prev: make(map[int32]proccpu.Sample)}
+}
+
+// candidate is a process with the values needed for ranking; details are read only for listed ones.
+type candidate struct {
+	proc       *process.Process
+	created    int64
+	cpuPercent float64
+	rss        uint64
+}
+
+// Collect ranks all processes and returns the top lists. Processes that exit while being
+// read are skipped.
+func (c *ProcessCollector) Collect(ctx context.Context) (entities.ProcessMetric, error) {
+	c.logger.Debug("Collecting processes")
+	now := time.Now().UTC()
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return entities.ProcessMetric{}, err
+	}
+	var totalMemory uint64
+	if vm, err := mem.VirtualMemoryWithContext(ctx); err == nil {
+		totalMemory = vm.Total
+	}
+
+	c.mu.Lock()
+	next := make(map[int32]proccpu.Sample, len(procs))
+	candidates := make([]candidate, 0, len(procs))
+	for _, p := range procs {
+		if ctx.Err() != nil {
+			c.mu.Unlock()
+			return entities.ProcessMetric{}, ctx.Err()
+		}
+		times, err := p.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		created, _ := p.CreateTimeWithContext(ctx)
+		var rss uint64
+		if info, err := p.MemoryInfoWithContext(ctx); err == nil {
+			rss = info.RSS
+		}
+		sample := proccpu.Sample{Created: created, Seconds: times.User + times.System, At: now}
+		next[p.Pid] = sample
+		candidates = append(candidates, candidate{
+			proc:       p,
+			created:    created,
+			cpuPercent: proccpu.Percent(c.prev[p.Pid], sample),
+			rss:        rss,
+		})
+	}
+	c.prev = next
+	c.mu.Unlock()
+
+	users := loadUsers()
+	details := make(map[int32]entities.ProcessInfo)
+	list := func(less func(a, b candidate) bool) []entities.ProcessInfo {
+		sort.Slice(candidates, func(i, j int) bool { return less(candidates[i], candidates[j]) })
+		out := make([]entities.ProcessInfo, 0, c.topN)
+		for _, cand := range candidates[:min(c.topN, len(candidates))] {
+			info, ok := details[cand.proc.Pid]
+			if !ok {
+				info = c.describe(ctx, cand, totalMemory, users)
+				details[cand.proc.Pid] = info
+			}
+			out = append(out, info)
+		}
+		return out
+	}
+
+	metric := entities.ProcessMetric{Timestamp: now, TotalProcesses: len(candidates)}
+	metric.TopCPU = list(func(a, b candidate) bool {
+		if a.cpuPercent != b.cpuPercent {
+			return a.cpuPercent > b.cpuPercent
+		}
+		return a.rss > b.rss
+	})
+	metric.TopMemory = list(func(a, b candidate) bool {
+		if a.rss != b.rss {
+			return a.rss > b.rss
+		}
+		return a.cpuPercent > b.cpuPercent
+	})
+	return metric, nil
+}
+
+// describe reads the details of a listed process; fields that cannot be read stay empty.
+func (c *ProcessCollector) describe(ctx context.Context, cand candidate, totalMemory uint64, users map[uint32]string) entities.ProcessInfo {
+	p := cand.proc
+	info := entities.ProcessInfo{
+		PID:        p.Pid,
+		CPUPercent: cand.cpuPercent,
+		RSSBytes:   cand.rss,
+	}
+	if cand.created > 0 {
+		info.StartedAt = time.UnixMilli(cand.created).UTC()
+	}
+	if totalMemory > 0 {
+		info.MemoryPercent = float64(cand.rss) / float64(totalMemory) * 100
+	}
+	info.Name, _ = p.NameWithContext(ctx)
+	if cmdline, err := p.CmdlineWithContext(ctx); err == nil {
+		info.Cmdline = textutil.Truncate(cmdline, entities.MaxCmdlineLen)
+	}
+	if uids, err := p.UidsWithContext(ctx); err == nil && len(uids) > 0 {
+		if name, ok := users[uids[0]]; ok {
+			info.User = name
+		} else {
+			info.User = strconv.FormatUint(uint64(uids[0]), 10)
+		}
+	}
+	info.Threads, _ = p.NumThreadsWithContext(ctx)
+	if fds, err := p.NumFDsWithContext(ctx); err == nil {
+		info.OpenFDs = &fds
+	}
+	if io, err := p.IOCountersWithContext(ctx); err == nil {
+		info.ReadBytes, info.WriteBytes = &io.ReadBytes, &io.WriteBytes
+	}
+	return info
+}
+
+// loadUsers maps uids to names from HOST_ETC/passwd (or /etc/passwd), so containerised
+// deployments show host user names.
+func loadUsers() map[uint32]string {
+	// Unreadable entries fall back to numeric uids
+	users, _ := parsePasswd(hostfs.Etc("passwd"))
+	return users
+}
+
+func parsePasswd(path string) (map[uint32]string, error) {
+	users := make(map[uint32]string)
+	f, err := os.Open(path)
+	if err != nil {
+		return users, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		uid, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			continue
+		}
+		if _, seen := users[uint32(uid)]; !seen {
+			users[uint32(uid)] = fields[0]
+		}
+	}
+	return users, scanner.Err()
+}
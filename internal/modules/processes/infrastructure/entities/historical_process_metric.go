@@ -0,0 +1,131 @@
+package entities
+
+import (
+	"time"
+
+	"system-stats/internal/app/textutil"
+)
+
+// Column sizes of process_samples. Long command lines are cut when collected; pushed values are
+// cut when stored.
+const (
+	MaxNameLen    = 255
+	MaxCmdlineLen = 1024
+	MaxUserLen    = 64
+)
+
+// HistoricalProcessSample is one listed process of one collection. A process in both top lists
+// is stored once with both ranks.
+type HistoricalProcessSample struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_process_samples_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_process_samples_host_ts,priority:2"`
+	PID       int32     `json:"pid" gorm:"primaryKey;autoIncrement:false"`
+
+	// CPURank and MemoryRank are 1-based positions in the top lists, 0 when not listed
+	CPURank    int `json:"cpu_rank"`
+	MemoryRank int `json:"memory_rank"`
+
+	Name          string    `json:"name" gorm:"size:255;index"`
+	Cmdline       string    `json:"cmdline" gorm:"size:1024"`
+	User          string    `json:"user" gorm:"size:64"`
+	CPUPercent    float64   `json:"cpu_percent"`
+	RSSBytes      uint64    `json:"rss_bytes"`
+	MemoryPercent float64   `json:"memory_percent"`
+	Threads       int32     `json:"threads"`
+	StartedAt     time.Time `json:"started_at"`
+	OpenFDs       *int32    `json:"open_fds"`
+	ReadBytes     *uint64   `json:"read_bytes"`
+	WriteBytes    *uint64   `json:"write_bytes"`
+	// TotalProcesses repeats the collection's process count so the latest collection can be rebuilt
+	TotalProcesses int `json:"-"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalProcessSample) TableName() string { return "process_samples" }
+
+// ToSamples flattens a metric into rows, merging processes listed in both top lists.
+func (m ProcessMetric) ToSamples(hostId uint) []HistoricalProcessSample {
+	ts := m.Timestamp.UTC().Truncate(time.Microsecond)
+	index := make(map[int32]int, len(m.TopCPU)+len(m.TopMemory))
+	var rows []HistoricalProcessSample
+	add := func(p ProcessInfo) *HistoricalProcessSample {
+		if i, ok := index[p.PID]; ok {
+			return &rows[i]
+		}
+		index[p.PID] = len(rows)
+		rows = append(rows, HistoricalProcessSample{
+			HostID:         hostId,
+			Timestamp:      ts,
+			PID:            p.PID,
+			Name:           textutil.Truncate(p.Name, MaxNameLen),
+			Cmdline:        textutil.Truncate(p.Cmdline, MaxCmdlineLen),
+			User:           textutil.Truncate(p.User, MaxUserLen),
+			CPUPercent:     p.CPUPercent,
+			RSSBytes:       p.RSSBytes,
+			MemoryPercent:  p.MemoryPercent,
+			Threads:        p.Threads,
+			StartedAt:      p.StartedAt,
+			OpenFDs:        p.OpenFDs,
+			ReadBytes:      p.ReadBytes,
+			WriteBytes:     p.WriteBytes,
+			TotalProcesses: m.TotalProcesses,
+		})
+		return &rows[len(rows)-1]
+	}
+	for i, p := range m.TopCPU {
+		add(p).CPURank = i + 1
+	}
+	for i, p := range m.TopMemory {
+		add(p).MemoryRank = i + 1
+	}
+	return rows
+}
+
+// ToProcessInfo converts a stored row back to a process entry.
+func (s HistoricalProcessSample) ToProcessInfo() ProcessInfo {
+	return ProcessInfo{
+		PID:           s.PID,
+		Name:          s.Name,
+		Cmdline:       s.Cmdline,
+		User:          s.User,
+		CPUPercent:    s.CPUPercent,
+		RSSBytes:      s.RSSBytes,
+		MemoryPercent: s.MemoryPercent,
+		Threads:       s.Threads,
+		StartedAt:     s.StartedAt,
+		OpenFDs:       s.OpenFDs,
+		ReadBytes:     s.ReadBytes,
+		WriteBytes:    s.WriteBytes,
+	}
+}
+
+// MetricFromSamples rebuilds the metric of one collection from its rows.
+func MetricFromSamples(rows []HistoricalProcessSample) *ProcessMetric {
+	if len(rows) == 0 {
+		return nil
+	}
+	m := &ProcessMetric{Timestamp: rows[0].Timestamp, TotalProcesses: rows[0].TotalProcesses}
+	cpu := make(map[int]ProcessInfo)
+	memory := make(map[int]ProcessInfo)
+	for _, row := range rows {
+		if row.CPURank > 0 {
+			cpu[row.CPURank] = row.ToProcessInfo()
+		}
+		if row.MemoryRank > 0 {
+			memory[row.MemoryRank] = row.ToProcessInfo()
+		}
+	}
+	m.TopCPU = ranked(cpu)
+	m.TopMemory = ranked(memory)
+	return m
+}
+
+func ranked(byRank map[int]ProcessInfo) []ProcessInfo {
+	out := make([]ProcessInfo, 0, len(byRank))
+	for rank := 1; len(out) < len(byRank); rank++ {
+		if p, ok := byRank[rank]; ok {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+// ProcessMetric is one collection of the busiest processes of a host.
+type ProcessMetric struct {
+	Timestamp time.Time `json:"timestamp"`
+	// TotalProcesses counts every process seen, not only the listed ones
+	TotalProcesses int `json:"total_processes"`
+	// TopCPU is ordered by CPU usage, TopMemory by resident memory; a process may be in both
+	TopCPU    []ProcessInfo `json:"top_cpu"`
+	TopMemory []ProcessInfo `json:"top_memory"`
+}
+
+// ProcessInfo describes one process at collection time.
+type ProcessInfo struct {
+	PID     int32  `json:"pid"`
+	Name    string `json:"name"`
+	Cmdline string `json:"cmdline"`
+	// User is resolved through HOST_ETC/passwd; the numeric uid when it has no entry
+	User string `json:"user"`
+	// CPUPercent is the share of one core used since the previous collection (or since start for new processes)
+	CPUPercent    float64   `json:"cpu_percent"`
+	RSSBytes      uint64    `json:"rss_bytes"`
+	MemoryPercent float64   `json:"memory_percent"`
+	Threads       int32     `json:"threads"`
+	StartedAt     time.Time `json:"started_at"`
+	// OpenFDs, ReadBytes and WriteBytes are nil when the collector may not read them (not running as root)
+	OpenFDs    *int32  `json:"open_fds"`
+	ReadBytes  *uint64 `json:"read_bytes"`
+	WriteBytes *uint64 `json:"write_bytes"`
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/processes/infrastructure/entities"
+)
+
+type ProcessRepository interface {
+	// SaveCurrentMetric stores the listed processes of one collection; a collection already
+	// stored (e.g. pushed twice by an agent) is skipped.
+	SaveCurrentMetric(ctx context.Context, metric entities.ProcessMetric, hostId uint) error
+	// GetLatestByHost rebuilds the most recent stored collection of a host; nil when there is none.
+	GetLatestByHost(ctx context.Context, hostId uint) (*entities.ProcessMetric, error)
+	// GetHistoryByHost returns stored process rows in time order, optionally for one PID or process name.
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, pid int32, name string) ([]entities.HistoricalProcessSample, error)
+}
+
+type processRepository struct {
+	db *gorm.DB
+}
+
+func NewProcessRepository(db *gorm.DB) ProcessRepository {
+	return &processRepository{db: db}
+}
+
+func (r *processRepository) SaveCurrentMetric(ctx context.Context, metric entities.ProcessMetric, hostId uint) error {
+	rows := metric.ToSamples(hostId)
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, 200).Error
+}
+
+func (r *processRepository) GetLatestByHost(ctx context.Context, hostId uint) (*entities.ProcessMetric, error) {
+	var rows []entities.HistoricalProcessSample
+	latest := r.db.Model(&entities.HistoricalProcessSample{}).Select("MAX(timestamp)").Where("host_id = ?", hostId)
+	if err := r.db.WithContext(ctx).
+		Where("host_id = ? AND timestamp = (?)", hostId, latest).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return entities.MetricFromSamples(rows), nil
+}
+
+func (r *processRepository) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, pid int32, name string) ([]entities.HistoricalProcessSample, error) {
+	rows := []entities.HistoricalProcessSample{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if pid > 0 {
+		q = q.Where("pid = ?", pid)
+	}
+	if name != "" {
+		q = q.Where("name = ?", name)
+	}
+	err := q.Order("timestamp ASC, cpu_percent DESC").Find(&rows).Error
+	return rows, err
+}
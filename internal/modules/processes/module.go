@@ -0,0 +1,78 @@
+// Package processes registers the processes module, which lists the processes using the most
+// CPU and resident memory on every collection and keeps a short history of them.
+package processes
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	processservice "system-stats/internal/modules/processes/application"
+	"system-stats/internal/modules/processes/infrastructure/collectors"
+	"system-stats/internal/modules/processes/infrastructure/entities"
+	"system-stats/internal/modules/processes/infrastructure/repositories"
+	handlers "system-stats/internal/modules/processes/presentation"
+)
+
+// Module collects the top PROCESSES_TOP_N processes and serves /processes and /processes/history.
+type Module struct{}
+
+func (Module) Name() string { return "processes" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 6,
+		Name:    "process_samples_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalProcessSample{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalProcessSample{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"process_samples"} }
+
+func (Module) HostTables() []string { return []string{"process_samples"} }
+
+// RetentionLimit keeps process rows for PROCESSES_RETENTION. An invalid value is reported by
+// Build; a disabled module falls back to the default.
+func (Module) RetentionLimit() time.Duration {
+	d, err := collectors.RetentionFromEnv()
+	if err != nil {
+		return collectors.DefaultRetention
+	}
+	return d
+}
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	topN, err := collectors.TopNFromEnv()
+	if err != nil {
+		return registry.Instance{}, err
+	}
+	if _, err := collectors.RetentionFromEnv(); err != nil {
+		return registry.Instance{}, err
+	}
+	service := processservice.NewService(deps.Logger, topN, repositories.NewProcessRepository(deps.DB))
+	handler := handlers.NewProcessHandler(deps.Logger, service, deps.Hosts)
+	// Process lists are not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.ProcessMetric]("processes", service.Collect, service.Save, nil)
+	return registry.Instance{
+		Collector: &collector,
+		Routes: func(r gin.IRoutes) {
+			r.GET("/processes", handler.HandleProcesses)
+			r.GET("/processes/history", handler.HandleProcessHistory)
+		},
+		PushData: registry.PushLatest(service.Latest),
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}, nil
+}
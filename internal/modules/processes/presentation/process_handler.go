@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	processservice "system-stats/internal/modules/processes/application"
+	"system-stats/internal/modules/processes/infrastructure/entities"
+)
+
+// ProcessHandler handles HTTP requests for top process lists.
+type ProcessHandler struct {
+	logger  *log.Logger
+	service processservice.Service
+	hosts   hostservice.Service
+}
+
+// NewProcessHandler creates a new HTTP handler for process endpoints.
+func NewProcessHandler(logger *log.Logger, service processservice.Service, hosts hostservice.Service) *ProcessHandler {
+	return &ProcessHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleProcesses returns the latest top CPU and memory process lists.
+//
+// @Summary     Top processes
+// @Description Returns the processes using the most CPU and resident memory. This server instance answers from its last collection; other hosts from the last collection their agent pushed.
+// @Tags        metrics
+// @Produce     json
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /processes [get]
+func (h *ProcessHandler) HandleProcesses(c *gin.Context) {
+	ctx := c.Request.Context()
+	queryHost := httputil.ParseHostIdQuery(c)
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyProcessesPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for processes", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	remote, err := metricshost.IsRemoteHost(ctx, h.hosts, effective)
+	if err != nil {
+		h.logger.Error("Failed to classify host for processes", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var latest *entities.ProcessMetric
+	if !remote {
+		latest = h.service.Latest()
+	}
+	if latest == nil {
+		latest, err = h.service.GetLatestByHost(ctx, effective)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				h.logger.Info("Client canceled request while fetching processes")
+				return
+			}
+			h.logger.Error("Failed to fetch processes", "error", err, "host_id", effective)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"latest": latest})
+}
+
+// HandleProcessHistory returns stored process rows.
+//
+// @Summary     Process history
+// @Description Returns the processes listed in each stored collection with their CPU and memory ranks. History is kept for PROCESSES_RETENTION.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       pid      query    integer  false  "Limit to one PID"
+// @Param       name     query    string   false  "Limit to one process name"
+// @Success     200      {object} map[string]interface{}
+// @Failure     400      {object} map[string]string
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /processes/history [get]
+func (h *ProcessHandler) HandleProcessHistory(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	var pid int32
+	if raw := c.Query("pid"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "pid must be a positive integer"})
+			return
+		}
+		pid = int32(n)
+	}
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyProcessHistoryPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for process history", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetHistoryByHost(ctx, effective, hours, pid, c.Query("name"))
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching process history")
+			return
+		}
+		h.logger.Error("Failed to fetch process history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
@@ -0,0 +1,71 @@
+package sensors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/modules/sensors/infrastructure/entities"
+)
+
+var sensorLabels = []string{"chip", "sensor"}
+
+var (
+	descSensorValue = map[string]*prometheus.Desc{
+		entities.TypeTemperature: prometheus.NewDesc("system_hwmon_temperature_celsius", "hwmon temperature.", sensorLabels, nil),
+		entities.TypeFan:         prometheus.NewDesc("system_hwmon_fan_rpm", "hwmon fan speed.", sensorLabels, nil),
+		entities.TypeVoltage:     prometheus.NewDesc("system_hwmon_voltage_volts", "hwmon voltage.", sensorLabels, nil),
+		entities.TypeCurrent:     prometheus.NewDesc("system_hwmon_current_amperes", "hwmon current.", sensorLabels, nil),
+		entities.TypePower:       prometheus.NewDesc("system_hwmon_power_watts", "hwmon power, and RAPL power per powercap zone (chip \"rapl\").", sensorLabels, nil),
+	}
+	descSensorThreshold = prometheus.NewDesc("system_hwmon_threshold", "hwmon threshold of a sensor (min, max, crit, lcrit) in the sensor's unit.", []string{"chip", "sensor", "type", "threshold"}, nil)
+	descSensorAlarm     = prometheus.NewDesc("system_hwmon_alarm", "1 when the chip raises the sensor's alarm, else 0.", []string{"chip", "sensor", "type"}, nil)
+)
+
+// PrometheusCollector exports the latest in-memory readings; scrapes never read sysfs.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range descSensorValue {
+		ch <- d
+	}
+	ch <- descSensorThreshold
+	ch <- descSensorAlarm
+}
+
+// Collect sends every reading of the last collection with its thresholds and alarm.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	latest := c.service.Latest()
+	if latest == nil {
+		return
+	}
+	// Chips without a device link may share a name; the first reading of a sensor is exported
+	seen := make(map[[2]string]bool, len(latest.Readings))
+	for _, r := range latest.Readings {
+		desc, ok := descSensorValue[r.Type]
+		sensor := r.Sensor()
+		if !ok || seen[[2]string{sensor, r.Type}] {
+			continue
+		}
+		seen[[2]string{sensor, r.Type}] = true
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, r.Value, r.Chip, sensor)
+		thresholds := map[string]*float64{"min": r.Min, "max": r.Max, "crit": r.Crit, "lcrit": r.LowCrit}
+		for name, v := range thresholds {
+			if v != nil {
+				ch <- prometheus.MustNewConstMetric(descSensorThreshold, prometheus.GaugeValue, *v, r.Chip, sensor, r.Type, name)
+			}
+		}
+		if r.Alarm != nil {
+			alarm := 0.0
+			if *r.Alarm {
+				alarm = 1
+			}
+			ch <- prometheus.MustNewConstMetric(descSensorAlarm, prometheus.GaugeValue, alarm, r.Chip, sensor, r.Type)
+		}
+	}
+}
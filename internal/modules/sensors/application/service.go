@@ -2,29 +2,96 @@ package sensors
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
 
 	"github.com/charmbracelet/log"
 
 	"system-stats/internal/modules/sensors/infrastructure/collectors"
 	"system-stats/internal/modules/sensors/infrastructure/entities"
+	"system-stats/internal/modules/sensors/infrastructure/repositories"
 )
 
+// maxPushReadings bounds the readings accepted from a push
+const maxPushReadings = 1024
+
 type Service interface {
+	// Collect reads the temperatures through gopsutil for the live /sensors response.
 	Collect(ctx context.Context) (entities.TemperatureMetric, error)
+	// CollectReadings reads every hwmon and RAPL sensor and keeps the result in memory.
+	CollectReadings(ctx context.Context) (entities.SensorMetric, error)
+	Save(ctx context.Context, metric entities.SensorMetric, hostId uint) error
+	// Latest returns this instance's last collection (no database access); nil before the first one.
+	Latest() *entities.SensorMetric
+	// ReceivePush stores readings pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalSensorReading, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, sensor, typ string) ([]entities.HistoricalSensorReading, error)
 }
 
 type service struct {
 	logger    *log.Logger
 	collector *collectors.SensorsCollector
+	repo      repositories.SensorRepository
+
+	mu     sync.RWMutex
+	latest *entities.SensorMetric
 }
 
-func NewService(logger *log.Logger) Service {
+func NewService(logger *log.Logger, repo repositories.SensorRepository) Service {
 	return &service{
 		logger:    logger,
 		collector: collectors.NewSensorsCollector(logger),
+		repo:      repo,
 	}
 }
 
 func (s *service) Collect(ctx context.Context) (entities.TemperatureMetric, error) {
 	return s.collector.CollectTemperatures(ctx)
 }
+
+func (s *service) CollectReadings(ctx context.Context) (entities.SensorMetric, error) {
+	metric, err := s.collector.CollectReadings(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	s.latest = &metric
+	s.mu.Unlock()
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.SensorMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "sensors", "error", err, "host_id", hostId)
+		return err
+	}
+	return nil
+}
+
+func (s *service) Latest() *entities.SensorMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+// ReceivePush bounds what an agent may send.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.SensorMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	metric.Readings = metric.Readings[:min(len(metric.Readings), maxPushReadings)]
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalSensorReading, error) {
+	return s.repo.GetLatestByHost(ctx, hostId)
+}
+
+func (s *service) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, sensor, typ string) ([]entities.HistoricalSensorReading, error) {
+	return s.repo.GetHistoryByHost(ctx, hostId, hours, sensor, typ)
+}
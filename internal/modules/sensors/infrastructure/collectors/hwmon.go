@@ -0,0 +1,153 @@
+package collectors
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/sensors/infrastructure/entities"
+)
+
+// hwmonInput matches the input attributes read: temp1_input, fan2_input, in0_input,
+// curr1_input, power1_input and power1_average (drivers without an instantaneous reading).
+var hwmonInput = regexp.MustCompile(`^(temp|fan|in|curr|power)(\d+)_(input|average)$`)
+
+// hwmonTypes maps an attribute prefix to its reading type and the divisor from sysfs units
+// (millidegrees, RPM, millivolts, milliamperes, microwatts) to base units.
+var hwmonTypes = map[string]struct {
+	typ     string
+	divisor float64
+}{
+	"temp":  {entities.TypeTemperature, 1000},
+	"fan":   {entities.TypeFan, 1},
+	"in":    {entities.TypeVoltage, 1000},
+	"curr":  {entities.TypeCurrent, 1000},
+	"power": {entities.TypePower, 1e6},
+}
+
+// ReadHwmon reads every input of every chip in HOST_SYS/class/hwmon, with its label and
+// thresholds; a host without hwmon has none. Inputs that report a fault, are disabled or cannot
+// be read (e.g. a sensor of a powered-down GPU) are skipped.
+func ReadHwmon() ([]entities.SensorReading, error) {
+	root := hostfs.Sys("class", "hwmon")
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var readings []entities.SensorReading
+	for _, e := range entries {
+		dir := filepath.Join(root, e.Name())
+		chip := readTrimmed(filepath.Join(dir, "name"))
+		device := ""
+		if target, err := os.Readlink(filepath.Join(dir, "device")); err == nil {
+			device = filepath.Base(target)
+		}
+		chipReadings := readHwmonDir(dir, chip, device)
+		if len(chipReadings) == 0 {
+			// Drivers of old kernels keep the attributes in the device directory
+			if chip == "" {
+				chip = readTrimmed(filepath.Join(dir, "device", "name"))
+			}
+			chipReadings = readHwmonDir(filepath.Join(dir, "device"), chip, device)
+		}
+		readings = append(readings, chipReadings...)
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		a, b := readings[i], readings[j]
+		if a.Sensor() != b.Sensor() {
+			return a.Sensor() < b.Sensor()
+		}
+		return a.Type < b.Type
+	})
+	return readings, nil
+}
+
+func readHwmonDir(dir, chip, device string) []entities.SensorReading {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	if chip == "" {
+		chip = filepath.Base(dir)
+	}
+	var out []entities.SensorReading
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		m := hwmonInput.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		base := m[1] + m[2]
+		// power1_input and power1_average may both exist; the first listed wins
+		if seen[base] {
+			continue
+		}
+		attr := func(suffix string) (float64, bool) {
+			raw, err := os.ReadFile(filepath.Join(dir, base+"_"+suffix))
+			if err != nil {
+				return 0, false
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(string(raw)), 64)
+			return v, err == nil
+		}
+		if v, ok := attr("fault"); ok && v != 0 {
+			continue
+		}
+		if v, ok := attr("enable"); ok && v == 0 {
+			continue
+		}
+		kind := hwmonTypes[m[1]]
+		raw, ok := attr(m[3])
+		if !ok {
+			continue
+		}
+		seen[base] = true
+		scaled := func(suffix string) *float64 {
+			if v, ok := attr(suffix); ok {
+				v /= kind.divisor
+				return &v
+			}
+			return nil
+		}
+		r := entities.SensorReading{
+			Chip:    chip,
+			Device:  device,
+			Label:   base,
+			Type:    kind.typ,
+			Value:   raw / kind.divisor,
+			Min:     scaled("min"),
+			Max:     scaled("max"),
+			Crit:    scaled("crit"),
+			LowCrit: scaled("lcrit"),
+		}
+		if label := readTrimmed(filepath.Join(dir, base+"_label")); label != "" {
+			r.Label = label
+		}
+		// Chips have either one alarm flag per input or one per threshold
+		for _, suffix := range []string{"alarm", "min_alarm", "max_alarm", "crit_alarm", "lcrit_alarm"} {
+			if v, ok := attr(suffix); ok {
+				alarm := v != 0 || (r.Alarm != nil && *r.Alarm)
+				r.Alarm = &alarm
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func readTrimmed(path string) string {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
@@ -0,0 +1,94 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/sensors/infrastructure/entities"
+)
+
+// raplSample is the energy counter of a powercap zone at one collection.
+type raplSample struct {
+	at       time.Time
+	energyUJ uint64
+}
+
+// raplZone is a powercap zone with its current energy counter.
+type raplZone struct {
+	id         string
+	name       string
+	energyUJ   uint64
+	maxRangeUJ uint64
+}
+
+// readRAPLZones reads the RAPL zones and subzones (intel-rapl:0, intel-rapl:0:1, ...; AMD CPUs use
+// the same driver) from HOST_SYS/class/powercap. energy_uj is root-only on most kernels, so
+// without root there are no zones.
+func readRAPLZones() []raplZone {
+	root := hostfs.Sys("class", "powercap")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+	var zones []raplZone
+	for _, e := range entries {
+		id := e.Name()
+		if !strings.HasPrefix(id, "intel-rapl") || !strings.Contains(id, ":") {
+			continue
+		}
+		dir := filepath.Join(root, id)
+		energy, err := strconv.ParseUint(readTrimmed(filepath.Join(dir, "energy_uj")), 10, 64)
+		if err != nil {
+			continue
+		}
+		maxRange, _ := strconv.ParseUint(readTrimmed(filepath.Join(dir, "max_energy_range_uj")), 10, 64)
+		name := readTrimmed(filepath.Join(dir, "name"))
+		if name == "" {
+			name = id
+		}
+		zones = append(zones, raplZone{id: id, name: name, energyUJ: energy, maxRangeUJ: maxRange})
+	}
+	return zones
+}
+
+// RAPLWatts converts two energy counter readings taken elapsed apart into watts. The counter
+// wraps at maxRangeUJ; a counter that went backwards without a known range yields ok=false.
+func RAPLWatts(prevUJ, curUJ, maxRangeUJ uint64, elapsed time.Duration) (watts float64, ok bool) {
+	if elapsed <= 0 {
+		return 0, false
+	}
+	delta := curUJ - prevUJ
+	if curUJ < prevUJ {
+		if maxRangeUJ == 0 || prevUJ > maxRangeUJ {
+			return 0, false
+		}
+		delta = maxRangeUJ - prevUJ + curUJ
+	}
+	return float64(delta) / 1e6 / elapsed.Seconds(), true
+}
+
+// raplReadings turns the zones into power readings against the previous collection; a zone
+// seen for the first time has no reading yet.
+func raplReadings(zones []raplZone, prev map[string]raplSample, now time.Time, maxGap time.Duration) []entities.SensorReading {
+	var out []entities.SensorReading
+	for _, z := range zones {
+		p, ok := prev[z.id]
+		if !ok || now.Sub(p.at) > maxGap {
+			continue
+		}
+		if watts, ok := RAPLWatts(p.energyUJ, z.energyUJ, z.maxRangeUJ, now.Sub(p.at)); ok {
+			out = append(out, entities.SensorReading{
+				Chip:   entities.ChipRAPL,
+				Device: z.id,
+				Label:  z.name,
+				Type:   entities.TypePower,
+				Value:  watts,
+			})
+		}
+	}
+	return out
+}
@@ -2,6 +2,7 @@ package collectors
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -10,12 +11,45 @@ import (
 	"system-stats/internal/modules/sensors/infrastructure/entities"
 )
 
+// raplMaxGap is the longest interval between two RAPL samples that still yields a power reading;
+// the energy counters wrap within minutes on busy servers
+const raplMaxGap = 10 * time.Minute
+
 type SensorsCollector struct {
 	logger *log.Logger
+
+	mu sync.Mutex
+	// rapl holds the previous energy counter per powercap zone
+	rapl map[string]raplSample
 }
 
 func NewSensorsCollector(logger *log.Logger) *SensorsCollector {
-	return &SensorsCollector{logger: logger}
+	return &SensorsCollector{logger: logger, rapl: make(map[string]raplSample)}
+}
+
+// CollectReadings reads every hwmon input (temperatures, fans, voltages, currents, power) with
+// its thresholds and the RAPL power of each powercap zone, averaged since the previous call.
+func (c *SensorsCollector) CollectReadings(ctx context.Context) (entities.SensorMetric, error) {
+	c.logger.Debug("Collecting hwmon and RAPL sensors")
+	now := time.Now().UTC()
+	readings, err := ReadHwmon()
+	if err != nil {
+		c.logger.Warn("Failed to read hwmon sensors", "error", err)
+	}
+
+	zones := readRAPLZones()
+	c.mu.Lock()
+	readings = append(readings, raplReadings(zones, c.rapl, now, raplMaxGap)...)
+	c.rapl = make(map[string]raplSample, len(zones))
+	for _, z := range zones {
+		c.rapl[z.id] = raplSample{at: now, energyUJ: z.energyUJ}
+	}
+	c.mu.Unlock()
+
+	if readings == nil {
+		readings = []entities.SensorReading{}
+	}
+	return entities.SensorMetric{Timestamp: now, Readings: readings}, ctx.Err()
 }
 
 func (c *SensorsCollector) CollectTemperatures(ctx context.Context) (entities.TemperatureMetric, error) {
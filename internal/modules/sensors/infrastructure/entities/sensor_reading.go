@@ -0,0 +1,98 @@
+package entities
+
+import "time"
+
+// Sensor reading types and their units.
+const (
+	TypeTemperature = "temperature" // °C
+	TypeFan         = "fan"         // RPM
+	TypeVoltage     = "voltage"     // V
+	TypeCurrent     = "current"     // A
+	TypePower       = "power"       // W
+)
+
+// ChipRAPL is the chip name of the RAPL power readings from powercap.
+const ChipRAPL = "rapl"
+
+// SensorMetric is one collection of every hwmon and RAPL reading.
+type SensorMetric struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Readings  []SensorReading `json:"readings"`
+}
+
+// SensorReading is one hwmon input (or RAPL zone) with its thresholds, converted to base units.
+// Thresholds the chip does not expose are nil.
+type SensorReading struct {
+	// Chip is the hwmon driver name, e.g. coretemp, nct6798 or nvme; "rapl" for powercap zones
+	Chip string `json:"chip"`
+	// Device is the device the chip belongs to, e.g. coretemp.0, nvme0 or intel-rapl:0
+	Device string `json:"device,omitempty"`
+	// Label is the input's label (e.g. "Package id 0", "CPU Fan"), or its name (temp1) without one
+	Label string  `json:"label"`
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Crit    *float64 `json:"crit,omitempty"`
+	LowCrit *float64 `json:"lcrit,omitempty"`
+	// Alarm is the chip's alarm flag for the input, when it has one
+	Alarm *bool `json:"alarm,omitempty"`
+}
+
+// Sensor identifies the reading within its host: "<chip>[-<device>]/<label>".
+func (r SensorReading) Sensor() string {
+	chip := r.Chip
+	if r.Device != "" {
+		chip += "-" + r.Device
+	}
+	return chip + "/" + r.Label
+}
+
+// HistoricalSensorReading stores one reading at one collection.
+type HistoricalSensorReading struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_sensor_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_sensor_host_ts,priority:2"`
+	// Sensor is SensorReading.Sensor(); labels may repeat across types, so the type is part of the key
+	Sensor string  `json:"sensor" gorm:"primaryKey;size:255"`
+	Type   string  `json:"type" gorm:"primaryKey;size:16"`
+	Chip   string  `json:"chip" gorm:"size:64"`
+	Value  float64 `json:"value"`
+
+	Min     *float64 `json:"min"`
+	Max     *float64 `json:"max"`
+	Crit    *float64 `json:"crit"`
+	LowCrit *float64 `json:"lcrit" gorm:"column:low_crit"`
+	Alarm   *bool    `json:"alarm"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalSensorReading) TableName() string { return "sensor_readings" }
+
+// ToRows flattens a metric into one row per reading.
+func (m SensorMetric) ToRows(hostId uint) []HistoricalSensorReading {
+	at := m.Timestamp.UTC().Truncate(time.Microsecond)
+	rows := make([]HistoricalSensorReading, 0, len(m.Readings))
+	seen := make(map[[2]string]bool, len(m.Readings))
+	for _, r := range m.Readings {
+		key := [2]string{r.Sensor(), r.Type}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		rows = append(rows, HistoricalSensorReading{
+			HostID:    hostId,
+			Timestamp: at,
+			Sensor:    key[0],
+			Type:      r.Type,
+			Chip:      r.Chip,
+			Value:     r.Value,
+			Min:       r.Min,
+			Max:       r.Max,
+			Crit:      r.Crit,
+			LowCrit:   r.LowCrit,
+			Alarm:     r.Alarm,
+		})
+	}
+	return rows
+}
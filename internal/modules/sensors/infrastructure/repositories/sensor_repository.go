@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/sensors/infrastructure/entities"
+)
+
+type SensorRepository interface {
+	// SaveCurrentMetric stores one row per reading; a collection already stored (e.g. pushed twice) is skipped.
+	SaveCurrentMetric(ctx context.Context, metric entities.SensorMetric, hostId uint) error
+	// GetLatestByHost returns the readings of the most recent collection of a host.
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalSensorReading, error)
+	// GetHistoryByHost returns readings in time order, optionally for one sensor and/or type.
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, sensor, typ string) ([]entities.HistoricalSensorReading, error)
+}
+
+type sensorRepository struct {
+	db *gorm.DB
+}
+
+func NewSensorRepository(db *gorm.DB) SensorRepository {
+	return &sensorRepository{db: db}
+}
+
+func (r *sensorRepository) SaveCurrentMetric(ctx context.Context, metric entities.SensorMetric, hostId uint) error {
+	rows := metric.ToRows(hostId)
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *sensorRepository) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalSensorReading, error) {
+	rows := []entities.HistoricalSensorReading{}
+	latest := r.db.Model(&entities.HistoricalSensorReading{}).Select("MAX(timestamp)").Where("host_id = ?", hostId)
+	err := r.db.WithContext(ctx).
+		Where("host_id = ? AND timestamp = (?)", hostId, latest).
+		Order("type ASC, sensor ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *sensorRepository) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, sensor, typ string) ([]entities.HistoricalSensorReading, error) {
+	rows := []entities.HistoricalSensorReading{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if sensor != "" {
+		q = q.Where("sensor = ?", sensor)
+	}
+	if typ != "" {
+		q = q.Where("type = ?", typ)
+	}
+	err := q.Order("timestamp ASC, type ASC, sensor ASC").Find(&rows).Error
+	return rows, err
+}
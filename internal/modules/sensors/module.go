@@ -0,0 +1,59 @@
+// Package sensors registers the hardware sensors module.
+package sensors
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	sensorsservice "system-stats/internal/modules/sensors/application"
+	"system-stats/internal/modules/sensors/infrastructure/entities"
+	"system-stats/internal/modules/sensors/infrastructure/repositories"
+	handlers "system-stats/internal/modules/sensors/presentation"
+)
+
+// Module collects hwmon and RAPL readings into sensor_readings and serves /sensors with the
+// live temperatures of this instance.
+type Module struct{}
+
+func (Module) Name() string { return "sensors" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 17,
+		Name:    "sensor_readings_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalSensorReading{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalSensorReading{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"sensor_readings"} }
+
+func (Module) HostTables() []string { return []string{"sensor_readings"} }
+
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := sensorsservice.NewService(deps.Logger, repositories.NewSensorRepository(deps.DB))
+	handler := handlers.NewSensorsHandler(deps.Logger, service, deps.Hosts)
+	// Sensor readings are not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.SensorMetric]("sensors", service.CollectReadings, service.Save, nil)
+	return registry.Instance{
+		Collector: &collector,
+		Routes: func(r gin.IRoutes) {
+			r.GET("/sensors", handler.HandleSensors)
+		},
+		Metrics:  sensorsservice.NewPrometheusCollector(service),
+		PushData: registry.PushLatest(service.Latest),
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}, nil
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/gin-gonic/gin"
 
+	"system-stats/internal/app/httputil"
 	"system-stats/internal/app/metricshost"
 	hostservice "system-stats/internal/modules/hosts/application"
 	sensorssrv "system-stats/internal/modules/sensors/application"
@@ -33,13 +35,16 @@ func parseHostIdQuery(c *gin.Context) uint {
 	return uint(hostId)
 }
 
-// HandleSensors returns temperature sensor readings.
+// HandleSensors returns live temperatures and the stored hwmon and RAPL readings.
 //
 // @Summary     Sensor readings
-// @Description Returns temperature sensor data for this server instance only (Linux). Empty for remote hosts or non-Linux.
+// @Description Returns `sensors`, the live temperatures of this server instance (Linux; null for remote hosts), and from the stored collections `latest` and `history` of every hwmon input (temperature °C, fan RPM, voltage V, current A, power W with min / max / crit / lcrit thresholds and alarm flag) and the RAPL power of each powercap zone (chip "rapl"), also for hosts pushing the sensors module.
 // @Tags        metrics
 // @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
 // @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       sensor   query    string   false  "Limit history to one sensor (<chip>[-<device>]/<label>)"
+// @Param       type     query    string   false  "Limit history to one type (temperature, fan, voltage, current, power)"
 // @Success     200      {object} map[string]interface{}
 // @Failure     401      {object} map[string]string
 // @Failure     500      {object} map[string]string
@@ -48,6 +53,7 @@ func parseHostIdQuery(c *gin.Context) uint {
 func (h *SensorsHandler) HandleSensors(c *gin.Context) {
 	ctx := c.Request.Context()
 	queryHost := parseHostIdQuery(c)
+	hours := httputil.ParseHoursQuery(c)
 
 	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
 	if errors.Is(err, metricshost.ErrHostNotFound) {
@@ -66,17 +72,44 @@ func (h *SensorsHandler) HandleSensors(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if remote {
-		c.JSON(http.StatusOK, metricshost.EmptySensorsPayload())
+
+	h.logger.Debug("Handling sensors request", "host_id", effective)
+	var live any
+	if !remote {
+		metric, err := h.service.Collect(ctx)
+		if err != nil {
+			h.logger.Error("Failed to collect sensors", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		live = metric
+	}
+
+	latest, err := h.service.GetLatestByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest sensor readings")
+			return
+		}
+		h.logger.Error("Failed to fetch latest sensor readings", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	h.logger.Debug("Handling sensors request", "host_id", effective)
-	metric, err := h.service.Collect(ctx)
+	history, err := h.service.GetHistoryByHost(ctx, effective, hours, c.Query("sensor"), c.Query("type"))
 	if err != nil {
-		h.logger.Error("Failed to collect sensors", "error", err)
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching sensor history")
+			return
+		}
+		h.logger.Error("Failed to fetch sensor history", "error", err, "hours", hours, "host_id", effective)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"sensors": metric})
+
+	c.JSON(http.StatusOK, gin.H{
+		"sensors": live,
+		"latest":  latest,
+		"history": history,
+	})
 }
@@ -0,0 +1,41 @@
+package smartmetrics
+
+import (
+	"context"
+
+	"system-stats/internal/modules/smart/infrastructure/entities"
+)
+
+// DiskIO is the latest I/O rate sample of a block device from the disk module.
+type DiskIO struct {
+	ReadIOPS          float64 `json:"read_iops"`
+	WriteIOPS         float64 `json:"write_iops"`
+	ReadBytesPerSec   float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec  float64 `json:"write_bytes_per_sec"`
+	AvgReadLatencyMs  float64 `json:"avg_read_latency_ms"`
+	AvgWriteLatencyMs float64 `json:"avg_write_latency_ms"`
+	UtilPercent       float64 `json:"util_percent"`
+}
+
+// DiskIOSource returns the latest I/O sample of each of a host's block devices, by device name.
+type DiskIOSource func(ctx context.Context, hostId uint) (map[string]DiskIO, error)
+
+// SmartDiskStatus is the latest SMART row of a disk with the current I/O of its block device.
+type SmartDiskStatus struct {
+	entities.HistoricalSmartSample
+	// IO is nil when the disk matched no block device or the disk module has no recent sample
+	IO *DiskIO `json:"io"`
+}
+
+// withIO pairs every row with the I/O sample of its block device.
+func withIO(rows []entities.HistoricalSmartSample, io map[string]DiskIO) []SmartDiskStatus {
+	out := make([]SmartDiskStatus, 0, len(rows))
+	for _, row := range rows {
+		status := SmartDiskStatus{HistoricalSmartSample: row}
+		if sample, ok := io[row.IODevice]; ok && row.IODevice != "" {
+			status.IO = &sample
+		}
+		out = append(out, status)
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package smartmetrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var smartLabels = []string{"device", "serial", "model"}
+
+var (
+	descSmartHealthy     = prometheus.NewDesc("system_smart_healthy", "1 when the disk's SMART self-assessment passed, else 0.", smartLabels, nil)
+	descSmartTemperature = prometheus.NewDesc("system_smart_temperature_celsius", "Disk temperature reported by SMART.", smartLabels, nil)
+	descSmartPowerOn     = prometheus.NewDesc("system_smart_power_on_hours", "Disk power-on hours.", smartLabels, nil)
+	descSmartUsed        = prometheus.NewDesc("system_smart_percentage_used", "Estimated share of the disk's rated endurance used.", smartLabels, nil)
+	descSmartSpare       = prometheus.NewDesc("system_smart_available_spare_percent", "NVMe spare capacity remaining.", smartLabels, nil)
+	descSmartWritten     = prometheus.NewDesc("system_smart_bytes_written_total", "Bytes written to the disk by the host over its lifetime.", smartLabels, nil)
+	descSmartErrors      = prometheus.NewDesc("system_smart_error_count", "SMART error and defect counters (reallocated, pending, offline_uncorrectable, reported_uncorrectable, crc, media).", []string{"device", "serial", "model", "type"}, nil)
+)
+
+// PrometheusCollector exports the latest in-memory SMART data; scrapes never run smartctl.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descSmartHealthy
+	ch <- descSmartTemperature
+	ch <- descSmartPowerOn
+	ch <- descSmartUsed
+	ch <- descSmartSpare
+	ch <- descSmartWritten
+	ch <- descSmartErrors
+}
+
+// Collect sends the SMART data of every disk of the last collection; attributes a disk does not
+// report are left out.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	latest := c.service.Latest()
+	if latest == nil {
+		return
+	}
+	for _, d := range latest.Disks {
+		labels := []string{d.Device, d.Serial, d.Model}
+		if d.Passed != nil {
+			healthy := 0.0
+			if *d.Passed {
+				healthy = 1
+			}
+			ch <- prometheus.MustNewConstMetric(descSmartHealthy, prometheus.GaugeValue, healthy, labels...)
+		}
+		gauge(ch, descSmartTemperature, d.TemperatureCelsius, labels)
+		gauge(ch, descSmartUsed, d.PercentageUsed, labels)
+		gauge(ch, descSmartSpare, d.AvailableSparePercent, labels)
+		if d.PowerOnHours != nil {
+			ch <- prometheus.MustNewConstMetric(descSmartPowerOn, prometheus.GaugeValue, float64(*d.PowerOnHours), labels...)
+		}
+		if d.BytesWritten != nil {
+			ch <- prometheus.MustNewConstMetric(descSmartWritten, prometheus.CounterValue, float64(*d.BytesWritten), labels...)
+		}
+		counters := map[string]*uint64{
+			"reallocated":            d.ReallocatedSectors,
+			"pending":                d.PendingSectors,
+			"offline_uncorrectable":  d.OfflineUncorrectable,
+			"reported_uncorrectable": d.ReportedUncorrectable,
+			"crc":                    d.CRCErrors,
+			"media":                  d.MediaErrors,
+		}
+		for kind, v := range counters {
+			if v != nil {
+				ch <- prometheus.MustNewConstMetric(descSmartErrors, prometheus.GaugeValue, float64(*v), append(labels, kind)...)
+			}
+		}
+	}
+}
+
+func gauge(ch chan<- prometheus.Metric, desc *prometheus.Desc, v *float64, labels []string) {
+	if v != nil {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *v, labels...)
+	}
+}
@@ -0,0 +1,108 @@
+package smartmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/modules/smart/infrastructure/collectors"
+	"system-stats/internal/modules/smart/infrastructure/entities"
+	"system-stats/internal/modules/smart/infrastructure/repositories"
+)
+
+// maxPushDisks bounds the disks accepted from a push
+const maxPushDisks = 256
+
+type Service interface {
+	// Collect returns the latest SMART data of every disk and keeps it in memory.
+	Collect(ctx context.Context) (entities.SmartMetric, error)
+	// Save stores the disk checks not stored yet.
+	Save(ctx context.Context, metric entities.SmartMetric, hostId uint) error
+	// Latest returns this instance's last collection (no database access); nil before the first one.
+	Latest() *entities.SmartMetric
+	// ReceivePush stores SMART data pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	// GetLatestByHost returns the latest check of every disk with the I/O of its block device.
+	GetLatestByHost(ctx context.Context, hostId uint) ([]SmartDiskStatus, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, diskId string) ([]entities.HistoricalSmartSample, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.SmartCollector
+	repo      repositories.SmartRepository
+	diskIO    DiskIOSource
+
+	mu     sync.RWMutex
+	latest *entities.SmartMetric
+}
+
+func NewService(logger *log.Logger, repo repositories.SmartRepository, cfg collectors.Config, diskIO DiskIOSource) Service {
+	return &service{
+		logger:    logger,
+		collector: collectors.NewSmartCollector(logger, cfg),
+		repo:      repo,
+		diskIO:    diskIO,
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.SmartMetric, error) {
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	s.latest = &metric
+	s.mu.Unlock()
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.SmartMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "smart", "error", err, "host_id", hostId)
+		return err
+	}
+	return nil
+}
+
+func (s *service) Latest() *entities.SmartMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+// ReceivePush bounds what an agent may send; unchanged checks pushed again are skipped on save.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.SmartMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	disks := make([]entities.SmartDisk, 0, len(metric.Disks))
+	for _, d := range metric.Disks[:min(len(metric.Disks), maxPushDisks)] {
+		if !d.CheckedAt.IsZero() && d.DiskID() != "" {
+			disks = append(disks, d)
+		}
+	}
+	metric.Disks = disks
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) ([]SmartDiskStatus, error) {
+	rows, err := s.repo.GetLatestByHost(ctx, hostId)
+	if err != nil {
+		return nil, err
+	}
+	var io map[string]DiskIO
+	if s.diskIO != nil && len(rows) > 0 {
+		if io, err = s.diskIO(ctx, hostId); err != nil {
+			return nil, err
+		}
+	}
+	return withIO(rows, io), nil
+}
+
+func (s *service) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, diskId string) ([]entities.HistoricalSmartSample, error) {
+	return s.repo.GetHistoryByHost(ctx, hostId, hours, diskId)
+}
@@ -0,0 +1,62 @@
+package collectors
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DevicesEnv lists the devices to check as comma-separated paths, each optionally followed by
+	// ":<smartctl -d type>" (e.g. "/dev/sda,/dev/sdb:sat"); devices are discovered with
+	// `smartctl --scan` when it is unset
+	DevicesEnv = "SMART_DEVICES"
+	// JSONDirEnv names a directory of `smartctl --json -a` output files (one *.json per disk) read
+	// instead of running smartctl, for containers without access to the disks
+	JSONDirEnv = "SMART_JSON_DIR"
+	// IntervalEnv sets the minimum time between smartctl runs
+	IntervalEnv = "SMART_INTERVAL"
+	// DefaultInterval checks disks twice an hour; SMART attributes change slowly
+	DefaultInterval = 30 * time.Minute
+)
+
+// Device is a disk to check and the smartctl device type to read it with ("" lets smartctl guess).
+type Device struct {
+	Path string `json:"path"`
+	Type string `json:"type,omitempty"`
+}
+
+// Config is the SMART collector configuration.
+type Config struct {
+	Devices  []Device
+	JSONDir  string
+	Interval time.Duration
+}
+
+// ConfigFromEnv reads SMART_DEVICES, SMART_JSON_DIR and SMART_INTERVAL.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		JSONDir:  strings.TrimSpace(os.Getenv(JSONDirEnv)),
+		Interval: DefaultInterval,
+	}
+	for _, item := range strings.Split(os.Getenv(DevicesEnv), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		path, typ, _ := strings.Cut(item, ":")
+		if !strings.HasPrefix(path, "/dev/") {
+			return Config{}, fmt.Errorf("%s entries must be device paths under /dev, got %q", DevicesEnv, item)
+		}
+		cfg.Devices = append(cfg.Devices, Device{Path: path, Type: typ})
+	}
+	if raw := strings.TrimSpace(os.Getenv(IntervalEnv)); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return Config{}, fmt.Errorf("%s must be a positive duration like \"1h\", got %q", IntervalEnv, raw)
+		}
+		cfg.Interval = d
+	}
+	return cfg, nil
+}
@@ -0,0 +1,221 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/shirou/gopsutil/v4/disk"
+
+	"system-stats/internal/modules/smart/infrastructure/entities"
+)
+
+const (
+	// smartctlTimeout bounds one smartctl run; a disk that does not answer must not hold up the rest
+	smartctlTimeout = 30 * time.Second
+	// intervalSlack lets a run happen on the tick that is due a little early because of jitter
+	intervalSlack = 500 * time.Millisecond
+)
+
+// Available reports whether disks can be checked: SMART_JSON_DIR is set or smartctl is on PATH.
+func Available(cfg Config) bool {
+	if cfg.JSONDir != "" {
+		return true
+	}
+	_, err := exec.LookPath("smartctl")
+	return err == nil
+}
+
+// SmartCollector runs smartctl (or reads its saved JSON output) and keeps the latest result of
+// every disk.
+type SmartCollector struct {
+	logger *log.Logger
+	cfg    Config
+
+	mu      sync.Mutex
+	lastRun time.Time
+	disks   map[string]entities.SmartDisk
+}
+
+// NewSmartCollector creates a collector for cfg (see ConfigFromEnv).
+func NewSmartCollector(logger *log.Logger, cfg Config) *SmartCollector {
+	return &SmartCollector{logger: logger, cfg: cfg, disks: make(map[string]entities.SmartDisk)}
+}
+
+// Collect returns the latest result of every disk. smartctl runs at most once per interval; in
+// between, and for a disk that could not be read (e.g. one in standby), the previous result is
+// returned with its original CheckedAt. JSON files are re-read every time and stamped with
+// their modification time.
+func (c *SmartCollector) Collect(ctx context.Context) (entities.SmartMetric, error) {
+	now := time.Now().UTC()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case c.cfg.JSONDir != "":
+		disks, err := c.readJSONDir()
+		if err != nil {
+			return entities.SmartMetric{}, err
+		}
+		c.disks = disks
+		c.linkIODevices(ctx)
+	case c.lastRun.IsZero() || now.Sub(c.lastRun)+intervalSlack >= c.cfg.Interval:
+		c.lastRun = now
+		c.runSmartctl(ctx, now)
+		c.linkIODevices(ctx)
+	}
+
+	metric := entities.SmartMetric{Timestamp: now, Disks: make([]entities.SmartDisk, 0, len(c.disks))}
+	for _, d := range c.disks {
+		metric.Disks = append(metric.Disks, d)
+	}
+	sort.Slice(metric.Disks, func(i, j int) bool { return metric.Disks[i].Device < metric.Disks[j].Device })
+	return metric, ctx.Err()
+}
+
+// runSmartctl checks the configured (or discovered) devices. Disks no longer listed are dropped.
+func (c *SmartCollector) runSmartctl(ctx context.Context, now time.Time) {
+	devices := c.cfg.Devices
+	if len(devices) == 0 {
+		out, err := exec.CommandContext(ctx, "smartctl", "--scan", "--json").Output()
+		if len(out) == 0 {
+			c.logger.Warn("Failed to list SMART devices", "error", err)
+			return
+		}
+		if devices, err = ParseScan(out); err != nil {
+			c.logger.Warn("Failed to list SMART devices", "error", err)
+			return
+		}
+	}
+
+	listed := make(map[string]bool, len(devices))
+	for _, dev := range devices {
+		listed[dev.Path] = true
+		d, err := c.check(ctx, dev)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if strings.Contains(err.Error(), "STANDBY") {
+				c.logger.Debug("Disk in standby, keeping previous SMART data", "device", dev.Path)
+			} else {
+				c.logger.Warn("Failed to read SMART data", "device", dev.Path, "error", err)
+			}
+			continue
+		}
+		d.Device = dev.Path
+		d.CheckedAt = now
+		c.disks[dev.Path] = d
+	}
+	for path := range c.disks {
+		if !listed[path] {
+			delete(c.disks, path)
+		}
+	}
+}
+
+// check runs smartctl for one device. "-n standby" leaves sleeping disks asleep.
+func (c *SmartCollector) check(parent context.Context, dev Device) (entities.SmartDisk, error) {
+	ctx, cancel := context.WithTimeout(parent, smartctlTimeout)
+	defer cancel()
+	args := []string{"--json", "-a", "-n", "standby"}
+	if dev.Type != "" {
+		args = append(args, "-d", dev.Type)
+	}
+	args = append(args, dev.Path)
+	// smartctl exits non-zero for failing disks too; the exit status is also in the JSON
+	out, err := exec.CommandContext(ctx, "smartctl", args...).Output()
+	if len(out) == 0 {
+		if err == nil {
+			err = errors.New("no output")
+		}
+		return entities.SmartDisk{}, fmt.Errorf("run smartctl: %w", err)
+	}
+	return ParseSmartctl(out)
+}
+
+// readJSONDir parses every *.json file in SMART_JSON_DIR. Files that do not parse are skipped.
+func (c *SmartCollector) readJSONDir() (map[string]entities.SmartDisk, error) {
+	paths, err := filepath.Glob(filepath.Join(c.cfg.JSONDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	disks := make(map[string]entities.SmartDisk, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			c.logger.Warn("Failed to read SMART file", "path", path, "error", err)
+			continue
+		}
+		d, err := ParseSmartctl(raw)
+		if err != nil {
+			c.logger.Warn("Failed to parse SMART file", "path", path, "error", err)
+			continue
+		}
+		if d.Device == "" {
+			d.Device = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		d.CheckedAt = info.ModTime().UTC()
+		disks[d.Device] = d
+	}
+	return disks, nil
+}
+
+// linkIODevices sets the block device of every disk from the serial numbers of the host's I/O
+// counters, so SMART data can be shown next to the disk module's I/O statistics.
+func (c *SmartCollector) linkIODevices(ctx context.Context) {
+	counters, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		c.logger.Debug("Failed to read I/O counters for SMART disks", "error", err)
+		return
+	}
+	serials := make(map[string]string, len(counters))
+	for name, io := range counters {
+		serials[name] = io.SerialNumber
+	}
+	for path, d := range c.disks {
+		d.IODevice = MatchIODevice(d, serials)
+		c.disks[path] = d
+	}
+}
+
+// MatchIODevice returns the block device (from device name to serial number, as in gopsutil's
+// I/O counters) that is the disk. Linux reports udev's ID_SERIAL, "<model>_<serial>" with
+// spaces replaced, so a serial matches exactly or as that suffix. Partitions share the disk's
+// serial; the shortest name is the whole disk. Without a serial match the device path decides
+// (/dev/sda is sda, /dev/nvme0 is nvme0n1).
+func MatchIODevice(d entities.SmartDisk, serials map[string]string) string {
+	best := ""
+	if serial := strings.ReplaceAll(d.Serial, " ", "_"); serial != "" {
+		for name, s := range serials {
+			if s != serial && !strings.HasSuffix(s, "_"+serial) {
+				continue
+			}
+			if best == "" || len(name) < len(best) || (len(name) == len(best) && name < best) {
+				best = name
+			}
+		}
+	}
+	if best != "" {
+		return best
+	}
+	base := filepath.Base(d.Device)
+	for _, name := range []string{base, base + "n1"} {
+		if _, ok := serials[name]; ok {
+			return name
+		}
+	}
+	return ""
+}
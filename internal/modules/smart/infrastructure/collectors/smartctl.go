@@ -0,0 +1,204 @@
+package collectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"system-stats/internal/modules/smart/infrastructure/entities"
+)
+
+const (
+	// exitCommandLine and exitDeviceOpen are the smartctl exit status bits for runs that read no
+	// data; the remaining bits report disk problems alongside valid output
+	exitCommandLine = 1 << 0
+	exitDeviceOpen  = 1 << 1
+	// nvmeDataUnit is the size of an NVMe "data unit" (1000 512-byte blocks)
+	nvmeDataUnit = 512 * 1000
+)
+
+// ATA attribute IDs read into the common schema.
+const (
+	ataReallocatedSectors    = 5
+	ataReportedUncorrectable = 187
+	ataPendingSectors        = 197
+	ataOfflineUncorrectable  = 198
+	ataCRCErrors             = 199
+	ataTotalLBAsWritten      = 241
+)
+
+// ataWearAttributes are vendor SSD attributes whose normalised value counts down from 100 as the
+// rated endurance is used, in order of preference.
+var ataWearAttributes = []int{
+	231, // SSD_Life_Left
+	233, // Media_Wearout_Indicator (Intel)
+	177, // Wear_Leveling_Count (Samsung)
+	202, // Percent_Lifetime_Remain (Micron/Crucial)
+}
+
+type smartctlOutput struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String   string `json:"string"`
+			Severity string `json:"severity"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	Device struct {
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		Protocol string `json:"protocol"`
+	} `json:"device"`
+	ModelName       string `json:"model_name"`
+	ScsiModelName   string `json:"scsi_model_name"`
+	SerialNumber    string `json:"serial_number"`
+	FirmwareVersion string `json:"firmware_version"`
+	UserCapacity    struct {
+		Bytes uint64 `json:"bytes"`
+	} `json:"user_capacity"`
+	LogicalBlockSize uint64 `json:"logical_block_size"`
+	SmartStatus      *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature *struct {
+		Current *float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime *struct {
+		Hours uint64 `json:"hours"`
+	} `json:"power_on_time"`
+	PowerCycleCount    *uint64 `json:"power_cycle_count"`
+	ATASmartAttributes *struct {
+		Table []ataAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeLog *struct {
+		CriticalWarning  *uint64  `json:"critical_warning"`
+		AvailableSpare   *float64 `json:"available_spare"`
+		PercentageUsed   *float64 `json:"percentage_used"`
+		DataUnitsWritten *uint64  `json:"data_units_written"`
+		MediaErrors      *uint64  `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+	SCSIGrownDefectList *uint64 `json:"scsi_grown_defect_list"`
+}
+
+type ataAttribute struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Value      int    `json:"value"`
+	WhenFailed string `json:"when_failed"`
+	Raw        struct {
+		Value uint64 `json:"value"`
+	} `json:"raw"`
+}
+
+// ParseSmartctl normalises the output of `smartctl --json -a` (smartctl 7.0+) for one disk. It
+// fails when smartctl could not read the disk, e.g. because it is in standby.
+func ParseSmartctl(raw []byte) (entities.SmartDisk, error) {
+	var out smartctlOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return entities.SmartDisk{}, fmt.Errorf("parse smartctl output: %w", err)
+	}
+	if out.Smartctl.ExitStatus&(exitCommandLine|exitDeviceOpen) != 0 {
+		var msgs []string
+		for _, m := range out.Smartctl.Messages {
+			msgs = append(msgs, m.String)
+		}
+		return entities.SmartDisk{}, fmt.Errorf("smartctl exit status %d: %s", out.Smartctl.ExitStatus, strings.Join(msgs, "; "))
+	}
+
+	disk := entities.SmartDisk{
+		Device:        out.Device.Name,
+		Protocol:      out.Device.Protocol,
+		Model:         out.ModelName,
+		Serial:        strings.TrimSpace(out.SerialNumber),
+		Firmware:      out.FirmwareVersion,
+		CapacityBytes: out.UserCapacity.Bytes,
+		PowerCycles:   out.PowerCycleCount,
+		ExitStatus:    out.Smartctl.ExitStatus,
+	}
+	if disk.Model == "" {
+		disk.Model = out.ScsiModelName
+	}
+	if out.SmartStatus != nil {
+		disk.Passed = &out.SmartStatus.Passed
+	}
+	if out.Temperature != nil {
+		disk.TemperatureCelsius = out.Temperature.Current
+	}
+	if out.PowerOnTime != nil {
+		disk.PowerOnHours = &out.PowerOnTime.Hours
+	}
+	disk.ReallocatedSectors = out.SCSIGrownDefectList
+
+	if nvme := out.NVMeLog; nvme != nil {
+		disk.MediaErrors = nvme.MediaErrors
+		disk.AvailableSparePercent = nvme.AvailableSpare
+		disk.CriticalWarning = nvme.CriticalWarning
+		disk.PercentageUsed = nvme.PercentageUsed
+		if nvme.DataUnitsWritten != nil {
+			written := *nvme.DataUnitsWritten * nvmeDataUnit
+			disk.BytesWritten = &written
+		}
+	}
+	if out.ATASmartAttributes != nil {
+		applyATAAttributes(&disk, out.ATASmartAttributes.Table, out.LogicalBlockSize)
+	}
+	return disk, nil
+}
+
+func applyATAAttributes(disk *entities.SmartDisk, table []ataAttribute, blockSize uint64) {
+	byID := make(map[int]ataAttribute, len(table))
+	for _, a := range table {
+		byID[a.ID] = a
+		if a.WhenFailed == "now" {
+			disk.FailingAttributes = append(disk.FailingAttributes, a.Name)
+		}
+	}
+	raw := func(id int) *uint64 {
+		if a, ok := byID[id]; ok {
+			v := a.Raw.Value
+			return &v
+		}
+		return nil
+	}
+	disk.ReallocatedSectors = raw(ataReallocatedSectors)
+	disk.ReportedUncorrectable = raw(ataReportedUncorrectable)
+	disk.PendingSectors = raw(ataPendingSectors)
+	disk.OfflineUncorrectable = raw(ataOfflineUncorrectable)
+	disk.CRCErrors = raw(ataCRCErrors)
+	if lbas := raw(ataTotalLBAsWritten); lbas != nil {
+		if blockSize == 0 {
+			blockSize = 512
+		}
+		written := *lbas * blockSize
+		disk.BytesWritten = &written
+	}
+	for _, id := range ataWearAttributes {
+		if a, ok := byID[id]; ok && a.Value >= 0 && a.Value <= 100 {
+			used := float64(100 - a.Value)
+			disk.PercentageUsed = &used
+			break
+		}
+	}
+}
+
+type scanOutput struct {
+	Devices []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"devices"`
+}
+
+// ParseScan reads the devices listed by `smartctl --scan --json`.
+func ParseScan(raw []byte) ([]Device, error) {
+	var out scanOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("parse smartctl scan: %w", err)
+	}
+	devices := make([]Device, 0, len(out.Devices))
+	for _, d := range out.Devices {
+		if d.Name != "" {
+			devices = append(devices, Device{Path: d.Name, Type: d.Type})
+		}
+	}
+	return devices, nil
+}
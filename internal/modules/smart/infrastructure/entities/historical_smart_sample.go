@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// HistoricalSmartSample is the SMART data of one disk at one check.
+type HistoricalSmartSample struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_smart_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_smart_host_ts,priority:2"`
+	// DiskID is the serial number, or the device path for disks that report none
+	DiskID string `json:"disk_id" gorm:"primaryKey;size:128"`
+
+	Device        string `json:"device" gorm:"size:128"`
+	IODevice      string `json:"io_device" gorm:"size:128"`
+	Protocol      string `json:"protocol" gorm:"size:8"`
+	Model         string `json:"model" gorm:"size:128"`
+	CapacityBytes uint64 `json:"capacity_bytes"`
+
+	Passed                *bool    `json:"passed"`
+	TemperatureCelsius    *float64 `json:"temperature_celsius"`
+	PowerOnHours          *uint64  `json:"power_on_hours"`
+	PowerCycles           *uint64  `json:"power_cycles"`
+	ReallocatedSectors    *uint64  `json:"reallocated_sectors"`
+	PendingSectors        *uint64  `json:"pending_sectors"`
+	OfflineUncorrectable  *uint64  `json:"offline_uncorrectable"`
+	ReportedUncorrectable *uint64  `json:"reported_uncorrectable"`
+	CRCErrors             *uint64  `json:"crc_errors" gorm:"column:crc_errors"`
+	MediaErrors           *uint64  `json:"media_errors"`
+	AvailableSparePercent *float64 `json:"available_spare_percent"`
+	CriticalWarning       *uint64  `json:"critical_warning"`
+	PercentageUsed        *float64 `json:"percentage_used"`
+	BytesWritten          *uint64  `json:"bytes_written"`
+	// FailingAttributes is the comma-separated list of failing ATA attributes
+	FailingAttributes string `json:"failing_attributes" gorm:"size:512"`
+	ExitStatus        int    `json:"exit_status"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalSmartSample) TableName() string { return "smart_samples" }
+
+// ToSamples flattens a metric into one row per disk, stamped with the time each disk was
+// checked so that unchanged results collected again are stored once.
+func (m SmartMetric) ToSamples(hostId uint) []HistoricalSmartSample {
+	rows := make([]HistoricalSmartSample, 0, len(m.Disks))
+	for _, d := range m.Disks {
+		rows = append(rows, HistoricalSmartSample{
+			HostID:                hostId,
+			Timestamp:             d.CheckedAt.UTC().Truncate(time.Microsecond),
+			DiskID:                d.DiskID(),
+			Device:                d.Device,
+			IODevice:              d.IODevice,
+			Protocol:              d.Protocol,
+			Model:                 d.Model,
+			CapacityBytes:         d.CapacityBytes,
+			Passed:                d.Passed,
+			TemperatureCelsius:    d.TemperatureCelsius,
+			PowerOnHours:          d.PowerOnHours,
+			PowerCycles:           d.PowerCycles,
+			ReallocatedSectors:    d.ReallocatedSectors,
+			PendingSectors:        d.PendingSectors,
+			OfflineUncorrectable:  d.OfflineUncorrectable,
+			ReportedUncorrectable: d.ReportedUncorrectable,
+			CRCErrors:             d.CRCErrors,
+			MediaErrors:           d.MediaErrors,
+			AvailableSparePercent: d.AvailableSparePercent,
+			CriticalWarning:       d.CriticalWarning,
+			PercentageUsed:        d.PercentageUsed,
+			BytesWritten:          d.BytesWritten,
+			FailingAttributes:     strings.Join(d.FailingAttributes, ","),
+			ExitStatus:            d.ExitStatus,
+		})
+	}
+	return rows
+}
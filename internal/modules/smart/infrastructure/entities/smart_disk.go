@@ -0,0 +1,65 @@
+package entities
+
+import "time"
+
+// SmartMetric is the latest SMART data of every checked disk.
+type SmartMetric struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Disks     []SmartDisk `json:"disks"`
+}
+
+// SmartDisk is the SMART data of one physical disk, normalised across ATA, NVMe and SCSI.
+// Attributes a disk or protocol does not report are nil.
+type SmartDisk struct {
+	// CheckedAt is when smartctl ran (or the JSON file was written)
+	CheckedAt time.Time `json:"checked_at"`
+	// Device is the path smartctl read, e.g. /dev/sda or /dev/nvme0
+	Device string `json:"device"`
+	// IODevice is the block device with the same serial number in the disk module's I/O
+	// counters (e.g. "sda", "nvme0n1"); empty when none matches
+	IODevice string `json:"io_device,omitempty"`
+	// Protocol is ATA, NVMe or SCSI
+	Protocol      string `json:"protocol"`
+	Model         string `json:"model"`
+	Serial        string `json:"serial"`
+	Firmware      string `json:"firmware,omitempty"`
+	CapacityBytes uint64 `json:"capacity_bytes"`
+
+	// Passed is the drive's overall health self-assessment
+	Passed             *bool    `json:"passed"`
+	TemperatureCelsius *float64 `json:"temperature_celsius"`
+	PowerOnHours       *uint64  `json:"power_on_hours"`
+	PowerCycles        *uint64  `json:"power_cycles"`
+
+	// ReallocatedSectors is ATA attribute 5 or the SCSI grown defect list
+	ReallocatedSectors *uint64 `json:"reallocated_sectors"`
+	// PendingSectors (197), OfflineUncorrectable (198), ReportedUncorrectable (187) and
+	// CRCErrors (199) are ATA attributes
+	PendingSectors        *uint64 `json:"pending_sectors"`
+	OfflineUncorrectable  *uint64 `json:"offline_uncorrectable"`
+	ReportedUncorrectable *uint64 `json:"reported_uncorrectable"`
+	CRCErrors             *uint64 `json:"crc_errors"`
+	// MediaErrors, AvailableSparePercent and CriticalWarning come from the NVMe health log
+	MediaErrors           *uint64  `json:"media_errors"`
+	AvailableSparePercent *float64 `json:"available_spare_percent"`
+	CriticalWarning       *uint64  `json:"critical_warning"`
+	// PercentageUsed is the estimated share of the rated endurance used (NVMe, or an ATA SSD
+	// wear attribute); it may exceed 100
+	PercentageUsed *float64 `json:"percentage_used"`
+	// BytesWritten is the total written by the host (NVMe data units or ATA attribute 241)
+	BytesWritten *uint64 `json:"bytes_written"`
+	// FailingAttributes lists ATA attributes at or below their failure threshold
+	FailingAttributes []string `json:"failing_attributes,omitempty"`
+
+	// ExitStatus is smartctl's exit status; bits 3-7 report failing health, attributes or logs
+	ExitStatus int `json:"exit_status"`
+}
+
+// DiskID identifies the disk across device renames: the serial number, or the device path for
+// disks that report none.
+func (d SmartDisk) DiskID() string {
+	if d.Serial != "" {
+		return d.Serial
+	}
+	return d.Device
+}
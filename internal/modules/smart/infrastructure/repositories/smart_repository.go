@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/smart/infrastructure/entities"
+)
+
+type SmartRepository interface {
+	// SaveCurrentMetric stores one row per disk check; a check already stored (collected again
+	// between smartctl runs, or pushed twice) is skipped.
+	SaveCurrentMetric(ctx context.Context, metric entities.SmartMetric, hostId uint) error
+	// GetLatestByHost returns the most recent row of every disk of a host.
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalSmartSample, error)
+	// GetHistoryByHost returns rows in time order, optionally for one disk ID.
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, diskId string) ([]entities.HistoricalSmartSample, error)
+}
+
+type smartRepository struct {
+	db *gorm.DB
+}
+
+func NewSmartRepository(db *gorm.DB) SmartRepository {
+	return &smartRepository{db: db}
+}
+
+func (r *smartRepository) SaveCurrentMetric(ctx context.Context, metric entities.SmartMetric, hostId uint) error {
+	rows := metric.ToSamples(hostId)
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *smartRepository) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalSmartSample, error) {
+	rows := []entities.HistoricalSmartSample{}
+	// Disks are checked independently (a disk in standby keeps its older check), so the latest
+	// row is taken per disk
+	latest := r.db.Table("smart_samples AS s").Select("MAX(s.timestamp)").
+		Where("s.host_id = smart_samples.host_id AND s.disk_id = smart_samples.disk_id")
+	err := r.db.WithContext(ctx).
+		Where("host_id = ? AND timestamp = (?)", hostId, latest).
+		Order("device ASC, disk_id ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *smartRepository) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, diskId string) ([]entities.HistoricalSmartSample, error) {
+	rows := []entities.HistoricalSmartSample{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if diskId != "" {
+		q = q.Where("disk_id = ?", diskId)
+	}
+	err := q.Order("timestamp ASC, disk_id ASC").Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,102 @@
+// Package smart registers the SMART module, which reads disk health from smartctl's JSON output
+// and links each disk to its block device in the disk module's I/O statistics.
+package smart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	diskrepos "system-stats/internal/modules/disk/infrastructure/repositories"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	smartservice "system-stats/internal/modules/smart/application"
+	"system-stats/internal/modules/smart/infrastructure/collectors"
+	"system-stats/internal/modules/smart/infrastructure/entities"
+	"system-stats/internal/modules/smart/infrastructure/repositories"
+	handlers "system-stats/internal/modules/smart/presentation"
+)
+
+// diskIOWindowHours is how far back the latest I/O sample of a disk is looked up
+const diskIOWindowHours = 5.0 / 60
+
+// Module reads SMART data with smartctl (or from SMART_JSON_DIR) and serves /smart.
+type Module struct{}
+
+func (Module) Name() string { return "smart" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 15,
+		Name:    "smart_samples_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalSmartSample{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalSmartSample{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"smart_samples"} }
+
+func (Module) HostTables() []string { return []string{"smart_samples"} }
+
+// Build fails on an invalid SMART_* setting and leaves out the collector when smartctl is not
+// installed and no SMART_JSON_DIR is set; stored and pushed data stay queryable.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	cfg, err := collectors.ConfigFromEnv()
+	if err != nil {
+		return registry.Instance{}, fmt.Errorf("smart: %w", err)
+	}
+	service := smartservice.NewService(deps.Logger, repositories.NewSmartRepository(deps.DB), cfg,
+		diskIO(diskrepos.NewDiskRepository(deps.DB)))
+	handler := handlers.NewSmartHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/smart", handler.HandleSmart)
+		},
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}
+	if !collectors.Available(cfg) {
+		deps.Logger.Info("smartctl not found and SMART_JSON_DIR not set, not collecting SMART data")
+		return inst, nil
+	}
+	// SMART data is not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.SmartMetric]("smart", service.Collect, service.Save, nil)
+	inst.Collector = &collector
+	inst.Metrics = smartservice.NewPrometheusCollector(service)
+	inst.PushData = registry.PushLatest(service.Latest)
+	return inst, nil
+}
+
+// diskIO reads the latest I/O sample of each block device from the disk module's io_history
+// table, which is part of the baseline schema (empty with the disk module disabled).
+func diskIO(repo diskrepos.DiskRepository) smartservice.DiskIOSource {
+	return func(ctx context.Context, hostId uint) (map[string]smartservice.DiskIO, error) {
+		rows, err := repo.GetIOHistoryByHost(ctx, hostId, diskIOWindowHours, "")
+		if err != nil {
+			return nil, err
+		}
+		// Rows are in time order, so the last one of a device wins
+		out := make(map[string]smartservice.DiskIO)
+		for _, r := range rows {
+			out[r.Device] = smartservice.DiskIO{
+				ReadIOPS:          r.ReadIOPS,
+				WriteIOPS:         r.WriteIOPS,
+				ReadBytesPerSec:   r.ReadBytesPerSec,
+				WriteBytesPerSec:  r.WriteBytesPerSec,
+				AvgReadLatencyMs:  r.AvgReadLatencyMs,
+				AvgWriteLatencyMs: r.AvgWriteLatencyMs,
+				UtilPercent:       r.UtilPercent,
+			}
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	smartservice "system-stats/internal/modules/smart/application"
+)
+
+// SmartHandler handles HTTP requests for SMART disk health.
+type SmartHandler struct {
+	logger  *log.Logger
+	service smartservice.Service
+	hosts   hostservice.Service
+}
+
+// NewSmartHandler creates a new HTTP handler for SMART endpoints.
+func NewSmartHandler(logger *log.Logger, service smartservice.Service, hosts hostservice.Service) *SmartHandler {
+	return &SmartHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleSmart returns the latest SMART data of every disk, with the current I/O of its block device, and the SMART history.
+//
+// @Summary     SMART disk health
+// @Description Returns the latest SMART data of every disk (health self-assessment, temperature, power-on hours, wear, reallocated/pending sectors, NVMe media errors and spare) normalised across ATA, NVMe and SCSI, each with `io_device` (the block device of /disk io_history with the same serial number) and its latest I/O rates, and the history of checks.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       disk     query    string   false  "Limit history to one disk ID (serial number)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /smart [get]
+func (h *SmartHandler) HandleSmart(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptySmartPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for SMART data", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	latest, err := h.service.GetLatestByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest SMART data")
+			return
+		}
+		h.logger.Error("Failed to fetch latest SMART data", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetHistoryByHost(ctx, effective, hours, c.Query("disk"))
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching SMART history")
+			return
+		}
+		h.logger.Error("Failed to fetch SMART history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"latest":  latest,
+		"history": history,
+	})
+}
@@ -0,0 +1,71 @@
+package socketmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	descTCPStates      = prometheus.NewDesc("system_tcp_connections", "TCP sockets (IPv4 and IPv6) by state.", []string{"state"}, nil)
+	descSocketsUsed    = prometheus.NewDesc("system_sockets_used", "Sockets of all families in use.", nil, nil)
+	descSocketMemory   = prometheus.NewDesc("system_socket_memory_bytes", "Socket buffer memory by protocol.", []string{"protocol"}, nil)
+	descTCPMemLimit    = prometheus.NewDesc("system_tcp_memory_limit_bytes", "TCP buffer memory limit (third value of tcp_mem).", nil, nil)
+	descTCPOrphan      = prometheus.NewDesc("system_tcp_orphan_sockets", "TCP sockets no longer attached to a process.", nil, nil)
+	descTCPCounter     = prometheus.NewDesc("system_tcp_events_total", "TCP counters from /proc/net/snmp and /proc/net/netstat.", []string{"event"}, nil)
+	descConntrackCount = prometheus.NewDesc("system_conntrack_entries", "Connection tracking table entries.", nil, nil)
+	descConntrackMax   = prometheus.NewDesc("system_conntrack_entries_limit", "Connection tracking table size (nf_conntrack_max).", nil, nil)
+)
+
+// PrometheusCollector exports the latest in-memory collection; scrapes never read /proc.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descTCPStates
+	ch <- descSocketsUsed
+	ch <- descSocketMemory
+	ch <- descTCPMemLimit
+	ch <- descTCPOrphan
+	ch <- descTCPCounter
+	ch <- descConntrackCount
+	ch <- descConntrackMax
+}
+
+// Collect sends the values of the last collection.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	latest := c.service.Latest()
+	if latest == nil {
+		return
+	}
+	for state, n := range latest.TCPStates.States() {
+		ch <- prometheus.MustNewConstMetric(descTCPStates, prometheus.GaugeValue, float64(n), state)
+	}
+	ch <- prometheus.MustNewConstMetric(descSocketsUsed, prometheus.GaugeValue, float64(latest.SocketsUsed))
+	ch <- prometheus.MustNewConstMetric(descSocketMemory, prometheus.GaugeValue, float64(latest.TCPMemBytes), "tcp")
+	ch <- prometheus.MustNewConstMetric(descSocketMemory, prometheus.GaugeValue, float64(latest.UDPMemBytes), "udp")
+	ch <- prometheus.MustNewConstMetric(descTCPMemLimit, prometheus.GaugeValue, float64(latest.TCPMemLimitBytes))
+	ch <- prometheus.MustNewConstMetric(descTCPOrphan, prometheus.GaugeValue, float64(latest.TCPOrphan))
+
+	counters := map[string]uint64{
+		"active_opens":     latest.Counters.ActiveOpens,
+		"passive_opens":    latest.Counters.PassiveOpens,
+		"attempt_fails":    latest.Counters.AttemptFails,
+		"estab_resets":     latest.Counters.EstabResets,
+		"out_segs":         latest.Counters.OutSegs,
+		"retrans_segs":     latest.Counters.RetransSegs,
+		"listen_overflows": latest.Counters.ListenOverflows,
+		"listen_drops":     latest.Counters.ListenDrops,
+	}
+	for event, v := range counters {
+		ch <- prometheus.MustNewConstMetric(descTCPCounter, prometheus.CounterValue, float64(v), event)
+	}
+	if latest.ConntrackCount != nil {
+		ch <- prometheus.MustNewConstMetric(descConntrackCount, prometheus.GaugeValue, float64(*latest.ConntrackCount))
+		ch <- prometheus.MustNewConstMetric(descConntrackMax, prometheus.GaugeValue, float64(*latest.ConntrackMax))
+	}
+}
@@ -0,0 +1,86 @@
+package socketmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/modules/sockets/infrastructure/collectors"
+	"system-stats/internal/modules/sockets/infrastructure/entities"
+	"system-stats/internal/modules/sockets/infrastructure/repositories"
+)
+
+type Service interface {
+	// Collect reads the socket statistics and keeps the result as the latest in-memory collection.
+	Collect(ctx context.Context) (entities.SocketMetric, error)
+	Save(ctx context.Context, metric entities.SocketMetric, hostId uint) error
+	// Latest returns this instance's last collection (no database access); nil before the first one.
+	Latest() *entities.SocketMetric
+	// ReceivePush stores a collection pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	GetLatestByHost(ctx context.Context, hostId uint) (*entities.HistoricalSocketMetric, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalSocketMetric, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.SocketCollector
+	repo      repositories.SocketRepository
+
+	mu     sync.RWMutex
+	latest *entities.SocketMetric
+}
+
+func NewService(logger *log.Logger, repo repositories.SocketRepository) Service {
+	return &service{
+		logger:    logger,
+		collector: collectors.NewSocketCollector(logger),
+		repo:      repo,
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.SocketMetric, error) {
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	s.latest = &metric
+	s.mu.Unlock()
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.SocketMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "sockets", "error", err, "host_id", hostId)
+		return err
+	}
+	return nil
+}
+
+func (s *service) Latest() *entities.SocketMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.SocketMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) (*entities.HistoricalSocketMetric, error) {
+	return s.repo.GetLatestByHost(ctx, hostId)
+}
+
+func (s *service) GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalSocketMetric, error) {
+	return s.repo.GetHistoryByHost(ctx, hostId, hours)
+}
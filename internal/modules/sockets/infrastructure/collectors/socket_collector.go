@@ -0,0 +1,225 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/app/procnet"
+	"system-stats/internal/modules/sockets/infrastructure/entities"
+)
+
+// maxSampleGap is the longest interval between two collections that still yields rates.
+const maxSampleGap = 10 * time.Minute
+
+// SocketCollector reads HOST_PROC/net (sockstat, sockstat6, snmp, netstat, tcp, tcp6) and the
+// conntrack counters under HOST_PROC/sys/net/netfilter. Rates are derived from the previous
+// collection.
+type SocketCollector struct {
+	logger   *log.Logger
+	pageSize uint64
+
+	mu     sync.Mutex
+	prev   *entities.TCPCounters
+	prevAt time.Time
+}
+
+// NewSocketCollector creates a socket statistics collector.
+func NewSocketCollector(logger *log.Logger) *SocketCollector {
+	return &SocketCollector{logger: logger, pageSize: uint64(os.Getpagesize())}
+}
+
+// Collect reads the socket tables and counters. Only sockstat is required; the other files are
+// optional (IPv6 disabled, nf_conntrack not loaded).
+func (c *SocketCollector) Collect(ctx context.Context) (entities.SocketMetric, error) {
+	c.logger.Debug("Collecting socket statistics")
+	metric := entities.SocketMetric{Timestamp: time.Now().UTC()}
+
+	sockstat, err := os.ReadFile(hostfs.Proc("net", "sockstat"))
+	if err != nil {
+		return metric, err
+	}
+	v4 := procnet.ParseSockstat(sockstat)
+	metric.SocketsUsed = v4["sockets"]["used"]
+	metric.TCPInUse = v4["TCP"]["inuse"]
+	metric.TCPOrphan = v4["TCP"]["orphan"]
+	metric.TCPAlloc = v4["TCP"]["alloc"]
+	// sockstat "mem" is in pages and already covers IPv6 sockets
+	metric.TCPMemBytes = v4["TCP"]["mem"] * c.pageSize
+	metric.UDPInUse = v4["UDP"]["inuse"]
+	metric.UDPMemBytes = v4["UDP"]["mem"] * c.pageSize
+	sockstat6, err := readOptional(hostfs.Proc("net", "sockstat6"))
+	if err != nil {
+		return metric, err
+	}
+	v6 := procnet.ParseSockstat(sockstat6)
+	metric.TCP6InUse = v6["TCP6"]["inuse"]
+	metric.UDP6InUse = v6["UDP6"]["inuse"]
+	tcpMem, err := readOptional(hostfs.Proc("sys", "net", "ipv4", "tcp_mem"))
+	if err != nil {
+		return metric, err
+	}
+	if fields := strings.Fields(string(tcpMem)); len(fields) == 3 {
+		limit, _ := strconv.ParseUint(fields[2], 10, 64)
+		metric.TCPMemLimitBytes = limit * c.pageSize
+	}
+
+	for _, table := range []string{"tcp", "tcp6"} {
+		if ctx.Err() != nil {
+			return metric, ctx.Err()
+		}
+		sockets, err := procnet.ReadSockets(table)
+		if err != nil {
+			return metric, err
+		}
+		for _, s := range sockets {
+			countState(&metric.TCPStates, s.State)
+		}
+	}
+
+	counters, err := readTCPCounters()
+	if err != nil {
+		return metric, err
+	}
+	metric.Counters = counters
+	c.deriveRates(&metric)
+
+	count, err := readUintFile(hostfs.Proc("sys", "net", "netfilter", "nf_conntrack_count"))
+	if err != nil {
+		return metric, err
+	}
+	limit, err := readUintFile(hostfs.Proc("sys", "net", "netfilter", "nf_conntrack_max"))
+	if err != nil {
+		return metric, err
+	}
+	if count != nil && limit != nil {
+		metric.ConntrackCount, metric.ConntrackMax = count, limit
+		if *limit > 0 {
+			percent := float64(*count) / float64(*limit) * 100
+			metric.ConntrackPercent = &percent
+		}
+	}
+	return metric, nil
+}
+
+func countState(counts *entities.TCPStateCounts, state uint8) {
+	switch state {
+	case procnet.StateEstablished:
+		counts.Established++
+	case procnet.StateSynSent:
+		counts.SynSent++
+	case procnet.StateSynRecv, procnet.StateNewSynRecv:
+		counts.SynRecv++
+	case procnet.StateFinWait1:
+		counts.FinWait1++
+	case procnet.StateFinWait2:
+		counts.FinWait2++
+	case procnet.StateTimeWait:
+		counts.TimeWait++
+	case procnet.StateClose:
+		counts.Close++
+	case procnet.StateCloseWait:
+		counts.CloseWait++
+	case procnet.StateLastAck:
+		counts.LastAck++
+	case procnet.StateListen:
+		counts.Listen++
+	case procnet.StateClosing:
+		counts.Closing++
+	}
+}
+
+func readTCPCounters() (entities.TCPCounters, error) {
+	var counters entities.TCPCounters
+	snmp, err := readOptional(hostfs.Proc("net", "snmp"))
+	if err != nil {
+		return counters, err
+	}
+	tcp := procnet.ParseCounterTables(snmp)["Tcp"]
+	counters.ActiveOpens = uint64(max(tcp["ActiveOpens"], 0))
+	counters.PassiveOpens = uint64(max(tcp["PassiveOpens"], 0))
+	counters.AttemptFails = uint64(max(tcp["AttemptFails"], 0))
+	counters.EstabResets = uint64(max(tcp["EstabResets"], 0))
+	counters.OutSegs = uint64(max(tcp["OutSegs"], 0))
+	counters.RetransSegs = uint64(max(tcp["RetransSegs"], 0))
+
+	netstat, err := readOptional(hostfs.Proc("net", "netstat"))
+	if err != nil {
+		return counters, err
+	}
+	ext := procnet.ParseCounterTables(netstat)["TcpExt"]
+	counters.ListenOverflows = uint64(max(ext["ListenOverflows"], 0))
+	counters.ListenDrops = uint64(max(ext["ListenDrops"], 0))
+	return counters, nil
+}
+
+// deriveRates fills the per-second rates from the counters of the previous collection.
+func (c *SocketCollector) deriveRates(metric *entities.SocketMetric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prev, prevAt := c.prev, c.prevAt
+	cur := metric.Counters
+	c.prev, c.prevAt = &cur, metric.Timestamp
+	if prev == nil {
+		return
+	}
+	elapsed := metric.Timestamp.Sub(prevAt).Seconds()
+	if elapsed <= 0 || elapsed > maxSampleGap.Seconds() {
+		return
+	}
+	// Counters only move backwards when the host rebooted (or the network namespace changed)
+	if cur.ActiveOpens < prev.ActiveOpens || cur.PassiveOpens < prev.PassiveOpens || cur.AttemptFails < prev.AttemptFails ||
+		cur.EstabResets < prev.EstabResets || cur.OutSegs < prev.OutSegs || cur.RetransSegs < prev.RetransSegs ||
+		cur.ListenOverflows < prev.ListenOverflows || cur.ListenDrops < prev.ListenDrops {
+		return
+	}
+	rate := func(now, before uint64) *float64 {
+		r := float64(now-before) / elapsed
+		return &r
+	}
+	metric.ActiveOpensPerSec = rate(cur.ActiveOpens, prev.ActiveOpens)
+	metric.PassiveOpensPerSec = rate(cur.PassiveOpens, prev.PassiveOpens)
+	metric.AttemptFailsPerSec = rate(cur.AttemptFails, prev.AttemptFails)
+	metric.EstabResetsPerSec = rate(cur.EstabResets, prev.EstabResets)
+	metric.RetransSegsPerSec = rate(cur.RetransSegs, prev.RetransSegs)
+	metric.ListenOverflowsPerSec = rate(cur.ListenOverflows, prev.ListenOverflows)
+	metric.ListenDropsPerSec = rate(cur.ListenDrops, prev.ListenDrops)
+	percent := 0.0
+	if sent := cur.OutSegs - prev.OutSegs; sent > 0 {
+		percent = float64(cur.RetransSegs-prev.RetransSegs) / float64(sent) * 100
+	}
+	metric.RetransPercent = &percent
+}
+
+// readOptional reads a file that may not exist; a missing file reads as empty.
+func readOptional(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return raw, err
+}
+
+// readUintFile reads a single-number file; nil when it does not exist.
+func readUintFile(path string) (*uint64, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,102 @@
+package entities
+
+import "time"
+
+// SocketMetric is one collection of socket, TCP and conntrack statistics.
+type SocketMetric struct {
+	Timestamp time.Time `json:"timestamp"`
+	SocketStats
+	// Counters are the cumulative TCP counters the rates are derived from
+	Counters TCPCounters `json:"counters"`
+}
+
+// SocketStats are the values stored per collection. Rates are per second since the previous
+// collection and nil on the first one or after a counter reset; conntrack fields are nil when
+// the nf_conntrack module is not loaded.
+type SocketStats struct {
+	// TCPStates counts IPv4 and IPv6 TCP sockets by state from the tcp / tcp6 tables
+	TCPStates TCPStateCounts `json:"tcp_states" gorm:"embedded;embeddedPrefix:tcp_"`
+
+	// SocketsUsed is the number of sockets of all families in use (sockstat)
+	SocketsUsed uint64 `json:"sockets_used"`
+
+	// TCP* come from sockstat (IPv4) and sockstat6 (TCP6InUse); TCPMemBytes is TCP buffer memory
+	// of both families, TCPMemLimitBytes the tcp_mem limit at which allocations fail
+	TCPInUse         uint64 `json:"tcp_inuse" gorm:"column:tcp_inuse"`
+	TCP6InUse        uint64 `json:"tcp6_inuse" gorm:"column:tcp6_inuse"`
+	TCPOrphan        uint64 `json:"tcp_orphan" gorm:"column:tcp_orphan"`
+	TCPAlloc         uint64 `json:"tcp_alloc" gorm:"column:tcp_alloc"`
+	TCPMemBytes      uint64 `json:"tcp_mem_bytes" gorm:"column:tcp_mem_bytes"`
+	TCPMemLimitBytes uint64 `json:"tcp_mem_limit_bytes" gorm:"column:tcp_mem_limit_bytes"`
+	UDPInUse         uint64 `json:"udp_inuse" gorm:"column:udp_inuse"`
+	UDP6InUse        uint64 `json:"udp6_inuse" gorm:"column:udp6_inuse"`
+	UDPMemBytes      uint64 `json:"udp_mem_bytes" gorm:"column:udp_mem_bytes"`
+
+	ActiveOpensPerSec  *float64 `json:"active_opens_per_sec"`
+	PassiveOpensPerSec *float64 `json:"passive_opens_per_sec"`
+	AttemptFailsPerSec *float64 `json:"attempt_fails_per_sec"`
+	EstabResetsPerSec  *float64 `json:"estab_resets_per_sec"`
+	RetransSegsPerSec  *float64 `json:"retrans_segs_per_sec"`
+	// RetransPercent is retransmitted segments as a share of segments sent
+	RetransPercent        *float64 `json:"retrans_percent"`
+	ListenOverflowsPerSec *float64 `json:"listen_overflows_per_sec"`
+	ListenDropsPerSec     *float64 `json:"listen_drops_per_sec"`
+
+	ConntrackCount   *uint64  `json:"conntrack_count"`
+	ConntrackMax     *uint64  `json:"conntrack_max"`
+	ConntrackPercent *float64 `json:"conntrack_percent"`
+}
+
+// TCPStateCounts counts TCP sockets per state; NEW_SYN_RECV request sockets count as SynRecv.
+type TCPStateCounts struct {
+	Established uint64 `json:"established"`
+	SynSent     uint64 `json:"syn_sent"`
+	SynRecv     uint64 `json:"syn_recv"`
+	FinWait1    uint64 `json:"fin_wait1" gorm:"column:fin_wait1"`
+	FinWait2    uint64 `json:"fin_wait2" gorm:"column:fin_wait2"`
+	TimeWait    uint64 `json:"time_wait"`
+	Close       uint64 `json:"close"`
+	CloseWait   uint64 `json:"close_wait"`
+	LastAck     uint64 `json:"last_ack"`
+	Listen      uint64 `json:"listen"`
+	Closing     uint64 `json:"closing"`
+}
+
+// States returns the counts keyed by lower-case state name.
+func (c TCPStateCounts) States() map[string]uint64 {
+	return map[string]uint64{
+		"established": c.Established,
+		"syn_sent":    c.SynSent,
+		"syn_recv":    c.SynRecv,
+		"fin_wait1":   c.FinWait1,
+		"fin_wait2":   c.FinWait2,
+		"time_wait":   c.TimeWait,
+		"close":       c.Close,
+		"close_wait":  c.CloseWait,
+		"last_ack":    c.LastAck,
+		"listen":      c.Listen,
+		"closing":     c.Closing,
+	}
+}
+
+// TCPCounters are cumulative counters from the Tcp table of snmp and TcpExt of netstat.
+type TCPCounters struct {
+	ActiveOpens     uint64 `json:"active_opens"`
+	PassiveOpens    uint64 `json:"passive_opens"`
+	AttemptFails    uint64 `json:"attempt_fails"`
+	EstabResets     uint64 `json:"estab_resets"`
+	OutSegs         uint64 `json:"out_segs"`
+	RetransSegs     uint64 `json:"retrans_segs"`
+	ListenOverflows uint64 `json:"listen_overflows"`
+	ListenDrops     uint64 `json:"listen_drops"`
+}
+
+// HistoricalSocketMetric is one stored collection.
+type HistoricalSocketMetric struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_socket_metrics_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_socket_metrics_host_ts,priority:2"`
+	SocketStats
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalSocketMetric) TableName() string { return "socket_metrics" }
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/sockets/infrastructure/entities"
+)
+
+type SocketRepository interface {
+	// SaveCurrentMetric stores one collection; a collection already stored (e.g. pushed twice) is skipped.
+	SaveCurrentMetric(ctx context.Context, metric entities.SocketMetric, hostId uint) error
+	// GetLatestByHost returns the most recent row of a host; nil when there is none.
+	GetLatestByHost(ctx context.Context, hostId uint) (*entities.HistoricalSocketMetric, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalSocketMetric, error)
+}
+
+type socketRepository struct {
+	db *gorm.DB
+}
+
+func NewSocketRepository(db *gorm.DB) SocketRepository {
+	return &socketRepository{db: db}
+}
+
+func (r *socketRepository) SaveCurrentMetric(ctx context.Context, metric entities.SocketMetric, hostId uint) error {
+	row := entities.HistoricalSocketMetric{
+		HostID:      hostId,
+		Timestamp:   metric.Timestamp.UTC(),
+		SocketStats: metric.SocketStats,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error
+}
+
+func (r *socketRepository) GetLatestByHost(ctx context.Context, hostId uint) (*entities.HistoricalSocketMetric, error) {
+	var row entities.HistoricalSocketMetric
+	err := r.db.WithContext(ctx).Where("host_id = ?", hostId).Order("timestamp DESC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (r *socketRepository) GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalSocketMetric, error) {
+	rows := []entities.HistoricalSocketMetric{}
+	err := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours).
+		Order("timestamp ASC").
+		Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,68 @@
+// Package sockets registers the socket and TCP connection statistics module.
+package sockets
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/app/registry"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	socketservice "system-stats/internal/modules/sockets/application"
+	"system-stats/internal/modules/sockets/infrastructure/entities"
+	"system-stats/internal/modules/sockets/infrastructure/repositories"
+	handlers "system-stats/internal/modules/sockets/presentation"
+)
+
+// Module collects TCP connection states, socket memory, TCP error rates and conntrack usage
+// from HOST_PROC/net and serves /sockets.
+type Module struct{}
+
+func (Module) Name() string { return "sockets" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 11,
+		Name:    "socket_metrics_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalSocketMetric{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalSocketMetric{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"socket_metrics"} }
+
+func (Module) HostTables() []string { return []string{"socket_metrics"} }
+
+// Build leaves out the collector where HOST_PROC/net/sockstat does not exist (non-Linux);
+// stored and pushed statistics stay queryable.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := socketservice.NewService(deps.Logger, repositories.NewSocketRepository(deps.DB))
+	handler := handlers.NewSocketHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/sockets", handler.HandleSocketStats)
+		},
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}
+	if _, err := os.Stat(hostfs.Proc("net", "sockstat")); err != nil {
+		deps.Logger.Info("Socket statistics not available, not collecting them", "path", hostfs.Proc("net", "sockstat"))
+		return inst, nil
+	}
+	// Socket statistics are not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.SocketMetric]("sockets", service.Collect, service.Save, nil)
+	inst.Collector = &collector
+	inst.Metrics = socketservice.NewPrometheusCollector(service)
+	inst.PushData = registry.PushLatest(service.Latest)
+	return inst, nil
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	socketservice "system-stats/internal/modules/sockets/application"
+)
+
+// SocketHandler handles HTTP requests for socket and TCP metrics.
+type SocketHandler struct {
+	logger  *log.Logger
+	service socketservice.Service
+	hosts   hostservice.Service
+}
+
+// NewSocketHandler creates a new HTTP handler for socket endpoints.
+func NewSocketHandler(logger *log.Logger, service socketservice.Service, hosts hostservice.Service) *SocketHandler {
+	return &SocketHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleSocketStats returns the latest socket collection with history.
+//
+// @Summary     Socket and TCP statistics
+// @Description Returns TCP connection counts by state, sockets and socket memory in use, TCP open / reset / retransmit / listen overflow rates and conntrack table usage. Rates are null on the first collection; conntrack fields are null when nf_conntrack is not loaded.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /sockets [get]
+func (h *SocketHandler) HandleSocketStats(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptySocketsPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for socket metrics", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	latest, err := h.service.GetLatestByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest socket metrics")
+			return
+		}
+		h.logger.Error("Failed to fetch latest socket metrics", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetHistoryByHost(ctx, effective, hours)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching socket history")
+			return
+		}
+		h.logger.Error("Failed to fetch socket history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"latest":  latest,
+		"history": history,
+	})
+}
@@ -0,0 +1,77 @@
+package storagehealth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/modules/storage/infrastructure/entities"
+)
+
+var (
+	descStorageHealthy = prometheus.NewDesc("system_storage_healthy", "1 when the md array, ZFS pool or btrfs filesystem is healthy, else 0.", []string{"kind", "name"}, nil)
+	descMDDevices      = prometheus.NewDesc("system_md_array_devices", "Members of an md array by state (total, active, failed).", []string{"name", "state"}, nil)
+	descMDSync         = prometheus.NewDesc("system_md_array_sync_percent", "Progress of a running md resync, recovery, check or reshape.", []string{"name", "action"}, nil)
+	descZFSSize        = prometheus.NewDesc("system_zfs_pool_size_bytes", "ZFS pool size.", []string{"pool"}, nil)
+	descZFSAllocated   = prometheus.NewDesc("system_zfs_pool_allocated_bytes", "ZFS pool space allocated.", []string{"pool"}, nil)
+	descBtrfsErrors    = prometheus.NewDesc("system_btrfs_device_errors_total", "btrfs device error counters (persistent until reset).", []string{"uuid", "devid", "type"}, nil)
+)
+
+// PrometheusCollector exports the latest in-memory check; scrapes never read /proc or /sys.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descStorageHealthy
+	ch <- descMDDevices
+	ch <- descMDSync
+	ch <- descZFSSize
+	ch <- descZFSAllocated
+	ch <- descBtrfsErrors
+}
+
+// Collect sends the health of every device of the last check.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	latest := c.service.Latest()
+	if latest == nil {
+		return
+	}
+	for _, d := range latest.Devices {
+		healthy := 0.0
+		if d.Health == entities.HealthOK {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(descStorageHealthy, prometheus.GaugeValue, healthy, d.Kind, d.Name)
+		switch d.Kind {
+		case entities.KindMD:
+			ch <- prometheus.MustNewConstMetric(descMDDevices, prometheus.GaugeValue, float64(d.Devices), d.Name, "total")
+			ch <- prometheus.MustNewConstMetric(descMDDevices, prometheus.GaugeValue, float64(d.ActiveDevices), d.Name, "active")
+			ch <- prometheus.MustNewConstMetric(descMDDevices, prometheus.GaugeValue, float64(len(d.FailedDevices)), d.Name, "failed")
+			if d.SyncPercent != nil {
+				ch <- prometheus.MustNewConstMetric(descMDSync, prometheus.GaugeValue, *d.SyncPercent, d.Name, d.SyncAction)
+			}
+		case entities.KindZFS:
+			if d.SizeBytes != nil {
+				ch <- prometheus.MustNewConstMetric(descZFSSize, prometheus.GaugeValue, float64(*d.SizeBytes), d.Name)
+				ch <- prometheus.MustNewConstMetric(descZFSAllocated, prometheus.GaugeValue, float64(*d.AllocatedBytes), d.Name)
+			}
+		case entities.KindBtrfs:
+			for _, bd := range d.BtrfsDevices {
+				counters := map[string]uint64{
+					"write":      bd.WriteErrors,
+					"read":       bd.ReadErrors,
+					"flush":      bd.FlushErrors,
+					"corruption": bd.CorruptionErrors,
+					"generation": bd.GenerationErrors,
+				}
+				for kind, v := range counters {
+					ch <- prometheus.MustNewConstMetric(descBtrfsErrors, prometheus.CounterValue, float64(v), d.Name, bd.DevID, kind)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package storagehealth
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/storage/infrastructure/collectors"
+	"system-stats/internal/modules/storage/infrastructure/entities"
+	"system-stats/internal/modules/storage/infrastructure/repositories"
+)
+
+const (
+	// maxPushEvents bounds the events kept for, and accepted from, a push
+	maxPushEvents = 256
+	// maxPushDevices bounds the devices accepted from a push
+	maxPushDevices = 256
+)
+
+type Service interface {
+	// Collect checks every device and keeps the result as the latest in-memory check.
+	Collect(ctx context.Context) (entities.StorageHealth, error)
+	// Save stores the device health and records the check's events.
+	Save(ctx context.Context, metric entities.StorageHealth, hostId uint) error
+	// Latest returns this instance's last check (no database access); nil before the first one.
+	Latest() *entities.StorageHealth
+	// PushData returns the last check with the events of recent checks; nil before the first one.
+	PushData() *entities.StorageHealth
+	// ReceivePush stores a check pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalStorageSample, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, name string) ([]entities.HistoricalStorageSample, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.StorageCollector
+	repo      repositories.StorageRepository
+	events    eventsservice.Service
+
+	mu     sync.RWMutex
+	latest *entities.StorageHealth
+	recent *eventsservice.Recent[evententities.HostEvent]
+	// resolved holds the hosts whose problems of removed devices were resolved since start
+	resolved map[uint]bool
+}
+
+func NewService(logger *log.Logger, repo repositories.StorageRepository, events eventsservice.Service) Service {
+	return &service{
+		logger:    logger,
+		collector: collectors.NewStorageCollector(logger),
+		repo:      repo,
+		events:    events,
+		recent:    eventsservice.NewRecentEvents(maxPushEvents),
+		resolved:  make(map[uint]bool),
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.StorageHealth, error) {
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest = &metric
+	s.recent.Add(metric.Timestamp, metric.Events)
+	return metric, nil
+}
+
+// Save stores the device health and records its events. The first save for a host also resolves
+// the problems left open by a previous run for devices that are no longer present, which the
+// collector cannot report as removed because it has not seen them.
+func (s *service) Save(ctx context.Context, metric entities.StorageHealth, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "storage", "error", err, "host_id", hostId)
+		return err
+	}
+	events := metric.Events
+	s.mu.Lock()
+	resolved := s.resolved[hostId]
+	s.resolved[hostId] = true
+	s.mu.Unlock()
+	if !resolved {
+		problems, err := s.events.Problems(ctx, hostId)
+		if err != nil {
+			return err
+		}
+		present := make(map[string]bool, len(metric.Devices))
+		for _, d := range metric.Devices {
+			present[d.Subject()] = true
+		}
+		events = events[:len(events):len(events)]
+		for _, p := range problems {
+			if p.Source == collectors.EventSource && !present[p.Subject] {
+				events = append(events, collectors.RemovedEvent(metric.Timestamp, p.Subject))
+			}
+		}
+	}
+	for _, e := range events {
+		if e.Severity == evententities.SeverityWarning || e.Severity == evententities.SeverityCritical {
+			s.logger.Warn("Storage health changed", "device", e.Subject, "host_id", hostId, "message", e.Message)
+		}
+	}
+	return s.events.Record(ctx, hostId, events)
+}
+
+func (s *service) Latest() *entities.StorageHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+func (s *service) PushData() *entities.StorageHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.latest == nil {
+		return nil
+	}
+	out := *s.latest
+	out.Events = s.recent.Items()
+	return &out
+}
+
+// ReceivePush bounds what an agent may send and records only storage events.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.StorageHealth
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	metric.Devices = metric.Devices[:min(len(metric.Devices), maxPushDevices)]
+	events := make([]evententities.HostEvent, 0, len(metric.Events))
+	for _, e := range metric.Events[:min(len(metric.Events), maxPushEvents)] {
+		if e.Source == collectors.EventSource && !e.Timestamp.IsZero() {
+			events = append(events, e)
+		}
+	}
+	metric.Events = events
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalStorageSample, error) {
+	return s.repo.GetLatestByHost(ctx, hostId)
+}
+
+func (s *service) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, name string) ([]entities.HistoricalStorageSample, error) {
+	return s.repo.GetHistoryByHost(ctx, hostId, hours, name)
+}
@@ -0,0 +1,98 @@
+package collectors
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/storage/infrastructure/entities"
+)
+
+// ReadBtrfs reads every mounted btrfs filesystem from HOST_SYS/fs/btrfs/<uuid>: the label and,
+// per device, the missing flag and error counters under devinfo/<devid> (kernel 5.14+; older
+// kernels report no devices).
+func ReadBtrfs() ([]entities.StorageDevice, error) {
+	entries, err := os.ReadDir(hostfs.Sys("fs", "btrfs"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var filesystems []entities.StorageDevice
+	for _, e := range entries {
+		// Filesystem directories are named by UUID next to "features"
+		if _, err := os.Stat(hostfs.Sys("fs", "btrfs", e.Name(), "devices")); err != nil {
+			continue
+		}
+		dev := entities.StorageDevice{Kind: entities.KindBtrfs, Name: e.Name()}
+		if raw, err := os.ReadFile(hostfs.Sys("fs", "btrfs", e.Name(), "label")); err == nil {
+			dev.Label = strings.TrimSpace(string(raw))
+		}
+		devids, _ := os.ReadDir(hostfs.Sys("fs", "btrfs", e.Name(), "devinfo"))
+		for _, d := range devids {
+			bd := entities.BtrfsDevice{DevID: d.Name()}
+			if raw, err := os.ReadFile(hostfs.Sys("fs", "btrfs", e.Name(), "devinfo", d.Name(), "missing")); err == nil {
+				bd.Missing = strings.TrimSpace(string(raw)) == "1"
+			}
+			if raw, err := os.ReadFile(hostfs.Sys("fs", "btrfs", e.Name(), "devinfo", d.Name(), "error_stats")); err == nil {
+				applyBtrfsErrorStats(&bd, raw)
+			}
+			dev.BtrfsDevices = append(dev.BtrfsDevices, bd)
+			dev.ErrorCount += bd.Errors()
+		}
+		sort.Slice(dev.BtrfsDevices, func(i, j int) bool {
+			a, _ := strconv.Atoi(dev.BtrfsDevices[i].DevID)
+			b, _ := strconv.Atoi(dev.BtrfsDevices[j].DevID)
+			return a < b
+		})
+		dev.Health = btrfsHealth(dev)
+		filesystems = append(filesystems, dev)
+	}
+	return filesystems, nil
+}
+
+// applyBtrfsErrorStats parses error_stats lines such as "write_errs 0".
+func applyBtrfsErrorStats(bd *entities.BtrfsDevice, raw []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "write_errs":
+			bd.WriteErrors = v
+		case "read_errs":
+			bd.ReadErrors = v
+		case "flush_errs":
+			bd.FlushErrors = v
+		case "corruption_errs":
+			bd.CorruptionErrors = v
+		case "generation_errs":
+			bd.GenerationErrors = v
+		}
+	}
+}
+
+func btrfsHealth(dev entities.StorageDevice) string {
+	for _, d := range dev.BtrfsDevices {
+		if d.Missing {
+			return entities.HealthDegraded
+		}
+	}
+	if dev.ErrorCount > 0 {
+		return entities.HealthErrors
+	}
+	return entities.HealthOK
+}
@@ -0,0 +1,115 @@
+package collectors
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"system-stats/internal/modules/storage/infrastructure/entities"
+)
+
+var (
+	// mdStatusRe matches the member counts of the status line, e.g. "[2/1] [U_]"
+	mdStatusRe = regexp.MustCompile(`\[(\d+)/(\d+)\]`)
+	// mdSyncRe matches a running sync, e.g. "recovery =  8.5% (893248/10476544) finish=1.2min"
+	mdSyncRe   = regexp.MustCompile(`(resync|recovery|check|repair|reshape)\s*=\s*([\d.]+)%`)
+	mdFinishRe = regexp.MustCompile(`finish=([\d.]+)min`)
+	// mdPendingRe matches a sync waiting for another array on the same disks
+	mdPendingRe = regexp.MustCompile(`(resync|recovery|check|repair|reshape)\s*=\s*(DELAYED|PENDING)`)
+)
+
+// ParseMdstat parses /proc/mdstat into one device per array:
+//
+//	md1 : active raid1 sdb2[1](F) sda2[0]
+//	      10476544 blocks super 1.2 [2/1] [U_]
+//	      [=>...................]  recovery =  8.5% (893248/10476544) finish=1.2min speed=127606K/sec
+func ParseMdstat(raw []byte) []entities.StorageDevice {
+	var arrays []entities.StorageDevice
+	var cur *entities.StorageDevice
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, rest, ok := strings.Cut(line, " : "); ok && strings.HasPrefix(name, "md") {
+			arrays = append(arrays, parseMdHeader(name, rest))
+			cur = &arrays[len(arrays)-1]
+			continue
+		}
+		if cur == nil || strings.TrimSpace(line) == "" {
+			cur = nil
+			continue
+		}
+		if m := mdStatusRe.FindStringSubmatch(line); m != nil {
+			cur.Devices, _ = strconv.Atoi(m[1])
+			cur.ActiveDevices, _ = strconv.Atoi(m[2])
+		}
+		if m := mdSyncRe.FindStringSubmatch(line); m != nil {
+			cur.SyncAction = m[1]
+			if pct, err := strconv.ParseFloat(m[2], 64); err == nil {
+				cur.SyncPercent = &pct
+			}
+			if f := mdFinishRe.FindStringSubmatch(line); f != nil {
+				if minutes, err := strconv.ParseFloat(f[1], 64); err == nil {
+					cur.SyncFinishMinutes = &minutes
+				}
+			}
+		} else if m := mdPendingRe.FindStringSubmatch(line); m != nil {
+			cur.SyncAction = m[1]
+		}
+	}
+	for i := range arrays {
+		arrays[i].Health = mdHealth(arrays[i])
+	}
+	return arrays
+}
+
+// parseMdHeader parses "active raid1 sdb2[1](F) sda2[0]", "active (auto-read-only) raid1 ..."
+// or "inactive sdb[0](S)".
+func parseMdHeader(name, rest string) entities.StorageDevice {
+	dev := entities.StorageDevice{Kind: entities.KindMD, Name: strings.TrimSpace(name)}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return dev
+	}
+	dev.State = fields[0]
+	fields = fields[1:]
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "(") {
+		dev.State += " " + fields[0]
+		fields = fields[1:]
+	}
+	members := 0
+	for _, f := range fields {
+		member, flags, isMember := strings.Cut(f, "[")
+		if !isMember {
+			dev.Level = f
+			continue
+		}
+		switch {
+		case strings.Contains(flags, "(F)"):
+			dev.FailedDevices = append(dev.FailedDevices, member)
+		case strings.Contains(flags, "(S)"):
+			continue
+		}
+		members++
+	}
+	// Arrays without redundancy (raid0, linear) have no [n/m] status; the members are the array
+	dev.Devices = members
+	dev.ActiveDevices = members - len(dev.FailedDevices)
+	return dev
+}
+
+func mdHealth(dev entities.StorageDevice) string {
+	switch {
+	case strings.HasPrefix(dev.State, "inactive"):
+		return entities.HealthFailed
+	// A faulty member left in an array that has all its members in sync does not degrade it
+	case dev.ActiveDevices < dev.Devices:
+		if dev.SyncAction == "recovery" {
+			return entities.HealthRebuilding
+		}
+		return entities.HealthDegraded
+	default:
+		return entities.HealthOK
+	}
+}
@@ -0,0 +1,154 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/hostfs"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/storage/infrastructure/entities"
+)
+
+// EventSource is the source of the events this collector reports.
+const EventSource = "storage"
+
+// KindRemoved is the event kind of a device that is no longer present (array stopped, pool
+// exported, filesystem unmounted); other events are kinded by the new health state.
+const KindRemoved = "removed"
+
+// Available reports whether the host has any of the sources this collector reads.
+func Available() bool {
+	for _, path := range []string{hostfs.Proc("mdstat"), hostfs.Proc("spl", "kstat", "zfs"), hostfs.Sys("fs", "btrfs")} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// StorageCollector checks md arrays (HOST_PROC/mdstat), ZFS pools (HOST_PROC/spl/kstat/zfs) and
+// btrfs filesystems (HOST_SYS/fs/btrfs) and reports health changes as events.
+type StorageCollector struct {
+	logger *log.Logger
+
+	mu sync.Mutex
+	// seen is false until the first check; health and errorCounts hold the previous check per subject
+	seen        bool
+	health      map[string]string
+	errorCounts map[string]uint64
+}
+
+// NewStorageCollector creates a storage health collector.
+func NewStorageCollector(logger *log.Logger) *StorageCollector {
+	return &StorageCollector{logger: logger, health: make(map[string]string), errorCounts: make(map[string]uint64)}
+}
+
+// Collect checks every device and returns their health and the events of changes. The first
+// check reports every device's health, so an open problem recorded before a restart of this
+// instance is resolved. Growing btrfs error counters are reported even without a health change.
+func (c *StorageCollector) Collect(ctx context.Context) (entities.StorageHealth, error) {
+	c.logger.Debug("Collecting storage health")
+	now := time.Now().UTC()
+	metric := entities.StorageHealth{Timestamp: now, Devices: []entities.StorageDevice{}}
+
+	mdstat, err := os.ReadFile(hostfs.Proc("mdstat"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return metric, err
+	}
+	metric.Devices = append(metric.Devices, ParseMdstat(mdstat)...)
+	pools, err := ReadZFSPools(ctx)
+	if err != nil {
+		return metric, err
+	}
+	metric.Devices = append(metric.Devices, pools...)
+	filesystems, err := ReadBtrfs()
+	if err != nil {
+		return metric, err
+	}
+	metric.Devices = append(metric.Devices, filesystems...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	present := make(map[string]bool, len(metric.Devices))
+	for _, d := range metric.Devices {
+		subject := d.Subject()
+		present[subject] = true
+		prev, known := c.health[subject]
+		if !known && c.seen && d.Health == entities.HealthOK {
+			// A new healthy device is not worth an event
+			prev = entities.HealthOK
+		}
+		switch {
+		case prev != d.Health:
+			metric.Events = append(metric.Events, event(now, subject, d.Health, describe(d)))
+		case d.Kind == entities.KindBtrfs && d.ErrorCount > c.errorCounts[subject]:
+			metric.Events = append(metric.Events, event(now, subject, d.Health,
+				fmt.Sprintf("%s: %d new device errors (%s)", subject, d.ErrorCount-c.errorCounts[subject], describe(d))))
+		}
+		c.health[subject] = d.Health
+		c.errorCounts[subject] = d.ErrorCount
+	}
+	for subject := range c.health {
+		if !present[subject] {
+			metric.Events = append(metric.Events, RemovedEvent(now, subject))
+			delete(c.health, subject)
+			delete(c.errorCounts, subject)
+		}
+	}
+	c.seen = true
+	return metric, nil
+}
+
+// RemovedEvent resolves the problems of a device that is no longer present.
+func RemovedEvent(at time.Time, subject string) evententities.HostEvent {
+	return evententities.HostEvent{
+		Timestamp: at, Source: EventSource, Subject: subject, Kind: KindRemoved,
+		Severity: evententities.SeverityOK, Message: subject + " is no longer present",
+	}
+}
+
+// describe summarises a device's health for an event message.
+func describe(d entities.StorageDevice) string {
+	parts := []string{d.Subject() + " is " + d.Health}
+	switch d.Kind {
+	case entities.KindMD:
+		parts = append(parts, fmt.Sprintf("%s, %d of %d devices active", d.Level, d.ActiveDevices, d.Devices))
+		if len(d.FailedDevices) > 0 {
+			parts = append(parts, "failed: "+strings.Join(d.FailedDevices, ", "))
+		}
+		if d.SyncPercent != nil {
+			parts = append(parts, fmt.Sprintf("%s %.1f%%", d.SyncAction, *d.SyncPercent))
+		}
+	case entities.KindZFS:
+		parts = append(parts, "pool state "+d.State)
+	case entities.KindBtrfs:
+		for _, bd := range d.BtrfsDevices {
+			if bd.Missing {
+				parts = append(parts, "devid "+bd.DevID+" missing")
+			}
+		}
+		if d.ErrorCount > 0 {
+			parts = append(parts, fmt.Sprintf("%d device errors", d.ErrorCount))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func event(at time.Time, subject, health, message string) evententities.HostEvent {
+	return evententities.HostEvent{
+		Timestamp: at,
+		Source:    EventSource,
+		Subject:   subject,
+		Kind:      health,
+		Severity:  entities.Severity(health),
+		Message:   message,
+	}
+}
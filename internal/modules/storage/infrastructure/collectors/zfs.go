@@ -0,0 +1,88 @@
+package collectors
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/storage/infrastructure/entities"
+)
+
+// ReadZFSPools reads the state of every imported pool from HOST_PROC/spl/kstat/zfs/<pool>/state
+// (OpenZFS 0.8+). The kstats hold no capacity; it is added from `zpool list` when the command is
+// available.
+func ReadZFSPools(ctx context.Context) ([]entities.StorageDevice, error) {
+	dir := hostfs.Proc("spl", "kstat", "zfs")
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pools []entities.StorageDevice
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		raw, err := os.ReadFile(hostfs.Proc("spl", "kstat", "zfs", e.Name(), "state"))
+		if err != nil {
+			continue
+		}
+		state := strings.TrimSpace(string(raw))
+		pools = append(pools, entities.StorageDevice{Kind: entities.KindZFS, Name: e.Name(), State: state, Health: zfsHealth(state)})
+	}
+	if len(pools) == 0 {
+		return nil, nil
+	}
+
+	if out, err := exec.CommandContext(ctx, "zpool", "list", "-Hp", "-o", "name,size,allocated").Output(); err == nil {
+		capacity := ParseZpoolList(out)
+		for i := range pools {
+			if c, ok := capacity[pools[i].Name]; ok {
+				pools[i].SizeBytes, pools[i].AllocatedBytes = &c[0], &c[1]
+			}
+		}
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools, nil
+}
+
+// ParseZpoolList parses `zpool list -Hp -o name,size,allocated` into size and allocated bytes
+// per pool.
+func ParseZpoolList(raw []byte) map[string][2]uint64 {
+	out := make(map[string][2]uint64)
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		size, errSize := strconv.ParseUint(fields[1], 10, 64)
+		alloc, errAlloc := strconv.ParseUint(fields[2], 10, 64)
+		if errSize == nil && errAlloc == nil {
+			out[fields[0]] = [2]uint64{size, alloc}
+		}
+	}
+	return out
+}
+
+func zfsHealth(state string) string {
+	switch state {
+	case "ONLINE":
+		return entities.HealthOK
+	case "DEGRADED":
+		return entities.HealthDegraded
+	default:
+		// FAULTED, UNAVAIL, SUSPENDED, REMOVED, OFFLINE
+		return entities.HealthFailed
+	}
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// HistoricalStorageSample is the health of one device at one check.
+type HistoricalStorageSample struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_storage_health_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_storage_health_host_ts,priority:2"`
+	Kind      string    `json:"kind" gorm:"primaryKey;size:8"`
+	Name      string    `json:"name" gorm:"primaryKey;size:128"`
+
+	Health        string `json:"health" gorm:"size:16;not null"`
+	State         string `json:"state" gorm:"size:32"`
+	Level         string `json:"level" gorm:"size:16"`
+	Devices       int    `json:"devices"`
+	ActiveDevices int    `json:"active_devices"`
+	// FailedDevices is the comma-separated list of faulty md members
+	FailedDevices  string   `json:"failed_devices" gorm:"size:512"`
+	SyncAction     string   `json:"sync_action" gorm:"size:16"`
+	SyncPercent    *float64 `json:"sync_percent"`
+	SizeBytes      *uint64  `json:"size_bytes"`
+	AllocatedBytes *uint64  `json:"allocated_bytes"`
+	ErrorCount     uint64   `json:"error_count"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalStorageSample) TableName() string { return "storage_health_samples" }
+
+// ToSamples flattens a check into one row per device.
+func (m StorageHealth) ToSamples(hostId uint) []HistoricalStorageSample {
+	ts := m.Timestamp.UTC().Truncate(time.Microsecond)
+	rows := make([]HistoricalStorageSample, 0, len(m.Devices))
+	for _, d := range m.Devices {
+		rows = append(rows, HistoricalStorageSample{
+			HostID:         hostId,
+			Timestamp:      ts,
+			Kind:           d.Kind,
+			Name:           d.Name,
+			Health:         d.Health,
+			State:          d.State,
+			Level:          d.Level,
+			Devices:        d.Devices,
+			ActiveDevices:  d.ActiveDevices,
+			FailedDevices:  strings.Join(d.FailedDevices, ","),
+			SyncAction:     d.SyncAction,
+			SyncPercent:    d.SyncPercent,
+			SizeBytes:      d.SizeBytes,
+			AllocatedBytes: d.AllocatedBytes,
+			ErrorCount:     d.ErrorCount,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"time"
+
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+)
+
+// Kinds of storage devices.
+const (
+	KindMD    = "md"
+	KindZFS   = "zfs"
+	KindBtrfs = "btrfs"
+)
+
+// Health states, from best to worst.
+const (
+	HealthOK = "ok"
+	// HealthRebuilding is a degraded md array recovering onto a replacement member
+	HealthRebuilding = "rebuilding"
+	// HealthErrors is a btrfs filesystem with non-zero device error counters
+	HealthErrors   = "errors"
+	HealthDegraded = "degraded"
+	HealthFailed   = "failed"
+)
+
+// Severity returns the host event severity of a health state.
+func Severity(health string) string {
+	switch health {
+	case HealthOK:
+		return evententities.SeverityOK
+	case HealthRebuilding, HealthErrors:
+		return evententities.SeverityWarning
+	default:
+		return evententities.SeverityCritical
+	}
+}
+
+// StorageHealth is one check of every md array, ZFS pool and btrfs filesystem.
+type StorageHealth struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Devices   []StorageDevice `json:"devices"`
+	// Events are the health changes of this check; a pushed check carries the events of recent
+	// checks so none is lost between pushes
+	Events []evententities.HostEvent `json:"events,omitempty"`
+}
+
+// StorageDevice is the health of one md array, ZFS pool or btrfs filesystem. Fields that do
+// not apply to the kind are empty.
+type StorageDevice struct {
+	Kind string `json:"kind"`
+	// Name is the array (md0), pool (tank) or filesystem UUID
+	Name   string `json:"name"`
+	Health string `json:"health"`
+	// State is the raw state: "active" / "inactive" for md arrays, ONLINE, DEGRADED, FAULTED, ... for ZFS pools
+	State string `json:"state,omitempty"`
+
+	// Level is the md RAID level (raid1, raid5, ...)
+	Level string `json:"level,omitempty"`
+	// Devices is the number of members the array should have, ActiveDevices those in sync
+	Devices       int `json:"devices,omitempty"`
+	ActiveDevices int `json:"active_devices,omitempty"`
+	// FailedDevices are the members marked faulty, e.g. ["sdb1"]
+	FailedDevices []string `json:"failed_devices,omitempty"`
+	// SyncAction is the running resync, recovery, check, repair or reshape; SyncPercent is nil
+	// while it is delayed or pending
+	SyncAction        string   `json:"sync_action,omitempty"`
+	SyncPercent       *float64 `json:"sync_percent,omitempty"`
+	SyncFinishMinutes *float64 `json:"sync_finish_minutes,omitempty"`
+
+	// SizeBytes and AllocatedBytes are the ZFS pool capacity; nil without the zpool command
+	SizeBytes      *uint64 `json:"size_bytes,omitempty"`
+	AllocatedBytes *uint64 `json:"allocated_bytes,omitempty"`
+
+	// Label is the btrfs filesystem label
+	Label string `json:"label,omitempty"`
+	// BtrfsDevices are the per-device error counters of a btrfs filesystem (kernel 5.14+)
+	BtrfsDevices []BtrfsDevice `json:"btrfs_devices,omitempty"`
+	// ErrorCount sums the btrfs device error counters
+	ErrorCount uint64 `json:"error_count,omitempty"`
+}
+
+// Subject identifies the device in host events, e.g. "md/md0" or "zfs/tank".
+func (d StorageDevice) Subject() string { return d.Kind + "/" + d.Name }
+
+// BtrfsDevice holds the error counters of one btrfs device. They persist on disk until reset
+// with `btrfs device stats -z`.
+type BtrfsDevice struct {
+	DevID            string `json:"devid"`
+	Missing          bool   `json:"missing"`
+	WriteErrors      uint64 `json:"write_errs"`
+	ReadErrors       uint64 `json:"read_errs"`
+	FlushErrors      uint64 `json:"flush_errs"`
+	CorruptionErrors uint64 `json:"corruption_errs"`
+	GenerationErrors uint64 `json:"generation_errs"`
+}
+
+// Errors returns the sum of the device's error counters.
+func (d BtrfsDevice) Errors() uint64 {
+	return d.WriteErrors + d.ReadErrors + d.FlushErrors + d.CorruptionErrors + d.GenerationErrors
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/storage/infrastructure/entities"
+)
+
+type StorageRepository interface {
+	// SaveCurrentMetric stores one row per device; a check already stored (e.g. pushed twice) is skipped.
+	SaveCurrentMetric(ctx context.Context, metric entities.StorageHealth, hostId uint) error
+	// GetLatestByHost returns the rows of the most recent check of a host.
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalStorageSample, error)
+	// GetHistoryByHost returns rows in time order, optionally for one device name.
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, name string) ([]entities.HistoricalStorageSample, error)
+}
+
+type storageRepository struct {
+	db *gorm.DB
+}
+
+func NewStorageRepository(db *gorm.DB) StorageRepository {
+	return &storageRepository{db: db}
+}
+
+func (r *storageRepository) SaveCurrentMetric(ctx context.Context, metric entities.StorageHealth, hostId uint) error {
+	rows := metric.ToSamples(hostId)
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *storageRepository) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalStorageSample, error) {
+	rows := []entities.HistoricalStorageSample{}
+	latest := r.db.Model(&entities.HistoricalStorageSample{}).Select("MAX(timestamp)").Where("host_id = ?", hostId)
+	err := r.db.WithContext(ctx).
+		Where("host_id = ? AND timestamp = (?)", hostId, latest).
+		Order("kind ASC, name ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *storageRepository) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, name string) ([]entities.HistoricalStorageSample, error) {
+	rows := []entities.HistoricalStorageSample{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if name != "" {
+		q = q.Where("name = ?", name)
+	}
+	err := q.Order("timestamp ASC, kind ASC, name ASC").Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,67 @@
+// Package storage registers the storage health module, which checks software RAID arrays, ZFS
+// pools and btrfs filesystems and reports health changes as events.
+package storage
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	storageservice "system-stats/internal/modules/storage/application"
+	"system-stats/internal/modules/storage/infrastructure/collectors"
+	"system-stats/internal/modules/storage/infrastructure/entities"
+	"system-stats/internal/modules/storage/infrastructure/repositories"
+	handlers "system-stats/internal/modules/storage/presentation"
+)
+
+// Module checks md arrays, ZFS pools and btrfs filesystems and serves /storage.
+type Module struct{}
+
+func (Module) Name() string { return "storage" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 14,
+		Name:    "storage_health_samples_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalStorageSample{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalStorageSample{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"storage_health_samples"} }
+
+func (Module) HostTables() []string { return []string{"storage_health_samples"} }
+
+// Build leaves out the collector on hosts without /proc/mdstat, ZFS kstats or btrfs in sysfs;
+// stored and pushed checks stay queryable.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	service := storageservice.NewService(deps.Logger, repositories.NewStorageRepository(deps.DB), deps.Events)
+	handler := handlers.NewStorageHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/storage", handler.HandleStorage)
+		},
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}
+	if !collectors.Available() {
+		deps.Logger.Info("No md, ZFS or btrfs storage found, not checking storage health")
+		return inst, nil
+	}
+	// Storage health is not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.StorageHealth]("storage", service.Collect, service.Save, nil)
+	inst.Collector = &collector
+	inst.Metrics = storageservice.NewPrometheusCollector(service)
+	inst.PushData = registry.PushLatest(service.PushData)
+	return inst, nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	storageservice "system-stats/internal/modules/storage/application"
+)
+
+// StorageHandler handles HTTP requests for storage health.
+type StorageHandler struct {
+	logger  *log.Logger
+	service storageservice.Service
+	hosts   hostservice.Service
+}
+
+// NewStorageHandler creates a new HTTP handler for storage endpoints.
+func NewStorageHandler(logger *log.Logger, service storageservice.Service, hosts hostservice.Service) *StorageHandler {
+	return &StorageHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleStorage returns the latest health of every md array, ZFS pool and btrfs filesystem and the health history.
+//
+// @Summary     Storage health
+// @Description Returns the latest health (ok, rebuilding, errors, degraded, failed) of every software RAID array (/proc/mdstat: members, failed devices, resync progress), ZFS pool (state, capacity) and btrfs filesystem (device error counters) and the history of checks. Health changes are listed by /events.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       name     query    string   false  "Limit history to one array, pool or filesystem UUID"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /storage [get]
+func (h *StorageHandler) HandleStorage(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyStoragePayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for storage health", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	latest, err := h.service.GetLatestByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest storage health")
+			return
+		}
+		h.logger.Error("Failed to fetch latest storage health", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetHistoryByHost(ctx, effective, hours, c.Query("name"))
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching storage health history")
+			return
+		}
+		h.logger.Error("Failed to fetch storage health history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"latest":  latest,
+		"history": history,
+	})
+}
@@ -37,7 +37,7 @@ func NewSystemMetricsCollector(logger *log.Logger) *systemMetricsCollector {
 		logger:           logger,
 		cpuCollector:     cpucollectors.NewCPUMetricsCollector(logger),
 		memoryCollector:  memorycollectors.NewMemoryMetricsCollector(logger),
-		diskCollector:    diskcollectors.NewDiskMetricsCollector(logger),
+		diskCollector:    diskcollectors.NewDiskMetricsCollector(logger, diskcollectors.DefaultMountTimeout),
 		networkCollector: networkcollectors.NewNetworkMetricsCollector(logger),
 	}
 }
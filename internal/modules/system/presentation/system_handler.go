@@ -41,7 +41,7 @@ func parseHostIDQueryOptional(c *gin.Context) uint {
 // HandleCurrentMetrics returns current system metrics for the dashboard.
 //
 // @Summary     Current system metrics
-// @Description Returns an aggregated snapshot of all live system metrics (CPU, memory, disk, network, Docker). Only available for this server instance; remote cluster hosts return empty fields until ingestion is implemented.
+// @Description Returns the latest collection snapshot of all system metrics (CPU, memory, disk, network, Docker); no collector runs per request, so fields are empty until the first collection. Only available for this server instance; remote cluster hosts return empty fields until ingestion is implemented.
 // @Tags        metrics
 // @Produce     json
 // @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
@@ -77,12 +77,17 @@ func (h *SystemHandler) HandleCurrentMetrics(c *gin.Context) {
 	}
 
 	h.logger.Debug("Handling current metrics JSON request", "client_ip", c.ClientIP())
-	metrics, err := h.service.CollectAllCurrent(ctx)
+	snap := h.service.Current()
+	if snap == nil {
+		c.JSON(http.StatusOK, metricshost.EmptyCurrentMetricsPayload())
+		return
+	}
+	body, err := snap.JSON()
 	if err != nil {
-		h.logger.Error("Failed to get current metrics", "error", err)
+		h.logger.Error("Failed to encode current metrics", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	h.logger.Debug("Current metrics response sent successfully")
-	c.JSON(http.StatusOK, metrics)
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
 }
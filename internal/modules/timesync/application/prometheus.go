@@ -0,0 +1,80 @@
+package timesync
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	descTimeSynced     = prometheus.NewDesc("system_time_synced", "1 when the kernel considers the clock synchronised (adjtimex), else 0.", nil, nil)
+	descTimeOffset     = prometheus.NewDesc("system_time_offset_seconds", "Remaining clock offset the kernel is correcting.", nil, nil)
+	descTimeMaxError   = prometheus.NewDesc("system_time_max_error_seconds", "Kernel maximum clock error estimate.", nil, nil)
+	descTimeEstError   = prometheus.NewDesc("system_time_estimated_error_seconds", "Kernel estimated clock error.", nil, nil)
+	descTimeFrequency  = prometheus.NewDesc("system_time_frequency_ppm", "Kernel clock frequency correction.", nil, nil)
+	descDaemonSynced   = prometheus.NewDesc("system_time_daemon_synced", "1 when the time sync daemon is synchronised to a reference, else 0.", []string{"daemon"}, nil)
+	descDaemonOffset   = prometheus.NewDesc("system_time_daemon_offset_seconds", "System clock offset from the daemon's reference time, positive when ahead (chrony).", []string{"daemon"}, nil)
+	descDaemonStratum  = prometheus.NewDesc("system_time_daemon_stratum", "NTP stratum of the daemon (chrony).", []string{"daemon"}, nil)
+	descClockSkew      = prometheus.NewDesc("system_clock_skew_seconds", "Agent clock minus this main's clock at the agent's last push, including push latency.", []string{"host_id"}, nil)
+	descClockSkewLimit = prometheus.NewDesc("system_clock_skew_threshold_seconds", "Clock skew beyond which a pushing host is flagged.", nil, nil)
+)
+
+// PrometheusCollector exports the latest in-memory time sync check and the clock skew of each
+// agent that pushed since start.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descTimeSynced
+	ch <- descTimeOffset
+	ch <- descTimeMaxError
+	ch <- descTimeEstError
+	ch <- descTimeFrequency
+	ch <- descDaemonSynced
+	ch <- descDaemonOffset
+	ch <- descDaemonStratum
+	ch <- descClockSkew
+	ch <- descClockSkewLimit
+}
+
+// Collect sends the last check and the skews; nothing is read from the database.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(descClockSkewLimit, prometheus.GaugeValue, c.service.SkewThreshold().Seconds())
+	for hostId, skew := range c.service.ClockSkews() {
+		ch <- prometheus.MustNewConstMetric(descClockSkew, prometheus.GaugeValue, skew, strconv.FormatUint(uint64(hostId), 10))
+	}
+
+	latest := c.service.Latest()
+	if latest == nil {
+		return
+	}
+	if k := latest.Kernel; k != nil {
+		ch <- prometheus.MustNewConstMetric(descTimeSynced, prometheus.GaugeValue, boolValue(k.Synced))
+		ch <- prometheus.MustNewConstMetric(descTimeOffset, prometheus.GaugeValue, k.OffsetSeconds)
+		ch <- prometheus.MustNewConstMetric(descTimeMaxError, prometheus.GaugeValue, k.MaxErrorSeconds)
+		ch <- prometheus.MustNewConstMetric(descTimeEstError, prometheus.GaugeValue, k.EstErrorSeconds)
+		ch <- prometheus.MustNewConstMetric(descTimeFrequency, prometheus.GaugeValue, k.FrequencyPPM)
+	}
+	if d := latest.Daemon; d != nil {
+		ch <- prometheus.MustNewConstMetric(descDaemonSynced, prometheus.GaugeValue, boolValue(d.Synced), d.Name)
+		if d.OffsetSeconds != nil {
+			ch <- prometheus.MustNewConstMetric(descDaemonOffset, prometheus.GaugeValue, *d.OffsetSeconds, d.Name)
+		}
+		if d.Stratum != nil {
+			ch <- prometheus.MustNewConstMetric(descDaemonStratum, prometheus.GaugeValue, float64(*d.Stratum), d.Name)
+		}
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,194 @@
+package timesync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/timesync/infrastructure/collectors"
+	"system-stats/internal/modules/timesync/infrastructure/entities"
+	"system-stats/internal/modules/timesync/infrastructure/repositories"
+)
+
+// Clock skew events: a host whose clock is off by more than the threshold at a push gets a
+// warning, and an ok event once a push is within it again.
+const (
+	EventSource    = "timesync"
+	EventSubject   = "clock"
+	EventSkew      = "skew"
+	EventRecovered = "recovered"
+)
+
+type Service interface {
+	// Collect reads the time sync state and keeps it as the latest in-memory check.
+	Collect(ctx context.Context) (entities.TimeSyncMetric, error)
+	Save(ctx context.Context, metric entities.TimeSyncMetric, hostId uint) error
+	// Latest returns this instance's last check (no database access); nil before the first one.
+	Latest() *entities.TimeSyncMetric
+	// ReceivePush stores a check pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	// RecordClockSkew stores the skew of an agent's clock at a push and flags the host when it
+	// is beyond the threshold.
+	RecordClockSkew(ctx context.Context, hostId uint, sentAt, receivedAt time.Time) error
+	// ClockSkews returns the skew in seconds at the last push of each host since start.
+	ClockSkews() map[uint]float64
+	// SkewThreshold is the skew beyond which a host is flagged.
+	SkewThreshold() time.Duration
+	GetLatestByHost(ctx context.Context, hostId uint) (*entities.HistoricalTimeSyncSample, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalTimeSyncSample, error)
+	GetLatestClockSkewByHost(ctx context.Context, hostId uint) (*entities.ClockSkewSample, error)
+	GetClockSkewHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.ClockSkewSample, error)
+}
+
+type service struct {
+	logger        *log.Logger
+	collector     *collectors.TimeSyncCollector
+	repo          repositories.TimeSyncRepository
+	events        eventsservice.Service
+	skewThreshold time.Duration
+
+	mu     sync.RWMutex
+	latest *entities.TimeSyncMetric
+	// skews and flagged hold each pushing host's last skew and whether it is over the threshold
+	skews   map[uint]float64
+	flagged map[uint]bool
+}
+
+// NewService creates the time sync service; events may be nil, which records no skew events.
+func NewService(logger *log.Logger, repo repositories.TimeSyncRepository, events eventsservice.Service, skewThreshold time.Duration) Service {
+	return &service{
+		logger:        logger,
+		collector:     collectors.NewTimeSyncCollector(logger),
+		repo:          repo,
+		events:        events,
+		skewThreshold: skewThreshold,
+		skews:         make(map[uint]float64),
+		flagged:       make(map[uint]bool),
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.TimeSyncMetric, error) {
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	s.latest = &metric
+	s.mu.Unlock()
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.TimeSyncMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "timesync", "error", err, "host_id", hostId)
+		return err
+	}
+	return nil
+}
+
+func (s *service) Latest() *entities.TimeSyncMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.TimeSyncMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) RecordClockSkew(ctx context.Context, hostId uint, sentAt, receivedAt time.Time) error {
+	skew := sentAt.Sub(receivedAt)
+	if err := s.repo.SaveClockSkew(ctx, entities.ClockSkewSample{HostID: hostId, Timestamp: receivedAt, SkewSeconds: skew.Seconds()}); err != nil {
+		return err
+	}
+	over := skew.Abs() > s.skewThreshold
+
+	s.mu.Lock()
+	s.skews[hostId] = skew.Seconds()
+	flagged, known := s.flagged[hostId]
+	s.mu.Unlock()
+	if s.events == nil {
+		return nil
+	}
+	if !known {
+		// After a restart a problem left open by the previous run decides whether the host is flagged
+		problems, err := s.events.Problems(ctx, hostId)
+		if err != nil {
+			return err
+		}
+		for _, p := range problems {
+			if p.Source == EventSource && p.Subject == EventSubject {
+				flagged = true
+			}
+		}
+	}
+	s.mu.Lock()
+	s.flagged[hostId] = over
+	s.mu.Unlock()
+	if over == flagged {
+		return nil
+	}
+
+	event := evententities.HostEvent{
+		Timestamp: receivedAt.UTC(),
+		Source:    EventSource,
+		Subject:   EventSubject,
+		Kind:      EventRecovered,
+		Severity:  evententities.SeverityOK,
+		Message:   fmt.Sprintf("Clock within %s of main (skew %s)", s.skewThreshold, skew.Round(time.Millisecond)),
+	}
+	if over {
+		direction := "ahead of"
+		if skew < 0 {
+			direction = "behind"
+		}
+		event.Kind = EventSkew
+		event.Severity = evententities.SeverityWarning
+		event.Message = fmt.Sprintf("Clock is %s %s main (threshold %s)", skew.Abs().Round(time.Millisecond), direction, s.skewThreshold)
+		s.logger.Warn("Agent clock skew over threshold", "host_id", hostId, "skew", skew, "threshold", s.skewThreshold)
+	}
+	return s.events.Record(ctx, hostId, []evententities.HostEvent{event})
+}
+
+func (s *service) ClockSkews() map[uint]float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[uint]float64, len(s.skews))
+	for id, v := range s.skews {
+		out[id] = v
+	}
+	return out
+}
+
+func (s *service) SkewThreshold() time.Duration {
+	return s.skewThreshold
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) (*entities.HistoricalTimeSyncSample, error) {
+	return s.repo.GetLatestByHost(ctx, hostId)
+}
+
+func (s *service) GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalTimeSyncSample, error) {
+	return s.repo.GetHistoryByHost(ctx, hostId, hours)
+}
+
+func (s *service) GetLatestClockSkewByHost(ctx context.Context, hostId uint) (*entities.ClockSkewSample, error) {
+	return s.repo.GetLatestClockSkewByHost(ctx, hostId)
+}
+
+func (s *service) GetClockSkewHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.ClockSkewSample, error) {
+	return s.repo.GetClockSkewHistoryByHost(ctx, hostId, hours)
+}
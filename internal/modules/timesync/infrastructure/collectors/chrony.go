@@ -0,0 +1,84 @@
+package collectors
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"system-stats/internal/modules/timesync/infrastructure/entities"
+)
+
+// chronycTimeout bounds a chronyc call; chronyd answers locally in milliseconds
+const chronycTimeout = 5 * time.Second
+
+// chronyNotSynced is chrony's leap status while it has no usable reference
+const chronyNotSynced = "Not synchronised"
+
+// readChrony asks chronyd for its tracking state. chronyc reaches chronyd through its Unix
+// socket or UDP port 323 on localhost, so in a container it needs the socket mounted or host
+// networking.
+func readChrony(ctx context.Context) (*entities.SyncDaemon, error) {
+	ctx, cancel := context.WithTimeout(ctx, chronycTimeout)
+	defer cancel()
+	// -n skips reverse DNS lookups of the reference
+	out, err := exec.CommandContext(ctx, "chronyc", "-n", "-c", "tracking").Output()
+	if err != nil {
+		return nil, fmt.Errorf("run chronyc: %w", err)
+	}
+	return ParseChronyTracking(out)
+}
+
+// ParseChronyTracking parses the CSV output of `chronyc -c tracking`: reference ID, reference,
+// stratum, reference time, system time, last offset, RMS offset, frequency, residual frequency,
+// skew, root delay, root dispersion, update interval and leap status. chronyc prints the system
+// time as the correction still to apply (positive when the clock is slow); it is negated so the
+// offset is positive when the clock is ahead.
+func ParseChronyTracking(out []byte) (*entities.SyncDaemon, error) {
+	records, err := csv.NewReader(strings.NewReader(strings.TrimSpace(string(out)))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) != 1 || len(records[0]) < 14 {
+		return nil, errors.New("unexpected chronyc tracking output")
+	}
+	f := records[0]
+	num := func(i int) (float64, error) {
+		v, err := strconv.ParseFloat(f[i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("chronyc tracking field %d: %w", i+1, err)
+		}
+		return v, nil
+	}
+	var values [14]float64
+	for _, i := range []int{2, 3, 4, 10, 11} {
+		if values[i], err = num(i); err != nil {
+			return nil, err
+		}
+	}
+	d := &entities.SyncDaemon{
+		Name:       entities.DaemonChrony,
+		Reference:  f[1],
+		LeapStatus: f[13],
+	}
+	stratum := int(values[2])
+	d.Stratum = &stratum
+	d.Synced = d.LeapStatus != chronyNotSynced && stratum > 0
+	if !d.Synced {
+		// Reference fields are zero until chronyd selects a source
+		return d, nil
+	}
+	offset, delay, dispersion := -values[4], values[10], values[11]
+	d.OffsetSeconds, d.RootDelaySeconds, d.RootDispersionSeconds = &offset, &delay, &dispersion
+	if values[3] > 0 {
+		sec, frac := math.Modf(values[3])
+		last := time.Unix(int64(sec), int64(frac*1e9)).UTC()
+		d.LastSync = &last
+	}
+	return d, nil
+}
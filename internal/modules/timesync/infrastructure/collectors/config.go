@@ -0,0 +1,30 @@
+package collectors
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// SkewThresholdEnv sets how far an agent's clock may be from main's at a cluster push before
+	// the host is flagged
+	SkewThresholdEnv = "TIMESYNC_SKEW_THRESHOLD"
+	// DefaultSkewThreshold leaves room for push latency over slow links; synced clocks are
+	// milliseconds apart
+	DefaultSkewThreshold = 2 * time.Second
+)
+
+// SkewThresholdFromEnv reads TIMESYNC_SKEW_THRESHOLD.
+func SkewThresholdFromEnv() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(SkewThresholdEnv))
+	if raw == "" {
+		return DefaultSkewThreshold, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration like \"500ms\", got %q", SkewThresholdEnv, raw)
+	}
+	return d, nil
+}
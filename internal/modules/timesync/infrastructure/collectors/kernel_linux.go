@@ -0,0 +1,36 @@
+//go:build linux
+
+package collectors
+
+import (
+	"golang.org/x/sys/unix"
+
+	"system-stats/internal/modules/timesync/infrastructure/entities"
+)
+
+// kernelClockSupported reports whether ReadKernelClock can read the kernel clock here.
+const kernelClockSupported = true
+
+// ReadKernelClock reads the kernel's NTP state with a read-only adjtimex(2) call, which needs no
+// privileges. The clock is shared by every container on the host, so no HOST_* path is needed.
+func ReadKernelClock() (*entities.KernelClock, error) {
+	var tx unix.Timex
+	state, err := unix.Adjtimex(&tx)
+	if err != nil {
+		return nil, err
+	}
+	// The offset is in microseconds unless the kernel runs in nanosecond mode
+	offsetUnit := 1e-6
+	if tx.Status&unix.STA_NANO != 0 {
+		offsetUnit = 1e-9
+	}
+	return &entities.KernelClock{
+		Synced:          state != unix.TIME_ERROR && tx.Status&unix.STA_UNSYNC == 0,
+		OffsetSeconds:   float64(tx.Offset) * offsetUnit,
+		MaxErrorSeconds: float64(tx.Maxerror) / 1e6,
+		EstErrorSeconds: float64(tx.Esterror) / 1e6,
+		// freq is in ppm with a 16-bit fractional part
+		FrequencyPPM: float64(tx.Freq) / 65536,
+		Status:       int(tx.Status),
+	}, nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package collectors
+
+import "system-stats/internal/modules/timesync/infrastructure/entities"
+
+const kernelClockSupported = false
+
+// ReadKernelClock has no adjtimex to read outside Linux.
+func ReadKernelClock() (*entities.KernelClock, error) {
+	return nil, nil
+}
@@ -0,0 +1,61 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/timesync/infrastructure/entities"
+)
+
+// Available reports whether the host has a kernel clock or a sync daemon this collector reads.
+func Available() bool {
+	if kernelClockSupported {
+		return true
+	}
+	if _, err := exec.LookPath("chronyc"); err == nil {
+		return true
+	}
+	_, err := os.Stat(hostfs.Root("run", "systemd", "timesync"))
+	return err == nil
+}
+
+// TimeSyncCollector reads the kernel clock discipline and chrony's or systemd-timesyncd's state.
+type TimeSyncCollector struct {
+	logger *log.Logger
+}
+
+// NewTimeSyncCollector creates a time sync collector.
+func NewTimeSyncCollector(logger *log.Logger) *TimeSyncCollector {
+	return &TimeSyncCollector{logger: logger}
+}
+
+// Collect reads the kernel clock and the daemon. chrony is asked first when chronyc is
+// installed; a host running neither has no daemon, which is not an error.
+func (c *TimeSyncCollector) Collect(ctx context.Context) (entities.TimeSyncMetric, error) {
+	c.logger.Debug("Collecting time sync state")
+	metric := entities.TimeSyncMetric{Timestamp: time.Now().UTC()}
+	kernel, err := ReadKernelClock()
+	if err != nil {
+		return metric, err
+	}
+	metric.Kernel = kernel
+
+	if _, err := exec.LookPath("chronyc"); err == nil {
+		daemon, err := readChrony(ctx)
+		if err == nil {
+			metric.Daemon = daemon
+			return metric, nil
+		}
+		if ctx.Err() != nil {
+			return metric, ctx.Err()
+		}
+		c.logger.Debug("chronyc tracking failed, trying systemd-timesyncd", "error", err)
+	}
+	metric.Daemon = ReadTimesyncd()
+	return metric, nil
+}
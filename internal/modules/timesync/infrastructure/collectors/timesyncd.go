@@ -0,0 +1,24 @@
+package collectors
+
+import (
+	"os"
+
+	"system-stats/internal/app/hostfs"
+	"system-stats/internal/modules/timesync/infrastructure/entities"
+)
+
+// ReadTimesyncd reads systemd-timesyncd's state from HOST_ROOT/run/systemd/timesync; nil when
+// the directory is missing (timesyncd not running). timesyncd touches `synchronized` there each
+// time it synchronises the clock (systemd 239+), so its mtime is the last sync.
+func ReadTimesyncd() *entities.SyncDaemon {
+	if st, err := os.Stat(hostfs.Root("run", "systemd", "timesync")); err != nil || !st.IsDir() {
+		return nil
+	}
+	d := &entities.SyncDaemon{Name: entities.DaemonTimesyncd}
+	if st, err := os.Stat(hostfs.Root("run", "systemd", "timesync", "synchronized")); err == nil {
+		d.Synced = true
+		last := st.ModTime().UTC()
+		d.LastSync = &last
+	}
+	return d
+}
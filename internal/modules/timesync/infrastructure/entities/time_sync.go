@@ -0,0 +1,118 @@
+package entities
+
+import "time"
+
+// Time sync daemons read besides the kernel clock.
+const (
+	DaemonChrony    = "chrony"
+	DaemonTimesyncd = "timesyncd"
+)
+
+// TimeSyncMetric is one check of the kernel clock discipline and the time sync daemon.
+type TimeSyncMetric struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Kernel is nil on hosts without adjtimex (non-Linux)
+	Kernel *KernelClock `json:"kernel"`
+	// Daemon is nil when neither chrony nor systemd-timesyncd is found
+	Daemon *SyncDaemon `json:"daemon"`
+}
+
+// KernelClock is the kernel's NTP state from adjtimex(2).
+type KernelClock struct {
+	// Synced is false when the kernel flags the clock unsynchronised (STA_UNSYNC, or a maximum
+	// error that grew past 16 s without an update from a sync daemon)
+	Synced bool `json:"synced"`
+	// OffsetSeconds is the remaining offset the kernel PLL is correcting
+	OffsetSeconds   float64 `json:"offset_seconds"`
+	MaxErrorSeconds float64 `json:"max_error_seconds"`
+	EstErrorSeconds float64 `json:"est_error_seconds"`
+	// FrequencyPPM is the frequency correction applied to the clock
+	FrequencyPPM float64 `json:"frequency_ppm"`
+	// Status is the raw timex status bits (STA_*)
+	Status int `json:"status"`
+}
+
+// SyncDaemon is the state of chrony (chronyc tracking) or systemd-timesyncd.
+type SyncDaemon struct {
+	Name   string `json:"name"`
+	Synced bool   `json:"synced"`
+	// Reference is the server the daemon follows (chrony only)
+	Reference string `json:"reference,omitempty"`
+	Stratum   *int   `json:"stratum"`
+	// OffsetSeconds is how far the system clock is ahead of the daemon's estimate of true time
+	// (negative when behind); chrony only
+	OffsetSeconds         *float64 `json:"offset_seconds"`
+	RootDelaySeconds      *float64 `json:"root_delay_seconds"`
+	RootDispersionSeconds *float64 `json:"root_dispersion_seconds"`
+	// LeapStatus is chrony's leap status: Normal, Insert second, Delete second or Not synchronised
+	LeapStatus string `json:"leap_status,omitempty"`
+	// LastSync is the time of the last clock update from the reference
+	LastSync *time.Time `json:"last_sync"`
+}
+
+// HistoricalTimeSyncSample is one check stored per host.
+type HistoricalTimeSyncSample struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_timesync_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_timesync_host_ts,priority:2"`
+
+	KernelSynced          *bool    `json:"kernel_synced"`
+	KernelOffsetSeconds   *float64 `json:"kernel_offset_seconds"`
+	KernelMaxErrorSeconds *float64 `json:"kernel_max_error_seconds"`
+	KernelEstErrorSeconds *float64 `json:"kernel_est_error_seconds"`
+	KernelFrequencyPPM    *float64 `json:"kernel_frequency_ppm" gorm:"column:kernel_frequency_ppm"`
+	KernelStatus          *int     `json:"kernel_status"`
+
+	Daemon                string     `json:"daemon" gorm:"size:16"`
+	DaemonSynced          *bool      `json:"daemon_synced"`
+	Reference             string     `json:"reference" gorm:"size:255"`
+	Stratum               *int       `json:"stratum"`
+	DaemonOffsetSeconds   *float64   `json:"daemon_offset_seconds"`
+	RootDelaySeconds      *float64   `json:"root_delay_seconds"`
+	RootDispersionSeconds *float64   `json:"root_dispersion_seconds"`
+	LeapStatus            string     `json:"leap_status" gorm:"size:32"`
+	LastSync              *time.Time `json:"last_sync"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalTimeSyncSample) TableName() string { return "timesync_samples" }
+
+// ToSample flattens a check into its row.
+func (m TimeSyncMetric) ToSample(hostId uint) HistoricalTimeSyncSample {
+	row := HistoricalTimeSyncSample{HostID: hostId, Timestamp: m.Timestamp.UTC().Truncate(time.Microsecond)}
+	if k := m.Kernel; k != nil {
+		synced, offset, maxErr, estErr, freq, status := k.Synced, k.OffsetSeconds, k.MaxErrorSeconds, k.EstErrorSeconds, k.FrequencyPPM, k.Status
+		row.KernelSynced = &synced
+		row.KernelOffsetSeconds = &offset
+		row.KernelMaxErrorSeconds = &maxErr
+		row.KernelEstErrorSeconds = &estErr
+		row.KernelFrequencyPPM = &freq
+		row.KernelStatus = &status
+	}
+	if d := m.Daemon; d != nil {
+		synced := d.Synced
+		row.Daemon = d.Name
+		row.DaemonSynced = &synced
+		row.Reference = d.Reference
+		row.Stratum = d.Stratum
+		row.DaemonOffsetSeconds = d.OffsetSeconds
+		row.RootDelaySeconds = d.RootDelaySeconds
+		row.RootDispersionSeconds = d.RootDispersionSeconds
+		row.LeapStatus = d.LeapStatus
+		row.LastSync = d.LastSync
+	}
+	return row
+}
+
+// ClockSkewSample is the difference between an agent's clock and main's at one cluster push.
+type ClockSkewSample struct {
+	HostID uint `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_clock_skew_host_ts,priority:1"`
+	// Timestamp is when main received the push
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_clock_skew_host_ts,priority:2"`
+	// SkewSeconds is the agent's send time minus main's receive time: positive when the agent's
+	// clock is ahead. It includes the request's network latency, so a synced agent reads
+	// slightly negative.
+	SkewSeconds float64 `json:"skew_seconds"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (ClockSkewSample) TableName() string { return "clock_skew_samples" }
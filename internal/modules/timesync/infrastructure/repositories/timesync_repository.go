@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/timesync/infrastructure/entities"
+)
+
+type TimeSyncRepository interface {
+	// SaveCurrentMetric stores one row per check; a check pushed twice is stored once.
+	SaveCurrentMetric(ctx context.Context, metric entities.TimeSyncMetric, hostId uint) error
+	// GetLatestByHost returns the most recent check of a host; nil when there is none.
+	GetLatestByHost(ctx context.Context, hostId uint) (*entities.HistoricalTimeSyncSample, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalTimeSyncSample, error)
+	SaveClockSkew(ctx context.Context, sample entities.ClockSkewSample) error
+	// GetLatestClockSkewByHost returns the skew at the host's last push; nil when there is none.
+	GetLatestClockSkewByHost(ctx context.Context, hostId uint) (*entities.ClockSkewSample, error)
+	GetClockSkewHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.ClockSkewSample, error)
+}
+
+type timeSyncRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeSyncRepository(db *gorm.DB) TimeSyncRepository {
+	return &timeSyncRepository{db: db}
+}
+
+func (r *timeSyncRepository) SaveCurrentMetric(ctx context.Context, metric entities.TimeSyncMetric, hostId uint) error {
+	row := metric.ToSample(hostId)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error
+}
+
+func (r *timeSyncRepository) GetLatestByHost(ctx context.Context, hostId uint) (*entities.HistoricalTimeSyncSample, error) {
+	var row entities.HistoricalTimeSyncSample
+	err := r.db.WithContext(ctx).Where("host_id = ?", hostId).Order("timestamp DESC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (r *timeSyncRepository) GetHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.HistoricalTimeSyncSample, error) {
+	rows := []entities.HistoricalTimeSyncSample{}
+	err := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours).
+		Order("timestamp ASC").Find(&rows).Error
+	return rows, err
+}
+
+func (r *timeSyncRepository) SaveClockSkew(ctx context.Context, sample entities.ClockSkewSample) error {
+	sample.Timestamp = sample.Timestamp.UTC().Truncate(time.Microsecond)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&sample).Error
+}
+
+func (r *timeSyncRepository) GetLatestClockSkewByHost(ctx context.Context, hostId uint) (*entities.ClockSkewSample, error) {
+	var row entities.ClockSkewSample
+	err := r.db.WithContext(ctx).Where("host_id = ?", hostId).Order("timestamp DESC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (r *timeSyncRepository) GetClockSkewHistoryByHost(ctx context.Context, hostId uint, hours float64) ([]entities.ClockSkewSample, error) {
+	rows := []entities.ClockSkewSample{}
+	err := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours).
+		Order("timestamp ASC").Find(&rows).Error
+	return rows, err
+}
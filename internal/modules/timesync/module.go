@@ -0,0 +1,77 @@
+// Package timesync registers the time sync module, which reports the kernel clock discipline and
+// chrony / systemd-timesyncd state, and on main measures the clock skew of every agent push.
+package timesync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	timesyncservice "system-stats/internal/modules/timesync/application"
+	"system-stats/internal/modules/timesync/infrastructure/collectors"
+	"system-stats/internal/modules/timesync/infrastructure/entities"
+	"system-stats/internal/modules/timesync/infrastructure/repositories"
+	handlers "system-stats/internal/modules/timesync/presentation"
+)
+
+// Module reads the time sync state into timesync_samples, stores agent clock skew in
+// clock_skew_samples and serves /timesync.
+type Module struct{}
+
+func (Module) Name() string { return "timesync" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 18,
+		Name:    "timesync_and_clock_skew_tables",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalTimeSyncSample{}, &entities.ClockSkewSample{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.ClockSkewSample{}, &entities.HistoricalTimeSyncSample{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"timesync_samples", "clock_skew_samples"} }
+
+func (Module) HostTables() []string { return []string{"timesync_samples", "clock_skew_samples"} }
+
+// Build fails on an invalid TIMESYNC_SKEW_THRESHOLD. Clock skew is measured on every push this
+// node receives, also on hosts where there is nothing to collect.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	threshold, err := collectors.SkewThresholdFromEnv()
+	if err != nil {
+		return registry.Instance{}, fmt.Errorf("timesync: %w", err)
+	}
+	service := timesyncservice.NewService(deps.Logger, repositories.NewTimeSyncRepository(deps.DB), deps.Events, threshold)
+	handler := handlers.NewTimeSyncHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/timesync", handler.HandleTimeSync)
+		},
+		Metrics: timesyncservice.NewPrometheusCollector(service),
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+		ReceivePushClock: func(ctx context.Context, hostId uint, sentAt, receivedAt time.Time) error {
+			return service.RecordClockSkew(ctx, hostId, sentAt, receivedAt)
+		},
+	}
+	if !collectors.Available() {
+		deps.Logger.Info("No kernel clock or time sync daemon to read, not collecting time sync state")
+		return inst, nil
+	}
+	// Time sync state is not part of the live snapshot
+	collector := historycore.NewModuleCollector[entities.TimeSyncMetric]("timesync", service.Collect, service.Save, nil)
+	inst.Collector = &collector
+	inst.PushData = registry.PushLatest(service.Latest)
+	return inst, nil
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	timesyncservice "system-stats/internal/modules/timesync/application"
+)
+
+// TimeSyncHandler handles HTTP requests for time synchronisation and clock skew.
+type TimeSyncHandler struct {
+	logger  *log.Logger
+	service timesyncservice.Service
+	hosts   hostservice.Service
+}
+
+// NewTimeSyncHandler creates a new HTTP handler for time sync endpoints.
+func NewTimeSyncHandler(logger *log.Logger, service timesyncservice.Service, hosts hostservice.Service) *TimeSyncHandler {
+	return &TimeSyncHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleTimeSync returns the time sync state of a host and the skew of its clock at cluster pushes.
+//
+// @Summary     Time synchronisation
+// @Description Returns the latest and historical time sync checks (kernel adjtimex sync state, offset, maximum / estimated error and frequency; chrony or systemd-timesyncd sync state, reference, stratum and offset), and for agents `skew` / `skew_history`: the agent's clock minus this main's at each push (positive when ahead, including push latency) with `skew_threshold_seconds`, beyond which the host has an open `timesync` problem.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /timesync [get]
+func (h *TimeSyncHandler) HandleTimeSync(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyTimeSyncPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for time sync data", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	latest, err := h.service.GetLatestByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest time sync check")
+			return
+		}
+		h.logger.Error("Failed to fetch latest time sync check", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetHistoryByHost(ctx, effective, hours)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching time sync history")
+			return
+		}
+		h.logger.Error("Failed to fetch time sync history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	skew, err := h.service.GetLatestClockSkewByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest clock skew")
+			return
+		}
+		h.logger.Error("Failed to fetch latest clock skew", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	skewHistory, err := h.service.GetClockSkewHistoryByHost(ctx, effective, hours)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching clock skew history")
+			return
+		}
+		h.logger.Error("Failed to fetch clock skew history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"latest":                 latest,
+		"history":                history,
+		"skew":                   skew,
+		"skew_history":           skewHistory,
+		"skew_threshold_seconds": h.service.SkewThreshold().Seconds(),
+	})
+}
@@ -0,0 +1,52 @@
+package watchlist
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"system-stats/internal/modules/watchlist/infrastructure/entities"
+)
+
+var (
+	descWatchUp        = prometheus.NewDesc("process_watch_up", "1 when at least min_count processes of the watch run, else 0.", []string{"watch"}, nil)
+	descWatchProcesses = prometheus.NewDesc("process_watch_processes", "Number of running processes matching the watch.", []string{"watch"}, nil)
+	descWatchCPU       = prometheus.NewDesc("process_watch_cpu_percent", "Summed CPU usage of the watch's processes (100 = one core).", []string{"watch"}, nil)
+	descWatchRSS       = prometheus.NewDesc("process_watch_resident_memory_bytes", "Summed resident memory of the watch's processes.", []string{"watch"}, nil)
+	descWatchRestarts  = prometheus.NewDesc("process_watch_restarts_total", "PID changes of the watch's processes since this instance started.", []string{"watch"}, nil)
+)
+
+// PrometheusCollector exports the latest in-memory watch states; scrapes never check processes.
+type PrometheusCollector struct {
+	service Service
+}
+
+func NewPrometheusCollector(service Service) *PrometheusCollector {
+	return &PrometheusCollector{service: service}
+}
+
+// Describe sends all descriptor pointers to the channel.
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descWatchUp
+	ch <- descWatchProcesses
+	ch <- descWatchCPU
+	ch <- descWatchRSS
+	ch <- descWatchRestarts
+}
+
+// Collect sends the state of every watch of the last check.
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	latest := c.service.Latest()
+	if latest == nil {
+		return
+	}
+	for _, w := range latest.Watches {
+		up := 0.0
+		if w.State == entities.StateUp {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(descWatchUp, prometheus.GaugeValue, up, w.Watch)
+		ch <- prometheus.MustNewConstMetric(descWatchProcesses, prometheus.GaugeValue, float64(w.Count), w.Watch)
+		ch <- prometheus.MustNewConstMetric(descWatchCPU, prometheus.GaugeValue, w.CPUPercent, w.Watch)
+		ch <- prometheus.MustNewConstMetric(descWatchRSS, prometheus.GaugeValue, float64(w.RSSBytes), w.Watch)
+		ch <- prometheus.MustNewConstMetric(descWatchRestarts, prometheus.CounterValue, float64(w.RestartsTotal), w.Watch)
+	}
+}
@@ -0,0 +1,129 @@
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/watchlist/infrastructure/collectors"
+	"system-stats/internal/modules/watchlist/infrastructure/entities"
+	"system-stats/internal/modules/watchlist/infrastructure/repositories"
+)
+
+const (
+	// maxPushEvents bounds the events kept for, and accepted from, a push
+	maxPushEvents = 256
+	// maxPushWatches bounds the watches accepted from a push
+	maxPushWatches = 256
+)
+
+type Service interface {
+	// Collect checks every watch and keeps the result as the latest in-memory check.
+	Collect(ctx context.Context) (entities.WatchMetric, error)
+	// Save stores the watch states and records the check's events.
+	Save(ctx context.Context, metric entities.WatchMetric, hostId uint) error
+	// Latest returns this instance's last check (no database access); nil before the first one.
+	Latest() *entities.WatchMetric
+	// PushData returns the last check with the events of recent checks; nil before the first one.
+	PushData() *entities.WatchMetric
+	// ReceivePush stores a check pushed by an agent.
+	ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalWatchSample, error)
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, watch string) ([]entities.HistoricalWatchSample, error)
+}
+
+type service struct {
+	logger    *log.Logger
+	collector *collectors.WatchCollector
+	repo      repositories.WatchRepository
+	events    eventsservice.Service
+
+	mu     sync.RWMutex
+	latest *entities.WatchMetric
+	recent *eventsservice.Recent[evententities.HostEvent]
+}
+
+func NewService(logger *log.Logger, watches []collectors.WatchConfig, repo repositories.WatchRepository, events eventsservice.Service) Service {
+	return &service{
+		logger:    logger,
+		collector: collectors.NewWatchCollector(logger, watches),
+		repo:      repo,
+		events:    events,
+		recent:    eventsservice.NewRecentEvents(maxPushEvents),
+	}
+}
+
+func (s *service) Collect(ctx context.Context) (entities.WatchMetric, error) {
+	s.logger.Debug("Collecting metrics", "module", "watchlist")
+	metric, err := s.collector.Collect(ctx)
+	if err != nil {
+		return metric, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest = &metric
+	s.recent.Add(metric.Timestamp, metric.Events)
+	return metric, nil
+}
+
+func (s *service) Save(ctx context.Context, metric entities.WatchMetric, hostId uint) error {
+	if err := s.repo.SaveCurrentMetric(ctx, metric, hostId); err != nil {
+		s.logger.Error("Failed to save metrics", "module", "watchlist", "error", err, "host_id", hostId)
+		return err
+	}
+	for _, e := range metric.Events {
+		if e.Kind != collectors.KindRestart {
+			s.logger.Info("Watched process "+e.Kind, "watch", e.Subject, "host_id", hostId, "message", e.Message)
+		}
+	}
+	return s.events.Record(ctx, hostId, metric.Events)
+}
+
+func (s *service) Latest() *entities.WatchMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+func (s *service) PushData() *entities.WatchMetric {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.latest == nil {
+		return nil
+	}
+	out := *s.latest
+	out.Events = s.recent.Items()
+	return &out
+}
+
+// ReceivePush bounds what an agent may send and records only watchlist events.
+func (s *service) ReceivePush(ctx context.Context, hostId uint, data json.RawMessage) error {
+	var metric entities.WatchMetric
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return err
+	}
+	if metric.Timestamp.IsZero() {
+		return nil
+	}
+	metric.Watches = metric.Watches[:min(len(metric.Watches), maxPushWatches)]
+	events := make([]evententities.HostEvent, 0, len(metric.Events))
+	for _, e := range metric.Events[:min(len(metric.Events), maxPushEvents)] {
+		if e.Source == collectors.EventSource && !e.Timestamp.IsZero() {
+			events = append(events, e)
+		}
+	}
+	metric.Events = events
+	return s.Save(ctx, metric, hostId)
+}
+
+func (s *service) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalWatchSample, error) {
+	return s.repo.GetLatestByHost(ctx, hostId)
+}
+
+func (s *service) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, watch string) ([]entities.HistoricalWatchSample, error) {
+	return s.repo.GetHistoryByHost(ctx, hostId, hours, watch)
+}
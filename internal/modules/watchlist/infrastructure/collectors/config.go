@@ -0,0 +1,96 @@
+package collectors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// WatchFileEnv names the JSON file listing the watched processes.
+const WatchFileEnv = "PROCESS_WATCH_FILE"
+
+var watchNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// WatchConfig is one watched process or service. Process and Cmdline may be combined (both
+// must match); Pidfile is used on its own.
+type WatchConfig struct {
+	Name string `json:"name"`
+	// Process matches the process name exactly (as shown by ps -e)
+	Process string `json:"process,omitempty"`
+	// Cmdline is a regular expression matched against the full command line
+	Cmdline string `json:"cmdline,omitempty"`
+	// Pidfile names a file holding the PID of the main process, as seen by this process
+	Pidfile string `json:"pidfile,omitempty"`
+	// MinCount is how many matching processes the watch needs to be up (default 1)
+	MinCount int `json:"min_count,omitempty"`
+
+	cmdline *regexp.Regexp
+}
+
+type watchFile struct {
+	Watches []WatchConfig `json:"watches"`
+}
+
+// LoadWatchesFromEnv reads the file named by PROCESS_WATCH_FILE; nothing is watched when it is unset.
+func LoadWatchesFromEnv() ([]WatchConfig, error) {
+	path := os.Getenv(WatchFileEnv)
+	if path == "" {
+		return nil, nil
+	}
+	return LoadWatches(path)
+}
+
+// LoadWatches reads and validates a watch file:
+//
+//	{"watches": [{"name": "nginx", "process": "nginx"}, {"name": "worker", "cmdline": "queue-worker --pool=\\w+", "min_count": 4}]}
+func LoadWatches(path string) ([]WatchConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	var file watchFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(file.Watches))
+	for i := range file.Watches {
+		w := &file.Watches[i]
+		if err := validateWatch(w); err != nil {
+			return nil, fmt.Errorf("%s: watch %d: %w", path, i+1, err)
+		}
+		if seen[w.Name] {
+			return nil, fmt.Errorf("%s: watch %q defined twice", path, w.Name)
+		}
+		seen[w.Name] = true
+	}
+	return file.Watches, nil
+}
+
+func validateWatch(w *WatchConfig) error {
+	if !watchNamePattern.MatchString(w.Name) {
+		return fmt.Errorf("name %q must be 1-128 letters, digits, '_', '.' or '-'", w.Name)
+	}
+	switch {
+	case w.Pidfile != "" && (w.Process != "" || w.Cmdline != ""):
+		return errors.New("pidfile cannot be combined with process or cmdline")
+	case w.Pidfile == "" && w.Process == "" && w.Cmdline == "":
+		return errors.New("one of process, cmdline or pidfile is required")
+	}
+	if w.Cmdline != "" {
+		re, err := regexp.Compile(w.Cmdline)
+		if err != nil {
+			return fmt.Errorf("cmdline: %w", err)
+		}
+		w.cmdline = re
+	}
+	if w.MinCount < 0 {
+		return errors.New("min_count must not be negative")
+	}
+	if w.MinCount == 0 {
+		w.MinCount = 1
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/shirou/gopsutil/v4/process"
+
+	"system-stats/internal/app/proccpu"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	"system-stats/internal/modules/watchlist/infrastructure/entities"
+)
+
+// EventSource is the source of the events this collector reports.
+const EventSource = "watchlist"
+
+// Event kinds.
+const (
+	KindUp      = "up"
+	KindDown    = "down"
+	KindRestart = "restart"
+)
+
+// watchState is what the previous check saw of a watch.
+type watchState struct {
+	up            bool
+	pids          map[int32]bool
+	restartsTotal uint64
+}
+
+// WatchCollector checks the configured watches. Processes are read through gopsutil, which
+// honours HOST_PROC.
+type WatchCollector struct {
+	logger  *log.Logger
+	watches []WatchConfig
+
+	mu    sync.Mutex
+	cpu   map[int32]proccpu.Sample
+	state map[string]*watchState
+}
+
+// NewWatchCollector creates a collector for watches (see LoadWatches).
+func NewWatchCollector(logger *log.Logger, watches []WatchConfig) *WatchCollector {
+	return &WatchCollector{
+		logger:  logger,
+		watches: watches,
+		cpu:     make(map[int32]proccpu.Sample),
+		state:   make(map[string]*watchState, len(watches)),
+	}
+}
+
+// Collect checks every watch and returns their state and the events of state changes. The
+// first check reports an up or down event for every watch, so an open problem recorded before
+// a restart of this instance is resolved.
+func (c *WatchCollector) Collect(ctx context.Context) (entities.WatchMetric, error) {
+	now := time.Now().UTC()
+	matches, err := c.match(ctx)
+	if err != nil {
+		return entities.WatchMetric{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	metric := entities.WatchMetric{Timestamp: now, Watches: make([]entities.WatchStatus, 0, len(c.watches))}
+	nextCPU := make(map[int32]proccpu.Sample)
+	for _, w := range c.watches {
+		procs := matches[w.Name]
+		status := entities.WatchStatus{Watch: w.Name, MinCount: w.MinCount, PIDs: make([]int32, 0, len(procs))}
+		pids := make(map[int32]bool, len(procs))
+		for _, p := range procs {
+			status.PIDs = append(status.PIDs, p.Pid)
+			pids[p.Pid] = true
+			created, _ := p.CreateTimeWithContext(ctx)
+			if times, err := p.TimesWithContext(ctx); err == nil {
+				sample := proccpu.Sample{Created: created, Seconds: times.User + times.System, At: now}
+				status.CPUPercent += proccpu.Percent(c.cpu[p.Pid], sample)
+				nextCPU[p.Pid] = sample
+			}
+			if info, err := p.MemoryInfoWithContext(ctx); err == nil {
+				status.RSSBytes += info.RSS
+			}
+		}
+		sort.Slice(status.PIDs, func(i, j int) bool { return status.PIDs[i] < status.PIDs[j] })
+		status.Count = len(procs)
+		up := status.Count >= w.MinCount
+		status.State = entities.StateDown
+		if up {
+			status.State = entities.StateUp
+		}
+
+		prev, seen := c.state[w.Name]
+		if !seen {
+			prev = &watchState{}
+			c.state[w.Name] = prev
+		}
+		status.Restarts = restarts(prev.pids, pids)
+		prev.restartsTotal += uint64(status.Restarts)
+		status.RestartsTotal = prev.restartsTotal
+
+		switch {
+		case (!seen || !prev.up) && up:
+			metric.Events = append(metric.Events, event(now, w.Name, KindUp, evententities.SeverityOK,
+				fmt.Sprintf("%s is up: %d of %d processes running", w.Name, status.Count, w.MinCount)))
+		case (!seen || prev.up) && !up:
+			metric.Events = append(metric.Events, event(now, w.Name, KindDown, evententities.SeverityCritical,
+				fmt.Sprintf("%s is down: %d of %d processes running", w.Name, status.Count, w.MinCount)))
+		}
+		if status.Restarts > 0 {
+			metric.Events = append(metric.Events, event(now, w.Name, KindRestart, evententities.SeverityInfo,
+				fmt.Sprintf("%s restarted: %d PID change(s)", w.Name, status.Restarts)))
+		}
+		prev.up = up
+		prev.pids = pids
+		metric.Watches = append(metric.Watches, status)
+	}
+	c.cpu = nextCPU
+	return metric, nil
+}
+
+// match returns the running processes of every watch.
+func (c *WatchCollector) match(ctx context.Context) (map[string][]*process.Process, error) {
+	out := make(map[string][]*process.Process, len(c.watches))
+	var scan bool
+	for _, w := range c.watches {
+		if w.Pidfile != "" {
+			if p := pidfileProcess(ctx, w.Pidfile); p != nil {
+				out[w.Name] = []*process.Process{p}
+			}
+			continue
+		}
+		scan = true
+	}
+	if !scan {
+		return out, nil
+	}
+
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range procs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Name and command line are read at most once per process and only when a watch needs them
+		var name, cmdline *string
+		for _, w := range c.watches {
+			if w.Pidfile != "" {
+				continue
+			}
+			if w.Process != "" {
+				if name == nil {
+					n, _ := p.NameWithContext(ctx)
+					name = &n
+				}
+				if *name != w.Process {
+					continue
+				}
+			}
+			if w.cmdline != nil {
+				if cmdline == nil {
+					cl, _ := p.CmdlineWithContext(ctx)
+					cmdline = &cl
+				}
+				if !w.cmdline.MatchString(*cmdline) {
+					continue
+				}
+			}
+			out[w.Name] = append(out[w.Name], p)
+		}
+	}
+	return out, nil
+}
+
+// pidfileProcess returns the process named by a pidfile, or nil when the file is missing or
+// the process is gone.
+func pidfileProcess(ctx context.Context, path string) *process.Process {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	pid, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 32)
+	if err != nil || pid <= 0 {
+		return nil
+	}
+	if ok, err := process.PidExistsWithContext(ctx, int32(pid)); err != nil || !ok {
+		return nil
+	}
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// restarts counts PID changes: each PID that went away paired with a new one. A watch that
+// comes up from nothing or scales up or down is not restarted.
+func restarts(prev, next map[int32]bool) int {
+	var gone, added int
+	for pid := range prev {
+		if !next[pid] {
+			gone++
+		}
+	}
+	for pid := range next {
+		if !prev[pid] {
+			added++
+		}
+	}
+	return min(gone, added)
+}
+
+func event(at time.Time, watch, kind, severity, message string) evententities.HostEvent {
+	return evententities.HostEvent{
+		Timestamp: at,
+		Source:    EventSource,
+		Subject:   watch,
+		Kind:      kind,
+		Severity:  severity,
+		Message:   message,
+	}
+}
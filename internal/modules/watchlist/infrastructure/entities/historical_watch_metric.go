@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoricalWatchSample is the state of one watch at one check.
+type HistoricalWatchSample struct {
+	HostID    uint      `json:"host_id" gorm:"primaryKey;autoIncrement:false;index:idx_process_watch_host_ts,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"primaryKey;index;index:idx_process_watch_host_ts,priority:2"`
+	Watch     string    `json:"watch" gorm:"primaryKey;size:128"`
+
+	State    string `json:"state" gorm:"size:8;not null"`
+	Count    int    `json:"count"`
+	MinCount int    `json:"min_count"`
+	// PIDs is the comma-separated list of matching PIDs
+	PIDs       string  `json:"pids" gorm:"size:1024"`
+	CPUPercent float64 `json:"cpu_percent"`
+	RSSBytes   uint64  `json:"rss_bytes"`
+	Restarts   int     `json:"restarts"`
+}
+
+// TableName returns the database table name for GORM operations.
+func (HistoricalWatchSample) TableName() string { return "process_watch_samples" }
+
+// maxStoredPIDs keeps the PID list of large worker pools within the column
+const maxStoredPIDs = 100
+
+// ToSamples flattens a metric into one row per watch.
+func (m WatchMetric) ToSamples(hostId uint) []HistoricalWatchSample {
+	ts := m.Timestamp.UTC().Truncate(time.Microsecond)
+	rows := make([]HistoricalWatchSample, 0, len(m.Watches))
+	for _, w := range m.Watches {
+		pids := make([]string, 0, min(len(w.PIDs), maxStoredPIDs))
+		for _, pid := range w.PIDs[:min(len(w.PIDs), maxStoredPIDs)] {
+			pids = append(pids, strconv.Itoa(int(pid)))
+		}
+		rows = append(rows, HistoricalWatchSample{
+			HostID:     hostId,
+			Timestamp:  ts,
+			Watch:      w.Watch,
+			State:      w.State,
+			Count:      w.Count,
+			MinCount:   w.MinCount,
+			PIDs:       strings.Join(pids, ","),
+			CPUPercent: w.CPUPercent,
+			RSSBytes:   w.RSSBytes,
+			Restarts:   w.Restarts,
+		})
+	}
+	return rows
+}
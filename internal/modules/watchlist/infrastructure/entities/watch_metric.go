@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+)
+
+// Watch states.
+const (
+	StateUp   = "up"
+	StateDown = "down"
+)
+
+// WatchMetric is one check of every watched process.
+type WatchMetric struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Watches   []WatchStatus `json:"watches"`
+	// Events are the up, down and restart events of this check; a pushed metric carries the
+	// events of recent checks so none is lost between pushes
+	Events []evententities.HostEvent `json:"events,omitempty"`
+}
+
+// WatchStatus is the state of one watch.
+type WatchStatus struct {
+	Watch string `json:"watch"`
+	// State is up when at least MinCount matching processes run
+	State    string  `json:"state"`
+	Count    int     `json:"count"`
+	MinCount int     `json:"min_count"`
+	PIDs     []int32 `json:"pids"`
+	// CPUPercent is the summed CPU of the matching processes (100 = one core)
+	CPUPercent float64 `json:"cpu_percent"`
+	RSSBytes   uint64  `json:"rss_bytes"`
+	// Restarts counts PID changes since the previous check; RestartsTotal since this instance started
+	Restarts      int    `json:"restarts"`
+	RestartsTotal uint64 `json:"restarts_total"`
+}
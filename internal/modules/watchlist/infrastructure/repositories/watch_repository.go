@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/modules/watchlist/infrastructure/entities"
+)
+
+type WatchRepository interface {
+	// SaveCurrentMetric stores one row per watch; a check already stored (e.g. pushed twice) is skipped.
+	SaveCurrentMetric(ctx context.Context, metric entities.WatchMetric, hostId uint) error
+	// GetLatestByHost returns the rows of the most recent check of a host.
+	GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalWatchSample, error)
+	// GetHistoryByHost returns rows in time order, optionally for one watch.
+	GetHistoryByHost(ctx context.Context, hostId uint, hours float64, watch string) ([]entities.HistoricalWatchSample, error)
+}
+
+type watchRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchRepository(db *gorm.DB) WatchRepository {
+	return &watchRepository{db: db}
+}
+
+func (r *watchRepository) SaveCurrentMetric(ctx context.Context, metric entities.WatchMetric, hostId uint) error {
+	rows := metric.ToSamples(hostId)
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *watchRepository) GetLatestByHost(ctx context.Context, hostId uint) ([]entities.HistoricalWatchSample, error) {
+	rows := []entities.HistoricalWatchSample{}
+	latest := r.db.Model(&entities.HistoricalWatchSample{}).Select("MAX(timestamp)").Where("host_id = ?", hostId)
+	err := r.db.WithContext(ctx).
+		Where("host_id = ? AND timestamp = (?)", hostId, latest).
+		Order("watch ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *watchRepository) GetHistoryByHost(ctx context.Context, hostId uint, hours float64, watch string) ([]entities.HistoricalWatchSample, error) {
+	rows := []entities.HistoricalWatchSample{}
+	q := database.TimeOffsetQueryWithHost(r.db.WithContext(ctx), hostId, hours)
+	if watch != "" {
+		q = q.Where("watch = ?", watch)
+	}
+	err := q.Order("timestamp ASC, watch ASC").Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,69 @@
+// Package watchlist registers the watchlist module, which checks that configured processes and
+// services run and reports up, down and restart events.
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	watchservice "system-stats/internal/modules/watchlist/application"
+	"system-stats/internal/modules/watchlist/infrastructure/collectors"
+	"system-stats/internal/modules/watchlist/infrastructure/entities"
+	"system-stats/internal/modules/watchlist/infrastructure/repositories"
+	handlers "system-stats/internal/modules/watchlist/presentation"
+)
+
+// Module checks the watches listed in PROCESS_WATCH_FILE and serves /watchlist.
+type Module struct{}
+
+func (Module) Name() string { return "watchlist" }
+
+func (Module) Migrations() []database.Migration {
+	return []database.Migration{{
+		Version: 8,
+		Name:    "process_watch_samples_table",
+		Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&entities.HistoricalWatchSample{})
+		}},
+		Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&entities.HistoricalWatchSample{})
+		}},
+	}}
+}
+
+func (Module) RetentionTables() []string { return []string{"process_watch_samples"} }
+
+func (Module) HostTables() []string { return []string{"process_watch_samples"} }
+
+// Build fails on an invalid watch file. Without watches there is nothing to check or export,
+// but stored states (including those pushed by agents) stay queryable.
+func (Module) Build(deps registry.Deps) (registry.Instance, error) {
+	watches, err := collectors.LoadWatchesFromEnv()
+	if err != nil {
+		return registry.Instance{}, err
+	}
+	service := watchservice.NewService(deps.Logger, watches, repositories.NewWatchRepository(deps.DB), deps.Events)
+	handler := handlers.NewWatchHandler(deps.Logger, service, deps.Hosts)
+	inst := registry.Instance{
+		Routes: func(r gin.IRoutes) {
+			r.GET("/watchlist", handler.HandleWatchlist)
+		},
+		ReceivePush: func(ctx context.Context, hostId uint, data json.RawMessage) error {
+			return service.ReceivePush(ctx, hostId, data)
+		},
+	}
+	if len(watches) > 0 {
+		// Watch states are not part of the live snapshot
+		collector := historycore.NewModuleCollector[entities.WatchMetric]("watchlist", service.Collect, service.Save, nil)
+		inst.Collector = &collector
+		inst.Metrics = watchservice.NewPrometheusCollector(service)
+		inst.PushData = registry.PushLatest(service.PushData)
+	}
+	return inst, nil
+}
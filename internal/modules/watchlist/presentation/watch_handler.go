@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/httputil"
+	"system-stats/internal/app/metricshost"
+	hostservice "system-stats/internal/modules/hosts/application"
+	watchservice "system-stats/internal/modules/watchlist/application"
+)
+
+// WatchHandler handles HTTP requests for watched processes.
+type WatchHandler struct {
+	logger  *log.Logger
+	service watchservice.Service
+	hosts   hostservice.Service
+}
+
+// NewWatchHandler creates a new HTTP handler for watchlist endpoints.
+func NewWatchHandler(logger *log.Logger, service watchservice.Service, hosts hostservice.Service) *WatchHandler {
+	return &WatchHandler{
+		logger:  logger,
+		service: service,
+		hosts:   hosts,
+	}
+}
+
+// HandleWatchlist returns the latest state of every watched process and the state history.
+//
+// @Summary     Process watchlist
+// @Description Returns the latest state (up/down, process count, PIDs, summed CPU and memory, restarts) of every watch in PROCESS_WATCH_FILE and the history of checks. Up, down and restart events are listed by /events.
+// @Tags        metrics
+// @Produce     json
+// @Param       hours    query    number   false  "History window in hours"  default(0.0833)
+// @Param       host_id  query    integer  false  "Host ID (0 = this server instance)"
+// @Param       watch    query    string   false  "Limit history to one watch"
+// @Success     200      {object} map[string]interface{}
+// @Failure     401      {object} map[string]string
+// @Failure     500      {object} map[string]string
+// @Security    BearerAuth
+// @Router      /watchlist [get]
+func (h *WatchHandler) HandleWatchlist(c *gin.Context) {
+	hours := httputil.ParseHoursQuery(c)
+	queryHost := httputil.ParseHostIdQuery(c)
+	ctx := c.Request.Context()
+
+	effective, err := metricshost.EffectiveHostID(ctx, h.hosts, queryHost)
+	if errors.Is(err, metricshost.ErrHostNotFound) {
+		c.JSON(http.StatusOK, metricshost.EmptyWatchlistPayload())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve host for watchlist", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	latest, err := h.service.GetLatestByHost(ctx, effective)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching latest watchlist")
+			return
+		}
+		h.logger.Error("Failed to fetch latest watchlist", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.GetHistoryByHost(ctx, effective, hours, c.Query("watch"))
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.logger.Info("Client canceled request while fetching watchlist history")
+			return
+		}
+		h.logger.Error("Failed to fetch watchlist history", "error", err, "hours", hours, "host_id", effective)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"latest":  latest,
+		"history": history,
+	})
+}
@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"system-stats/internal/app/config"
+)
+
+func TestLoad_CollectionSchedule(t *testing.T) {
+	t.Setenv("COLLECT_INTERVAL", "10s")
+	t.Setenv("COLLECT_TIMEOUT", "20")
+	t.Setenv("COLLECT_INTERVAL_DISK", "1m")
+	t.Setenv("COLLECT_TIMEOUT_DOCKER", "3s")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		module   string
+		interval time.Duration
+		timeout  time.Duration
+	}{
+		{"cpu", 10 * time.Second, 20 * time.Second},
+		{"disk", time.Minute, 20 * time.Second},
+		{"docker", 10 * time.Second, 3 * time.Second},
+	}
+	for _, tt := range tests {
+		got := cfg.Collection.For(tt.module)
+		if got.Interval != tt.interval || got.Timeout != tt.timeout {
+			t.Errorf("For(%q) = %+v, want interval %s timeout %s", tt.module, got, tt.interval, tt.timeout)
+		}
+	}
+}
+
+func TestLoad_CollectionDefaults(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got := cfg.Collection.For("network")
+	if got.Interval != config.DefaultCollectInterval || got.Timeout != config.DefaultCollectTimeout {
+		t.Errorf("For(network) = %+v, want defaults", got)
+	}
+}
+
+func TestLoad_CollectionRejectsInvalidValues(t *testing.T) {
+	for key, value := range map[string]string{
+		"COLLECT_INTERVAL":        "soon",
+		"COLLECT_INTERVAL_CPU":    "100ms",
+		"COLLECT_TIMEOUT_NETWORK": "0",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := config.Load(); err == nil {
+				t.Errorf("%s=%s: expected error", key, value)
+			}
+		})
+	}
+}
+
+func TestLoad_ModulesDisabled(t *testing.T) {
+	t.Setenv("MODULES_DISABLED", " Docker, ,sensors ")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := strings.Join(cfg.ModulesDisabled, ","); got != "docker,sensors" {
+		t.Errorf("ModulesDisabled = %q, want docker,sensors", got)
+	}
+}
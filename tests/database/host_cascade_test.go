@@ -0,0 +1,53 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/modules"
+	cpuentities "system-stats/internal/modules/cpu/infrastructure/entities"
+	hostentities "system-stats/internal/modules/hosts/infrastructure/entities"
+	hostrepos "system-stats/internal/modules/hosts/infrastructure/repositories"
+)
+
+func TestDeleteHostCascade_EmptiesEveryModuleHostTable(t *testing.T) {
+	db := openEmptyDB(t)
+	reg, err := registry.New(modules.All(), nil)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	if err := database.Migrate(db, reg.Migrations()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	for _, table := range reg.HostTables() {
+		if !db.Migrator().HasColumn(table, "host_id") {
+			t.Errorf("host table %s has no host_id column", table)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, id := range []uint{1, 2} {
+		if err := db.Create(&hostentities.Host{ID: id, Name: fmt.Sprintf("host-%d", id), MacAddress: fmt.Sprintf("02:00:00:00:00:%02d", id), LastSeen: now}).Error; err != nil {
+			t.Fatalf("create host %d: %v", id, err)
+		}
+		if err := db.Create(&cpuentities.HistoricalCPUMetric{HostID: id, Timestamp: now, Usage: 10}).Error; err != nil {
+			t.Fatalf("create cpu row: %v", err)
+		}
+	}
+
+	if err := hostrepos.NewHostRepository(db, reg.HostTables()).DeleteHostCascade(context.Background(), 2); err != nil {
+		t.Fatalf("DeleteHostCascade: %v", err)
+	}
+	var hosts []uint
+	db.Raw("SELECT DISTINCT host_id FROM cpu_metrics").Scan(&hosts)
+	if len(hosts) != 1 || hosts[0] != 1 {
+		t.Errorf("cpu_metrics hosts after delete = %v, want only 1", hosts)
+	}
+	if db.First(&hostentities.Host{}, 2).Error == nil {
+		t.Error("host 2 still exists")
+	}
+}
@@ -0,0 +1,112 @@
+package database_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"system-stats/internal/app/database"
+)
+
+// Legacy DDL as produced by AutoMigrate before metric tables were keyed by host.
+var legacySchema = []string{
+	"CREATE TABLE `cpu_metrics` (`host_id` integer DEFAULT null,`timestamp` datetime,`usage` real,`cores` integer,`load_avg_1` real,`load_avg_5` real,`load_avg_15` real,`temperature` real,PRIMARY KEY (`timestamp`))",
+	"CREATE INDEX `idx_cpu_host_ts` ON `cpu_metrics`(`host_id`,`timestamp`)",
+	"CREATE INDEX `idx_cpu_metrics_timestamp` ON `cpu_metrics`(`timestamp`)",
+	"CREATE TABLE `docker_metrics` (`host_id` integer DEFAULT null,`timestamp` datetime,`total_containers` integer,`running_containers` integer,`docker_available` numeric,PRIMARY KEY (`timestamp`))",
+	"CREATE INDEX `idx_docker_host_ts` ON `docker_metrics`(`host_id`,`timestamp`)",
+	"CREATE TABLE `docker_container_entities` (`id` text,`metric_timestamp` datetime,`name` text,`image` text,`state` text,`status` text,`ports` text,`cpu_percent` real,`cpu_limit` real,`cpu_percent_of_limit` real,`memory_usage` integer,`memory_limit` integer,`memory_percent` real,`network_rx` integer,`network_tx` integer,`block_read` integer,`block_write` integer,`created` text,`finished_at` text,PRIMARY KEY (`id`,`metric_timestamp`),CONSTRAINT `fk_docker_metrics_containers` FOREIGN KEY (`metric_timestamp`) REFERENCES `docker_metrics`(`timestamp`))",
+}
+
+func openLegacyDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "legacy.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	for _, stmt := range legacySchema {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("legacy schema: %v", err)
+		}
+	}
+	return db
+}
+
+func TestMigrateHostScopedKeys_SQLiteLegacyData(t *testing.T) {
+	db := openLegacyDB(t)
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	db.Exec("INSERT INTO cpu_metrics (host_id, timestamp, usage) VALUES (NULL, ?, 12.5)", ts)
+	db.Exec("INSERT INTO docker_metrics (host_id, timestamp, total_containers) VALUES (3, ?, 1)", ts)
+	db.Exec("INSERT INTO docker_container_entities (id, metric_timestamp, name) VALUES ('abc', ?, 'web')", ts)
+	// No docker_metrics row at this instant: dropped like on Postgres
+	db.Exec("INSERT INTO docker_container_entities (id, metric_timestamp, name) VALUES ('orphan', ?, 'old')", ts.Add(time.Minute))
+
+	if err := database.Migrate(db, database.Migrations()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	// A second run must detect the new keys and do nothing
+	if err := database.Migrate(db, database.Migrations()); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+
+	var cpuHost uint
+	var usage float64
+	row := db.Raw("SELECT host_id, usage FROM cpu_metrics").Row()
+	if err := row.Scan(&cpuHost, &usage); err != nil {
+		t.Fatalf("read cpu_metrics: %v", err)
+	}
+	if cpuHost != 1 || usage != 12.5 {
+		t.Errorf("cpu row = (%d, %v), want NULL host mapped to 1 with usage kept", cpuHost, usage)
+	}
+
+	var containerHost uint
+	if err := db.Raw("SELECT host_id FROM docker_container_entities WHERE id = 'abc'").Scan(&containerHost).Error; err != nil {
+		t.Fatalf("read containers: %v", err)
+	}
+	if containerHost != 3 {
+		t.Errorf("container host_id = %d, want parent's host 3", containerHost)
+	}
+	var orphans int64
+	db.Raw("SELECT COUNT(*) FROM docker_container_entities WHERE id = 'orphan'").Scan(&orphans)
+	if orphans != 0 {
+		t.Errorf("orphaned container rows = %d, want them deleted", orphans)
+	}
+
+	// Another host may now write the same instant without colliding
+	if err := db.Exec("INSERT INTO cpu_metrics (host_id, timestamp, usage) VALUES (2, ?, 1)", ts).Error; err != nil {
+		t.Errorf("insert same timestamp for another host: %v", err)
+	}
+
+	var legacy int64
+	db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE name LIKE '%__legacy'").Scan(&legacy)
+	if legacy != 0 {
+		t.Errorf("legacy tables left behind: %d", legacy)
+	}
+}
+
+func TestMigrateHostScopedKeys_ContainersCascadeWithParent(t *testing.T) {
+	db := openLegacyDB(t)
+	if err := database.Migrate(db, database.Migrations()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	db.Exec("PRAGMA foreign_keys = ON")
+
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	db.Exec("INSERT INTO docker_metrics (host_id, timestamp) VALUES (1, ?), (2, ?)", ts, ts)
+	db.Exec("INSERT INTO docker_container_entities (host_id, id, metric_timestamp) VALUES (1, 'a', ?), (2, 'b', ?)", ts, ts)
+
+	if err := db.Exec("DELETE FROM docker_metrics WHERE host_id = 1").Error; err != nil {
+		t.Fatalf("delete parent: %v", err)
+	}
+	var ids []string
+	db.Raw("SELECT id FROM docker_container_entities").Scan(&ids)
+	if len(ids) != 1 || ids[0] != "b" {
+		t.Errorf("remaining containers = %v, want only host 2's row", ids)
+	}
+}
@@ -0,0 +1,161 @@
+package database_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"system-stats/internal/app/database"
+)
+
+func openEmptyDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "m.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	return db
+}
+
+func testMigrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version: 1,
+			Name:    "create_widgets",
+			Up: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+				return tx.Exec("CREATE TABLE widgets (id INTEGER PRIMARY KEY, label TEXT)").Error
+			}},
+			Down: database.Steps{database.AnyDialect: func(tx *gorm.DB) error {
+				return tx.Exec("DROP TABLE widgets").Error
+			}},
+		},
+		{
+			Version: 2,
+			Name:    "widgets_color",
+			Up: database.Steps{
+				"sqlite":   func(tx *gorm.DB) error { return tx.Exec("ALTER TABLE widgets ADD COLUMN color TEXT").Error },
+				"postgres": func(tx *gorm.DB) error { return tx.Exec("ALTER TABLE widgets ADD COLUMN color text").Error },
+			},
+		},
+	}
+}
+
+func TestMigrator_UpRecordsVersionsInOrder(t *testing.T) {
+	db := openEmptyDB(t)
+	m := database.NewMigrator(db, testMigrations())
+
+	ran, err := m.Up(0, false)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(ran) != 2 || ran[0].Version != 1 || ran[1].Version != 2 {
+		t.Fatalf("ran = %+v, want versions 1, 2", ran)
+	}
+	if v, _ := m.CurrentVersion(); v != 2 {
+		t.Errorf("CurrentVersion = %d, want 2", v)
+	}
+	if !db.Migrator().HasColumn("widgets", "color") {
+		t.Error("widgets.color was not created")
+	}
+
+	ran, err = m.Up(0, false)
+	if err != nil || len(ran) != 0 {
+		t.Errorf("second Up = (%v, %v), want nothing to do", ran, err)
+	}
+}
+
+func TestMigrator_DryRunDoesNotTouchDatabase(t *testing.T) {
+	db := openEmptyDB(t)
+	m := database.NewMigrator(db, testMigrations())
+
+	ran, err := m.Up(0, true)
+	if err != nil || len(ran) != 2 {
+		t.Fatalf("dry-run Up = (%v, %v), want 2 pending", ran, err)
+	}
+	if db.Migrator().HasTable("widgets") || db.Migrator().HasTable("schema_migrations") {
+		t.Error("dry run created tables")
+	}
+}
+
+func TestMigrator_DownRollsBackAndStopsAtIrreversible(t *testing.T) {
+	db := openEmptyDB(t)
+	m := database.NewMigrator(db, testMigrations())
+	if _, err := m.Up(1, false); err != nil {
+		t.Fatalf("Up to 1: %v", err)
+	}
+
+	if _, err := m.Down(1, false); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if db.Migrator().HasTable("widgets") {
+		t.Error("widgets table still exists after rollback")
+	}
+	if v, _ := m.CurrentVersion(); v != 0 {
+		t.Errorf("CurrentVersion = %d, want 0", v)
+	}
+
+	if _, err := m.Up(0, false); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if _, err := m.Down(1, false); !errors.Is(err, database.ErrIrreversible) {
+		t.Errorf("Down of migration without down step = %v, want ErrIrreversible", err)
+	}
+}
+
+func TestMigrator_RefusesNewerSchema(t *testing.T) {
+	db := openEmptyDB(t)
+	if _, err := database.NewMigrator(db, testMigrations()).Up(0, false); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	older := database.NewMigrator(db, testMigrations()[:1])
+	if err := older.CheckNotNewer(); !errors.Is(err, database.ErrSchemaNewer) {
+		t.Errorf("CheckNotNewer = %v, want ErrSchemaNewer", err)
+	}
+	if _, err := older.Up(0, false); !errors.Is(err, database.ErrSchemaNewer) {
+		t.Errorf("Up on newer schema = %v, want ErrSchemaNewer", err)
+	}
+}
+
+func TestCoreMigrations_RollBackToFirstVersion(t *testing.T) {
+	db := openEmptyDB(t)
+	m := database.NewMigrator(db, database.Migrations())
+	if _, err := m.Up(3, false); err != nil {
+		t.Fatalf("Up to 3: %v", err)
+	}
+	if err := db.Exec("INSERT INTO user_invitations (token, email, created_by) VALUES ('t1', 'a@example.com', 1)").Error; err != nil {
+		t.Fatalf("insert invitation: %v", err)
+	}
+
+	if _, err := m.Down(1, false); err != nil {
+		t.Fatalf("Down of version 3: %v", err)
+	}
+	if err := db.Exec("INSERT INTO user_invitations (token, created_by) VALUES ('t2', 1)").Error; err != nil {
+		t.Errorf("insert without email after rollback: %v", err)
+	}
+	if !db.Migrator().HasIndex("user_invitations", "idx_user_invitations_token") {
+		t.Error("token index lost by the rollback")
+	}
+	var kept int64
+	db.Table("user_invitations").Where("email = ?", "a@example.com").Count(&kept)
+	if kept != 1 {
+		t.Errorf("invitations with email after rollback = %d, want 1", kept)
+	}
+
+	if _, err := m.Down(1, false); err != nil {
+		t.Fatalf("Down of version 2: %v", err)
+	}
+	for _, table := range []string{"hosts", "users", "refresh_tokens", "cpu_metrics"} {
+		if db.Migrator().HasTable(table) {
+			t.Errorf("%s still exists after rolling back the baseline", table)
+		}
+	}
+	// Version 1 is intentionally irreversible
+	if _, err := m.Down(1, false); !errors.Is(err, database.ErrIrreversible) {
+		t.Errorf("Down of version 1 = %v, want ErrIrreversible", err)
+	}
+}
@@ -0,0 +1,32 @@
+package proccpu_test
+
+import (
+	"testing"
+	"time"
+
+	"system-stats/internal/app/proccpu"
+)
+
+func TestPercent(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := start.UnixMilli()
+	prev := proccpu.Sample{Created: created, Seconds: 10, At: start.Add(100 * time.Second)}
+
+	cases := []struct {
+		name string
+		prev proccpu.Sample
+		cur  proccpu.Sample
+		want float64
+	}{
+		{"since previous sample", prev, proccpu.Sample{Created: created, Seconds: 15, At: start.Add(110 * time.Second)}, 50},
+		{"first sample is since start", proccpu.Sample{}, proccpu.Sample{Created: created, Seconds: 20, At: start.Add(100 * time.Second)}, 20},
+		{"reused PID is since start", prev, proccpu.Sample{Created: start.Add(90 * time.Second).UnixMilli(), Seconds: 5, At: start.Add(100 * time.Second)}, 50},
+		{"unknown start", proccpu.Sample{}, proccpu.Sample{Seconds: 5, At: start}, 0},
+		{"counter went back", prev, proccpu.Sample{Created: created, Seconds: 5, At: start.Add(110 * time.Second)}, 0},
+	}
+	for _, tc := range cases {
+		if got := proccpu.Percent(tc.prev, tc.cur); got != tc.want {
+			t.Errorf("%s: Percent = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
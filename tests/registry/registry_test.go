@@ -0,0 +1,145 @@
+package registry_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/modules"
+)
+
+type fakeModule struct {
+	name       string
+	migrations []database.Migration
+	tables     []string
+	built      *int
+}
+
+func (m fakeModule) Name() string                     { return m.name }
+func (m fakeModule) Migrations() []database.Migration { return m.migrations }
+func (m fakeModule) RetentionTables() []string        { return m.tables }
+func (m fakeModule) HostTables() []string             { return m.tables }
+func (m fakeModule) Build(registry.Deps) (registry.Instance, error) {
+	if m.built != nil {
+		*m.built++
+	}
+	return registry.Instance{Routes: func(gin.IRoutes) {}}, nil
+}
+
+func TestNew_BuiltinModulesAreValid(t *testing.T) {
+	reg, err := registry.New(modules.All(), []string{" Docker ", ""})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if reg.Enabled("docker") || !reg.Enabled("cpu") {
+		t.Errorf("Enabled: docker=%v cpu=%v, want false/true", reg.Enabled("docker"), reg.Enabled("cpu"))
+	}
+	// Data of a disabled module still ages out
+	if tables := strings.Join(reg.RetentionTables(), ","); !strings.Contains(tables, "docker_metrics") {
+		t.Errorf("RetentionTables = %s, want docker_metrics included", tables)
+	}
+}
+
+func TestMigrations_BuiltinVersionsStrictlyIncrease(t *testing.T) {
+	reg, err := registry.New(modules.All(), nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	migrations := reg.Migrations()
+	if len(migrations) == 0 {
+		t.Fatal("no migrations registered")
+	}
+	for i := 1; i < len(migrations); i++ {
+		if prev, cur := migrations[i-1], migrations[i]; cur.Version <= prev.Version {
+			t.Errorf("migration %d (%s) follows %d (%s), want strictly increasing versions",
+				cur.Version, cur.Name, prev.Version, prev.Name)
+		}
+	}
+}
+
+func TestNew_RejectsInvalidRegistrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		modules  []registry.Module
+		disabled []string
+		want     string
+	}{
+		{"unknown disabled module", []registry.Module{fakeModule{name: "cpu"}}, []string{"gpu"}, `unknown module "gpu"`},
+		{"duplicate name", []registry.Module{fakeModule{name: "cpu"}, fakeModule{name: "cpu"}}, nil, "registered twice"},
+		{"version used by core", []registry.Module{fakeModule{name: "gpu", migrations: []database.Migration{{Version: 2, Name: "gpu"}}}}, nil, "already used by core"},
+		{"version used by module", []registry.Module{
+			fakeModule{name: "a", migrations: []database.Migration{{Version: 100}}},
+			fakeModule{name: "b", migrations: []database.Migration{{Version: 100}}},
+		}, nil, `already used by a`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registry.New(tt.modules, tt.disabled)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("New error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild_SkipsDisabledModules(t *testing.T) {
+	var cpuBuilt, dockerBuilt int
+	reg, err := registry.New([]registry.Module{
+		fakeModule{name: "cpu", built: &cpuBuilt},
+		fakeModule{name: "docker", built: &dockerBuilt, migrations: []database.Migration{{Version: 100, Name: "docker_extra"}}},
+	}, []string{"docker"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	built, err := reg.Build(registry.Deps{})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	var names []string
+	for _, b := range built {
+		names = append(names, b.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"cpu"}) || cpuBuilt != 1 || dockerBuilt != 0 {
+		t.Errorf("built %v (cpu %d, docker %d), want only cpu", names, cpuBuilt, dockerBuilt)
+	}
+
+	// Disabled modules keep their migrations so the schema does not depend on configuration
+	migrations := reg.Migrations()
+	if last := migrations[len(migrations)-1]; last.Version != 100 {
+		t.Errorf("last migration = %d, want the disabled module's 100", last.Version)
+	}
+}
+
+type failingModule struct{ fakeModule }
+
+func (failingModule) Build(registry.Deps) (registry.Instance, error) {
+	return registry.Instance{}, errors.New("no socket")
+}
+
+func TestBuild_ReportsModuleErrors(t *testing.T) {
+	reg, err := registry.New([]registry.Module{failingModule{fakeModule{name: "docker"}}}, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := reg.Build(registry.Deps{}); err == nil || !strings.Contains(err.Error(), "build module docker") {
+		t.Errorf("Build error = %v, want module name", err)
+	}
+}
+
+func TestPushLatest_NilPointerSendsNothing(t *testing.T) {
+	var latest *struct{ N int }
+	push := registry.PushLatest(func() *struct{ N int } { return latest })
+	if data := push(); data != nil {
+		t.Errorf("PushData before the first collection = %#v, want untyped nil", data)
+	}
+	latest = &struct{ N int }{N: 1}
+	if data := push(); data != latest {
+		t.Errorf("PushData = %#v, want the latest collection", data)
+	}
+}
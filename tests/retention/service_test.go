@@ -9,9 +9,21 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"system-stats/internal/app/registry"
 	"system-stats/internal/app/retention"
+	"system-stats/internal/modules"
 )
 
+// metricTables returns the retention tables declared by the built-in modules.
+func metricTables(t *testing.T) []string {
+	t.Helper()
+	reg, err := registry.New(modules.All(), nil)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	return reg.RetentionTables()
+}
+
 func setupTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
@@ -20,7 +32,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	if err != nil {
 		t.Fatalf("failed to open sqlite: %v", err)
 	}
-	for _, table := range retention.MetricTables {
+	for _, table := range metricTables(t) {
 		sql := "CREATE TABLE " + table + " (id INTEGER PRIMARY KEY, host_id INTEGER, timestamp DATETIME)"
 		if err := db.Exec(sql).Error; err != nil {
 			t.Fatalf("create table %s: %v", table, err)
@@ -47,7 +59,7 @@ func countRows(t *testing.T, db *gorm.DB, table string) int64 {
 
 func TestCleanup_DeletesOldRows(t *testing.T) {
 	db := setupTestDB(t)
-	svc := retention.NewService(db, log.Default(), 30)
+	svc := retention.NewService(db, log.Default(), 30, metricTables(t))
 
 	old := time.Now().AddDate(0, 0, -60)
 	insertRow(t, db, "cpu_metrics", old)
@@ -62,7 +74,7 @@ func TestCleanup_DeletesOldRows(t *testing.T) {
 
 func TestCleanup_PreservesRecentRows(t *testing.T) {
 	db := setupTestDB(t)
-	svc := retention.NewService(db, log.Default(), 30)
+	svc := retention.NewService(db, log.Default(), 30, metricTables(t))
 
 	recent := time.Now().Add(-time.Hour)
 	insertRow(t, db, "cpu_metrics", recent)
@@ -76,22 +88,42 @@ func TestCleanup_PreservesRecentRows(t *testing.T) {
 
 func TestCleanup_MultipleTablesAtOnce(t *testing.T) {
 	db := setupTestDB(t)
-	svc := retention.NewService(db, log.Default(), 7)
+	svc := retention.NewService(db, log.Default(), 7, metricTables(t))
 
 	old := time.Now().AddDate(0, 0, -14)
 	recent := time.Now().Add(-time.Hour)
 
-	for _, table := range retention.MetricTables {
+	for _, table := range metricTables(t) {
 		insertRow(t, db, table, old)
 		insertRow(t, db, table, recent)
 	}
 
 	svc.Cleanup()
 
-	for _, table := range retention.MetricTables {
+	for _, table := range metricTables(t) {
 		n := countRows(t, db, table)
 		if n != 1 {
 			t.Errorf("%s: expected 1 row, got %d", table, n)
 		}
 	}
 }
+
+func TestCleanup_TableLimitShortensRetention(t *testing.T) {
+	db := setupTestDB(t)
+	svc := retention.NewService(db, log.Default(), 30, metricTables(t)).
+		WithLimits(map[string]time.Duration{"process_samples": 24 * time.Hour})
+
+	twoDays := time.Now().Add(-48 * time.Hour)
+	insertRow(t, db, "process_samples", twoDays)
+	insertRow(t, db, "process_samples", time.Now().Add(-time.Hour))
+	insertRow(t, db, "cpu_metrics", twoDays)
+
+	svc.Cleanup()
+
+	if n := countRows(t, db, "process_samples"); n != 1 {
+		t.Errorf("process_samples: expected 1 row, got %d", n)
+	}
+	if n := countRows(t, db, "cpu_metrics"); n != 1 {
+		t.Errorf("cpu_metrics: expected 1 row kept by METRICS_RETENTION_DAYS, got %d", n)
+	}
+}
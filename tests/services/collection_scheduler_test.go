@@ -0,0 +1,167 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/collectorhealth"
+	"system-stats/internal/app/config"
+	"system-stats/internal/app/snapshot"
+	cpuentities "system-stats/internal/modules/cpu/infrastructure/entities"
+	historyapp "system-stats/internal/modules/history_metrics/application"
+	historycore "system-stats/internal/modules/history_metrics/core"
+	hostentities "system-stats/internal/modules/hosts/infrastructure/entities"
+)
+
+type fakeHostService struct{}
+
+func (fakeHostService) RegisterOrUpdateCurrentHost(context.Context) (*hostentities.Host, error) {
+	return &hostentities.Host{ID: hostentities.LocalCollectorHostID}, nil
+}
+func (fakeHostService) GetHostByMacAddress(context.Context, string) (*hostentities.Host, error) {
+	return nil, nil
+}
+func (fakeHostService) GetHostByID(context.Context, uint) (*hostentities.Host, error) {
+	return nil, nil
+}
+func (fakeHostService) GetAllHosts(context.Context) ([]hostentities.Host, error) { return nil, nil }
+func (fakeHostService) GetCurrentHost(context.Context) (*hostentities.Host, error) {
+	return &hostentities.Host{ID: hostentities.LocalCollectorHostID}, nil
+}
+func (fakeHostService) GetCurrentHostInfo(context.Context) (hostentities.HostInfo, error) {
+	return hostentities.HostInfo{}, nil
+}
+
+func statsFor(t *testing.T, tracker *collectorhealth.Tracker, module string) collectorhealth.Status {
+	t.Helper()
+	for _, st := range tracker.Statuses() {
+		if st.Module == module {
+			return st
+		}
+	}
+	t.Fatalf("no stats for module %q", module)
+	return collectorhealth.Status{}
+}
+
+func TestScheduler_SlowCollectorDoesNotBlockOthers(t *testing.T) {
+	store := snapshot.NewStore()
+	collection := config.CollectionConfig{
+		Default: config.CollectorSchedule{Interval: 5 * time.Second, Timeout: 5 * time.Second},
+		Modules: map[string]config.CollectorSchedule{
+			"docker": {Interval: 10 * time.Millisecond, Timeout: 50 * time.Millisecond},
+		},
+	}
+	collectors := []historycore.ModuleCollector{
+		{
+			Name: "cpu",
+			CollectAndSave: func(context.Context, uint) (snapshot.Update, error) {
+				return func(s *snapshot.Snapshot) { s.CPU = &cpuentities.CPUMetric{UsagePercent: 12} }, nil
+			},
+		},
+		{
+			Name: "docker",
+			CollectAndSave: func(ctx context.Context, _ uint) (snapshot.Update, error) {
+				<-ctx.Done() // a hung daemon that only gives up on cancellation
+				return nil, ctx.Err()
+			},
+		},
+	}
+	tracker := collectorhealth.NewTracker()
+	svc := historyapp.NewHistoricalMetricsService(log.New(io.Discard), collectors, collection, store, tracker, fakeHostService{})
+
+	start := time.Now()
+	if err := svc.CollectAndSaveMetrics(context.Background()); err != nil {
+		t.Fatalf("CollectAndSaveMetrics: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("cycle took %s; docker timeout was not applied", elapsed)
+	}
+
+	snap := store.Latest()
+	if snap == nil || snap.CPU == nil || snap.CPU.UsagePercent != 12 {
+		t.Fatalf("cpu update not published: %+v", snap)
+	}
+	if snap.Docker != nil {
+		t.Error("failed docker run should not set a docker metric")
+	}
+
+	docker := statsFor(t, tracker, "docker")
+	if docker.Timeouts != 1 || docker.Overruns != 1 || docker.State != collectorhealth.StateFailing {
+		t.Errorf("docker stats = %+v, want 1 timeout, 1 overrun and failing", docker)
+	}
+	if cpu := statsFor(t, tracker, "cpu"); cpu.IntervalSeconds != 5 || cpu.Timeouts != 0 || cpu.State != collectorhealth.StateOK {
+		t.Errorf("cpu stats = %+v, want default schedule, no timeouts and ok", cpu)
+	}
+}
+
+func TestScheduler_SkipsWhilePreviousRunInProgress(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	collectors := []historycore.ModuleCollector{{
+		Name: "disk",
+		CollectAndSave: func(context.Context, uint) (snapshot.Update, error) {
+			started <- struct{}{}
+			<-release
+			return nil, nil
+		},
+	}}
+	collection := config.CollectionConfig{Default: config.CollectorSchedule{Interval: time.Second, Timeout: time.Minute}}
+	tracker := collectorhealth.NewTracker()
+	svc := historyapp.NewHistoricalMetricsService(log.New(io.Discard), collectors, collection, snapshot.NewStore(), tracker, fakeHostService{})
+
+	done := make(chan struct{})
+	go func() {
+		_ = svc.CollectAndSaveMetrics(context.Background())
+		close(done)
+	}()
+	<-started
+
+	if err := svc.CollectAndSaveMetrics(context.Background()); err != nil {
+		t.Fatalf("second cycle: %v", err)
+	}
+	close(release)
+	<-done
+
+	if st := statsFor(t, tracker, "disk"); st.Skipped != 1 {
+		t.Errorf("Skipped = %d, want 1", st.Skipped)
+	}
+}
+
+func TestScheduler_StopDoesNotWaitForInitialCollection(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	collectors := []historycore.ModuleCollector{{
+		Name: "smart",
+		CollectAndSave: func(context.Context, uint) (snapshot.Update, error) {
+			started <- struct{}{}
+			<-release
+			return nil, nil
+		},
+	}}
+	collection := config.CollectionConfig{Default: config.CollectorSchedule{Interval: time.Hour, Timeout: time.Hour}}
+	svc := historyapp.NewHistoricalMetricsService(log.New(io.Discard), collectors, collection, snapshot.NewStore(), collectorhealth.NewTracker(), fakeHostService{})
+
+	done := make(chan struct{})
+	go func() {
+		_ = svc.StartPeriodicCollection(context.Background())
+		close(done)
+	}()
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		svc.StopPeriodicCollection()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Error("StopPeriodicCollection blocked on the initial collection")
+	}
+	close(release)
+	<-done
+}
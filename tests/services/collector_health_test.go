@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"system-stats/internal/app/collectorhealth"
+)
+
+func TestTracker_StateTransitions(t *testing.T) {
+	tracker := collectorhealth.NewTracker()
+	tracker.Register("cpu", time.Minute, 10*time.Second)
+
+	if st := statsFor(t, tracker, "cpu"); st.State != collectorhealth.StatePending {
+		t.Fatalf("State = %q before any run, want pending", st.State)
+	}
+
+	tracker.RecordRun("cpu", 20*time.Millisecond, nil, false)
+	if st := statsFor(t, tracker, "cpu"); st.State != collectorhealth.StateOK || st.LastSuccessAt == nil {
+		t.Fatalf("after success: %+v", st)
+	}
+
+	tracker.RecordRun("cpu", 10*time.Second, errors.New("boom"), true)
+	tracker.RecordRun("cpu", time.Second, errors.New("boom again"), false)
+	st := statsFor(t, tracker, "cpu")
+	if st.State != collectorhealth.StateFailing || st.ConsecutiveFailures != 2 {
+		t.Fatalf("after failures: %+v", st)
+	}
+	if st.LastError != "boom again" || st.Errors != 2 || st.Timeouts != 1 || st.LastDurationSeconds != 1 {
+		t.Errorf("failure details = %+v", st)
+	}
+	if collectorhealth.Healthy(tracker.Statuses()) {
+		t.Error("Healthy = true with a failing collector")
+	}
+
+	tracker.RecordRun("cpu", time.Millisecond, nil, false)
+	st = statsFor(t, tracker, "cpu")
+	if st.State != collectorhealth.StateOK || st.ConsecutiveFailures != 0 || st.Runs != 4 {
+		t.Errorf("after recovery: %+v", st)
+	}
+	if st.LastError == "" {
+		t.Error("LastError should be kept after recovery")
+	}
+}
+
+func TestTracker_StaleWithoutRecentSuccess(t *testing.T) {
+	tracker := collectorhealth.NewTracker()
+	tracker.Register("disk", time.Millisecond, time.Second)
+	tracker.RecordRun("disk", 0, nil, false)
+
+	time.Sleep(10 * time.Millisecond)
+	if st := statsFor(t, tracker, "disk"); st.State != collectorhealth.StateStale {
+		t.Errorf("State = %q, want stale", st.State)
+	}
+	if collectorhealth.Healthy(tracker.Statuses()) {
+		t.Error("Healthy = true with a stale collector")
+	}
+}
+
+func TestSummarize_ListsFailingModulesWithoutErrors(t *testing.T) {
+	statuses := []collectorhealth.Status{
+		{Module: "cpu", State: collectorhealth.StateOK},
+		{Module: "docker", State: collectorhealth.StateFailing, LastError: "dial unix /var/run/docker.sock: permission denied"},
+		{Module: "disk", State: collectorhealth.StateStale},
+		{Module: "smart", State: collectorhealth.StatePending},
+	}
+	summary := collectorhealth.Summarize(statuses)
+	if summary.State != "degraded" || !reflect.DeepEqual(summary.Failing, []string{"docker", "disk"}) {
+		t.Errorf("Summarize = %+v, want degraded with docker and disk", summary)
+	}
+	if got := collectorhealth.Summarize(statuses[:1]); got.State != "ok" || got.Failing != nil {
+		t.Errorf("Summarize of healthy collectors = %+v, want ok", got)
+	}
+}
@@ -0,0 +1,189 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"system-stats/internal/app/database"
+	"system-stats/internal/app/registry"
+	"system-stats/internal/app/retention"
+	"system-stats/internal/modules"
+	custommetrics "system-stats/internal/modules/custom/application"
+	"system-stats/internal/modules/custom/infrastructure/entities"
+	"system-stats/internal/modules/custom/infrastructure/repositories"
+)
+
+// openModulesDB returns a SQLite database migrated with the core and every module's migrations.
+func openModulesDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "stats.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	reg, err := registry.New(modules.All(), nil)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	if err := database.Migrate(db, reg.Migrations()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func newCustomMetricsService(t *testing.T) (custommetrics.Service, *gorm.DB) {
+	t.Helper()
+	db := openModulesDB(t)
+	svc := custommetrics.NewService(log.New(os.Stderr), repositories.NewCustomMetricRepository(db), repositories.NewTokenRepository(db))
+	return svc, db
+}
+
+func createToken(t *testing.T, svc custommetrics.Service, hostID *uint, quota int) *entities.CustomMetricToken {
+	t.Helper()
+	_, plaintext, err := svc.CreateToken(context.Background(), 1, "app", hostID, quota)
+	if err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+	token, err := svc.Authenticate(context.Background(), plaintext)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	return token
+}
+
+func TestCustomMetrics_IngestAndQueryByLabel(t *testing.T) {
+	svc, _ := newCustomMetricsService(t)
+	ctx := context.Background()
+	token := createToken(t, svc, nil, 0)
+	if token.QuotaPerMinute != custommetrics.DefaultQuotaPerMinute {
+		t.Errorf("QuotaPerMinute = %d, want default", token.QuotaPerMinute)
+	}
+
+	earlier := time.Now().UTC().Add(-time.Minute)
+	samples := []entities.Sample{
+		{Name: "jobs_backlog", Value: 5, Labels: map[string]string{"queue": "mail", "env": "prod"}, Timestamp: &earlier},
+		{Name: "jobs_backlog", Value: 7, Labels: map[string]string{"queue": "mail", "env": "prod"}},
+		{Name: "jobs_backlog", Value: 1, Labels: map[string]string{"queue": "sms", "env": "prod"}},
+		{Name: "active_users", Value: 42},
+	}
+	if err := svc.Ingest(ctx, token, 1, samples); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+
+	all, err := svc.ListSeries(ctx, 1, "", nil)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("ListSeries = %+v, %v; want 3 series", all, err)
+	}
+
+	mail, err := svc.QuerySeries(ctx, 1, 1, "jobs_backlog", []entities.LabelMatcher{{Key: "queue", Value: "mail"}, {Key: "env", Value: "prod"}})
+	if err != nil {
+		t.Fatalf("QuerySeries: %v", err)
+	}
+	if len(mail) != 1 || len(mail[0].Points) != 2 || mail[0].LastValue != 7 || mail[0].Labels["queue"] != "mail" {
+		t.Fatalf("mail series = %+v", mail)
+	}
+	if mail[0].Points[0].Value != 5 || mail[0].Points[1].Value != 7 {
+		t.Errorf("points not in time order: %+v", mail[0].Points)
+	}
+
+	// Backfilling an older sample does not move the newest value back
+	older := time.Now().UTC().Add(-2 * time.Minute)
+	if err := svc.Ingest(ctx, token, 1, []entities.Sample{{Name: "jobs_backlog", Value: 3, Labels: map[string]string{"queue": "mail", "env": "prod"}, Timestamp: &older}}); err != nil {
+		t.Fatalf("Ingest backfill: %v", err)
+	}
+	mail, _ = svc.ListSeries(ctx, 1, "jobs_backlog", []entities.LabelMatcher{{Key: "queue", Value: "mail"}})
+	if len(mail) != 1 || mail[0].LastValue != 7 {
+		t.Errorf("after backfill: %+v", mail)
+	}
+
+	other, _ := svc.ListSeries(ctx, 2, "", nil)
+	if len(other) != 0 {
+		t.Errorf("host 2 series = %+v, want none", other)
+	}
+}
+
+func TestCustomMetrics_Validation(t *testing.T) {
+	now := time.Now().UTC()
+	old := now.Add(-48 * time.Hour)
+	bad := map[string][]entities.Sample{
+		"empty":       nil,
+		"bad name":    {{Name: "1bad", Value: 1}},
+		"bad label":   {{Name: "ok", Value: 1, Labels: map[string]string{"bad-key": "x"}}},
+		"too old":     {{Name: "ok", Value: 1, Timestamp: &old}},
+		"many labels": {{Name: "ok", Value: 1, Labels: map[string]string{"a": "", "b": "", "c": "", "d": "", "e": "", "f": "", "g": "", "h": "", "i": "", "j": "", "k": ""}}},
+	}
+	for name, samples := range bad {
+		var verr *custommetrics.ValidationError
+		if err := custommetrics.ValidateSamples(samples, now); !errors.As(err, &verr) {
+			t.Errorf("%s: err = %v, want ValidationError", name, err)
+		}
+	}
+}
+
+func TestCustomMetrics_QuotaAndHostBinding(t *testing.T) {
+	svc, _ := newCustomMetricsService(t)
+	ctx := context.Background()
+	host := uint(1)
+	token := createToken(t, svc, &host, 3)
+
+	if err := svc.Ingest(ctx, token, 2, []entities.Sample{{Name: "x", Value: 1}}); !errors.Is(err, custommetrics.ErrHostNotAllowed) {
+		t.Errorf("write to other host: err = %v, want ErrHostNotAllowed", err)
+	}
+	if err := svc.Ingest(ctx, token, 1, []entities.Sample{{Name: "x", Value: 1}, {Name: "y", Value: 2}}); err != nil {
+		t.Fatalf("Ingest within quota: %v", err)
+	}
+	var qerr *custommetrics.QuotaError
+	if err := svc.Ingest(ctx, token, 1, []entities.Sample{{Name: "x", Value: 1}, {Name: "y", Value: 2}}); !errors.As(err, &qerr) || qerr.RetryAfter <= 0 {
+		t.Fatalf("Ingest over quota: err = %v, want QuotaError with RetryAfter", err)
+	}
+	// The rejected write consumed nothing: the one remaining sample still fits
+	if err := svc.Ingest(ctx, token, 1, []entities.Sample{{Name: "x", Value: 1}}); err != nil {
+		t.Errorf("Ingest of remaining quota: %v", err)
+	}
+
+	tokens, _ := svc.ListTokens(ctx)
+	if len(tokens) != 1 || tokens[0].LastUsedAt == nil {
+		t.Errorf("tokens = %+v, want last_used_at set", tokens)
+	}
+	if err := svc.DeleteToken(ctx, token.ID); err != nil {
+		t.Fatalf("DeleteToken: %v", err)
+	}
+	if err := svc.DeleteToken(ctx, token.ID); !errors.Is(err, custommetrics.ErrTokenNotFound) {
+		t.Errorf("second DeleteToken: err = %v, want ErrTokenNotFound", err)
+	}
+}
+
+func TestCustomMetrics_RetentionRemovesStaleSeries(t *testing.T) {
+	svc, db := newCustomMetricsService(t)
+	ctx := context.Background()
+	token := createToken(t, svc, nil, 0)
+	if err := svc.Ingest(ctx, token, 1, []entities.Sample{{Name: "x", Value: 1, Labels: map[string]string{"a": "b"}}}); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	old := time.Now().UTC().AddDate(0, 0, -40)
+	db.Exec("UPDATE custom_metrics SET timestamp = ?", old)
+	db.Exec("UPDATE custom_metric_series SET timestamp = ?", old)
+
+	retention.NewService(db, log.New(os.Stderr), 30, []string{"custom_metrics", "custom_metric_series"}).Cleanup()
+
+	for _, table := range []string{"custom_metrics", "custom_metric_series", "custom_metric_labels"} {
+		var n int64
+		db.Table(table).Count(&n)
+		if n != 0 {
+			t.Errorf("%s has %d rows after retention, want 0", table, n)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"system-stats/internal/modules/disk/infrastructure/value_objects"
+)
+
+func TestDiskIOCalculator_FirstSampleHasNoRate(t *testing.T) {
+	calc := value_objects.NewDiskIOCalculator()
+	rate := calc.Calculate("sda", time.Now(), value_objects.DiskIOCounters{ReadCount: 100})
+	if rate.Valid {
+		t.Fatalf("first sample should not produce a rate: %+v", rate)
+	}
+}
+
+func TestDiskIOCalculator_ComputesRates(t *testing.T) {
+	calc := value_objects.NewDiskIOCalculator()
+	start := time.Now()
+	calc.Calculate("sda", start, value_objects.DiskIOCounters{
+		ReadCount: 100, WriteCount: 50, ReadBytes: 1000, WriteBytes: 2000,
+		ReadTime: 10, WriteTime: 20, IoTime: 100,
+	})
+	rate := calc.Calculate("sda", start.Add(2*time.Second), value_objects.DiskIOCounters{
+		ReadCount: 120, WriteCount: 60, ReadBytes: 5000, WriteBytes: 4000,
+		ReadTime: 50, WriteTime: 40, IoTime: 600,
+	})
+
+	if !rate.Valid {
+		t.Fatal("expected a valid rate")
+	}
+	checks := map[string][2]float64{
+		"ReadIOPS":          {rate.ReadIOPS, 10},
+		"WriteIOPS":         {rate.WriteIOPS, 5},
+		"ReadBytesPerSec":   {rate.ReadBytesPerSec, 2000},
+		"WriteBytesPerSec":  {rate.WriteBytesPerSec, 1000},
+		"AvgReadLatencyMs":  {rate.AvgReadLatencyMs, 2},
+		"AvgWriteLatencyMs": {rate.AvgWriteLatencyMs, 2},
+		"UtilPercent":       {rate.UtilPercent, 25},
+	}
+	for name, c := range checks {
+		if math.Abs(c[0]-c[1]) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, c[0], c[1])
+		}
+	}
+}
+
+func TestDiskIOCalculator_CounterResetRebaselines(t *testing.T) {
+	calc := value_objects.NewDiskIOCalculator()
+	start := time.Now()
+	calc.Calculate("sda", start, value_objects.DiskIOCounters{ReadCount: 1000, ReadBytes: 1 << 20})
+
+	rate := calc.Calculate("sda", start.Add(time.Second), value_objects.DiskIOCounters{ReadCount: 5, ReadBytes: 512})
+	if rate.Valid || rate.ReadIOPS != 0 {
+		t.Fatalf("reset should yield no rate, got %+v", rate)
+	}
+
+	rate = calc.Calculate("sda", start.Add(2*time.Second), value_objects.DiskIOCounters{ReadCount: 15, ReadBytes: 1536})
+	if !rate.Valid || rate.ReadIOPS != 10 {
+		t.Fatalf("expected rate from new baseline, got %+v", rate)
+	}
+}
+
+func TestDiskIOCalculator_32BitWrap(t *testing.T) {
+	calc := value_objects.NewDiskIOCalculator()
+	start := time.Now()
+	calc.Calculate("sda", start, value_objects.DiskIOCounters{ReadCount: 100, IoTime: math.MaxUint32 - 99})
+
+	// The io time is a 32-bit millisecond counter; 500 ms in one second is a wrap
+	rate := calc.Calculate("sda", start.Add(time.Second), value_objects.DiskIOCounters{ReadCount: 110, IoTime: 400})
+	if !rate.Valid || rate.ReadIOPS != 10 || rate.UtilPercent != 50 {
+		t.Fatalf("32-bit wrap should yield 10 IOPS at 50%% util, got %+v", rate)
+	}
+}
+
+func TestDiskIOCalculator_ResetIsNotAWrap(t *testing.T) {
+	calc := value_objects.NewDiskIOCalculator()
+	start := time.Now()
+	// Operation counts are 64-bit, and an io time that fell back by more than the interval was reset
+	calc.Calculate("sda", start, value_objects.DiskIOCounters{ReadCount: math.MaxUint32 - 4})
+	if rate := calc.Calculate("sda", start.Add(time.Second), value_objects.DiskIOCounters{ReadCount: 5}); rate.Valid {
+		t.Errorf("count reset yielded %+v", rate)
+	}
+	calc.Calculate("sda", start.Add(2*time.Second), value_objects.DiskIOCounters{IoTime: 3 << 30})
+	if rate := calc.Calculate("sda", start.Add(3*time.Second), value_objects.DiskIOCounters{IoTime: 10}); rate.Valid {
+		t.Errorf("io time reset yielded %+v", rate)
+	}
+}
+
+func TestDiskIOCalculator_UtilCappedAt100(t *testing.T) {
+	calc := value_objects.NewDiskIOCalculator()
+	start := time.Now()
+	calc.Calculate("nvme0n1", start, value_objects.DiskIOCounters{IoTime: 0})
+	rate := calc.Calculate("nvme0n1", start.Add(time.Second), value_objects.DiskIOCounters{IoTime: 1500})
+	if rate.UtilPercent != 100 {
+		t.Errorf("UtilPercent = %v, want 100", rate.UtilPercent)
+	}
+}
@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/shirou/gopsutil/v4/disk"
+
+	"system-stats/internal/app/collectorhealth"
+	"system-stats/internal/app/prometheusmetrics"
+	"system-stats/internal/app/snapshot"
+	diskservice "system-stats/internal/modules/disk/application"
+	diskcollectors "system-stats/internal/modules/disk/infrastructure/collectors"
+	diskentities "system-stats/internal/modules/disk/infrastructure/entities"
+	diskrepos "system-stats/internal/modules/disk/infrastructure/repositories"
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	eventrepos "system-stats/internal/modules/events/infrastructure/repositories"
+)
+
+const testMountstats = `device rootfs mounted on / with fstype rootfs
+device nas:/export/home mounted on /mnt/nfs\040home with fstype nfs4 statvers=1.1
+	opts:	rw,vers=4.2,rsize=1048576,wsize=1048576,hard,proto=tcp,timeo=600,retrans=2
+	age:	86400
+	bytes:	1000 2000 0 0 4096 8192 1 2
+	RPC iostats version: 1.1  p/v: 100003/4 (nfs)
+	xprt:	tcp 0 1 2 0 0 500 500 0 500 0 2 0 0
+	per-op statistics
+	        NULL: 1 1 0 44 24 0 0 0 0
+	        READ: 100 103 1 12000 1300000 5 200 210 0
+	       WRITE: 50 50 0 800000 6000 2 100 110 0
+device //fs/share mounted on /mnt/cifs with fstype cifs
+`
+
+func TestParseMountstats(t *testing.T) {
+	mounts, err := diskcollectors.ParseMountstats(strings.NewReader(testMountstats))
+	if err != nil || len(mounts) != 1 {
+		t.Fatalf("ParseMountstats = %+v, %v; want the NFS mount only", mounts, err)
+	}
+	m := mounts[0]
+	if m.Mountpoint != "/mnt/nfs home" || m.Export != "nas:/export/home" || m.Fstype != "nfs4" {
+		t.Errorf("mount = %+v", m)
+	}
+	if m.Ops != 151 || m.Retransmissions != 3 || m.MajorTimeouts != 1 || m.RTTMs != 300 || m.ExecuteMs != 320 ||
+		m.ReadBytes != 4096 || m.WriteBytes != 8192 {
+		t.Errorf("counters = %+v", m)
+	}
+}
+
+func TestMountProber_StaleUntilHungCallReturns(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	usage := func(ctx context.Context, path string) (*disk.UsageStat, error) {
+		calls.Add(1)
+		if path == "/mnt/hung" {
+			<-release
+		}
+		return &disk.UsageStat{Path: path, Total: 100}, nil
+	}
+	prober := diskcollectors.NewMountProber(20*time.Millisecond, usage)
+	ctx := context.Background()
+
+	if u, err := prober.Usage(ctx, "/"); err != nil || u.Total != 100 {
+		t.Fatalf("Usage(/) = %+v, %v", u, err)
+	}
+	if _, err := prober.Usage(ctx, "/mnt/hung"); !errors.Is(err, diskcollectors.ErrMountStale) {
+		t.Fatalf("hung mount: err = %v, want ErrMountStale", err)
+	}
+	// While the first call hangs, the mount is stale at once and no second call is started
+	start := time.Now()
+	if _, err := prober.Usage(ctx, "/mnt/hung"); !errors.Is(err, diskcollectors.ErrMountStale) || time.Since(start) > 10*time.Millisecond {
+		t.Fatalf("still hung: err = %v after %s", err, time.Since(start))
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("statfs calls = %d, want 2", n)
+	}
+
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+	if u, err := prober.Usage(ctx, "/mnt/hung"); err != nil || u.Total != 100 {
+		t.Fatalf("recovered mount = %+v, %v", u, err)
+	}
+}
+
+func TestMountTimeoutFromEnv(t *testing.T) {
+	t.Setenv(diskcollectors.MountTimeoutEnv, "")
+	if d, err := diskcollectors.MountTimeoutFromEnv(); err != nil || d != diskcollectors.DefaultMountTimeout {
+		t.Errorf("default = %s, %v", d, err)
+	}
+	t.Setenv(diskcollectors.MountTimeoutEnv, "500ms")
+	if d, err := diskcollectors.MountTimeoutFromEnv(); err != nil || d != 500*time.Millisecond {
+		t.Errorf("500ms = %s, %v", d, err)
+	}
+	t.Setenv(diskcollectors.MountTimeoutEnv, "0")
+	if _, err := diskcollectors.MountTimeoutFromEnv(); err == nil {
+		t.Error("accepted a zero timeout")
+	}
+}
+
+func TestDiskService_SavesStaleMountsNFSAndResolvesOldProblems(t *testing.T) {
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	ctx := context.Background()
+	// A stale mount recorded by a previous run that was unmounted since
+	old := evententities.HostEvent{
+		Timestamp: time.Now().UTC().Add(-time.Hour), Source: diskservice.EventSource, Subject: "/mnt/old",
+		Kind: diskservice.EventStale, Severity: evententities.SeverityWarning,
+	}
+	if err := events.Record(ctx, 1, []evententities.HostEvent{old}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := diskservice.NewService(log.Default(), diskrepos.NewDiskRepository(db), events, diskcollectors.DefaultMountTimeout)
+	rtt := 2.5
+	metric := diskentities.DiskMetric{
+		Total: 100,
+		Mounts: []diskentities.UsageStat{
+			{Path: "/", Fstype: "ext4", Total: 100},
+			{Path: "/mnt/nfs", Fstype: "nfs4", Stale: true},
+		},
+		NFSMounts: []diskentities.NFSMountStat{{Mountpoint: "/mnt/nfs", Export: "nas:/export", Fstype: "nfs4", Ops: 10, Retransmissions: 2, AvgRTTMs: &rtt}},
+	}
+	if err := svc.Save(ctx, metric, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	mounts, err := svc.GetMountHistoryByHost(ctx, 1, 1, "/mnt/nfs")
+	if err != nil || len(mounts) != 1 || !mounts[0].Stale {
+		t.Errorf("mount history = %+v, %v", mounts, err)
+	}
+	nfs, err := svc.GetNFSHistoryByHost(ctx, 1, 1, "")
+	if err != nil || len(nfs) != 1 || nfs[0].Retransmissions != 2 || nfs[0].AvgRTTMs == nil || *nfs[0].AvgRTTMs != 2.5 {
+		t.Errorf("nfs history = %+v, %v", nfs, err)
+	}
+	problems, err := events.Problems(ctx, 1)
+	if err != nil || len(problems) != 0 {
+		t.Errorf("problems = %+v, %v; the unmounted stale mount must be resolved", problems, err)
+	}
+}
+
+func TestPrometheus_RepeatedMountpointsKeepScrapeWorking(t *testing.T) {
+	store := snapshot.NewStore()
+	store.Apply(1, time.Now().UTC(), func(s *snapshot.Snapshot) {
+		s.Disk = &diskentities.DiskMetric{
+			// A bind mount stacked on the same path as an NFS mount
+			Mounts: []diskentities.UsageStat{
+				{Path: "/mnt/data", Fstype: "nfs4", Stale: true},
+				{Path: "/mnt/data", Fstype: "nfs4"},
+			},
+			NFSMounts: []diskentities.NFSMountStat{
+				{Mountpoint: "/mnt/data", Ops: 10},
+				{Mountpoint: "/mnt/data", Ops: 20},
+			},
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	prometheusmetrics.New(store, collectorhealth.NewTracker()).Handler().
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `system_disk_mount_stale{fstype="nfs4",mountpoint="/mnt/data"} 1`) {
+		t.Errorf("first mount not kept:\n%s", body)
+	}
+	if !strings.Contains(body, `system_nfs_ops_total{mountpoint="/mnt/data"} 10`) {
+		t.Errorf("first NFS mount not kept:\n%s", body)
+	}
+}
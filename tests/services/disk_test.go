@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/log"
 
 	diskservice "system-stats/internal/modules/disk/application"
+	diskcollectors "system-stats/internal/modules/disk/infrastructure/collectors"
 	diskentities "system-stats/internal/modules/disk/infrastructure/entities"
 	diskrepos "system-stats/internal/modules/disk/infrastructure/repositories"
 )
@@ -46,10 +47,22 @@ func (m *mockDiskRepository) GetHistoricalMetricsByHost(_ context.Context, _ uin
 	return m.historicalMetrics, m.historicalErr
 }
 
+func (m *mockDiskRepository) GetMountHistoryByHost(_ context.Context, _ uint, _ float64, _ string) ([]diskentities.HistoricalDiskMountMetric, error) {
+	return nil, nil
+}
+
+func (m *mockDiskRepository) GetIOHistoryByHost(_ context.Context, _ uint, _ float64, _ string) ([]diskentities.HistoricalDiskIOMetric, error) {
+	return nil, nil
+}
+
+func (m *mockDiskRepository) GetNFSHistoryByHost(_ context.Context, _ uint, _ float64, _ string) ([]diskentities.HistoricalDiskNFSMetric, error) {
+	return nil, nil
+}
+
 var _ diskrepos.DiskRepository = (*mockDiskRepository)(nil)
 
 func newDiskService(repo diskrepos.DiskRepository) diskservice.Service {
-	return diskservice.NewService(log.Default(), repo)
+	return diskservice.NewService(log.Default(), repo, nil, diskcollectors.DefaultMountTimeout)
 }
 
 func TestDisk_GetLatest_Success(t *testing.T) {
@@ -0,0 +1,78 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"system-stats/internal/modules/docker/domain"
+)
+
+func TestBuildContainerSeries_SurvivesRecreation(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	samples := []domain.ContainerSample{
+		{Timestamp: t0, ContainerID: "aaa", Name: "shop-web-1", Stack: "shop", Service: "web", CPUPercent: 10, NetworkRx: 1000},
+		{Timestamp: t0.Add(5 * time.Second), ContainerID: "aaa", Name: "shop-web-1", Stack: "shop", Service: "web", CPUPercent: 20, NetworkRx: 6000},
+		// Redeployed: new container ID, counters restart from zero
+		{Timestamp: t0.Add(10 * time.Second), ContainerID: "bbb", Name: "shop-web-1", Stack: "shop", Service: "web", CPUPercent: 30, NetworkRx: 100},
+		{Timestamp: t0.Add(15 * time.Second), ContainerID: "bbb", Name: "shop-web-1", Stack: "shop", Service: "web", CPUPercent: 40, NetworkRx: 600},
+	}
+
+	series := domain.BuildContainerSeries(samples, false, 0, domain.AggregateAvg)
+	if len(series) != 1 {
+		t.Fatalf("expected one series across recreation, got %d", len(series))
+	}
+	if series[0].Key != "shop/web" {
+		t.Errorf("Key = %q, want shop/web", series[0].Key)
+	}
+	pts := series[0].Points
+	if len(pts) != 4 {
+		t.Fatalf("expected 4 points, got %d", len(pts))
+	}
+	wantRx := []float64{0, 1000, 0, 100}
+	for i, w := range wantRx {
+		if pts[i].NetworkRxBytesPerSec != w {
+			t.Errorf("point %d rx = %v, want %v", i, pts[i].NetworkRxBytesPerSec, w)
+		}
+	}
+}
+
+func TestBuildContainerSeries_SumsReplicasAndGroupsByStack(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	samples := []domain.ContainerSample{
+		{Timestamp: t0, ContainerID: "a", Stack: "shop", Service: "web", CPUPercent: 10, MemoryUsage: 100},
+		{Timestamp: t0, ContainerID: "b", Stack: "shop", Service: "web", CPUPercent: 15, MemoryUsage: 200},
+		{Timestamp: t0, ContainerID: "c", Stack: "shop", Service: "db", CPUPercent: 5, MemoryUsage: 300},
+	}
+
+	perService := domain.BuildContainerSeries(samples, false, 0, domain.AggregateAvg)
+	if len(perService) != 2 {
+		t.Fatalf("expected 2 service series, got %d", len(perService))
+	}
+	if web := perService[1]; web.Key != "shop/web" || web.Points[0].CPUPercent != 25 || web.Points[0].MemoryUsage != 300 {
+		t.Errorf("web replicas not summed: %+v", web)
+	}
+
+	perStack := domain.BuildContainerSeries(samples, true, 0, domain.AggregateAvg)
+	if len(perStack) != 1 || perStack[0].Points[0].CPUPercent != 30 {
+		t.Errorf("stack series = %+v, want one series with cpu 30", perStack)
+	}
+}
+
+func TestBuildContainerSeries_Buckets(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var samples []domain.ContainerSample
+	for i, cpu := range []float64{10, 30, 50, 70} {
+		samples = append(samples, domain.ContainerSample{
+			Timestamp: t0.Add(time.Duration(i) * 30 * time.Second), ContainerID: "a", Stack: "s", Service: "x", CPUPercent: cpu,
+		})
+	}
+
+	avg := domain.BuildContainerSeries(samples, false, time.Minute, domain.AggregateAvg)[0].Points
+	if len(avg) != 2 || avg[0].CPUPercent != 20 || avg[1].CPUPercent != 60 {
+		t.Errorf("avg buckets = %+v", avg)
+	}
+	mx := domain.BuildContainerSeries(samples, false, time.Minute, domain.AggregateMax)[0].Points
+	if len(mx) != 2 || mx[0].CPUPercent != 30 || mx[1].CPUPercent != 70 {
+		t.Errorf("max buckets = %+v", mx)
+	}
+}
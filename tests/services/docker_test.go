@@ -46,6 +46,10 @@ func (m *mockDockerRepository) GetHistoricalMetricsByHost(_ context.Context, _ u
 	return m.historicalMetrics, m.historicalErr
 }
 
+func (m *mockDockerRepository) GetContainerHistoryByHost(_ context.Context, _ dockerrepos.ContainerHistoryQuery) ([]dockerentities.DockerContainerEntity, error) {
+	return nil, nil
+}
+
 type mockDockerCollector struct {
 	metric dockerentities.DockerMetric
 	err    error
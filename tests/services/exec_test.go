@@ -0,0 +1,212 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/modules/exec/infrastructure/collectors"
+	"system-stats/internal/modules/exec/infrastructure/entities"
+)
+
+func TestParseJSON_ObjectIsFlattened(t *testing.T) {
+	gauges, err := collectors.ParseJSON([]byte(`{"depth": 3, "queue": {"lag": 1.5, "ok": true}, "name": "x"}`))
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+	want := map[string]float64{"depth": 3, "queue.lag": 1.5, "queue.ok": 1}
+	if len(gauges) != len(want) {
+		t.Fatalf("gauges = %+v, want %v", gauges, want)
+	}
+	for _, g := range gauges {
+		if v, ok := want[g.Name]; !ok || v != g.Value {
+			t.Errorf("gauge %s = %v, want %v", g.Name, g.Value, want[g.Name])
+		}
+	}
+}
+
+func TestParseJSON_ListWithLabels(t *testing.T) {
+	gauges, err := collectors.ParseJSON([]byte(`[{"name": "jobs", "value": 7, "labels": {"queue": "mail"}}]`))
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+	if len(gauges) != 1 || gauges[0].Value != 7 || gauges[0].Labels["queue"] != "mail" {
+		t.Fatalf("gauges = %+v", gauges)
+	}
+	if _, err := collectors.ParseJSON([]byte(`[{"name": "jobs"}]`)); err == nil {
+		t.Error("expected an error for an item without a value")
+	}
+}
+
+func TestParsePrometheus(t *testing.T) {
+	out := `# TYPE jobs gauge
+jobs{queue="mail"} 4
+# TYPE latency summary
+latency_sum 2.5
+latency_count 10
+`
+	gauges, err := collectors.ParsePrometheus([]byte(out))
+	if err != nil {
+		t.Fatalf("ParsePrometheus: %v", err)
+	}
+	got := make(map[string]entities.Gauge, len(gauges))
+	for _, g := range gauges {
+		got[g.Name] = g
+	}
+	if g := got["jobs"]; g.Value != 4 || g.Labels["queue"] != "mail" {
+		t.Errorf("jobs = %+v", g)
+	}
+	if got["latency_sum"].Value != 2.5 || got["latency_count"].Value != 10 {
+		t.Errorf("summary = %+v", gauges)
+	}
+}
+
+func TestParseNagios(t *testing.T) {
+	out := "DISK OK - free space: / 3326 MB | /=2643MB;5948;5958;0;5968\nlong text | 'inode used'=12%;80;90 load=U\n"
+	message, gauges, err := collectors.ParseNagios([]byte(out))
+	if err != nil {
+		t.Fatalf("ParseNagios: %v", err)
+	}
+	if message != "DISK OK - free space: / 3326 MB" {
+		t.Errorf("message = %q", message)
+	}
+	if len(gauges) != 2 {
+		t.Fatalf("gauges = %+v, want 2 (U value skipped)", gauges)
+	}
+	if g := gauges[0]; g.Name != "/" || g.Value != 2643 || g.Unit != "MB" {
+		t.Errorf("gauges[0] = %+v", g)
+	}
+	if g := gauges[1]; g.Name != "inode used" || g.Value != 12 || g.Unit != "%" {
+		t.Errorf("gauges[1] = %+v", g)
+	}
+}
+
+func writeChecks(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "checks.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write checks: %v", err)
+	}
+	return path
+}
+
+func TestLoadChecks_Validation(t *testing.T) {
+	checks, err := collectors.LoadChecks(writeChecks(t, `{"checks": [{"name": "q", "command": ["true"], "format": "json", "interval": "1m"}]}`))
+	if err != nil {
+		t.Fatalf("LoadChecks: %v", err)
+	}
+	if len(checks) != 1 || time.Duration(checks[0].Interval) != time.Minute || time.Duration(checks[0].Timeout) != collectors.DefaultCheckTimeout {
+		t.Errorf("checks = %+v", checks)
+	}
+
+	bad := map[string]string{
+		"bad name":      `{"checks": [{"name": "a b", "command": ["true"], "format": "json"}]}`,
+		"no command":    `{"checks": [{"name": "a", "format": "json"}]}`,
+		"bad format":    `{"checks": [{"name": "a", "command": ["true"], "format": "xml"}]}`,
+		"duplicate":     `{"checks": [{"name": "a", "command": ["true"], "format": "json"}, {"name": "a", "command": ["true"], "format": "json"}]}`,
+		"bad duration":  `{"checks": [{"name": "a", "command": ["true"], "format": "json", "interval": 30}]}`,
+		"negative time": `{"checks": [{"name": "a", "command": ["true"], "format": "json", "timeout": "-1s"}]}`,
+	}
+	for name, body := range bad {
+		if _, err := collectors.LoadChecks(writeChecks(t, body)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestExecCollector_RunsChecks(t *testing.T) {
+	checks := []collectors.CheckConfig{
+		{Name: "depth", Command: []string{"sh", "-c", `echo '{"depth": 3}'`}, Format: collectors.FormatJSON, Timeout: collectors.Duration(5 * time.Second)},
+		{Name: "crit", Command: []string{"sh", "-c", "echo 'CRITICAL - down | up=0'; exit 2"}, Format: collectors.FormatNagios, Timeout: collectors.Duration(5 * time.Second)},
+		{Name: "slow", Command: []string{"sleep", "5"}, Format: collectors.FormatJSON, Timeout: collectors.Duration(100 * time.Millisecond)},
+	}
+	c := collectors.NewExecMetricsCollector(log.New(os.Stderr), checks)
+
+	metric, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(metric.Checks) != 3 {
+		t.Fatalf("checks = %+v", metric.Checks)
+	}
+	depth, crit, slow := metric.Checks[0], metric.Checks[1], metric.Checks[2]
+	if depth.Status != entities.StatusOK || len(depth.Gauges) != 1 || depth.Gauges[0].Value != 3 {
+		t.Errorf("depth = %+v", depth)
+	}
+	if crit.Status != entities.StatusCritical || crit.State != "critical" || crit.Output != "CRITICAL - down" || len(crit.Gauges) != 1 {
+		t.Errorf("crit = %+v", crit)
+	}
+	if slow.Status != entities.StatusUnknown || !strings.Contains(slow.Error, "timed out") {
+		t.Errorf("slow = %+v", slow)
+	}
+}
+
+func TestExecCollector_BoundsOutputAndGauges(t *testing.T) {
+	// A JSON object with one valid gauge and one whose 600-character name does not fit the column,
+	// printed as a single line longer than the output column with multi-byte characters at the cut
+	script := `printf '{"ok": 1, "%s": 2}\n' "$(printf 'é%.0s' $(seq 600))"`
+	checks := []collectors.CheckConfig{
+		{Name: "long", Command: []string{"sh", "-c", script}, Format: collectors.FormatJSON, Timeout: collectors.Duration(5 * time.Second)},
+	}
+	c := collectors.NewExecMetricsCollector(log.New(os.Stderr), checks)
+
+	metric, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	res := metric.Checks[0]
+	if len(res.Output) > 1024 || !utf8.ValidString(res.Output) || !strings.HasPrefix(res.Output, `{"ok": 1, "é`) {
+		t.Errorf("output = %d bytes, valid UTF-8 %v", len(res.Output), utf8.ValidString(res.Output))
+	}
+	if len(res.Gauges) != 1 || res.Gauges[0].Name != "ok" {
+		t.Errorf("gauges = %+v", res.Gauges)
+	}
+}
+
+func TestValidGauge(t *testing.T) {
+	cases := []struct {
+		name  string
+		gauge entities.Gauge
+		want  bool
+	}{
+		{"nagios label", entities.Gauge{Name: "/var used", Unit: "MB"}, true},
+		{"labels", entities.Gauge{Name: "queue", Labels: map[string]string{"queue": "mail"}}, true},
+		{"empty name", entities.Gauge{Name: ""}, false},
+		{"long name", entities.Gauge{Name: strings.Repeat("a", 256)}, false},
+		{"control character", entities.Gauge{Name: "a\x00b"}, false},
+		{"invalid UTF-8", entities.Gauge{Name: "a\xffb"}, false},
+		{"long unit", entities.Gauge{Name: "a", Unit: strings.Repeat("u", 17)}, false},
+		{"label key", entities.Gauge{Name: "a", Labels: map[string]string{"bad key": "v"}}, false},
+		{"long label value", entities.Gauge{Name: "a", Labels: map[string]string{"k": strings.Repeat("v", 129)}}, false},
+	}
+	for _, tc := range cases {
+		if got := collectors.ValidGauge(tc.gauge); got != tc.want {
+			t.Errorf("%s: ValidGauge = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExecCollector_HonoursInterval(t *testing.T) {
+	checks := []collectors.CheckConfig{
+		{Name: "hourly", Command: []string{"true"}, Format: collectors.FormatJSON, Interval: collectors.Duration(time.Hour), Timeout: collectors.Duration(5 * time.Second)},
+	}
+	c := collectors.NewExecMetricsCollector(log.New(os.Stderr), checks)
+
+	first, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	second, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(second.Checks) != 1 || !second.Checks[0].RanAt.Equal(first.Checks[0].RanAt) {
+		t.Errorf("check ran again before its interval: first %v, second %+v", first.Checks[0].RanAt, second.Checks)
+	}
+}
@@ -0,0 +1,20 @@
+package services_test
+
+import (
+	"testing"
+
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	healthentities "system-stats/internal/modules/health/infrastructure/entities"
+)
+
+func TestCountProblems_BySeverity(t *testing.T) {
+	problems := []evententities.HostEvent{
+		{Source: "watchlist", Subject: "nginx", Severity: evententities.SeverityCritical, Message: "nginx is down"},
+		{Source: "disk", Subject: "/mnt/nfs", Severity: evententities.SeverityWarning, Message: "/mnt/nfs stopped answering"},
+		{Source: "kernellog", Subject: "oom", Severity: evententities.SeverityCritical, Message: "Out of memory: Killed process 1234"},
+	}
+	got := healthentities.CountProblems(problems)
+	if got != (healthentities.ProblemCounts{Warning: 1, Critical: 2}) {
+		t.Errorf("CountProblems = %+v, want 1 warning and 2 critical", got)
+	}
+}
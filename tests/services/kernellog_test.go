@@ -0,0 +1,213 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	eventrepos "system-stats/internal/modules/events/infrastructure/repositories"
+	kernellogservice "system-stats/internal/modules/kernellog/application"
+	kernellogcollectors "system-stats/internal/modules/kernellog/infrastructure/collectors"
+	kernellogentities "system-stats/internal/modules/kernellog/infrastructure/entities"
+	kernellogrepos "system-stats/internal/modules/kernellog/infrastructure/repositories"
+)
+
+func TestClassifyKernelMessage(t *testing.T) {
+	cases := []struct {
+		message            string
+		category, severity string
+	}{
+		{"Out of memory: Killed process 4242 (java) total-vm:8388608kB, anon-rss:4194304kB", kernellogentities.CategoryOOM, evententities.SeverityCritical},
+		{"Memory cgroup out of memory: Killed process 77 (node) total-vm:1024kB", kernellogentities.CategoryOOM, evententities.SeverityWarning},
+		{"blk_update_request: I/O error, dev sdb, sector 123456 op 0x0:(READ) flags 0x0", kernellogentities.CategoryIO, evententities.SeverityCritical},
+		{"ata3.00: exception Emask 0x0 SAct 0x0 SErr 0x0 action 0x6 frozen", kernellogentities.CategoryIO, evententities.SeverityWarning},
+		{"nvme nvme0: I/O 12 QID 3 timeout, aborting", kernellogentities.CategoryIO, evententities.SeverityWarning},
+		{"XFS (dm-0): metadata I/O error in \"xfs_trans_read_buf_map\" at daddr 0x2", kernellogentities.CategoryFilesystem, evententities.SeverityCritical},
+		{"EXT4-fs error (device sda1): ext4_find_entry:1455: inode #2: comm ls: reading directory lblock 0", kernellogentities.CategoryFilesystem, evententities.SeverityCritical},
+		{"mce: [Hardware Error]: Machine check events logged", kernellogentities.CategoryHardware, evententities.SeverityWarning},
+		{"EDAC MC0: 1 UE memory read error on CPU_SrcID#0_Ha#0_Chan#1_DIMM#0", kernellogentities.CategoryHardware, evententities.SeverityCritical},
+		{"Kernel panic - not syncing: Fatal machine check", kernellogentities.CategoryHardware, evententities.SeverityCritical},
+		{"myapp[1234]: segfault at 0 ip 000055d0c1a2b3c4 sp 00007ffd1c2b3a40 error 4 in myapp[55d0c1a00000+2000]", kernellogentities.CategorySegfault, evententities.SeverityWarning},
+		{"traps: worker[999] general protection fault ip:7f1c2a3b4c5d sp:7ffd00000000 error:0 in libc.so.6", kernellogentities.CategorySegfault, evententities.SeverityWarning},
+		{"general protection fault, probably for non-canonical address 0xdead000000000100: 0000 [#1] SMP NOPTI", kernellogentities.CategoryKernel, evententities.SeverityCritical},
+		{"watchdog: BUG: soft lockup - CPU#3 stuck for 23s! [kworker/3:1:123]", kernellogentities.CategoryKernel, evententities.SeverityCritical},
+		{"INFO: task jbd2/sda1-8:321 blocked for more than 120 seconds.", kernellogentities.CategoryKernel, evententities.SeverityWarning},
+	}
+	for _, tc := range cases {
+		category, severity, ok := kernellogcollectors.Classify(tc.message)
+		if !ok || category != tc.category || severity != tc.severity {
+			t.Errorf("Classify(%q) = %q, %q, %v; want %q, %q", tc.message, category, severity, ok, tc.category, tc.severity)
+		}
+	}
+	for _, message := range []string{"e1000e: eth0 NIC Link is Up 1000 Mbps Full Duplex", "EXT4-fs (sda1): mounted filesystem with ordered data mode"} {
+		if category, _, ok := kernellogcollectors.Classify(message); ok {
+			t.Errorf("Classify(%q) = %q, want no category", message, category)
+		}
+	}
+}
+
+func TestParseKmsgRecordAndKernelMessage(t *testing.T) {
+	r, ok := kernellogcollectors.ParseKmsgRecord([]byte("3,1234,5678901,-;Out of memory: Killed process 42 (x)\n SUBSYSTEM=memory\n"))
+	if !ok || r.Priority != 3 || r.Facility != 0 || r.Seq != 1234 || r.Monotonic != 5678901*time.Microsecond || r.Message != "Out of memory: Killed process 42 (x)" {
+		t.Errorf("ParseKmsgRecord = %+v, %v", r, ok)
+	}
+	// systemd writing to /dev/kmsg: facility daemon (3)
+	if r, ok := kernellogcollectors.ParseKmsgRecord([]byte("30,1235,5679000,-;systemd[1]: Started foo.service.\n")); !ok || r.Facility != 3 || r.Priority != 6 {
+		t.Errorf("ParseKmsgRecord(userspace) = %+v, %v", r, ok)
+	}
+	if _, ok := kernellogcollectors.ParseKmsgRecord([]byte("garbage")); ok {
+		t.Error("ParseKmsgRecord accepted a record without a header")
+	}
+
+	for line, want := range map[string]string{
+		"Mar  1 12:00:00 host kernel: [12345.678901] Out of memory: Killed process 1 (x)": "Out of memory: Killed process 1 (x)",
+		"2026-03-01T12:00:00.123456+00:00 host kernel: traps: a[1] trap int3 ip:1":        "traps: a[1] trap int3 ip:1",
+		"[  12.345678] EXT4-fs error (device sda1): x":                                    "[  12.345678] EXT4-fs error (device sda1): x",
+	} {
+		if got := kernellogcollectors.KernelMessage([]byte(line)); got != want {
+			t.Errorf("KernelMessage(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestKernelLogService_FileCursorAndEvents(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOST_ROOT", root)
+	kernLog := filepath.Join(root, "var", "log", "kern.log")
+	writeHostFile(t, kernLog, "Mar  1 00:00:00 host kernel: [1.0] blk_update_request: I/O error, dev sda, sector 8\n")
+
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	repo := kernellogrepos.NewKernelLogRepository(db)
+	svc := kernellogservice.NewService(log.Default(), repo, events, "/var/log/kern.log", time.Nanosecond)
+	ctx := context.Background()
+	collect := func(svc kernellogservice.Service) kernellogentities.KernelLogMetric {
+		t.Helper()
+		metric, err := svc.Collect(ctx)
+		if err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+		if err := svc.Save(ctx, metric, 2); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		return metric
+	}
+
+	if !kernellogcollectors.Available("/var/log/kern.log") {
+		t.Fatal("Available = false with the log file present")
+	}
+	// The file is followed from its end
+	if first := collect(svc); len(first.Samples) != 0 || len(first.Events) != 0 {
+		t.Fatalf("first read = %+v, want nothing", first)
+	}
+
+	appendHostFile(t, kernLog, []byte("Mar  1 00:01:00 host kernel: [60.0] blk_update_request: I/O error, dev sda, sector 16\n"+
+		"Mar  1 00:01:01 host kernel: [61.0] ata1.00: exception Emask 0x0 SAct 0x0 SErr 0x0 action 0x6 frozen\n"+
+		"Mar  1 00:01:02 host kernel: [62.0] e1000e: eth0 NIC Link is Up\n"+
+		"Mar  1 00:01:03 host kernel: [63.0] app[7]: segfault at 0 ip 0 sp 0 error 4 in app[400000+1000]\n"))
+	second := collect(svc)
+	if len(second.Samples) != 2 {
+		t.Fatalf("second read samples = %+v, want io and segfault", second.Samples)
+	}
+	io := second.Samples[1]
+	if second.Samples[0].Category != kernellogentities.CategorySegfault ||
+		io.Category != kernellogentities.CategoryIO || io.Count != 2 || io.Severity != evententities.SeverityCritical {
+		t.Errorf("second read samples = %+v", second.Samples)
+	}
+	if problems, _ := events.Problems(ctx, 2); len(problems) != 2 {
+		t.Errorf("problems = %+v, want io and segfault", problems)
+	}
+
+	// A restart resumes at the saved cursor and takes over the open problems
+	appendHostFile(t, kernLog, []byte("Mar  1 00:02:00 host kernel: [120.0] Buffer I/O error on dev sda, logical block 2\n"))
+	restarted := kernellogservice.NewService(log.Default(), repo, events, "/var/log/kern.log", time.Nanosecond)
+	third := collect(restarted)
+	if len(third.Samples) != 1 || third.Samples[0].Count != 1 {
+		t.Fatalf("read after restart = %+v, want the one new I/O error", third.Samples)
+	}
+	collect(restarted)
+	if problems, _ := events.Problems(ctx, 2); len(problems) != 0 {
+		t.Errorf("problems after a quiet read = %+v, want none", problems)
+	}
+
+	counts, err := restarted.GetCountsByHost(ctx, 2, 1)
+	if err != nil || len(counts) != 2 || counts[0].Category != kernellogentities.CategoryIO || counts[0].Count != 3 {
+		t.Errorf("counts = %+v, %v", counts, err)
+	}
+	if totals := restarted.MessageTotals(); totals[kernellogentities.CategoryIO] != 1 {
+		t.Errorf("MessageTotals = %v", totals)
+	}
+}
+
+func TestKernelLogService_LongMessageFitsTheEventColumn(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOST_ROOT", root)
+	kernLog := filepath.Join(root, "var", "log", "kern.log")
+	writeHostFile(t, kernLog, "")
+
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	svc := kernellogservice.NewService(log.Default(), kernellogrepos.NewKernelLogRepository(db), events, "/var/log/kern.log", time.Hour)
+	ctx := context.Background()
+	if _, err := svc.Collect(ctx); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	// The sample keeps 1000 bytes of the message; the event adds its count and category
+	appendHostFile(t, kernLog, []byte("Mar  1 00:01:00 host kernel: [60.0] blk_update_request: I/O error, dev "+strings.Repeat("é", 1000)+"\n"))
+	metric, err := svc.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(metric.Events) != 1 {
+		t.Fatalf("events = %+v", metric.Events)
+	}
+	if msg := metric.Events[0].Message; len(msg) > evententities.MaxMessageLen || !utf8.ValidString(msg) {
+		t.Errorf("event message = %d bytes, valid UTF-8 %v", len(msg), utf8.ValidString(msg))
+	}
+	if err := svc.Save(ctx, metric, 2); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+}
+
+func TestKernelLogService_ReceivePush(t *testing.T) {
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	svc := kernellogservice.NewService(log.Default(), kernellogrepos.NewKernelLogRepository(db), events, "", time.Hour)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	metric := kernellogentities.KernelLogMetric{
+		Timestamp: now,
+		Samples: []kernellogentities.KernelLogSample{
+			{Category: kernellogentities.CategoryOOM, Severity: evententities.SeverityCritical, Count: 1, FirstAt: now.Add(-time.Second), LastAt: now.Add(-time.Second), Message: "Out of memory: Killed process 1 (x)"},
+		},
+		Events: []evententities.HostEvent{
+			{Timestamp: now.Add(-time.Second), Source: kernellogservice.EventSource, Subject: kernellogentities.CategoryOOM, Kind: kernellogservice.EventLogged, Severity: evententities.SeverityCritical},
+			// Events of other sources are not taken from a kernel log push
+			{Timestamp: now, Source: "watchlist", Subject: "nginx", Kind: "down", Severity: evententities.SeverityCritical},
+		},
+	}
+	data, _ := json.Marshal(metric)
+	for range 2 {
+		if err := svc.ReceivePush(ctx, 9, data); err != nil {
+			t.Fatalf("ReceivePush: %v", err)
+		}
+	}
+	samples, err := svc.GetSamplesByHost(ctx, 9, 1, "")
+	if err != nil || len(samples) != 1 || samples[0].Category != kernellogentities.CategoryOOM {
+		t.Errorf("samples = %+v, %v", samples, err)
+	}
+	problems, _ := events.Problems(ctx, 9)
+	if len(problems) != 1 || problems[0].Source != kernellogservice.EventSource {
+		t.Errorf("problems = %+v, want the oom event only", problems)
+	}
+}
@@ -0,0 +1,228 @@
+package services_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/log"
+
+	loginservice "system-stats/internal/modules/logins/application"
+	logincollectors "system-stats/internal/modules/logins/infrastructure/collectors"
+	loginentities "system-stats/internal/modules/logins/infrastructure/entities"
+	loginrepos "system-stats/internal/modules/logins/infrastructure/repositories"
+)
+
+// utmpRecord encodes a glibc struct utmp in the host's byte order.
+func utmpRecord(typ int16, pid int32, line, user, host string, at time.Time, addr []byte) []byte {
+	b := make([]byte, 384)
+	binary.NativeEndian.PutUint16(b[0:2], uint16(typ))
+	binary.NativeEndian.PutUint32(b[4:8], uint32(pid))
+	copy(b[8:40], line)
+	copy(b[44:76], user)
+	copy(b[76:332], host)
+	binary.NativeEndian.PutUint32(b[340:344], uint32(at.Unix()))
+	binary.NativeEndian.PutUint32(b[344:348], uint32(at.Nanosecond()/1000))
+	copy(b[348:364], addr)
+	return b
+}
+
+func appendHostFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseUtmp(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 250000000, time.UTC)
+	data := append(utmpRecord(logincollectors.UtmpUserProcess, 4242, "pts/0", "alice", "", at, []byte{192, 168, 1, 20}),
+		utmpRecord(logincollectors.UtmpUserProcess, 4243, "pts/1", "bob", "", at, []byte{0x20, 0x01, 0x0d, 0xb8, 15: 1})...)
+	// A trailing partial record is ignored
+	data = append(data, make([]byte, 100)...)
+
+	records := logincollectors.ParseUtmp(data)
+	if len(records) != 2 {
+		t.Fatalf("ParseUtmp = %+v, want 2 records", records)
+	}
+	r := records[0]
+	if r.Type != logincollectors.UtmpUserProcess || r.PID != 4242 || r.Line != "pts/0" || r.User != "alice" || !r.Time.Equal(at) {
+		t.Errorf("record = %+v", r)
+	}
+	if r.Source() != "192.168.1.20" {
+		t.Errorf("Source() = %q, want the IPv4 address", r.Source())
+	}
+	if records[1].Source() != "2001:db8::1" {
+		t.Errorf("Source() = %q, want the IPv6 address", records[1].Source())
+	}
+}
+
+func TestParseAuthLine(t *testing.T) {
+	cases := []struct {
+		line                  string
+		service, user, source string
+		ok                    bool
+	}{
+		{"Mar  1 12:00:00 host sshd[1234]: Failed password for root from 203.0.113.7 port 52144 ssh2", loginentities.ServiceSSH, "root", "203.0.113.7", true},
+		{"2026-03-01T12:00:00.000000+00:00 host sshd-session[99]: Failed password for invalid user admin from 2001:db8::7 port 22 ssh2", loginentities.ServiceSSH, "admin", "2001:db8::7", true},
+		{"Mar  1 12:00:00 host sshd[1234]: Failed publickey for git from 198.51.100.2 port 40000 ssh2: RSA SHA256:abc", loginentities.ServiceSSH, "git", "198.51.100.2", true},
+		{"Mar  1 12:00:00 host sudo: pam_unix(sudo:auth): authentication failure; logname=alice uid=1000 euid=0 tty=/dev/pts/0 ruser=alice rhost=  user=alice", loginentities.ServiceSudo, "alice", "", true},
+		{"Mar  1 12:00:00 host sshd[1234]: Accepted publickey for alice from 192.168.1.20 port 50000 ssh2", "", "", "", false},
+		{"Mar  1 12:00:00 host sshd[1234]: Failed none for invalid user  from 203.0.113.7 port 1 ssh2", loginentities.ServiceSSH, "", "203.0.113.7", true},
+	}
+	for _, tc := range cases {
+		service, user, source, ok := logincollectors.ParseAuthLine([]byte(tc.line))
+		if ok != tc.ok || service != tc.service || user != tc.user || source != tc.source {
+			t.Errorf("ParseAuthLine(%q) = %q, %q, %q, %v", tc.line, service, user, source, ok)
+		}
+	}
+}
+
+func TestLogins_LongValuesFitTheColumns(t *testing.T) {
+	long := strings.Repeat("é", 100)
+	line := "Mar  1 12:00:00 host sshd[1234]: Failed password for invalid user " + long + " from 203.0.113.7 port 1 ssh2"
+	_, user, _, ok := logincollectors.ParseAuthLine([]byte(line))
+	if !ok || len(user) > loginentities.MaxUserLen || !utf8.ValidString(user) {
+		t.Errorf("user = %q (%d bytes), want at most %d valid bytes", user, len(user), loginentities.MaxUserLen)
+	}
+
+	// Pushed values are cut when stored as well
+	pushed := loginentities.LoginMetric{
+		Events:   []loginentities.LoginEvent{{Kind: loginentities.KindLogin, User: long, Line: "pts/0", Source: strings.Repeat("h", 300)}},
+		Failures: []loginentities.AuthFailure{{Service: loginentities.ServiceSSH, User: long, Source: long, Count: 1}},
+	}
+	events, failures := pushed.ToRows(1)
+	if len(events[0].User) > loginentities.MaxUserLen || len(events[0].Source) > loginentities.MaxSourceLen {
+		t.Errorf("event row = %+v", events[0])
+	}
+	if len(failures[0].User) > loginentities.MaxUserLen || len(failures[0].Source) > loginentities.MaxFailureSourceLen {
+		t.Errorf("failure row = %+v", failures[0])
+	}
+}
+
+func TestLoginCollector_SessionsEventsAndFailures(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOST_ROOT", root)
+	now := time.Now().UTC().Truncate(time.Second)
+	ipv4 := []byte{10, 0, 0, 5}
+
+	writeHostFile(t, filepath.Join(root, "run", "utmp"), string(append(
+		utmpRecord(logincollectors.UtmpBootTime, 0, "~", "reboot", "6.8.0", now.Add(-time.Hour), nil),
+		utmpRecord(logincollectors.UtmpUserProcess, 100, "pts/0", "alice", "laptop.lan", now.Add(-10*time.Minute), ipv4)...)))
+	wtmp := filepath.Join(root, "var", "log", "wtmp")
+	writeHostFile(t, wtmp, string(append(append(
+		// Older than the backfill window
+		utmpRecord(logincollectors.UtmpUserProcess, 1, "pts/3", "old", "", now.Add(-48*time.Hour), nil),
+		utmpRecord(logincollectors.UtmpBootTime, 0, "~", "reboot", "6.8.0", now.Add(-time.Hour), nil)...),
+		utmpRecord(logincollectors.UtmpUserProcess, 100, "pts/0", "alice", "laptop.lan", now.Add(-10*time.Minute), ipv4)...)))
+	authLog := filepath.Join(root, "var", "log", "auth.log")
+	writeHostFile(t, authLog, "Mar  1 00:00:00 host sshd[1]: Failed password for root from 203.0.113.7 port 1 ssh2\n")
+
+	c := logincollectors.NewLoginCollector(log.Default(), []string{"/var/log/auth.log", "/var/log/secure"})
+	if !logincollectors.Available([]string{"/var/log/secure"}) {
+		t.Fatal("Available = false with utmp and wtmp present")
+	}
+	first, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(first.Sessions) != 1 || first.Sessions[0].User != "alice" || first.Sessions[0].Source != "laptop.lan" || first.Sessions[0].PID != 100 {
+		t.Errorf("sessions = %+v", first.Sessions)
+	}
+	if len(first.Events) != 2 || first.Events[0].Kind != loginentities.KindReboot || first.Events[1].Kind != loginentities.KindLogin || first.Events[1].User != "alice" {
+		t.Errorf("first events = %+v, want the reboot and alice's login", first.Events)
+	}
+	// The auth log is read from its end at the first collection
+	if len(first.Failures) != 0 {
+		t.Errorf("first failures = %+v, want none", first.Failures)
+	}
+
+	// alice logs out (no user in the record), two failed SSH logins and a failed sudo follow
+	appendHostFile(t, wtmp, utmpRecord(logincollectors.UtmpDeadProcess, 100, "pts/0", "", "", now, nil))
+	appendHostFile(t, authLog, []byte("Mar  1 00:00:01 host sshd[2]: Failed password for invalid user admin from 203.0.113.7 port 2 ssh2\n"+
+		"Mar  1 00:00:02 host sshd[3]: Failed password for invalid user admin from 203.0.113.7 port 3 ssh2\n"+
+		"Mar  1 00:00:03 host sudo: pam_unix(sudo:auth): authentication failure; logname=bob uid=1001 euid=0 tty=/dev/pts/1 ruser=bob rhost=  user=bob\n"+
+		"Mar  1 00:00:04 host sshd[4]: Failed password for root from 203.0.113.9"))
+	second, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(second.Events) != 1 || second.Events[0].Kind != loginentities.KindLogout || second.Events[0].User != "alice" || second.Events[0].Line != "pts/0" {
+		t.Errorf("second events = %+v, want alice's logout", second.Events)
+	}
+	// The unterminated last line is left for the next collection
+	if len(second.Failures) != 2 ||
+		second.Failures[0].Service != loginentities.ServiceSSH || second.Failures[0].User != "admin" || second.Failures[0].Count != 2 ||
+		second.Failures[1].Service != loginentities.ServiceSudo || second.Failures[1].User != "bob" || second.Failures[1].Count != 1 {
+		t.Errorf("second failures = %+v", second.Failures)
+	}
+}
+
+func TestLoginService_PushAndRepository(t *testing.T) {
+	db := openModulesDB(t)
+	svc := loginservice.NewService(log.Default(), loginrepos.NewLoginRepository(db), nil)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	metric := loginentities.LoginMetric{
+		Timestamp: now,
+		Sessions: []loginentities.Session{
+			{User: "alice", Line: "pts/0", Source: "10.0.0.5", LoginAt: now.Add(-time.Hour), PID: 100},
+			{User: "bob", Line: "tty1", LoginAt: now.Add(-2 * time.Hour), PID: 200},
+		},
+		Events: []loginentities.LoginEvent{
+			{Timestamp: now.Add(-time.Hour), Kind: loginentities.KindLogin, User: "alice", Line: "pts/0", Source: "10.0.0.5"},
+			{Timestamp: now.Add(-2 * time.Hour), Kind: loginentities.KindLogin, User: "bob", Line: "tty1"},
+		},
+		Failures: []loginentities.AuthFailure{
+			{Timestamp: now, Service: loginentities.ServiceSSH, User: "root", Source: "203.0.113.7", Count: 5},
+			{Timestamp: now, Service: loginentities.ServiceSudo, User: "bob", Count: 1},
+		},
+	}
+	data, _ := json.Marshal(metric)
+	// Pushed twice: events and failures stored once
+	for range 2 {
+		if err := svc.ReceivePush(ctx, 4, data); err != nil {
+			t.Fatalf("ReceivePush: %v", err)
+		}
+	}
+	events, err := svc.GetEventsByHost(ctx, 4, 3, "")
+	if err != nil || len(events) != 2 {
+		t.Errorf("events = %+v, %v", events, err)
+	}
+	events, err = svc.GetEventsByHost(ctx, 4, 3, "alice")
+	if err != nil || len(events) != 1 || events[0].Source != "10.0.0.5" {
+		t.Errorf("alice's events = %+v, %v", events, err)
+	}
+	failures, err := svc.GetFailuresByHost(ctx, 4, 1, loginentities.ServiceSSH)
+	if err != nil || len(failures) != 1 || failures[0].Count != 5 {
+		t.Errorf("sshd failures = %+v, %v", failures, err)
+	}
+
+	// The next collection replaces the session inventory; an older one does not
+	next := loginentities.LoginMetric{Timestamp: now.Add(time.Minute), Sessions: metric.Sessions[1:]}
+	if err := svc.Save(ctx, next, 4); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := svc.Save(ctx, metric, 4); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	sessions, err := svc.GetSessionsByHost(ctx, 4)
+	if err != nil || len(sessions) != 1 || sessions[0].User != "bob" {
+		t.Errorf("sessions = %+v, %v", sessions, err)
+	}
+	if other, _ := svc.GetSessionsByHost(ctx, 5); len(other) != 0 {
+		t.Errorf("host 5 sessions = %+v, want none", other)
+	}
+}
@@ -0,0 +1,124 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	memservice "system-stats/internal/modules/memory/application"
+	"system-stats/internal/modules/memory/infrastructure/collectors"
+	mementities "system-stats/internal/modules/memory/infrastructure/entities"
+	memrepos "system-stats/internal/modules/memory/infrastructure/repositories"
+	"system-stats/internal/modules/memory/infrastructure/value_objects"
+)
+
+const testMeminfo = `MemTotal:        8000000 kB
+MemFree:         1000000 kB
+Dirty:               120 kB
+Writeback:             8 kB
+AnonHugePages:      2048 kB
+SReclaimable:     300000 kB
+SUnreclaim:        50000 kB
+CommitLimit:     4000000 kB
+Committed_AS:    6000000 kB
+HugePages_Total:      16
+HugePages_Free:       12
+HugePages_Rsvd:        2
+HugePages_Surp:        0
+Hugepagesize:       2048 kB
+`
+
+func TestMemoryExtended_ReadHostProc(t *testing.T) {
+	proc := t.TempDir()
+	t.Setenv("HOST_PROC", proc)
+	writeHostFile(t, filepath.Join(proc, "meminfo"), testMeminfo)
+	writeHostFile(t, filepath.Join(proc, "vmstat"), "nr_free_pages 250000\npswpin 10\npswpout 20\npgfault 1000\npgmajfault 5\noom_kill 1\n")
+
+	m, err := collectors.ReadMemoryExtended()
+	if err != nil || m == nil {
+		t.Fatalf("ReadMemoryExtended = %v, %v", m, err)
+	}
+	if m.SlabReclaimable != 300000*1024 || m.SlabUnreclaimable != 50000*1024 || m.Dirty != 120*1024 {
+		t.Errorf("slab/dirty = %+v", m)
+	}
+	if m.CommittedAS <= m.CommitLimit || m.HugePagesTotal != 16 || m.HugePagesReserved != 2 || m.HugePageSize != 2048*1024 {
+		t.Errorf("commit/hugepages = %+v", m)
+	}
+	want := mementities.VmstatCounters{SwapIn: 10, SwapOut: 20, PageFaults: 1000, MajorFaults: 5, OOMKill: 1}
+	if m.Counters != want {
+		t.Errorf("counters = %+v, want %+v", m.Counters, want)
+	}
+	if m.OOMKills != nil || m.SwapInPerSec != nil {
+		t.Error("rates must be nil until derived from a previous collection")
+	}
+
+	// PID 1's view of meminfo is preferred, as for the basic figures
+	writeHostFile(t, filepath.Join(proc, "1", "root", "proc", "meminfo"), "Dirty:               240 kB\n")
+	if m, err := collectors.ReadMemoryExtended(); err != nil || m == nil || m.Dirty != 240*1024 {
+		t.Errorf("with PID 1 meminfo = %+v, %v; want its dirty bytes", m, err)
+	}
+
+	// Without /proc (non-Linux) there is nothing to report and no error
+	t.Setenv("HOST_PROC", t.TempDir())
+	if m, err := collectors.ReadMemoryExtended(); m != nil || err != nil {
+		t.Errorf("missing meminfo = %v, %v; want nil, nil", m, err)
+	}
+}
+
+func TestVmstatCalculator(t *testing.T) {
+	calc := value_objects.NewVmstatCalculator()
+	start := time.Now()
+	if r := calc.Calculate(start, mementities.VmstatCounters{SwapIn: 100, PageFaults: 1000, OOMKill: 2}); r.Valid {
+		t.Fatal("first sample must not yield a rate")
+	}
+	r := calc.Calculate(start.Add(10*time.Second), mementities.VmstatCounters{SwapIn: 150, PageFaults: 3000, OOMKill: 5})
+	if !r.Valid || r.SwapInPerSec != 5 || r.PageFaultsPerSec != 200 || r.OOMKills != 3 {
+		t.Errorf("rate = %+v", r)
+	}
+	// A reboot resets the counters; the sample re-baselines instead of underflowing
+	if r := calc.Calculate(start.Add(20*time.Second), mementities.VmstatCounters{SwapIn: 1}); r.Valid {
+		t.Errorf("reset counters yielded %+v", r)
+	}
+	if r := calc.Calculate(start.Add(time.Hour), mementities.VmstatCounters{SwapIn: 2}); r.Valid {
+		t.Errorf("long gap yielded %+v", r)
+	}
+}
+
+func TestMemoryExtended_SaveAndPush(t *testing.T) {
+	db := openModulesDB(t)
+	repo := memrepos.NewMemoryRepository(db)
+	svc := memservice.NewService(log.Default(), repo)
+	ctx := context.Background()
+
+	kills := uint64(1)
+	ext := mementities.MemoryExtended{Timestamp: time.Now().UTC().Add(-time.Minute), Dirty: 4096, OOMKills: &kills}
+	if err := svc.Save(ctx, mementities.MemoryMetric{Total: 100, Used: 50, Extended: &ext}, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	pushed := ext
+	pushed.Timestamp = ext.Timestamp.Add(30 * time.Second)
+	data, _ := json.Marshal(pushed)
+	// The agent pushes the same collection until the next one; only one row is kept
+	for range 2 {
+		if err := svc.ReceivePush(ctx, 2, data); err != nil {
+			t.Fatalf("ReceivePush: %v", err)
+		}
+	}
+
+	local, err := svc.GetExtendedHistoryByHost(ctx, 1, 1)
+	if err != nil || len(local) != 1 || local[0].DirtyBytes != 4096 || local[0].OOMKills == nil || *local[0].OOMKills != 1 {
+		t.Fatalf("host 1 history = %+v, %v", local, err)
+	}
+	if local[0].SwapInPerSec != nil {
+		t.Error("underived rate should be stored as null")
+	}
+	remote, err := svc.GetExtendedHistoryByHost(ctx, 2, 1)
+	if err != nil || len(remote) != 1 {
+		t.Fatalf("host 2 history = %+v, %v", remote, err)
+	}
+}
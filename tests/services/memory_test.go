@@ -46,6 +46,14 @@ func (m *mockMemoryRepository) GetHistoricalMetricsByHost(_ context.Context, _ u
 	return m.historicalMetrics, m.historicalErr
 }
 
+func (m *mockMemoryRepository) SaveExtendedMetric(_ context.Context, _ mementities.MemoryExtended, _ uint) error {
+	return m.saveErr
+}
+
+func (m *mockMemoryRepository) GetExtendedHistoryByHost(_ context.Context, _ uint, _ float64) ([]mementities.HistoricalMemoryExtendedMetric, error) {
+	return nil, m.historicalErr
+}
+
 var _ memrepos.MemoryRepository = (*mockMemoryRepository)(nil)
 
 func newMemoryService(repo memrepos.MemoryRepository) memservice.Service {
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"system-stats/internal/modules/network/infrastructure/collectors"
+	"system-stats/internal/modules/network/infrastructure/value_objects"
+)
+
+func TestReadInterfaceLink_HostSys(t *testing.T) {
+	sys := t.TempDir()
+	t.Setenv("HOST_SYS", sys)
+	eth0 := filepath.Join(sys, "class", "net", "eth0")
+	writeHostFile(t, filepath.Join(eth0, "speed"), "1000\n")
+	writeHostFile(t, filepath.Join(eth0, "duplex"), "full\n")
+	writeHostFile(t, filepath.Join(eth0, "mtu"), "9000\n")
+	writeHostFile(t, filepath.Join(eth0, "operstate"), "up\n")
+	writeHostFile(t, filepath.Join(eth0, "carrier"), "1\n")
+	writeHostFile(t, filepath.Join(eth0, "carrier_changes"), "4\n")
+	// Virtual interfaces report an unknown speed as -1
+	veth := filepath.Join(sys, "class", "net", "veth0")
+	writeHostFile(t, filepath.Join(veth, "speed"), "-1\n")
+	writeHostFile(t, filepath.Join(veth, "operstate"), "down\n")
+
+	link := collectors.ReadInterfaceLink("eth0")
+	if link.LinkSpeedMbps == nil || *link.LinkSpeedMbps != 1000 || link.Duplex != "full" || link.MTU != 9000 || link.OperState != "up" {
+		t.Errorf("eth0 link = %+v", link)
+	}
+	if link.Carrier == nil || !*link.Carrier || link.CarrierChanges == nil || *link.CarrierChanges != 4 {
+		t.Errorf("eth0 carrier = %v / %v", link.Carrier, link.CarrierChanges)
+	}
+
+	link = collectors.ReadInterfaceLink("veth0")
+	if link.LinkSpeedMbps != nil || link.Carrier != nil || link.CarrierChanges != nil || link.OperState != "down" {
+		t.Errorf("veth0 link = %+v", link)
+	}
+	if link = collectors.ReadInterfaceLink("missing0"); link.OperState != "" || link.MTU != 0 {
+		t.Errorf("missing interface link = %+v", link)
+	}
+}
+
+func TestInterfaceRateCalculator_CarrierChangesAndUtil(t *testing.T) {
+	calc := value_objects.NewInterfaceRateCalculator()
+	start := time.Now()
+	calc.Calculate("eth0", start, value_objects.InterfaceCounters{BytesRecv: 0, CarrierChanges: 2})
+	r := calc.Calculate("eth0", start.Add(10*time.Second), value_objects.InterfaceCounters{BytesRecv: 1_187_500_000, CarrierChanges: 6})
+	if !r.Valid || r.CarrierChanges != 4 {
+		t.Fatalf("rate = %+v, want 4 carrier changes", r)
+	}
+	// 118.75 MB/s on a 1 Gbit/s link
+	if util := value_objects.LinkUtilPercent(r.RxBytesPerSec, 1000); util != 95 {
+		t.Errorf("LinkUtilPercent = %v, want 95", util)
+	}
+}
@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"system-stats/internal/modules/network/infrastructure/value_objects"
+)
+
+func TestCounterDelta(t *testing.T) {
+	cases := []struct {
+		name      string
+		prev, cur uint64
+		want      uint64
+		wantOK    bool
+	}{
+		{"increase", 100, 150, 50, true},
+		{"unchanged", 7, 7, 0, true},
+		// Interface counters are 64-bit: a decrease in the upper half of the 32-bit range is a reset
+		{"reset near the 32-bit limit", math.MaxUint32 - 9, 5, 0, false},
+		{"reboot from small counter", 5000, 10, 0, false},
+		{"reset of 64-bit counter", 1 << 40, 10, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := value_objects.CounterDelta(tc.prev, tc.cur)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("CounterDelta(%d, %d) = (%d, %v), want (%d, %v)", tc.prev, tc.cur, got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestCounter32Delta(t *testing.T) {
+	cases := []struct {
+		name           string
+		prev, cur, max uint64
+		want           uint64
+		wantOK         bool
+	}{
+		{"increase", 100, 150, 10, 50, true},
+		{"wrap within the interval", math.MaxUint32 - 9, 5, 1000, 15, true},
+		{"reset in the upper half", 3 << 30, 10, 1000, 0, false},
+		{"reset of a larger counter", 1 << 40, 10, math.MaxUint64, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := value_objects.Counter32Delta(tc.prev, tc.cur, tc.max)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("Counter32Delta(%d, %d, %d) = (%d, %v), want (%d, %v)", tc.prev, tc.cur, tc.max, got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestInterfaceRateCalculator_Rates(t *testing.T) {
+	calc := value_objects.NewInterfaceRateCalculator()
+	start := time.Now()
+	if r := calc.Calculate("eth0", start, value_objects.InterfaceCounters{BytesRecv: 1000}); r.Valid {
+		t.Fatalf("first sample should not be valid: %+v", r)
+	}
+
+	r := calc.Calculate("eth0", start.Add(5*time.Second), value_objects.InterfaceCounters{
+		BytesRecv: 6000, BytesSent: 500, PacketsRecv: 50, PacketsSent: 10, Errin: 2, Dropout: 3,
+	})
+	if !r.Valid {
+		t.Fatal("expected valid rate")
+	}
+	if r.RxBytesPerSec != 1000 || r.TxBytesPerSec != 100 {
+		t.Errorf("bytes/s = %v/%v, want 1000/100", r.RxBytesPerSec, r.TxBytesPerSec)
+	}
+	if r.RxPacketsPerSec != 10 || r.TxPacketsPerSec != 2 {
+		t.Errorf("packets/s = %v/%v, want 10/2", r.RxPacketsPerSec, r.TxPacketsPerSec)
+	}
+	if r.RxErrors != 2 || r.TxDrops != 3 {
+		t.Errorf("errors/drops = %d/%d, want 2/3", r.RxErrors, r.TxDrops)
+	}
+}
+
+func TestInterfaceRateCalculator_RebootRebaselines(t *testing.T) {
+	calc := value_objects.NewInterfaceRateCalculator()
+	start := time.Now()
+	calc.Calculate("eth0", start, value_objects.InterfaceCounters{BytesRecv: 1 << 35})
+
+	if r := calc.Calculate("eth0", start.Add(time.Second), value_objects.InterfaceCounters{BytesRecv: 100}); r.Valid {
+		t.Fatalf("reset should not produce a rate: %+v", r)
+	}
+	r := calc.Calculate("eth0", start.Add(2*time.Second), value_objects.InterfaceCounters{BytesRecv: 300})
+	if !r.Valid || r.RxBytesPerSec != 200 {
+		t.Fatalf("expected 200 B/s from new baseline, got %+v", r)
+	}
+}
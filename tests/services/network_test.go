@@ -46,6 +46,10 @@ func (m *mockNetworkRepository) GetHistoricalMetricsByHost(_ context.Context, _
 	return m.historicalMetrics, m.historicalErr
 }
 
+func (m *mockNetworkRepository) GetInterfaceHistoryByHost(_ context.Context, _ uint, _ float64, _ string, _ bool) ([]netentities.HistoricalNetworkInterfaceMetric, error) {
+	return nil, nil
+}
+
 var _ netrepos.NetworkRepository = (*mockNetworkRepository)(nil)
 
 func newNetworkService(repo netrepos.NetworkRepository) netservice.Service {
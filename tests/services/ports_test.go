@@ -0,0 +1,170 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	eventrepos "system-stats/internal/modules/events/infrastructure/repositories"
+	portmetrics "system-stats/internal/modules/ports/application"
+	"system-stats/internal/modules/ports/infrastructure/entities"
+	"system-stats/internal/modules/ports/infrastructure/repositories"
+)
+
+const testPortsTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 100 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0016 0100007F:D431 01 00000000:00000000 00:00000000 00000000     0        0 101 1 0000000000000000 20 4 30 10 -1
+`
+
+const testPortsUDP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+   0: 00000000:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 200 2 0000000000000000 0
+   1: 00000000:9C40 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 201 2 0000000000000000 0
+   2: 0100007F:0050 0100007F:0035 01 00000000:00000000 00:00000000 00000000     0        0 202 2 0000000000000000 0
+`
+
+func noContainerPorts(context.Context, uint) ([]portmetrics.ContainerPort, error) { return nil, nil }
+
+func TestPortCollector_HostProc(t *testing.T) {
+	proc := t.TempDir()
+	t.Setenv("HOST_PROC", proc)
+	writeHostFile(t, filepath.Join(proc, "net", "tcp"), testPortsTCP)
+	writeHostFile(t, filepath.Join(proc, "net", "udp"), testPortsUDP)
+	writeHostFile(t, filepath.Join(proc, "321", "comm"), "sshd\n")
+	if err := os.MkdirAll(filepath.Join(proc, "321", "fd"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("socket:[100]", filepath.Join(proc, "321", "fd", "3")); err != nil {
+		t.Fatal(err)
+	}
+
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	svc := portmetrics.NewService(log.Default(), repositories.NewPortRepository(db), events, noContainerPorts)
+	metric, err := svc.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	// The established TCP socket, the ephemeral UDP port and the connected UDP socket are left out
+	if len(metric.Ports) != 2 {
+		t.Fatalf("ports = %+v, want tcp/22 and udp/53", metric.Ports)
+	}
+	ssh, dns := metric.Ports[0], metric.Ports[1]
+	if ssh.Key() != "tcp/0.0.0.0:22" || ssh.Process != "sshd" || ssh.PID == nil || *ssh.PID != 321 {
+		t.Errorf("ssh = %+v", ssh)
+	}
+	if dns.Key() != "udp/0.0.0.0:53" || dns.PID != nil {
+		t.Errorf("dns = %+v", dns)
+	}
+}
+
+func TestPortService_InventoryEvents(t *testing.T) {
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	svc := portmetrics.NewService(log.Default(), repositories.NewPortRepository(db), events, noContainerPorts)
+	ctx := context.Background()
+	t0 := time.Now().UTC().Add(-time.Minute).Truncate(time.Microsecond)
+	pid := int32(10)
+	ssh := entities.ListeningPort{Protocol: entities.ProtocolTCP, Address: "0.0.0.0", Port: 22, PID: &pid, Process: "sshd"}
+	web := entities.ListeningPort{Protocol: entities.ProtocolTCP, Address: "::", Port: 8080}
+
+	// The first inventory is recorded without events
+	if err := svc.Save(ctx, entities.PortMetric{Timestamp: t0, Ports: []entities.ListeningPort{ssh}}, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if list, _ := events.List(ctx, 1, 1, portmetrics.EventSource, ""); len(list) != 0 {
+		t.Fatalf("initial inventory produced events: %+v", list)
+	}
+
+	if err := svc.Save(ctx, entities.PortMetric{Timestamp: t0.Add(time.Second), Ports: []entities.ListeningPort{web}}, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// An older (re-sent) collection must not roll the inventory back
+	if err := svc.Save(ctx, entities.PortMetric{Timestamp: t0, Ports: []entities.ListeningPort{ssh}}, 1); err != nil {
+		t.Fatalf("Save stale: %v", err)
+	}
+	list, err := events.List(ctx, 1, 1, portmetrics.EventSource, "")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("events = %+v, %v; want one added and one removed", list, err)
+	}
+	kinds := map[string]string{}
+	for _, e := range list {
+		kinds[e.Subject] = e.Kind
+	}
+	if kinds["tcp/[::]:8080"] != portmetrics.EventAdded || kinds["tcp/0.0.0.0:22"] != portmetrics.EventRemoved {
+		t.Errorf("event kinds = %+v", kinds)
+	}
+
+	inv, err := svc.GetInventory(ctx, 1)
+	if err != nil || len(inv.Ports) != 1 || inv.Ports[0].Port != 8080 || !inv.Ports[0].FirstSeen.Equal(t0.Add(time.Second)) {
+		t.Fatalf("GetInventory = %+v, %v", inv, err)
+	}
+}
+
+func TestPortService_EmptiedInventoryIsNotInitial(t *testing.T) {
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	svc := portmetrics.NewService(log.Default(), repositories.NewPortRepository(db), events, noContainerPorts)
+	ctx := context.Background()
+	t0 := time.Now().UTC().Add(-time.Minute).Truncate(time.Microsecond)
+	ssh := entities.ListeningPort{Protocol: entities.ProtocolTCP, Address: "0.0.0.0", Port: 22}
+
+	if err := svc.Save(ctx, entities.PortMetric{Timestamp: t0, Ports: []entities.ListeningPort{ssh}}, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := svc.Save(ctx, entities.PortMetric{Timestamp: t0.Add(time.Second)}, 1); err != nil {
+		t.Fatalf("Save empty: %v", err)
+	}
+	// SO_REUSEPORT workers pushed by an agent repeat the key
+	if err := svc.Save(ctx, entities.PortMetric{Timestamp: t0.Add(2 * time.Second), Ports: []entities.ListeningPort{ssh, ssh}}, 1); err != nil {
+		t.Fatalf("Save with repeated port: %v", err)
+	}
+
+	list, err := events.List(ctx, 1, 1, portmetrics.EventSource, "")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("events = %+v, %v; want removed and added again", list, err)
+	}
+	inv, err := svc.GetInventory(ctx, 1)
+	if err != nil || len(inv.Ports) != 1 {
+		t.Fatalf("GetInventory = %+v, %v; want tcp/22 once", inv, err)
+	}
+}
+
+func TestPortService_ContainerCrossReference(t *testing.T) {
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	containers := func(context.Context, uint) ([]portmetrics.ContainerPort, error) {
+		return []portmetrics.ContainerPort{
+			{ContainerID: "abc", ContainerName: "web", Protocol: "tcp", IP: "0.0.0.0", PublicPort: 8080, PrivatePort: 80},
+			{ContainerID: "abc", ContainerName: "web", Protocol: "tcp", IP: "::", PublicPort: 8080, PrivatePort: 80},
+			{ContainerID: "def", ContainerName: "db", Protocol: "tcp", IP: "127.0.0.1", PublicPort: 5432, PrivatePort: 5432},
+		}, nil
+	}
+	svc := portmetrics.NewService(log.Default(), repositories.NewPortRepository(db), events, containers)
+	ctx := context.Background()
+	ports := []entities.ListeningPort{
+		{Protocol: entities.ProtocolTCP, Address: "0.0.0.0", Port: 8080},
+		{Protocol: entities.ProtocolUDP, Address: "0.0.0.0", Port: 8080},
+	}
+	if err := svc.Save(ctx, entities.PortMetric{Timestamp: time.Now().UTC(), Ports: ports}, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	inv, err := svc.GetInventory(ctx, 1)
+	if err != nil || len(inv.Ports) != 2 {
+		t.Fatalf("GetInventory = %+v, %v", inv, err)
+	}
+	if c := inv.Ports[0].Containers; inv.Ports[0].Protocol != "tcp" || len(c) != 1 || c[0].ContainerName != "web" {
+		t.Errorf("tcp/8080 containers = %+v", inv.Ports[0])
+	}
+	if len(inv.Ports[1].Containers) != 0 {
+		t.Errorf("udp/8080 matched a tcp container port: %+v", inv.Ports[1])
+	}
+	if len(inv.UnmatchedContainerPorts) != 1 || inv.UnmatchedContainerPorts[0].ContainerName != "db" {
+		t.Errorf("unmatched = %+v", inv.UnmatchedContainerPorts)
+	}
+}
@@ -0,0 +1,148 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	pressuremetrics "system-stats/internal/modules/pressure/application"
+	"system-stats/internal/modules/pressure/infrastructure/collectors"
+	"system-stats/internal/modules/pressure/infrastructure/repositories"
+)
+
+func writeHostFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParsePressure(t *testing.T) {
+	p, err := collectors.ParsePressure([]byte("some avg10=1.50 avg60=0.75 avg300=0.10 total=123456\nfull avg10=0.25 avg60=0.00 avg300=0.00 total=42\n"))
+	if err != nil {
+		t.Fatalf("ParsePressure: %v", err)
+	}
+	if p.Some.Avg10 != 1.5 || p.Some.Avg60 != 0.75 || p.Some.TotalUs != 123456 {
+		t.Errorf("some = %+v", p.Some)
+	}
+	if p.Full == nil || p.Full.Avg10 != 0.25 || p.Full.TotalUs != 42 {
+		t.Errorf("full = %+v", p.Full)
+	}
+
+	// CPU pressure on kernels before 5.13 has no full line
+	p, err = collectors.ParsePressure([]byte("some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n"))
+	if err != nil || p.Full != nil {
+		t.Errorf("some-only file = %+v, %v", p, err)
+	}
+	if _, err := collectors.ParsePressure([]byte("some avg10=x\n")); err == nil {
+		t.Error("expected an error for a malformed value")
+	}
+}
+
+func TestPressureCollector_HostPaths(t *testing.T) {
+	proc, sys := t.TempDir(), t.TempDir()
+	t.Setenv("HOST_PROC", proc)
+	t.Setenv("HOST_SYS", sys)
+	writeHostFile(t, filepath.Join(proc, "pressure", "cpu"), "some avg10=2.00 avg60=1.00 avg300=0.50 total=1000\n")
+	writeHostFile(t, filepath.Join(proc, "pressure", "memory"), "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")
+	cgroup := filepath.Join(sys, "fs", "cgroup")
+	writeHostFile(t, filepath.Join(cgroup, "cgroup.controllers"), "cpu io memory\n")
+	writeHostFile(t, filepath.Join(cgroup, "cpu.stat"), "usage_usec 10000000\nuser_usec 6000000\nsystem_usec 4000000\n")
+	writeHostFile(t, filepath.Join(cgroup, "io.stat"), "8:0 rbytes=100 wbytes=200 rios=1 wios=2\n8:16 rbytes=1 wbytes=2 rios=1 wios=1\n")
+	writeHostFile(t, filepath.Join(cgroup, "system.slice", "cpu.stat"), "usage_usec 4000000\nthrottled_usec 500000\n")
+	writeHostFile(t, filepath.Join(cgroup, "system.slice", "memory.current"), "1048576\n")
+	writeHostFile(t, filepath.Join(cgroup, "system.slice", "memory.max"), "max\n")
+	writeHostFile(t, filepath.Join(cgroup, "system.slice", "cpu.pressure"), "some avg10=3.00 avg60=0.00 avg300=0.00 total=5\n")
+
+	collector := collectors.NewPressureCollector(log.New(os.Stderr))
+	metric, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if metric.CPU == nil || metric.CPU.Some.Avg10 != 2 || metric.CPU.Full != nil {
+		t.Errorf("cpu = %+v", metric.CPU)
+	}
+	if metric.Memory == nil || metric.Memory.Full == nil {
+		t.Errorf("memory = %+v", metric.Memory)
+	}
+	if metric.IO != nil {
+		t.Errorf("io should be nil without its file, got %+v", metric.IO)
+	}
+	if len(metric.Cgroups) != 2 || metric.Cgroups[0].Cgroup != "/" || metric.Cgroups[1].Cgroup != "system.slice" {
+		t.Fatalf("cgroups = %+v", metric.Cgroups)
+	}
+	root, slice := metric.Cgroups[0], metric.Cgroups[1]
+	if root.CPUUsageSeconds != 10 || root.IOReadBytes != 101 || root.IOWriteBytes != 202 || root.MemoryCurrentBytes != nil {
+		t.Errorf("root = %+v", root)
+	}
+	if slice.MemoryCurrentBytes == nil || *slice.MemoryCurrentBytes != 1<<20 || slice.MemoryMaxBytes != nil ||
+		slice.CPUThrottledSeconds != 0.5 || slice.CPUPressure == nil || *slice.CPUPressure != 3 {
+		t.Errorf("system.slice = %+v", slice)
+	}
+
+	// CPU percent comes from the usage delta between collections
+	writeHostFile(t, filepath.Join(cgroup, "system.slice", "cpu.stat"), "usage_usec 4500000\n")
+	time.Sleep(50 * time.Millisecond)
+	metric, err = collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if metric.Cgroups[1].CPUPercent <= 0 {
+		t.Errorf("expected system.slice CPU percent from the delta, got %+v", metric.Cgroups[1])
+	}
+}
+
+func TestPressureCollector_AbsentIsEmpty(t *testing.T) {
+	t.Setenv("HOST_PROC", t.TempDir())
+	t.Setenv("HOST_SYS", t.TempDir())
+	metric, err := collectors.NewPressureCollector(log.New(os.Stderr)).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if metric.Available() || len(metric.Cgroups) != 0 {
+		t.Errorf("expected an empty metric, got %+v", metric)
+	}
+}
+
+func TestPressureService_SaveAndLatest(t *testing.T) {
+	proc, sys := t.TempDir(), t.TempDir()
+	t.Setenv("HOST_PROC", proc)
+	t.Setenv("HOST_SYS", sys)
+	writeHostFile(t, filepath.Join(proc, "pressure", "io"), "some avg10=4.00 avg60=2.00 avg300=1.00 total=77\nfull avg10=1.00 avg60=0.50 avg300=0.25 total=33\n")
+	writeHostFile(t, filepath.Join(sys, "fs", "cgroup", "cgroup.controllers"), "cpu\n")
+	writeHostFile(t, filepath.Join(sys, "fs", "cgroup", "user.slice", "memory.current"), "4096\n")
+
+	db := openModulesDB(t)
+	svc := pressuremetrics.NewService(log.New(os.Stderr), repositories.NewPressureRepository(db))
+	ctx := context.Background()
+	metric, err := svc.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if err := svc.Save(ctx, metric, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	latest, err := svc.GetLatestByHost(ctx, 1)
+	if err != nil || latest == nil {
+		t.Fatalf("GetLatestByHost: %v, %v", latest, err)
+	}
+	if latest.IO == nil || latest.IO.Full == nil || latest.IO.Full.TotalUs != 33 || latest.CPU != nil {
+		t.Errorf("latest PSI = cpu %+v, io %+v", latest.CPU, latest.IO)
+	}
+	if len(latest.Cgroups) != 2 || latest.Cgroups[1].MemoryCurrentBytes == nil || *latest.Cgroups[1].MemoryCurrentBytes != 4096 {
+		t.Errorf("latest cgroups = %+v", latest.Cgroups)
+	}
+
+	history, err := svc.GetCgroupHistoryByHost(ctx, 1, 1, "user.slice")
+	if err != nil || len(history) != 1 {
+		t.Errorf("cgroup history = %+v, %v", history, err)
+	}
+}
@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/log"
+
+	processmetrics "system-stats/internal/modules/processes/application"
+	"system-stats/internal/modules/processes/infrastructure/collectors"
+	"system-stats/internal/modules/processes/infrastructure/entities"
+	"system-stats/internal/modules/processes/infrastructure/repositories"
+)
+
+func sampleProcessMetric() entities.ProcessMetric {
+	fds := int32(12)
+	return entities.ProcessMetric{
+		Timestamp:      time.Now().UTC().Add(-time.Minute),
+		TotalProcesses: 120,
+		TopCPU: []entities.ProcessInfo{
+			{PID: 10, Name: "stress", CPUPercent: 98, RSSBytes: 1 << 20},
+			{PID: 20, Name: "postgres", CPUPercent: 12, RSSBytes: 1 << 30, OpenFDs: &fds},
+		},
+		TopMemory: []entities.ProcessInfo{
+			{PID: 20, Name: "postgres", CPUPercent: 12, RSSBytes: 1 << 30, OpenFDs: &fds},
+			{PID: 30, Name: "java", CPUPercent: 1, RSSBytes: 1 << 29},
+		},
+	}
+}
+
+func TestProcessSamples_MergeAndRebuild(t *testing.T) {
+	metric := sampleProcessMetric()
+	rows := metric.ToSamples(1)
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows (postgres merged), got %d", len(rows))
+	}
+	for _, r := range rows {
+		if r.PID == 20 && (r.CPURank != 2 || r.MemoryRank != 1) {
+			t.Errorf("postgres ranks = cpu %d, memory %d; want 2, 1", r.CPURank, r.MemoryRank)
+		}
+	}
+
+	rebuilt := entities.MetricFromSamples(rows)
+	if rebuilt.TotalProcesses != 120 || len(rebuilt.TopCPU) != 2 || len(rebuilt.TopMemory) != 2 {
+		t.Fatalf("unexpected rebuilt metric: %+v", rebuilt)
+	}
+	if rebuilt.TopCPU[0].Name != "stress" || rebuilt.TopMemory[0].Name != "postgres" || rebuilt.TopMemory[1].Name != "java" {
+		t.Errorf("rebuilt lists out of order: %+v", rebuilt)
+	}
+}
+
+func TestProcessSamples_LongValuesFitTheColumns(t *testing.T) {
+	metric := entities.ProcessMetric{
+		Timestamp: time.Now().UTC(),
+		TopCPU:    []entities.ProcessInfo{{PID: 1, Name: strings.Repeat("n", 300), Cmdline: "x" + strings.Repeat("é", 1000), User: strings.Repeat("u", 100)}},
+	}
+	row := metric.ToSamples(1)[0]
+	if len(row.Name) != entities.MaxNameLen || len(row.User) != entities.MaxUserLen {
+		t.Errorf("name = %d bytes, user = %d bytes", len(row.Name), len(row.User))
+	}
+	// The cut falls inside a two-byte character, which is left out
+	if len(row.Cmdline) != entities.MaxCmdlineLen-1 || !utf8.ValidString(row.Cmdline) {
+		t.Errorf("cmdline = %d bytes, valid UTF-8 %v", len(row.Cmdline), utf8.ValidString(row.Cmdline))
+	}
+}
+
+func TestProcessService_ReceivePushStoresLatest(t *testing.T) {
+	db := openModulesDB(t)
+	svc := processmetrics.NewService(log.New(os.Stderr), collectors.DefaultTopN, repositories.NewProcessRepository(db))
+	ctx := context.Background()
+
+	data, _ := json.Marshal(sampleProcessMetric())
+	if err := svc.ReceivePush(ctx, 1, data); err != nil {
+		t.Fatalf("ReceivePush: %v", err)
+	}
+	// A repeated push of the same collection is ignored
+	if err := svc.ReceivePush(ctx, 1, data); err != nil {
+		t.Fatalf("ReceivePush again: %v", err)
+	}
+
+	latest, err := svc.GetLatestByHost(ctx, 1)
+	if err != nil || latest == nil {
+		t.Fatalf("GetLatestByHost: %v, %v", latest, err)
+	}
+	if len(latest.TopCPU) != 2 || latest.TopCPU[1].OpenFDs == nil || *latest.TopCPU[1].OpenFDs != 12 {
+		t.Errorf("unexpected latest: %+v", latest)
+	}
+
+	history, err := svc.GetHistoryByHost(ctx, 1, 1, 0, "postgres")
+	if err != nil {
+		t.Fatalf("GetHistoryByHost: %v", err)
+	}
+	if len(history) != 1 || history[0].PID != 20 {
+		t.Errorf("expected one postgres row, got %+v", history)
+	}
+	if other, _ := svc.GetLatestByHost(ctx, 2); other != nil {
+		t.Errorf("expected no collection for host 2, got %+v", other)
+	}
+}
+
+func TestProcessCollector_ListsOwnProcess(t *testing.T) {
+	collector := collectors.NewProcessCollector(log.New(os.Stderr), collectors.MaxTopN)
+	metric, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if metric.TotalProcesses == 0 || len(metric.TopMemory) == 0 {
+		t.Fatalf("expected processes, got %+v", metric)
+	}
+	if len(metric.TopCPU) > collectors.MaxTopN {
+		t.Errorf("top CPU list has %d entries", len(metric.TopCPU))
+	}
+	for i := 1; i < len(metric.TopMemory); i++ {
+		if metric.TopMemory[i].RSSBytes > metric.TopMemory[i-1].RSSBytes {
+			t.Fatalf("top memory not sorted at %d", i)
+		}
+	}
+}
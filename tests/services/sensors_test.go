@@ -0,0 +1,192 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	sensorservice "system-stats/internal/modules/sensors/application"
+	sensorcollectors "system-stats/internal/modules/sensors/infrastructure/collectors"
+	sensorentities "system-stats/internal/modules/sensors/infrastructure/entities"
+	sensorrepos "system-stats/internal/modules/sensors/infrastructure/repositories"
+)
+
+func writeHwmonChip(t *testing.T, sys string) {
+	t.Helper()
+	chip := filepath.Join(sys, "class", "hwmon", "hwmon1")
+	for name, body := range map[string]string{
+		"name":          "nct6775\n",
+		"temp1_input":   "45500\n",
+		"temp1_label":   "SYSTIN\n",
+		"temp1_max":     "80000\n",
+		"temp1_crit":    "100000\n",
+		"fan2_input":    "1230\n",
+		"fan2_min":      "300\n",
+		"fan2_alarm":    "0\n",
+		"in0_input":     "1112\n",
+		"in0_min_alarm": "0\n",
+		"in0_max_alarm": "1\n",
+		"curr1_input":   "2500\n",
+		"power1_input":  "15250000\n",
+		"fan3_input":    "0\n",
+		"fan3_fault":    "1\n",
+		"temp2_input":   "30000\n",
+		"temp2_enable":  "0\n",
+	} {
+		writeHostFile(t, filepath.Join(chip, name), body)
+	}
+	if err := os.MkdirAll(filepath.Join(sys, "devices", "platform", "nct6775.656"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../../../devices/platform/nct6775.656", filepath.Join(chip, "device")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadHwmon_TypesThresholdsAndAlarms(t *testing.T) {
+	sys := t.TempDir()
+	t.Setenv("HOST_SYS", sys)
+	writeHwmonChip(t, sys)
+
+	readings, err := sensorcollectors.ReadHwmon()
+	if err != nil {
+		t.Fatalf("ReadHwmon: %v", err)
+	}
+	byType := make(map[string]sensorentities.SensorReading)
+	for _, r := range readings {
+		if r.Chip != "nct6775" || r.Device != "nct6775.656" {
+			t.Errorf("reading %+v: want chip nct6775 on device nct6775.656", r)
+		}
+		byType[r.Type] = r
+	}
+	if len(readings) != 5 {
+		t.Fatalf("got %d readings, want 5 (faulted fan and disabled temp skipped): %+v", len(readings), readings)
+	}
+
+	temp := byType[sensorentities.TypeTemperature]
+	if temp.Label != "SYSTIN" || temp.Value != 45.5 || temp.Max == nil || *temp.Max != 80 || temp.Crit == nil || *temp.Crit != 100 {
+		t.Errorf("temperature = %+v", temp)
+	}
+	if temp.Sensor() != "nct6775-nct6775.656/SYSTIN" {
+		t.Errorf("Sensor() = %q", temp.Sensor())
+	}
+	fan := byType[sensorentities.TypeFan]
+	if fan.Label != "fan2" || fan.Value != 1230 || fan.Min == nil || *fan.Min != 300 || fan.Alarm == nil || *fan.Alarm {
+		t.Errorf("fan = %+v", fan)
+	}
+	volt := byType[sensorentities.TypeVoltage]
+	if volt.Value != 1.112 || volt.Alarm == nil || !*volt.Alarm {
+		t.Errorf("voltage = %+v; want 1.112 V with the max alarm raised", volt)
+	}
+	if c := byType[sensorentities.TypeCurrent]; c.Value != 2.5 {
+		t.Errorf("current = %+v", c)
+	}
+	if p := byType[sensorentities.TypePower]; p.Value != 15.25 {
+		t.Errorf("power = %+v", p)
+	}
+}
+
+func TestRAPLWatts_Wraparound(t *testing.T) {
+	if w, ok := sensorcollectors.RAPLWatts(1_000_000, 21_000_000, 0, 2*time.Second); !ok || w != 10 {
+		t.Errorf("RAPLWatts = %v, %v; want 10 W", w, ok)
+	}
+	// The counter wrapped at max_energy_range_uj: 1 J before the wrap and 3 J after it
+	if w, ok := sensorcollectors.RAPLWatts(99_000_000, 3_000_000, 100_000_000, time.Second); !ok || w != 4 {
+		t.Errorf("RAPLWatts(wrapped) = %v, %v; want 4 W", w, ok)
+	}
+	if _, ok := sensorcollectors.RAPLWatts(5, 1, 0, time.Second); ok {
+		t.Error("RAPLWatts without a range accepted a counter that went backwards")
+	}
+}
+
+func TestSensorsCollector_RAPLPowerFromSecondCollect(t *testing.T) {
+	sys := t.TempDir()
+	t.Setenv("HOST_SYS", sys)
+	zone := filepath.Join(sys, "class", "powercap", "intel-rapl:0")
+	writeHostFile(t, filepath.Join(zone, "name"), "package-0\n")
+	writeHostFile(t, filepath.Join(zone, "max_energy_range_uj"), "262143328850\n")
+	writeHostFile(t, filepath.Join(zone, "energy_uj"), "1000000\n")
+	// The control type directory is not a zone
+	writeHostFile(t, filepath.Join(sys, "class", "powercap", "intel-rapl", "enabled"), "1\n")
+
+	c := sensorcollectors.NewSensorsCollector(log.Default())
+	first, err := c.CollectReadings(context.Background())
+	if err != nil {
+		t.Fatalf("CollectReadings: %v", err)
+	}
+	if first.Readings == nil || len(first.Readings) != 0 {
+		t.Fatalf("first collect = %+v, want an empty non-nil slice", first.Readings)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	writeHostFile(t, filepath.Join(zone, "energy_uj"), "2000000\n")
+	second, err := c.CollectReadings(context.Background())
+	if err != nil {
+		t.Fatalf("CollectReadings: %v", err)
+	}
+	if len(second.Readings) != 1 {
+		t.Fatalf("second collect = %+v, want one RAPL reading", second.Readings)
+	}
+	r := second.Readings[0]
+	if r.Chip != sensorentities.ChipRAPL || r.Type != sensorentities.TypePower || r.Label != "package-0" || r.Value <= 0 {
+		t.Errorf("RAPL reading = %+v", r)
+	}
+}
+
+func TestSensorRepository_LatestAndHistory(t *testing.T) {
+	db := openModulesDB(t)
+	svc := sensorservice.NewService(log.Default(), sensorrepos.NewSensorRepository(db))
+	ctx := context.Background()
+
+	crit := 100.0
+	now := time.Now().UTC()
+	for i, v := range []float64{40, 42} {
+		metric := sensorentities.SensorMetric{
+			Timestamp: now.Add(time.Duration(i-1) * time.Minute),
+			Readings: []sensorentities.SensorReading{
+				{Chip: "coretemp", Label: "Package id 0", Type: sensorentities.TypeTemperature, Value: v, Crit: &crit},
+				{Chip: "nct6775", Label: "fan2", Type: sensorentities.TypeFan, Value: 1200 + v},
+				// Duplicate (sensor, type) readings collapse to the first
+				{Chip: "nct6775", Label: "fan2", Type: sensorentities.TypeFan, Value: 0},
+			},
+		}
+		if err := svc.Save(ctx, metric, 3); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	latest, err := svc.GetLatestByHost(ctx, 3)
+	if err != nil {
+		t.Fatalf("GetLatestByHost: %v", err)
+	}
+	if len(latest) != 2 {
+		t.Fatalf("latest = %+v, want 2 rows", latest)
+	}
+	for _, r := range latest {
+		if r.Type == sensorentities.TypeTemperature && (r.Value != 42 || r.Crit == nil || *r.Crit != 100) {
+			t.Errorf("latest temperature = %+v", r)
+		}
+		if r.Type == sensorentities.TypeFan && r.Value != 1242 {
+			t.Errorf("latest fan = %+v", r)
+		}
+	}
+
+	history, err := svc.GetHistoryByHost(ctx, 3, 1, "", sensorentities.TypeFan)
+	if err != nil {
+		t.Fatalf("GetHistoryByHost: %v", err)
+	}
+	if len(history) != 2 || history[0].Value != 1240 || history[1].Value != 1242 {
+		t.Errorf("fan history = %+v", history)
+	}
+	history, err = svc.GetHistoryByHost(ctx, 3, 1, "coretemp/Package id 0", "")
+	if err != nil || len(history) != 2 {
+		t.Errorf("temperature history = %+v, %v", history, err)
+	}
+	if other, _ := svc.GetLatestByHost(ctx, 4); len(other) != 0 {
+		t.Errorf("host 4 latest = %+v, want none", other)
+	}
+}
@@ -0,0 +1,172 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	smartmetrics "system-stats/internal/modules/smart/application"
+	"system-stats/internal/modules/smart/infrastructure/collectors"
+	"system-stats/internal/modules/smart/infrastructure/entities"
+	"system-stats/internal/modules/smart/infrastructure/repositories"
+)
+
+const testSmartATA = `{
+  "smartctl": {"version": [7, 3], "exit_status": 4},
+  "device": {"name": "/dev/sda", "type": "sat", "protocol": "ATA"},
+  "model_name": "Samsung SSD 860 EVO 500GB",
+  "serial_number": "S3Z9NB0K123456X",
+  "firmware_version": "RVT04B6Q",
+  "user_capacity": {"blocks": 976773168, "bytes": 500107862016},
+  "logical_block_size": 512,
+  "smart_status": {"passed": true},
+  "ata_smart_attributes": {"table": [
+    {"id": 5, "name": "Reallocated_Sector_Ct", "value": 100, "thresh": 10, "when_failed": "", "raw": {"value": 3}},
+    {"id": 177, "name": "Wear_Leveling_Count", "value": 93, "thresh": 0, "when_failed": "", "raw": {"value": 41}},
+    {"id": 187, "name": "Reported_Uncorrect", "value": 100, "thresh": 0, "when_failed": "", "raw": {"value": 0}},
+    {"id": 197, "name": "Current_Pending_Sector", "value": 100, "thresh": 0, "when_failed": "", "raw": {"value": 1}},
+    {"id": 199, "name": "UDMA_CRC_Error_Count", "value": 1, "thresh": 10, "when_failed": "now", "raw": {"value": 12}},
+    {"id": 241, "name": "Total_LBAs_Written", "value": 99, "thresh": 0, "when_failed": "", "raw": {"value": 1000}}
+  ]},
+  "power_on_time": {"hours": 12345},
+  "power_cycle_count": 210,
+  "temperature": {"current": 31}
+}`
+
+const testSmartNVMe = `{
+  "smartctl": {"version": [7, 3], "exit_status": 0},
+  "device": {"name": "/dev/nvme0", "type": "nvme", "protocol": "NVMe"},
+  "model_name": "Samsung SSD 970 EVO Plus 1TB",
+  "serial_number": "S4EWNX0N123456",
+  "user_capacity": {"bytes": 1000204886016},
+  "smart_status": {"passed": true},
+  "nvme_smart_health_information_log": {
+    "critical_warning": 0, "temperature": 38, "available_spare": 100, "percentage_used": 2,
+    "data_units_written": 2000, "power_cycles": 99, "power_on_hours": 4000, "media_errors": 0
+  },
+  "temperature": {"current": 38},
+  "power_cycle_count": 99,
+  "power_on_time": {"hours": 4000}
+}`
+
+func TestParseSmartctl_NormalisesATAAndNVMe(t *testing.T) {
+	ata, err := collectors.ParseSmartctl([]byte(testSmartATA))
+	if err != nil {
+		t.Fatalf("ParseSmartctl(ATA): %v", err)
+	}
+	if ata.Protocol != "ATA" || ata.Serial != "S3Z9NB0K123456X" || ata.Passed == nil || !*ata.Passed ||
+		ata.ReallocatedSectors == nil || *ata.ReallocatedSectors != 3 || ata.PendingSectors == nil || *ata.PendingSectors != 1 ||
+		ata.CRCErrors == nil || *ata.CRCErrors != 12 || ata.OfflineUncorrectable != nil || ata.MediaErrors != nil ||
+		ata.PercentageUsed == nil || *ata.PercentageUsed != 7 || ata.BytesWritten == nil || *ata.BytesWritten != 512000 ||
+		ata.PowerOnHours == nil || *ata.PowerOnHours != 12345 || ata.TemperatureCelsius == nil || *ata.TemperatureCelsius != 31 {
+		t.Errorf("ATA disk = %+v", ata)
+	}
+	if len(ata.FailingAttributes) != 1 || ata.FailingAttributes[0] != "UDMA_CRC_Error_Count" || ata.ExitStatus != 4 {
+		t.Errorf("ATA failing = %v, exit %d", ata.FailingAttributes, ata.ExitStatus)
+	}
+
+	nvme, err := collectors.ParseSmartctl([]byte(testSmartNVMe))
+	if err != nil {
+		t.Fatalf("ParseSmartctl(NVMe): %v", err)
+	}
+	if nvme.Protocol != "NVMe" || nvme.MediaErrors == nil || *nvme.MediaErrors != 0 || nvme.PercentageUsed == nil || *nvme.PercentageUsed != 2 ||
+		nvme.AvailableSparePercent == nil || *nvme.AvailableSparePercent != 100 || nvme.BytesWritten == nil || *nvme.BytesWritten != 2000*512000 ||
+		nvme.ReallocatedSectors != nil || nvme.PowerCycles == nil || *nvme.PowerCycles != 99 {
+		t.Errorf("NVMe disk = %+v", nvme)
+	}
+
+	if _, err := collectors.ParseSmartctl([]byte(`{"smartctl": {"exit_status": 2, "messages": [{"string": "Device is in STANDBY mode, exit(2)"}]}}`)); err == nil {
+		t.Error("ParseSmartctl accepted a run that read no data")
+	}
+}
+
+func TestMatchIODevice(t *testing.T) {
+	serials := map[string]string{
+		"sda":       "Samsung_SSD_860_EVO_500GB_S3Z9NB0K123456X",
+		"sda1":      "Samsung_SSD_860_EVO_500GB_S3Z9NB0K123456X",
+		"nvme0n1":   "",
+		"nvme0n1p1": "",
+	}
+	if got := collectors.MatchIODevice(entities.SmartDisk{Device: "/dev/sdz", Serial: "S3Z9NB0K123456X"}, serials); got != "sda" {
+		t.Errorf("serial match = %q, want sda", got)
+	}
+	if got := collectors.MatchIODevice(entities.SmartDisk{Device: "/dev/nvme0", Serial: "S4EWNX0N123456"}, serials); got != "nvme0n1" {
+		t.Errorf("path fallback = %q, want nvme0n1", got)
+	}
+	if got := collectors.MatchIODevice(entities.SmartDisk{Device: "/dev/sdq", Serial: "OTHER"}, serials); got != "" {
+		t.Errorf("no match = %q", got)
+	}
+}
+
+func TestSmartConfigFromEnv(t *testing.T) {
+	t.Setenv(collectors.DevicesEnv, "/dev/sda, /dev/sdb:sat")
+	t.Setenv(collectors.IntervalEnv, "")
+	cfg, err := collectors.ConfigFromEnv()
+	if err != nil || len(cfg.Devices) != 2 || cfg.Devices[1] != (collectors.Device{Path: "/dev/sdb", Type: "sat"}) || cfg.Interval != collectors.DefaultInterval {
+		t.Fatalf("ConfigFromEnv = %+v, %v", cfg, err)
+	}
+	t.Setenv(collectors.DevicesEnv, "sda")
+	if _, err := collectors.ConfigFromEnv(); err == nil {
+		t.Error("accepted a device outside /dev")
+	}
+	t.Setenv(collectors.DevicesEnv, "")
+	t.Setenv(collectors.IntervalEnv, "-1m")
+	if _, err := collectors.ConfigFromEnv(); err == nil {
+		t.Error("accepted a negative interval")
+	}
+}
+
+func TestSmartService_JSONDirHistoryAndIOLink(t *testing.T) {
+	t.Setenv("HOST_PROC", t.TempDir())
+	dir := t.TempDir()
+	writeHostFile(t, filepath.Join(dir, "sda.json"), testSmartATA)
+	writeHostFile(t, filepath.Join(dir, "nvme0.json"), testSmartNVMe)
+	writeHostFile(t, filepath.Join(dir, "broken.json"), "{")
+	checked := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(filepath.Join(dir, "sda.json"), checked, checked); err != nil {
+		t.Fatal(err)
+	}
+
+	db := openModulesDB(t)
+	io := func(ctx context.Context, hostId uint) (map[string]smartmetrics.DiskIO, error) {
+		return map[string]smartmetrics.DiskIO{"sda": {ReadIOPS: 42, UtilPercent: 7}}, nil
+	}
+	svc := smartmetrics.NewService(log.New(os.Stderr), repositories.NewSmartRepository(db), collectors.Config{JSONDir: dir, Interval: collectors.DefaultInterval}, io)
+	ctx := context.Background()
+
+	for range 2 {
+		metric, err := svc.Collect(ctx)
+		if err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+		if len(metric.Disks) != 2 || metric.Disks[0].Device != "/dev/nvme0" || !metric.Disks[1].CheckedAt.Equal(checked) {
+			t.Fatalf("Collect = %+v", metric.Disks)
+		}
+		// The agent has no I/O counters here; link the ATA disk as the collector would
+		metric.Disks[1].IODevice = "sda"
+		if err := svc.Save(ctx, metric, 1); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	history, err := svc.GetHistoryByHost(ctx, 1, 2, "")
+	if err != nil || len(history) != 2 {
+		t.Fatalf("history = %+v, %v; an unchanged check must be stored once", history, err)
+	}
+	latest, err := svc.GetLatestByHost(ctx, 1)
+	if err != nil || len(latest) != 2 {
+		t.Fatalf("latest = %+v, %v", latest, err)
+	}
+	sda := latest[1]
+	if sda.DiskID != "S3Z9NB0K123456X" || sda.IODevice != "sda" || sda.IO == nil || sda.IO.ReadIOPS != 42 ||
+		sda.FailingAttributes != "UDMA_CRC_Error_Count" || sda.PercentageUsed == nil || *sda.PercentageUsed != 7 {
+		t.Errorf("sda = %+v io %+v", sda, sda.IO)
+	}
+	if latest[0].IO != nil {
+		t.Errorf("unlinked disk has I/O %+v", latest[0].IO)
+	}
+}
@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"system-stats/internal/app/snapshot"
+	cpuentities "system-stats/internal/modules/cpu/infrastructure/entities"
+)
+
+func TestSnapshotJSON_OmitsFailedModules(t *testing.T) {
+	snap := &snapshot.Snapshot{
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		HostID:    1,
+		CPU:       &cpuentities.CPUMetric{UsagePercent: 42.5},
+	}
+
+	body, err := snap.JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	for _, key := range []string{"timestamp", "collecting_host_id", "cpu"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing key %q in %s", key, body)
+		}
+	}
+	// Failed collectors are omitted, not null, so SSE clients keep their previous values
+	for _, key := range []string{"memory", "disk", "network", "docker"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("unexpected key %q in %s", key, body)
+		}
+	}
+
+	again, _ := snap.JSON()
+	if &again[0] != &body[0] {
+		t.Error("JSON re-encoded the snapshot instead of returning the cached bytes")
+	}
+}
+
+func TestSnapshotStore_LatestWins(t *testing.T) {
+	store := snapshot.NewStore()
+	if store.Latest() != nil {
+		t.Fatal("new store should be empty")
+	}
+
+	first := &snapshot.Snapshot{HostID: 1}
+	second := &snapshot.Snapshot{HostID: 2}
+	store.Set(first)
+	store.Set(second)
+	if store.Latest() != second {
+		t.Error("Latest did not return the most recently set snapshot")
+	}
+}
@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"system-stats/internal/app/procnet"
+	socketmetrics "system-stats/internal/modules/sockets/application"
+	"system-stats/internal/modules/sockets/infrastructure/repositories"
+)
+
+const testTCPTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 4242 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0CEA 0100007F:D431 01 00000000:00000000 00:00000000 00000000   999        0 4243 1 0000000000000000 20 4 30 10 -1
+   2: 0100007F:D431 0100007F:0CEA 06 00000000:00000000 03:00000FAB 00000000     0        0 0 3 0000000000000000
+`
+
+const testTCP6Table = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000001000000:1F90 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 5151 1 0000000000000000 100 0 0 10 0
+`
+
+const testSNMP = `Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ActiveOpens PassiveOpens AttemptFails EstabResets CurrEstab InSegs OutSegs RetransSegs InErrs OutRsts InCsumErrors
+Tcp: 1 200 120000 -1 %d 50 2 3 1 1000 %d %d 0 4 0
+`
+
+func TestProcnet_ParseSockets(t *testing.T) {
+	v4, err := procnet.ParseSockets([]byte(testTCPTable))
+	if err != nil || len(v4) != 3 {
+		t.Fatalf("ParseSockets(tcp) = %+v, %v", v4, err)
+	}
+	if v4[0].Local != netip.MustParseAddrPort("127.0.0.1:3306") || v4[0].State != procnet.StateListen || v4[0].UID != 999 || v4[0].Inode != 4242 {
+		t.Errorf("listener = %+v", v4[0])
+	}
+	if v4[2].State != procnet.StateTimeWait || v4[2].Remote.Port() != 3306 {
+		t.Errorf("time-wait socket = %+v", v4[2])
+	}
+	v6, err := procnet.ParseSockets([]byte(testTCP6Table))
+	if err != nil || len(v6) != 1 || v6[0].Local != netip.MustParseAddrPort("[::1]:8080") {
+		t.Fatalf("ParseSockets(tcp6) = %+v, %v", v6, err)
+	}
+
+	tables := procnet.ParseCounterTables([]byte("Tcp: MaxConn ActiveOpens\nTcp: -1 7\nUdp: InDatagrams\nUdp: 9\n"))
+	if tables["Tcp"]["MaxConn"] != -1 || tables["Tcp"]["ActiveOpens"] != 7 || tables["Udp"]["InDatagrams"] != 9 {
+		t.Errorf("counter tables = %+v", tables)
+	}
+}
+
+func TestSocketCollector_HostProc(t *testing.T) {
+	proc := t.TempDir()
+	t.Setenv("HOST_PROC", proc)
+	writeHostFile(t, filepath.Join(proc, "net", "sockstat"), "sockets: used 120\nTCP: inuse 2 orphan 1 tw 1 alloc 4 mem 3\nUDP: inuse 5 mem 1\n")
+	writeHostFile(t, filepath.Join(proc, "net", "tcp"), testTCPTable)
+	writeHostFile(t, filepath.Join(proc, "net", "tcp6"), testTCP6Table)
+	writeHostFile(t, filepath.Join(proc, "net", "snmp"), fmt.Sprintf(testSNMP, 100, 1000, 10))
+	writeHostFile(t, filepath.Join(proc, "net", "netstat"), "TcpExt: SyncookiesSent ListenOverflows ListenDrops\nTcpExt: 0 5 6\n")
+	writeHostFile(t, filepath.Join(proc, "sys", "net", "netfilter", "nf_conntrack_count"), "250\n")
+	writeHostFile(t, filepath.Join(proc, "sys", "net", "netfilter", "nf_conntrack_max"), "1000\n")
+
+	db := openModulesDB(t)
+	svc := socketmetrics.NewService(log.Default(), repositories.NewSocketRepository(db))
+	ctx := context.Background()
+	first, err := svc.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	states := first.TCPStates
+	if states.Listen != 2 || states.Established != 1 || states.TimeWait != 1 {
+		t.Errorf("states = %+v", states)
+	}
+	if first.SocketsUsed != 120 || first.TCPOrphan != 1 || first.TCPMemBytes == 0 || first.UDPInUse != 5 {
+		t.Errorf("sockstat = %+v", first.SocketStats)
+	}
+	if first.ConntrackPercent == nil || *first.ConntrackPercent != 25 {
+		t.Errorf("conntrack percent = %v", first.ConntrackPercent)
+	}
+	if first.RetransSegsPerSec != nil {
+		t.Error("rates must be nil on the first collection")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	writeHostFile(t, filepath.Join(proc, "net", "snmp"), fmt.Sprintf(testSNMP, 110, 1100, 20))
+	second, err := svc.Collect(ctx)
+	if err != nil {
+		t.Fatalf("second Collect: %v", err)
+	}
+	if second.RetransPercent == nil || *second.RetransPercent != 10 || second.ActiveOpensPerSec == nil || *second.ActiveOpensPerSec <= 0 {
+		t.Errorf("rates = retrans %v, opens %v", second.RetransPercent, second.ActiveOpensPerSec)
+	}
+	if second.ListenOverflowsPerSec == nil || *second.ListenOverflowsPerSec != 0 {
+		t.Errorf("listen overflows = %v", second.ListenOverflowsPerSec)
+	}
+
+	if err := svc.Save(ctx, first, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := svc.Save(ctx, second, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	latest, err := svc.GetLatestByHost(ctx, 1)
+	if err != nil || latest == nil || latest.RetransPercent == nil || latest.TCPStates.Listen != 2 {
+		t.Fatalf("latest = %+v, %v", latest, err)
+	}
+	history, _ := svc.GetHistoryByHost(ctx, 1, 1)
+	if len(history) != 2 {
+		t.Errorf("history has %d rows, want 2", len(history))
+	}
+}
@@ -0,0 +1,165 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	eventrepos "system-stats/internal/modules/events/infrastructure/repositories"
+	storagehealth "system-stats/internal/modules/storage/application"
+	"system-stats/internal/modules/storage/infrastructure/collectors"
+	"system-stats/internal/modules/storage/infrastructure/entities"
+	"system-stats/internal/modules/storage/infrastructure/repositories"
+)
+
+const testMdstat = `Personalities : [raid1] [raid6] [raid5] [raid4] [raid0]
+md1 : active raid1 sdb2[1](F) sda2[0] sdc2[2]
+      10476544 blocks super 1.2 [2/1] [U_]
+      [=>...................]  recovery =  8.5% (893248/10476544) finish=1.2min speed=127606K/sec
+
+md0 : active raid1 sdb1[1] sda1[0]
+      1048512 blocks super 1.2 [2/2] [UU]
+      [==========>..........]  check = 52.1% (546304/1048512) finish=0.1min speed=91050K/sec
+
+md2 : active raid5 sdf[3] sde[1] sdd[0]
+      41908224 blocks super 1.2 level 5, 512k chunk, algorithm 2 [4/3] [UU_U]
+
+md3 : active raid0 sdh[1] sdg[0]
+      2095104 blocks super 1.2 512k chunks
+
+md127 : inactive sdi[0](S)
+      1048576 blocks super 1.2
+
+unused devices: <none>
+`
+
+func TestParseMdstat(t *testing.T) {
+	arrays := collectors.ParseMdstat([]byte(testMdstat))
+	if len(arrays) != 5 {
+		t.Fatalf("ParseMdstat = %+v, want 5 arrays", arrays)
+	}
+	byName := map[string]entities.StorageDevice{}
+	for _, a := range arrays {
+		byName[a.Name] = a
+	}
+	md1 := byName["md1"]
+	if md1.Health != entities.HealthRebuilding || md1.Level != "raid1" || md1.Devices != 2 || md1.ActiveDevices != 1 ||
+		len(md1.FailedDevices) != 1 || md1.FailedDevices[0] != "sdb2" || md1.SyncPercent == nil || *md1.SyncPercent != 8.5 ||
+		md1.SyncFinishMinutes == nil || *md1.SyncFinishMinutes != 1.2 {
+		t.Errorf("md1 = %+v", md1)
+	}
+	if md0 := byName["md0"]; md0.Health != entities.HealthOK || md0.SyncAction != "check" || md0.SyncPercent == nil {
+		t.Errorf("md0 = %+v", md0)
+	}
+	if md2 := byName["md2"]; md2.Health != entities.HealthDegraded || md2.Devices != 4 || md2.ActiveDevices != 3 {
+		t.Errorf("md2 = %+v", md2)
+	}
+	if md3 := byName["md3"]; md3.Health != entities.HealthOK || md3.Level != "raid0" || md3.Devices != 2 {
+		t.Errorf("md3 = %+v", md3)
+	}
+	if md127 := byName["md127"]; md127.Health != entities.HealthFailed || md127.State != "inactive" {
+		t.Errorf("md127 = %+v", md127)
+	}
+
+	sizes := collectors.ParseZpoolList([]byte("tank\t1000\t250\nbad line\n"))
+	if c, ok := sizes["tank"]; !ok || c[0] != 1000 || c[1] != 250 || len(sizes) != 1 {
+		t.Errorf("ParseZpoolList = %+v", sizes)
+	}
+}
+
+func TestStorageCollector_FixturesAndEvents(t *testing.T) {
+	proc, sys := t.TempDir(), t.TempDir()
+	t.Setenv("HOST_PROC", proc)
+	t.Setenv("HOST_SYS", sys)
+	t.Setenv("PATH", "")
+	writeHostFile(t, filepath.Join(proc, "mdstat"), "md0 : active raid1 sdb1[1] sda1[0]\n      1048512 blocks super 1.2 [2/2] [UU]\n\nunused devices: <none>\n")
+	writeHostFile(t, filepath.Join(proc, "spl", "kstat", "zfs", "tank", "state"), "ONLINE\n")
+	writeHostFile(t, filepath.Join(proc, "spl", "kstat", "zfs", "backup", "state"), "DEGRADED\n")
+	fs := filepath.Join(sys, "fs", "btrfs", "0b1c-uuid")
+	writeHostFile(t, filepath.Join(fs, "label"), "data\n")
+	writeHostFile(t, filepath.Join(fs, "devices", "sdc"), "")
+	writeHostFile(t, filepath.Join(fs, "devinfo", "1", "missing"), "0\n")
+	writeHostFile(t, filepath.Join(fs, "devinfo", "1", "error_stats"), "write_errs 0\nread_errs 0\nflush_errs 0\ncorruption_errs 0\ngeneration_errs 0\n")
+	if !collectors.Available() {
+		t.Fatal("Available = false with fixtures")
+	}
+
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	svc := storagehealth.NewService(log.Default(), repositories.NewStorageRepository(db), events)
+	ctx := context.Background()
+
+	// The first check reports every device
+	first, err := svc.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(first.Devices) != 4 || len(first.Events) != 4 {
+		t.Fatalf("first check = %+v", first)
+	}
+	if err := svc.Save(ctx, first, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	problems, _ := events.Problems(ctx, 1)
+	if len(problems) != 1 || problems[0].Subject != "zfs/backup" || problems[0].Severity != evententities.SeverityCritical {
+		t.Fatalf("problems = %+v, want zfs/backup", problems)
+	}
+
+	// md0 loses a member, btrfs reports errors, the degraded pool is exported
+	writeHostFile(t, filepath.Join(proc, "mdstat"), "md0 : active raid1 sdb1[1](F) sda1[0]\n      1048512 blocks super 1.2 [2/1] [U_]\n\nunused devices: <none>\n")
+	writeHostFile(t, filepath.Join(fs, "devinfo", "1", "error_stats"), "write_errs 2\nread_errs 1\nflush_errs 0\ncorruption_errs 0\ngeneration_errs 0\n")
+	if err := os.RemoveAll(filepath.Join(proc, "spl", "kstat", "zfs", "backup")); err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	kinds := map[string]string{}
+	for _, e := range second.Events {
+		kinds[e.Subject] = e.Kind
+	}
+	if kinds["md/md0"] != entities.HealthDegraded || kinds["btrfs/0b1c-uuid"] != entities.HealthErrors ||
+		kinds["zfs/backup"] != collectors.KindRemoved || len(kinds) != 3 {
+		t.Fatalf("second check events = %+v", second.Events)
+	}
+	if err := svc.Save(ctx, second, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	problems, _ = events.Problems(ctx, 1)
+	if len(problems) != 2 {
+		t.Errorf("problems = %+v, want md0 and btrfs", problems)
+	}
+	latest, err := svc.GetLatestByHost(ctx, 1)
+	if err != nil || len(latest) != 3 {
+		t.Fatalf("GetLatestByHost = %+v, %v", latest, err)
+	}
+	for _, row := range latest {
+		if row.Name == "md0" && row.FailedDevices != "sdb1" {
+			t.Errorf("md0 row = %+v", row)
+		}
+		if row.Kind == entities.KindBtrfs && row.ErrorCount != 3 {
+			t.Errorf("btrfs row = %+v", row)
+		}
+	}
+
+	// md0 is stopped while this instance is down; a restarted instance resolves its problem
+	writeHostFile(t, filepath.Join(proc, "mdstat"), "unused devices: <none>\n")
+	restarted := storagehealth.NewService(log.Default(), repositories.NewStorageRepository(db), events)
+	third, err := restarted.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if err := restarted.Save(ctx, third, 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	problems, _ = events.Problems(ctx, 1)
+	if len(problems) != 1 || problems[0].Subject != "btrfs/0b1c-uuid" {
+		t.Errorf("problems after restart = %+v, want btrfs only", problems)
+	}
+}
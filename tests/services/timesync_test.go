@@ -0,0 +1,171 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	eventrepos "system-stats/internal/modules/events/infrastructure/repositories"
+	timesyncservice "system-stats/internal/modules/timesync/application"
+	timesynccollectors "system-stats/internal/modules/timesync/infrastructure/collectors"
+	timesyncentities "system-stats/internal/modules/timesync/infrastructure/entities"
+	timesyncrepos "system-stats/internal/modules/timesync/infrastructure/repositories"
+)
+
+func TestParseChronyTracking(t *testing.T) {
+	d, err := timesynccollectors.ParseChronyTracking([]byte("C0A80001,192.168.0.1,3,1700000000.500000000,0.000250000,-0.000012000,0.000045000,-12.345,-0.001,0.012,0.001500000,0.000750000,64.4,Normal\n"))
+	if err != nil {
+		t.Fatalf("ParseChronyTracking: %v", err)
+	}
+	if d.Name != timesyncentities.DaemonChrony || !d.Synced || d.Reference != "192.168.0.1" || d.Stratum == nil || *d.Stratum != 3 || d.LeapStatus != "Normal" {
+		t.Errorf("daemon = %+v", d)
+	}
+	// chronyc reports the clock 250µs slow, i.e. behind
+	if d.OffsetSeconds == nil || *d.OffsetSeconds != -0.00025 {
+		t.Errorf("offset = %v, want -0.00025", d.OffsetSeconds)
+	}
+	if d.RootDelaySeconds == nil || *d.RootDelaySeconds != 0.0015 || d.RootDispersionSeconds == nil || *d.RootDispersionSeconds != 0.00075 {
+		t.Errorf("root delay / dispersion = %v / %v", d.RootDelaySeconds, d.RootDispersionSeconds)
+	}
+	if d.LastSync == nil || !d.LastSync.Equal(time.Unix(1700000000, 500000000)) {
+		t.Errorf("last sync = %v", d.LastSync)
+	}
+
+	d, err = timesynccollectors.ParseChronyTracking([]byte("00000000,,0,0.000000000,0.000000000,0.000000000,0.000000000,0.000,0.000,0.000,1.000000000,1.000000000,0.0,Not synchronised\n"))
+	if err != nil {
+		t.Fatalf("ParseChronyTracking(unsynced): %v", err)
+	}
+	if d.Synced || d.OffsetSeconds != nil || d.LastSync != nil {
+		t.Errorf("unsynced daemon = %+v", d)
+	}
+
+	if _, err := timesynccollectors.ParseChronyTracking([]byte("506 Cannot talk to daemon\n")); err == nil {
+		t.Error("ParseChronyTracking accepted an error message")
+	}
+}
+
+func TestReadTimesyncd(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOST_ROOT", root)
+	if d := timesynccollectors.ReadTimesyncd(); d != nil {
+		t.Fatalf("ReadTimesyncd without timesyncd = %+v, want nil", d)
+	}
+
+	dir := filepath.Join(root, "run", "systemd", "timesync")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if d := timesynccollectors.ReadTimesyncd(); d == nil || d.Synced || d.LastSync != nil {
+		t.Fatalf("ReadTimesyncd before the first sync = %+v", d)
+	}
+	writeHostFile(t, filepath.Join(dir, "synchronized"), "")
+	synced := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(dir, "synchronized"), synced, synced); err != nil {
+		t.Fatal(err)
+	}
+	d := timesynccollectors.ReadTimesyncd()
+	if d == nil || d.Name != timesyncentities.DaemonTimesyncd || !d.Synced || d.LastSync == nil || !d.LastSync.Equal(synced) {
+		t.Errorf("ReadTimesyncd = %+v", d)
+	}
+}
+
+func TestTimeSyncService_ClockSkewEvents(t *testing.T) {
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.Default(), eventrepos.NewHostEventRepository(db))
+	repo := timesyncrepos.NewTimeSyncRepository(db)
+	svc := timesyncservice.NewService(log.Default(), repo, events, 2*time.Second)
+	ctx := context.Background()
+	now := time.Now().UTC().Add(-time.Minute)
+
+	steps := []struct {
+		skew time.Duration
+		open bool
+	}{
+		{skew: 50 * time.Millisecond},
+		{skew: -5 * time.Second, open: true},
+		{skew: -6 * time.Second, open: true},
+		{skew: 100 * time.Millisecond},
+	}
+	for i, step := range steps {
+		received := now.Add(time.Duration(i) * time.Second)
+		if err := svc.RecordClockSkew(ctx, 7, received.Add(step.skew), received); err != nil {
+			t.Fatalf("RecordClockSkew: %v", err)
+		}
+		problems, err := events.Problems(ctx, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if open := len(problems) == 1 && problems[0].Source == timesyncservice.EventSource; open != step.open || len(problems) > 1 {
+			t.Errorf("step %d (skew %s): problems = %+v, want open %v", i, step.skew, problems, step.open)
+		}
+	}
+	list, err := events.List(ctx, 7, 1, timesyncservice.EventSource, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Errorf("events = %+v, want one skew and one recovered", list)
+	}
+	if svc.ClockSkews()[7] != 0.1 {
+		t.Errorf("ClockSkews = %v", svc.ClockSkews())
+	}
+
+	history, err := svc.GetClockSkewHistoryByHost(ctx, 7, 1)
+	if err != nil || len(history) != len(steps) || history[1].SkewSeconds != -5 {
+		t.Errorf("skew history = %+v, %v", history, err)
+	}
+
+	// A new instance picks up a problem left open by the previous one
+	if err := svc.RecordClockSkew(ctx, 7, now.Add(time.Minute+10*time.Second), now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	restarted := timesyncservice.NewService(log.Default(), repo, events, 2*time.Second)
+	received := now.Add(time.Minute + time.Second)
+	if err := restarted.RecordClockSkew(ctx, 7, received, received); err != nil {
+		t.Fatal(err)
+	}
+	if problems, _ := events.Problems(ctx, 7); len(problems) != 0 {
+		t.Errorf("problems after restart = %+v, want the skew resolved", problems)
+	}
+}
+
+func TestTimeSyncService_ReceivePush(t *testing.T) {
+	db := openModulesDB(t)
+	svc := timesyncservice.NewService(log.Default(), timesyncrepos.NewTimeSyncRepository(db), nil, timesynccollectors.DefaultSkewThreshold)
+	ctx := context.Background()
+
+	stratum := 2
+	metric := timesyncentities.TimeSyncMetric{
+		Timestamp: time.Now().UTC(),
+		Kernel:    &timesyncentities.KernelClock{Synced: true, OffsetSeconds: 0.000012, MaxErrorSeconds: 0.25, FrequencyPPM: -3.5, Status: 0x2001},
+		Daemon:    &timesyncentities.SyncDaemon{Name: timesyncentities.DaemonChrony, Synced: true, Reference: "10.0.0.1", Stratum: &stratum},
+	}
+	data, _ := json.Marshal(metric)
+	// Pushed twice: stored once
+	for range 2 {
+		if err := svc.ReceivePush(ctx, 5, data); err != nil {
+			t.Fatalf("ReceivePush: %v", err)
+		}
+	}
+	latest, err := svc.GetLatestByHost(ctx, 5)
+	if err != nil || latest == nil {
+		t.Fatalf("GetLatestByHost = %v, %v", latest, err)
+	}
+	if latest.KernelSynced == nil || !*latest.KernelSynced || latest.KernelFrequencyPPM == nil || *latest.KernelFrequencyPPM != -3.5 ||
+		latest.Daemon != "chrony" || latest.Reference != "10.0.0.1" || latest.Stratum == nil || *latest.Stratum != 2 {
+		t.Errorf("latest = %+v", latest)
+	}
+	history, err := svc.GetHistoryByHost(ctx, 5, 1)
+	if err != nil || len(history) != 1 {
+		t.Errorf("history = %+v, %v", history, err)
+	}
+	if other, _ := svc.GetLatestByHost(ctx, 6); other != nil {
+		t.Errorf("host 6 latest = %+v, want nil", other)
+	}
+}
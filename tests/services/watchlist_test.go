@@ -0,0 +1,243 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	eventsservice "system-stats/internal/modules/events/application"
+	evententities "system-stats/internal/modules/events/infrastructure/entities"
+	eventrepos "system-stats/internal/modules/events/infrastructure/repositories"
+	watchservice "system-stats/internal/modules/watchlist/application"
+	"system-stats/internal/modules/watchlist/infrastructure/collectors"
+	"system-stats/internal/modules/watchlist/infrastructure/entities"
+	"system-stats/internal/modules/watchlist/infrastructure/repositories"
+)
+
+func loadWatches(t *testing.T, body string) []collectors.WatchConfig {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "watches.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	watches, err := collectors.LoadWatches(path)
+	if err != nil {
+		t.Fatalf("LoadWatches: %v", err)
+	}
+	return watches
+}
+
+func writePidfile(t *testing.T, path string, pid int) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strconv.Itoa(pid)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func startSleep(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill(); _, _ = cmd.Process.Wait() })
+	return cmd
+}
+
+func TestLoadWatches_Validation(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"no matcher":       `{"watches":[{"name":"a"}]}`,
+		"pidfile and name": `{"watches":[{"name":"a","process":"nginx","pidfile":"/run/nginx.pid"}]}`,
+		"bad regex":        `{"watches":[{"name":"a","cmdline":"("}]}`,
+		"duplicate":        `{"watches":[{"name":"a","process":"x"},{"name":"a","process":"y"}]}`,
+	} {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".json")
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := collectors.LoadWatches(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	watches := loadWatches(t, `{"watches":[{"name":"web","process":"nginx"}]}`)
+	if watches[0].MinCount != 1 {
+		t.Errorf("default min_count = %d, want 1", watches[0].MinCount)
+	}
+}
+
+func TestWatchCollector_UpDownAndRestart(t *testing.T) {
+	pidfile := filepath.Join(t.TempDir(), "svc.pid")
+	first := startSleep(t)
+	writePidfile(t, pidfile, first.Process.Pid)
+
+	watches := loadWatches(t, `{"watches":[
+		{"name":"svc","pidfile":`+strconv.Quote(pidfile)+`},
+		{"name":"sleepers","cmdline":"^sleep 30$"},
+		{"name":"missing","process":"no-such-process-xyz"}
+	]}`)
+	collector := collectors.NewWatchCollector(log.New(os.Stderr), watches)
+	ctx := context.Background()
+
+	metric, err := collector.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	byName := map[string]entities.WatchStatus{}
+	for _, w := range metric.Watches {
+		byName[w.Watch] = w
+	}
+	if svc := byName["svc"]; svc.State != entities.StateUp || len(svc.PIDs) != 1 || svc.PIDs[0] != int32(first.Process.Pid) {
+		t.Errorf("svc = %+v, want up with pid %d", svc, first.Process.Pid)
+	}
+	if byName["sleepers"].State != entities.StateUp {
+		t.Errorf("sleepers = %+v, want up", byName["sleepers"])
+	}
+	if byName["missing"].State != entities.StateDown {
+		t.Errorf("missing = %+v, want down", byName["missing"])
+	}
+	// The first check reports the state of every watch
+	kinds := map[string]string{}
+	for _, e := range metric.Events {
+		kinds[e.Subject] = e.Kind
+	}
+	if kinds["svc"] != collectors.KindUp || kinds["missing"] != collectors.KindDown {
+		t.Errorf("first check events = %+v", metric.Events)
+	}
+
+	second := startSleep(t)
+	_ = first.Process.Kill()
+	_, _ = first.Process.Wait()
+	writePidfile(t, pidfile, second.Process.Pid)
+
+	metric, err = collector.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	// The cmdline watch sees the same PID change
+	if len(metric.Events) != 2 || metric.Events[0].Subject != "svc" || metric.Events[0].Kind != collectors.KindRestart {
+		t.Fatalf("expected svc and sleepers restart events, got %+v", metric.Events)
+	}
+	if svc := metric.Watches[0]; svc.Restarts != 1 || svc.RestartsTotal != 1 {
+		t.Errorf("svc restarts = %d (total %d), want 1", svc.Restarts, svc.RestartsTotal)
+	}
+
+	_ = second.Process.Kill()
+	_, _ = second.Process.Wait()
+	metric, err = collector.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if metric.Watches[0].State != entities.StateDown {
+		t.Errorf("svc = %+v, want down", metric.Watches[0])
+	}
+	var down bool
+	for _, e := range metric.Events {
+		if e.Subject == "svc" && e.Kind == collectors.KindDown && e.Severity == evententities.SeverityCritical {
+			down = true
+		}
+	}
+	if !down {
+		t.Errorf("expected a critical svc down event, got %+v", metric.Events)
+	}
+}
+
+func TestHostEvents_ProblemsOpenAndClose(t *testing.T) {
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.New(os.Stderr), eventrepos.NewHostEventRepository(db))
+	ctx := context.Background()
+	t0 := time.Now().UTC().Add(-time.Minute)
+
+	down := evententities.HostEvent{Timestamp: t0, Source: "watchlist", Subject: "web", Kind: "down", Severity: evententities.SeverityCritical}
+	if err := events.Record(ctx, 1, []evententities.HostEvent{down}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	// A repeated push of the same event is stored once
+	if err := events.Record(ctx, 1, []evententities.HostEvent{down}); err != nil {
+		t.Fatalf("Record again: %v", err)
+	}
+	problems, err := events.Problems(ctx, 1)
+	if err != nil || len(problems) != 1 || problems[0].Subject != "web" {
+		t.Fatalf("Problems = %+v, %v; want web down", problems, err)
+	}
+
+	// Info events do not close a problem; ok events do
+	restart := evententities.HostEvent{Timestamp: t0.Add(time.Second), Source: "watchlist", Subject: "web", Kind: "restart", Severity: evententities.SeverityInfo}
+	_ = events.Record(ctx, 1, []evententities.HostEvent{restart})
+	if problems, _ := events.Problems(ctx, 1); len(problems) != 1 {
+		t.Fatalf("restart closed the problem: %+v", problems)
+	}
+	up := evententities.HostEvent{Timestamp: t0.Add(2 * time.Second), Source: "watchlist", Subject: "web", Kind: "up", Severity: evententities.SeverityOK}
+	_ = events.Record(ctx, 1, []evententities.HostEvent{up})
+	if problems, _ := events.Problems(ctx, 1); len(problems) != 0 {
+		t.Fatalf("expected no problems after up, got %+v", problems)
+	}
+
+	all, err := events.List(ctx, 1, 1, "watchlist", "web")
+	if err != nil || len(all) != 3 || all[0].Kind != "up" {
+		t.Fatalf("List = %+v, %v; want 3 events newest first", all, err)
+	}
+}
+
+func TestRecentEvents_WindowAndLimit(t *testing.T) {
+	now := time.Now().UTC()
+	event := func(subject string, at time.Time) evententities.HostEvent {
+		return evententities.HostEvent{Timestamp: at, Source: "watchlist", Subject: subject, Kind: "down"}
+	}
+	recent := eventsservice.NewRecentEvents(3)
+	recent.Add(now, []evententities.HostEvent{event("old", now.Add(-eventsservice.PushWindow)), event("a", now)})
+	// Added items are kept even when older than the window
+	if items := recent.Items(); len(items) != 2 {
+		t.Fatalf("items = %+v, want both", items)
+	}
+
+	recent.Add(now.Add(time.Second), []evententities.HostEvent{event("b", now), event("c", now), event("d", now)})
+	items := recent.Items()
+	subjects := make([]string, len(items))
+	for i, e := range items {
+		subjects[i] = e.Subject
+	}
+	// "old" left the window; the limit drops "a", the oldest addition
+	if strings.Join(subjects, ",") != "b,c,d" {
+		t.Errorf("subjects = %v, want b,c,d", subjects)
+	}
+}
+
+func TestWatchService_ReceivePush(t *testing.T) {
+	db := openModulesDB(t)
+	events := eventsservice.NewService(log.New(os.Stderr), eventrepos.NewHostEventRepository(db))
+	svc := watchservice.NewService(log.New(os.Stderr), nil, repositories.NewWatchRepository(db), events)
+	ctx := context.Background()
+	now := time.Now().UTC().Add(-time.Minute)
+
+	data, _ := json.Marshal(entities.WatchMetric{
+		Timestamp: now,
+		Watches:   []entities.WatchStatus{{Watch: "db", State: entities.StateDown, MinCount: 1, PIDs: []int32{}}},
+		Events: []evententities.HostEvent{
+			{Timestamp: now, Source: collectors.EventSource, Subject: "db", Kind: collectors.KindDown, Severity: evententities.SeverityCritical},
+			// Agents may only report watchlist events
+			{Timestamp: now, Source: "other", Subject: "x", Kind: "down", Severity: evententities.SeverityCritical},
+		},
+	})
+	if err := svc.ReceivePush(ctx, 2, data); err != nil {
+		t.Fatalf("ReceivePush: %v", err)
+	}
+
+	latest, err := svc.GetLatestByHost(ctx, 2)
+	if err != nil || len(latest) != 1 || latest[0].State != entities.StateDown {
+		t.Fatalf("GetLatestByHost = %+v, %v", latest, err)
+	}
+	problems, err := events.Problems(ctx, 2)
+	if err != nil || len(problems) != 1 || problems[0].Source != collectors.EventSource {
+		t.Fatalf("Problems = %+v, %v; want the db down event only", problems, err)
+	}
+}
@@ -0,0 +1,27 @@
+package textutil_test
+
+import (
+	"testing"
+
+	"system-stats/internal/app/textutil"
+)
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"abcdef", 3, "abc"},
+		// "é" is two bytes; a cut inside it drops the whole character
+		{"aéb", 2, "a"},
+		{"a\xffb", 10, "a�b"},
+		{"", 0, ""},
+	}
+	for _, tc := range cases {
+		if got := textutil.Truncate(tc.in, tc.n); got != tc.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tc.in, tc.n, got, tc.want)
+		}
+	}
+}